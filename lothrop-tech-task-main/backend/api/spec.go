@@ -0,0 +1,321 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.0 DO NOT EDIT.
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+x9i3MbN9Lnv4Li7VXsOlKmZNmOpUp9J0tyrESWtJKcbDbOpw+cAUlEM8AEwEhmcv7f",
+	"r7oBzGBeJGXLjpOdrU1CkfMAGkA/f939xyCSaSYFE0YPdv4Y6GjOUoof9zJ+znQmhWbwZ6ZkxpThDH9k",
+	"SkmFH97RNEvYYGdKE82GA7PI2GBnMJEyYVQM3g8HqZ5VLhzMWZJIcitVEg+KG7RRXMwG798PB4r9lnPF",
+	"4sHOz+499iG/FBfLya8sMvDwvTzm5lAYtWiOkUaGS4HvFnkKT4sUo4YNhoM8i+2HmCUMPyimjVTwicbx",
+	"VcwViwy+WbFU3rDwG7hAz6lic5nETBWPq33pbqx++WuuuI45juwqmlMxYzCvkjjFyGp0GcJ0LMljpiPF",
+	"Mzu5wXc/XhKdI0GImVNDUhozYuaM2MfvEpEnCbmdM0FuFTeMKJkbpglVjOSC5mbOhOERNSwekrcDGqdc",
+	"vB2QqVQEP7vrB5VRaqZGm1uPB8MBPJ1O4FujctYybthgVCyueFzdBptbj9n2k6fPRuzr55PR5lb8eES3",
+	"nzwdbW89fbq5vflsezweD4aDqVQpNfDOnMdtdEHS25XFNxQ3wA8jw9NWatZGs70VvIkL83S7vIkLw2ZM",
+	"NfYmDieY3dDvOL9WtbG17d8X1ETzfbyg+7DZd7g/uGEpfviHYtPBzuB/PSpP8CN3fB/t21EN3hevpEpR",
+	"/DtWiyuVi+ZGumCGSEFitSAqF3pIbudSM1K8nNwyBZsnSVhMJjS6JoqaOVOw6wTR9IbFg7bzj0d4/aEj",
+	"RY4MSw/hvuYMaotQ0qZ4UyedD/Cwd9PZMoO4SZqTPJ0wReQ0oIa/ODgWm80tMxwIaa6mMhctjz1nv+VM",
+	"GxaTowPtD6+J5iwmQpKE33jqL4YEzqG9nEhleUlBzpVHZCkFy4mUQ+0kYbkwDepNOUtaJnk6nTIRczEj",
+	"eMHQciLgT7hchGuiMxbxKY+IkUQKVuE0Akl/JacFB9atx1nE7F3z5WdSc/gISwev5OKGJjz2ZAWqwtee",
+	"spZAweu32la0IdD80wRNGcynoO4q6WZH3S3d9v1u+0y8IcqVYsJcZXTGmsTcHE2oZjGBX0kkhaFcwLrK",
+	"6VQzs0vGdmkTnnIDZBgTECEkypW26zzjN0ysPDAxm+SzVZP4Z87U4gCvfD8czKm+EuydaY75xzlDHlWe",
+	"W8VSygWhU4Osi2ucDx4wOxEYtRtzRmdcUMfTi1FX5FzA6GAUmWI33aMoXke0ocroYhSMTLnSJjjuG2zD",
+	"jwcohxLCsdrxYLiG2oXLUNmnW+NW/sTemSs735bDm9HfcubJMZV2rHCLo1qmmGbC2JVfRWRCRYwPiNmU",
+	"5okhWiqcXq5ZvEHOqNaEGytbqMYri4VQNGWGqY23laUYvP51b/F6Mb59fTG+ff3DP29fH0j7z8vs69eX",
+	"R7//+/Kfmye/Rubfl7MnP/Hxu9fpP38//vFwfHL5kzk5ONo6+fVw/PoyGr8+2LtdR5mxS1IhaytVjTQ0",
+	"WU+QIMuHc2S3QWKY0rtktGlpykWU5DG7wgcCrXDBK4foSfcI8CTrZePAC+CDPbarhrXeGceFXn8GX69U",
+	"tUIpb0nrN3ixJNUZ13hZwCKCc9rNchddjHZxReNYMa0buix5kWsumNbkwijGzJAcSxFLMSRvvh8MBykX",
+	"x0zMzDzkek0lGURI9dGH9hPZlyojxyaGZ9F3/llbT56sfHZdQS6fvTXeejwab47Gm5fj8Q7+/9+hyr1U",
+	"g8bHWnPlXh9r1ZEr2s7NrcD2EveWaqLl1IzcXbtEimThdbNgNycc9axbbubFhvRXzekNA75jd1HrKFfy",
+	"hVI5aQz6shzvV5oUFw4J2ITaWM7vBl5hp6pQD3HY/8XSCYu/CdWgteT9gbuhTeB/KpssNHObFNmnQgoe",
+	"0cRqTHJKpGBeRYtZlshFyoT5ShOaJPKWxSR8nh6C8jHls1x50vywd3x0cPXdm/Oji4Oj/cuj05ML8uDN",
+	"90NywcWMZlJZ0bNPFykV5EgnVMSaTBZeED3En90emSzIt4eX5BHN+KObzUeVV9ekz5vv2yYvqMkVA5V1",
+	"4lhClcgXcmpuqWLkgN2wRGYw13X2WJsqHD74MXIGnoKvY3M8Rr7g/up8eqgIFI8PXBbVNzypvuHOr1Bs",
+	"xrVRiyv7rpad4TXpQj4pFkkVe0WdvTNMCZoQ/6TKcow3tx7D7l2HmMVQtMxV1KLtHtZf5SwF94cb4YQl",
+	"Usw0MbIykoLxXM1lrtfiIJpFV5GMa9z/4nDfTmqtR9TWbRkfCq/9UFZUed+a3OiivKeNId1SBTZFm8oi",
+	"xWhKQYWg8a+5NnBqtHV23c55wkimZMS0BpWFWk/XkLCN2QYxKhfo3rIWaMWT9XPLaQ1uMJLAPgdXmqKR",
+	"Yda7UEz0boY2ssoKZ6zJ/WFDyagJ75rQXaK/7M9ZdK3ztNtwpMlMKm7maZPURzEThk9Bblo/on0W7p7c",
+	"oD00dN7FmPApyE52wxThFdIOdJ4+3R6l8ZMRj0duzKObzTaW6V/R4pXKUy8ZlLwlc6rnTJNUxnkiydZ/",
+	"P90mVJPNpwTkhIqoZmTO3pGYz7ipjmY8fUyfR1uTZ/H2JntKv24bRqG1V/yCK3RTr48WcxgGtF22RDIX",
+	"pnt9PmIsS156FC/xJXwZZjSPq0Ln5/W0kl8+3CG2vrH8VzH/GpxnqeFUrHv7xsmF2StYrvNZtjpPDW1O",
+	"eS+F+4GR0hj5qVXfc2GGREgx+p0pidrXhJlbxgQZIcOFb+DDLhFsRg24Qo2EOIcBPrzCh9R0cBraPjfk",
+	"q+5odM6sxfJr+nHhZUNCDUmlNmT/9PXZ3slPV3sHB+eHFxdXr/f+dXV8ePLt5atAlBApIhRehumMRug6",
+	"jGSS0ExX/cprmJcrDcqaKw+oufXkSWM0OlcKPMCwIasjM4qnaW1cLbbpHU2B07+I4r9B9qlmIy40E5rD",
+	"ftwl10LeCkITTjXT5AHqGm8Hs8nbAcTR9Az+6z1eCZvRaEHeDmAoTOi3A6IzliRczB5iJE4Ay0r476w8",
+	"JIWVdEOTnG18ibZHdTFfuCO86Y/vBjmFTRVa7HNa2sBOudeECmL1A5Lm2hDFMkYN3MaVU7QhbMCjOW5E",
+	"4ItCGjJnSUEsBcoIcf7JcOW+0sTZKLuEioVBVssS7Rz1wAn8ZqJke2urRubHH2c5raDPeNgxZOAXF6/2",
+	"zg9fnR4fHJ5fXL34qbLLrQOug7zBIO5A4Q8iz5P/DKuvYeyRBzULD33jwHhopCj+UXCgIYmQ/VwVj4Sf",
+	"qyzp4a5dlwkjmoG0nFl9Cwlfp989GppNE4ulmVlYjqPtmFBBadukF4f7BB5ErLo1DPgq2QIibZM8y5xW",
+	"njCDUmYqHWefLJyWvksoSbm2r2nfdUQK4oddY4N3MJBrasHdLLFu9cH71zr1h7VFcGVi31HByEXKzbyN",
+	"zyuZdD1zczzueuZrKkAVn5GDEs2ynEiOGvi6bhIERv09U2EvShl5JRNQSHSXiiFvBVN6zrMr2G1MmNbw",
+	"5beVGBqyYK+ugbfMbrc8Q4lyK0nMIp7ShGQJjarol60nG09CP7HMYc8V43KntIOSrWNto2yxRJ366BcN",
+	"qBk8i56zp0+fPR892956Mtoex2z0fHt7MmLjZ9Noc/p8TNmzdUbTjIhUTkY9FtJ5UpafguAxsBlWxe0b",
+	"yJ/wnKwB+0EIxbKgvpgmwJnEzK1yzUFz4JVm9o5ruKxQAGjJP+0ASJxnCcK7tMNeHLw5Oz7a37s8vDo7",
+	"PT7a/6lkuuusRxP512XIO28b+JriGGEYNDmrzLO59SrTPJaRU4lTpjUEkEFyMhrNCyCHR26E2BL8Bd0N",
+	"BNEs9sdRAhovueEywd8qR/qPmr20DNJRNWgGfiTh1zuFNHeBjZ/ffB+YJFXZ/8vgfcsOaYBM9oQDzMgI",
+	"Q5uti+PIsWLTuKuGhCZakkTOZl7aghtvQTRT4M5L5IwkXFiqc4McUzGTK8FQNfvXyDH70VHVLJxLbR7R",
+	"SbQJYUD43+ZH4zwP36Hh/5lwMLcgKFKqrptUPKZqBhsudMQWVCnUec1FxKwWZfekkFa1dl7TXZJRrQuk",
+	"A17+wbHHYrQrlj2hJcKkY8hXPL7jqC37q3ONu2li5dq1Lf1Rmkllzhn8u2XdW+Laz1thRXfFPdoHg9f5",
+	"g6GPU8qT2thaIU/wjrX3raOHvHUkWYmQLIIHbjjufUtoLW+7yH2fIL8OFaJr+/oFcTt4HVkF3KvlCANP",
+	"cyadszCnPGFD65b0rtg5ozFTsM/hKWRzXVhg9V0/lLLIUgJh1aKUX/K2xZ5zcieiQkhD2LuIsbipJTem",
+	"qw01eYvfQV/zLAMpStW1JpQUr0Y3iqfrhEUULFlK4ImRIRw3A5nTOBwuvLmKpwfSuwsGQ/+yKqq9vHA5",
+	"J8AFKybStkO/01LsZbwDgxozQ3nyoejMVTuwlJv6o+XfcJAyQ1ed9XC2r+H6Jav86vLyjNgf0SzHYdrF",
+	"FDOSMUW+uzg92dk7O6oMdns8bhud4aauN7+gMXEzXrmQbpD+OatW8kBGOboim+kNbszu+MTuwmGoiWjD",
+	"aAyLVFGrSx4UJZwJQzQTsSZ7UcQyQ2hmtWIuxaMbEW/QjP+fX7UUG+SyQGO6V+J4Ji4YCjw0V2y3Q6l0",
+	"/o0hKn72ftBXrdusJYVmfbZf2fireP4SJHxjS/2lrI8O+XMZYLsrJgGMlLav1RA5QuwOc3Xr4A16h1Ay",
+	"kfHCC7VMas0ngC+TBuEEUhFhYRHWYTa0aMjfAJxcYlZrHquao6l5jppLFpgWGI5rW7NcmGUBxyCbgDPt",
+	"5V7NB7Y01NwM5SwPRqxyt8GAf1kx23OmZZL799Wm7OMjywBm4VvtIpWYZYS54J50WRdrxFf8lavt39r0",
+	"/Y1tMz7BoM3p1If47mmN0aLD0LyNCsElRVQojKC2LnZ7JKl57DBtLm55xdAmnsEhqY5J5qaR5jGFN0fz",
+	"O5sOLaNctrfOMN2qEe+tJY1QZThNCqZmrbxdQg1JGNU4eMddvJnvtpTF+YY+js3hXYPJhRO+jyb30eS/",
+	"ZjT5M0ePuYHZWXZeBNA+Prb5+C8a6v1E5HiyZmi3NTB790Ds63XDoSQXCYI2A9LQRDEaWxJNJMYJloVM",
+	"/4NCpGtom2dSJudMs2XO1kRqFl/xOGFXkRSCWba2TDeBa0lwrU13Rb+afdoK/0rdqdUxglahr+QkYekB",
+	"+gba4Fsv98mzr8fPnL0GGv+9mpeZfT+al4PhX8jYavOmFFgNaWx0pdNK6yM/Hxb5gQ1HRVRzvng9ouBe",
+	"j9YM9/6FY0Shr6s0TcfbrYjbpr/qRBrysmuL2i/Cy+lE5mZnklBxvdKYxV/9S5f6K89yNVtW2iE3cjrt",
+	"gkRxVk1vIxM2hW2ERp3hKbMMNIN3xGtn2LnL10InZ0ylVDBhyqy6uul417IZxWjd3NuoFqR3txqdoGM7",
+	"N4uVI1QT9o5FORYxKTYgMgXMJ7/Ci78Bk7LBgqmaXRW29XL1T/+W1PNzjg/3LwmPh2RjY4O8PD99HVDv",
+	"x1eH54fk+PTHw/MHIZd4SL5x3/5j8yE5PT84PCcvfiIhbIAcHF7sDwm3H8jx0eujS/KPLXL68uXF4SX5",
+	"x+PVLtjfksEwmFwbnRG4E+9ZW/RbJfOsuUWXWKqFkfGgSL+Ih4GlNyoM0IekTGmpWqJF1o12lmjiLNH8",
+	"utsSva96B+useo2s5Tzs3cMV4csKibvCajMg/fpTalm2VR5h94bOEbqUrL8o2Oj5ZIttTzfp6HH0JB5t",
+	"s6fT0df02WS0GW3Fj9n29Al9OvkwsFEbAG0l5mg9QBrSvYhuOrUDbo0xjcbd+qlxaZ1YptZJrIFtuhA0",
+	"03NpztykPyFOg73LuGLa5Yqvt3Ug2SWVqmU9XlIwhqUowRI2pcnmOFn8Ofyk3QTRw+ZtlxXu33LawQgq",
+	"E1hGyiV12CoU6MiWbw4YjGiqYjScIUISGX7DzWKX2KoOqPO5K+3sqbAWHFBV5mZtXcO/ulXbfEUFPDOj",
+	"WpeOKiwuAmokvr8cOxNxJrmoZ/VNnrFncTQdPZ48iUbbdPvZiI7p16Mn8dNoi21On9PN8WpJGQxy5Zpc",
+	"Kir0lKlqmKID9LrcXXlZcxggCdzjK0yhll1c2BN3C7o0Z2NdHbniZnEBp80L/JSLS3nNRFERELc2o4oF",
+	"iOG5Mdng/Xu0WKbS2bKGRlagpmg5DnSegcT7v27wG5FMPYfZGUBY98Je0DQBX9DomomYwEW+8MyxNHMl",
+	"M3LJojm5pPq60MF3Bo3fiI103zClnZt6Y7wxRvacMUEzPtgZPN4Yb4AmlVEzx7l7Mws+z5hpy/UC3VIT",
+	"SoIyhoWVaqR1KqsUx801/H4NK4NvVWh/HsWARmZmL+MXPkyu3AnHQWyNx56cLiYeuhPQjeAXZiWCIKzi",
+	"iItVT6+NIqb1NE+IKi4bDp7c4wiqWFcYQqdzZO1n1lw69YeGAf31H9oKSGih2ZFwOSLOBrbYRbhO52lK",
+	"1cIuL+4AZxnCj35r4fEK7Hi0h5B1yNZoVMMC0yRqtw4hOwV49UxaYBocmYvTl5dXB4fHh5eHV+eHl4cn",
+	"6FO+5SIGlELVs2p9xIWLHrlw6OZGbARXhOYxN2TOtZFqQXSubvgNc5cbutBEMRpDDI9MlUxxGP5iz8U3",
+	"CBrGYYmWiAose2cnMWHEleSMbdyDi0gxWBKaQEKQopHB9zCliTYyI5oxh93iihiZTrSRgukN4tIzrYsY",
+	"qU8McDeLj7FszX7jSh9gyqo3H/cOXh+dXF2efn94YkNqPmZk/ePVU42TsmUG4/1A5H+y4111L7Rs1v3S",
+	"lEeCw+neHm/2p7vrdL/mtoaEVIWrMdgzPXP8cOboNI3Bzs9VHePnX97/EvJO3NNVzlaqz8s46R88fv/I",
+	"cY1uhvpGxBK4Fb7BsdTPyiTO7Qj3CxxrgRTSSJv25MijA4RZDnZQWSmVKNRZS53PgibKNV1h8r7/5RMy",
+	"p8Jy62JLi4LH92zpo9nS9ni7J18X+U5krSjcIoBHHR1Y+j3v6ddFv71KOeJCXSSapsyGyoS8tSHKVnCh",
+	"RUYKicpm5VFzqssnFYH3OwkMx1AJra9xi7SIJyAhmBllUibdQmIfnA/a+jzgShaTmBo6oboaxdaSTBXT",
+	"cyKFK6nOtKGThOs5KK6I4nEelPIBgCGWN0x9bqnDzIEbAsT4P6la2sAQtKkKdUiAc6b1ouDjRMEdjw4z",
+	"bVsbd33l/FQ8t62+ETB6qY3NgVMxSepgVJlZLICrcwSTANOuUh+q4SYJ7ailmtJrW5owKCNRvt5IFxUs",
+	"UZXIdFBJ/UqT46OLS4urxEDbA0iiD4B9u3D1W+H+dCWc+e8IEMBbDw5f7r05vvS3b1Xu3iClK9ZpxQLM",
+	"Y032jo9Pf7Q3Xf378Px0+FYg3OubsRuuJkIG05jkhlBBKKSAUsOILQv1ACNRI2AQD+3RRz0RQ56loujL",
+	"PpWb0Y3PFrsqKjm2lpNaJ1ZsJIHkH/TWlUu6QU6xzpQmdCJvWAfZXVgT6F6lPPLUKt5pG8pC4RJMc1V6",
+	"L3wNYovX6aZDUfaqhRB3psNL3MjhCi0qcg9YvC+1UmYEAKksmAtDPlOiAVRRg6t7EOh/hV9+8+b7t/l4",
+	"vPW08mXhEH64QV77emIgEWooUzxtFBFKgdD1mFOqSRWmCpJSJjds6MqQ+dS4JfDTXZILi2R1+Lhw+Tyi",
+	"MEsQW2dNlbYlquUKlAsVFIV78/3dSjF2lDa/ZgvNTFHhXMmUUALVobnMdeET/UqToE46LKo9nKWTHtbe",
+	"F8OjhmS+8YDThTzneCDYbVFp86Ft5bDrE/0m+LAJF36nl5X0tFSme0vbYVUo1YgLtDuyrVYRsGnMfbXZ",
+	"h7bCv3ZoEwjLPKhvqIcb5AUAZsLV5jMBFt1Gx1g1oyqaV8YaYHCi9pyYxrHLk2SEQ7KPI6BOkQp0C7Z6",
+	"MykB1uOWTfxteiEMfUce/JZLYC/ZXMFJAMC1VB5wPbqVCqNT7B0cguKMueniElqwpmIJu6EiYja1yB5x",
+	"pomi4hplHTLAlkG5C4cey+rr4mMxdQCLt28Pvxs7l6Bzw/zWQX+XhkFGkVpkRq6zFO37yKNYKO7eoErk",
+	"+cv9x48fP8dAojY0zbq2tH3AFd7aMdqt8db2B9YZ/7B5hPCru07E3rtsJptbo8ebl1uPd54833ny/FPN",
+	"BHYOD2LcJyTlAhtCTWyxq1IZTGR0PSSU6LlUJsqNzeUJF2aDeJz2pAJ43368NR6TB4/HJKYLvUQpUSxi",
+	"wly5IbRT5+k4KDuNT67WnV4tpfdlmtKRZiCAQxeAxamGyqFLu+DxMISFvh3sEmu6ujvw4KfcZgGCzEBo",
+	"qrtlg+xZAPcO4sLCB5V/ORjRsGYlNzMuhqQleyL8slrJ2VvQQ1KD2A/rwPlhBW82rFS0GJKyDP8GeeNE",
+	"OsxAtytk3UyKx1ewE7u3QFGUue1g1Bbiw7b/AvsrPWiWwH0YoruneVLmfNkIue4YsptTuw7Z0ZGlOdCz",
+	"Qkcm2iwSBhuPCUykYW8H5FbRDDWQPDE+mQ/U/UeoyD/yXXfeDmylgreDwmSgZAKLhKqPQ+RmUjmVD59B",
+	"IFaez+bkX6NL+HuEKYcb5IU0czsYjZkXAI1/8vzrr8kxF9euJILuXsqKIddCmmJ6QQ2B4Cv7/MEvayzy",
+	"vkzyFAETKCsniw3yo0sthC+GoVammKNMTUpbJ8pv1a4loX5GHoQnBBPBH1o+cMs16yYDjKC6n91ca1u5",
+	"puauV+q7myYXQAnLIrgUu17nRP4GVyL7hsdBXmCeChuxpToq59RlMrmOY80ZUR0NrH9irSHuoZ8Cj+YD",
+	"1fR9hQ6vh2TksOdcm45okK8Kih08So7lOnm0ntxq74+PPMFFXydpa4c4bLxnIm2lnTfIhU3IwpcUNnNR",
+	"Fdmimp0gmqI9O+WCG0Z0pKSzsewZhtULet7gRsfiqKPNYfVgl4mDHtVGEjjQgRizuq6vBA1nQhueJIWr",
+	"oZvBO4dF93Go1LBup3hXCnWd4AgJx/2z40tjFzYYotVws1z885g8wLEhSNLJRpudyQ0QaobONUdxqsn/",
+	"IEj8f4qCo44nFfbfBjlxpZAQekB4CTdvdeKGG3kIVwvYGYTeUI7Jzc5/e3Z2dXjywzeZknHuFAAYY7Rc",
+	"lJKKf+UbyzW7yR/g3z9yu4fUtxwVp3z4r7PjvaMT8mDvZO/4p38fDsmLNy9fHp5fPAT6iwK9FYD2qTUo",
+	"H2UJ5SIUwk7crSSqXcu16dpNHfBGUC4+kjKHl3TWcB7gfP0OghS+x+Ntm1FUpCfwhJV+GJ/RZA/ewmqa",
+	"vOBkTjoWIz+ajk6kYCM0Rpfa/h8b45WCnU7R4bo62hs2K3w/XOeOUCHDWz4SFfzHB/cB+AXzIYt9WSVC",
+	"dcExQcVu7wduC2FyCZ6Oh81XNb34x85NHggzGThUrc6K29kfe0ybz0tFCae4T6M5G+1LYZRsqYSR0ncj",
+	"YPdyan2t+3v7rw7R47r37SHRLJIi1ruYzaeZa/bSciF0elvuXsIj0IJBZvSaMGG4WRBDZ2V2mV1vl2yJ",
+	"9eVskQBmT7TnFxWEWCxXjAG01OYYUJfDsh83wxJeXBWFehd/h4l6u06KoDFj0b4Q7w8w4hu1J6LiDWqL",
+	"f4xPE+XKu/NuZZ7EvqpXzQO+i243LvISO6eY5f4VB+SJWzBbXcN7/8kDJ4wfBt03HFtxavsy8lV0hha4",
+	"MvwYRFcK9aZcoQcNuVQciMaLS3sd3v14vN3yRi81LEv0/jSgfYX7Edh8sItxP4U81gYTe7xWZzARinq5",
+	"HdJj2+4B+ItsPYCrDbsABjZjm4LB2YRVZEre8BhTaK0Mw1ASWLgE0odjlmbSMBEtRt+zhbPLh66qvzc4",
+	"KjpFFbBxzRaFsyDMM/CWbsn3MMk00M8i5d7gsBxtSINKV5WV8DZMnh9FEHcQOLAHmyOoJZMpLgzqdHsX",
+	"+0dHQW2ZhySl6E9XzCgEQdMp2yDfs4UmNnnDOZyPDg5fn51eHp7s/3T1/eFPV5eXx7tEsdz2CBMkF/by",
+	"GN/rainEfDpliglT0A55iidXURijXSerrkyF7a3IGrNaGr7xhYwX9wfCa+txUz8370a3t7cj2GqjXCVM",
+	"gAcx/uh3vB+2FPMLgrsb5NhGEo2UiSYpXRRbDQ4NRrpXDRIra9S2t63NBEti6yAoRmzNjgB/gMp1Yf/R",
+	"iWbC7GKxEBThrpIAnEBshhbRxPswq1jL9w3devNz4if9idVFHkeyQEXS/zABDyBhVCWcqQ52UNu1mH1o",
+	"HStYl8TMg0vM6JxlCV2w2LEdr5YibQLFtOWO9tq7mPSUh/U0guaud5nGde3AteiH0q5DW80vDLe2FZod",
+	"kmbtBUAaL33X+17vWFvvIKOwcIcrXdkDMlcBMpdgMX39o3vEYw4H21tb/XJ0L4cVOrbENqEVYo+K2tcq",
+	"T3xXUlpUlvENqgLTKuwSraoM2Ol8wBz9MueaxZ3qy9s+Z+U+9Po2fb0dHrkR6ZtOiCSUW6SpXiNQQKgm",
+	"+xc/+HV1wlZB1l4NU2QBlwqcwCSIKZZqtysJaBz0C3zWl+C0iFiSaKINVajSuzeBQpUntFS48fZMsSl/",
+	"V+4zRMy0af+H7zKpStDmvr5pmgAfAwG7C+JrTfxRS9jqbpCUdXEad8SHfMxjlwAr7oSlWO07Roep2/NL",
+	"FKKGBnvxA6HG0GiOoRhbi7l6iHodahXfsuhvyzHKA19jXfZEhhFx5Czt3OvRBMMJnYkRrqEBq7Mwx5NK",
+	"zAEXmjnMQQq/UgJmd8JshQPq0LFHU4TAsgRTe1F86lAb9B1IeADFcrHMwoecoJJjghLgXMQ8YnqVc2IN",
+	"TPl5budVPMROJZiDxXtyHbYHGTrEhXVpU+/ynbAKCkvmpvSn8K6Ite9b0sKwyoDUR/gO1ou2tBr4DZb7",
+	"frV1fH/H+QXWcsaBLcsyObB9XXZrWF3TtizYkhiDJdb8xepqtr3L++G9mvZrDn6vkkPhRtnzxVUJMjQB",
+	"0cliF16yLVecC0ghVniksW6ZZXa9rfl3tjXXPGpYO1yRVKqQTTh7rpRIu4VICtxTvYV1fxaWT4UJ1BWD",
+	"1eSX6SsOGtattlyU+DGvuVR2YkV9kVMMvncoLdhXx1ZU6fAMEiwwAKFZKdgGPgvFjU35EbL6aotPBHWB",
+	"xZW+YrD3vFHopHuhMB0dEK5JWjC6QE+ys2zNAbWVSaqFST5Ma+iqUaCHYVPV3aAicZiYEFaz+nm9UoJ3",
+	"yfX5/GqIJewy9nJZ0T5sdWJUL9xy9UL9TkIdWPXRwUrRXuXWAal7bv2R3Nru+Ltz68WoaOK+pMSa4gyy",
+	"FQseOVkgcLPRCJ7Y3AaLzKl1j+/OJV68WJyXneSX2n/NfvD1zu+u5btU9TbvDzvR4TDkpUVb7tCsoDXZ",
+	"Jeymv6SHftvoih7z64yuu8PDn11XBhlsz1PXrCTgTpH0DUL6cjIrCNfsgtDLlPspmljh+QXTDWoXN2VK",
+	"NGfRtc7TZQKlgDq1FYeohF8cgqSIwRTIdV+60GbNCBtxGXERs4yJGNMJ3EAckCAdEi2JXojItQixNQ7x",
+	"vYoROqNc2DoUXJEEOl6QSGaLDXIIyYTQnnVO9dwWVigRWZtPyJy9c41h4E1p/OTB2wFUBXgc8Rj/y/6f",
+	"/bPSpJoL8kbwdyTlkZIO+Wuvfjt4iKUUYbrYmxad/85j5ufEnaleTC8cYSrjPJFk67+fbqN5tPk0GOQG",
+	"2SuQKkPXTY1gY01UIVqDX7bjtCbctDpx3aCWuHH7ENPfKMT00aqC3zDLK2X6TKnwHDdBzr1WcT9hKb8m",
+	"HcUdA/7u4eifgbkPi8jMlLn7QBZB3+uNJhuCgfU8qOdB6/Eg2C3LGNDJksyKnuncE9NBJr+K4zCMmG+I",
+	"2I/1HrA8gt0mXDBwQvOUGxZjO3afk01JQq8Z4aD0WCfzPYB8Dp2fW7BqpAVqHvnn4xf43KFLM/JNe3OB",
+	"tYeqkOkhyZJcE0pcWX37NZnbBinO05BhbYgrf4lN2IRHnr25RAF7tne5/+qzVj+soZJOYt+Mr+fY/xEc",
+	"+92oPMx3ACmdHOAhXYJTso+1/f6rxUNIhuFOwXre/cG8u68E+tkqgbahxOz27xSS2IRhpWJeLy5XdUqU",
+	"VfMYuaWGqZSq6yGRScy0cW4A4jJ4yyqElXIgZa8H8qBaBOQhyErXG7jMuvdvQVFU/HXFLXYL5q25cEEF",
+	"/AQ/GVlk5u4SA7ORojoVkL8ThfILwhUxen8i2GpRjsUXHcE0EYxjlBcrfwipXOKetTJahRfeuD5ozZUm",
+	"K2da9OEoihO4wexinjLMDmHA5YUTNuNC2M41rdIKCPNBQqE61gr9lwyzqBbjX9w5KNvFaf0K+MMPqiN7",
+	"9yKrm+Owjtnm+J4l3B1ZoaXrUn8Inr0w/94jL4N95bITMAcecuR6WbemrOsyUI7KNjfJwm9/EtXXooMn",
+	"87ToL9kKg9lztV8pSfPE8AyOfJ4lksYltAuA2dzXPkhcNiW2/gmSEFwtODMvi0k9qGK+wlpX1qtWrXzn",
+	"cC82yLj7CQvf4cvrTePh5d60cbX9KgiVh956grlyHeSD+kOA38DP7j6EO1s3uxQ1lPMePqZMzw5TGeXt",
+	"V7pauTS0zKo05UYX80Shb3Q9RgKDdbrABjkSJGECu5OncIv7oRx1gTsKQNW2hoXT/ndhNMAqI/cIC5Yq",
+	"HkNSRoUuUBYlTLtNjh2lFSPsb4K9bsgPRy6pPPGrpdgqBbCPXp+dnl9evT49OOwYA1C9tfSafc1gOHBv",
+	"aSvAthQXXrCAR5hTDV6IKr+r9jIEZlCRpxMuKI50eQ9CvK+l9+BnRWnZrefa77aw5TOmRnjU8Tq3hXph",
+	"tr79kYuy6RtP2JCk7je/xl5O2IxGKUnqHes9/noV/lpYdHXpEa5Bp0Ox0YK8Lu9ejb7uxDffJyR71Wm8",
+	"qAgcrL6cMKoNSlY4pjA2J4U6BlxVqtKabetKtYGqg+ypXZvyfWF1t0J1mjEsuAqb35ZgslIeDwJ8WRFX",
+	"8qbS2LURnircxSJ2ZgY8e27b5BZtFWZF9VafW9XlKLYqDNauUmyasMjlSaHDmSJ6QGYMrDxnrBaWuuLG",
+	"MOEq1Lm3erOVZhmjCg1XPedTWxxLO9iCpxm6hDWhbb1FdirBuowJDxq0PQuyzJejpG2ti11NtIQhF4F2",
+	"kUS6x2SwR8r+vRvktOE1vjjZO7t4depqbp1Z37EtzD5u01lggX0b5t5f/LfyF9+fY7HRp7uNp7lrcKf2",
+	"eO+Pcwpv9dpCJ+HAUE8bLqxClrkKhTZpC/Yi+NbAfrR+WABfL0Z7+NmBxGrC9BQZNno2uQb6F89eJUYf",
+	"/eE/HsXvrSz1WTut3dcqvestW0GTN+jNrxgoBtaI022ypgW1AQ/vZukdp9YJ4aB2a725Pqgf7my3Nuos",
+	"574UXb0aSN1SKLEYZthCrcfvdp4R19Fg6HUKqYgvBVfZzAFwAC4MFBIg8Uo8UrM1UODazlxd1somspof",
+	"+uI1bmmr6PCUydxYJ1yLShQ8HR6Krjwo5Y3JjFjEuE21OWHvjN84Z3TGvvyz8B/pqq8sUQe/R/97hhf0",
+	"asVaaoVd/Z5P3jefPEeb129Jf5Hbm63qAdbSW6IMBKm7zYywN2+ODixb9D9wTeY8jplwjXnQBC6w+9BA",
+	"MaIC3MG+9bSrLYix8Y2lKbNr9+qGUX2mbt3b3dm4Pj4e1obs+cOa/AGWkLjl6LlEnw31p2XY0rLa3HBl",
+	"vmxRaLCFTS7Nhz2K/3Tetl5TjdX9NHitXPzH989ojOzV5eUZgn+KWo7F+I6pNqPXMuZTzuK2QboWj3UU",
+	"lGC34LE9suFnq1JXRggXaSbMsurT0+LNo4sGPmedxOG+S96n7ZL32Zrg1ddSscStaZL4dutGFj2UuEi4",
+	"YEOodYX4hKI0b8ZUcE+wZgGRQ9KuRwZymE5YHNfGA9des8x1Iam2PrL06aYfgwd2kK8cajjSLzVnvVJE",
+	"+05NVYp61c12Khy9BW7briqSHXKw9p4c7k1fVdnYyoLYnT0+At09Fx7rBFuvygElflFlcX3Xj17L7bXc",
+	"v1HO/9HB4L3lgU1eccxnc3PL4N8W08BE5FLUCRX6lqmijrEp4IoY5v728LKrWBfWjrE8pMVJ+orRuNeS",
+	"/3O15A6doH3VcU/q+xDhVFd61n57ePm3ktqBgL6LYFqfIX9A+UHLRUhUXcv3cOBdReSab9QVsgCIiQXv",
+	"oNGTKQYb0MNywiZCu6U1VBpICZuakn4OstLyvEpXmiExcmZ7zxbcDtmgqzRoa5K4PnUpeTBFxy0qxK6E",
+	"fWhQEcVGxd2MOLdoYXsV+nMFSe1sq4e2yIm95YYmOcMhxw7ojB0PSCxxkSbQUB6RgtYYsTuwJbH+DEt2",
+	"fiE+1+H6Z4lQF5xWjMa2for9xRW2tgbQ5hbhleOOSDx/gJblt9RTa+8OsvlETabCBav0f/p8CN81DCy/",
+	"Sr1D/IMb9fAmfx668ovIJovijB0dfXr74m72RY+L/rLqUm/2PZCWAs/C3m2pU/lcOm1DePU9pf6cnlK9",
+	"3+Ie/BZnVBmOOZpOw6vE6bK8NU6XJTQCzlQ0oJTTSnpGERq59MwnEKM+uwE/Ay3aNGdrlPSq819Hde7u",
+	"ndrrzn973bnXlHtNudeUe02515R7Tfnvqim/aejHnTDgjSyerqwnRUmmuLAZtu4ttfAJqJaWHLaS4NnB",
+	"y64SbouRf+8QE2xpZHvCp1TQGVMIkkHUCJG50Ty23dD3zo5WFRlcnMXTdfXvzwYWXraTHelbHt9Z56FZ",
+	"RWEdQvda45rgjKjcHr1GeGeNsK0FahndK/iCv6abKT0qxcE6NagDHhQ08bbl7LCO3Qa5CKvXNcprcRVG",
+	"3BQrJ9U094+5NgfF8P5K7OYDG5H6ybZ3/VrqbAiFes9/ev7zOfkPnFNCu3ZjR6222NbPcFf60lEFBxMx",
+	"0czoNuBz7dqvdIty67pkaayXldDIl7q3+o9mxl2ZLDbIS1eXUThEgbuIa4vXZb/lNPHvdI/d8dF9ElEB",
+	"ZHGlPLkpao3Zh9D411wb1BeKLC2LGMbuH6Q0XSMqyJxCnkPZYbAkZJtCthfHBcP4Apjjp3Ie+jneyXt4",
+	"f9UvSp7c0n3Z/UZoHPe43A9gvQRPaokh6lnxBzkHe+/J6k1XSAZf6l+qqsRxfkLw+VVYb03Y7cVxILfW",
+	"0qwf/eE/rqgMcs5SeeNKgxTjvZsoVCylHGpeLRGK1ZTiNNeGXDOWVQuR+ZuGvhRn6HX6ShNoT3BwdH64",
+	"f3l6fnH14qer796cH10cHO1fHp2eeLdTm9iyk/xCJFcjhlew9K4Xlkv56XOdD8qFA5r1EuauEkaW1mav",
+	"6K8vXUKy9ZLmg+IeviwvAqBtkh+g+wPnB3ZBrzPWohJ8o/AEMIA1Bc+cayPVYi2HDs1jbkgiZ4QJozjT",
+	"TkjYBDtJitTQelbosCwxIZWtAzllCv6sQP2lKgAMMNdy+lhfMcxaXeJFAhGY5aparx1FlvUouQm3SZsy",
+	"DfyVveg/wY+0B4t6KIxa3N2TZDeE2wy9wOm9SZ/bm1TJmSs2pOdpS9heyEdhsB0wtVEsUx7xhFU82jtF",
+	"U1ZMeanz5SGZ5GlWB3WJ2HFLXYvKXtmHBYfJwd4aySnV6vtl01zA0cnbhGszJDThVDPt89ld+djWtBZf",
+	"zaCaJsONCwlAZzKwCCpDQFnVxjsvHVP/rlrk9W/oaWqb6ZeLVguEbc+ge59TD0j7ki0BrgvXksOeGapm",
+	"zLRA0JbB2Bx8zcyZYr0ltgb961IWdi+19V0ctUOR6XDYUmDRUpD+AKfqIVL3AZHywjXY4EYWgMtwnZZp",
+	"dy3eT1Ty2tOX9wwoeZjBQCHSGLPE0CFUB4jmJKULMM0Fm1FoXTfsCihWXmbb80Ax5sSD930PvChXilWC",
+	"fZrYyjTyhinoNsBscSg7Y1q0VEBk5GJkr7BqGNkreotErptvDs+jmoxLFdL+hJ5bbXA25pYxQTYLt/aH",
+	"eGutXrkJJYxerNB2RZEYEmq4pyJijfyFuvd5RYiVK++5hhe68OqEOeK2t17aw9+8txS7IP9NI6Iwtb1i",
+	"m32JKmoZ9e6101477bXTL1M7KoWI9VRPIW3Pg4BB/XGqElHgx9glpqMiSq+KriA2Kh5Ast+Zktg1D7R/",
+	"Zb3/UHuduL5Dt3TRiEm3ic9eJb0HldSK0FbMmj0W62milYqLn0EZDd/319BHCzhcTR8NZlK0c3mAx8HV",
+	"xoIGVhev9s4PX50eHxy26KwIiZCCPbxXZTUc16fWVy/Kd/Uqa6+y9iprr7L2FOtV1r++ytoiRHut9dNo",
+	"rSGpVyqudXX1Dtlea0N12hK+wpvXyfm6qBYy/9vDdYL53h2vU1nUXnnp4Tp/dvJXfUMuzf8KLl4jBazC",
+	"SJZkgbUIoC89Eax6qI00NCHyVjCl5zwjNFJSV0nrX8jeRYxh1OZ/+7bSy1LKxnVW3g7uX2V9Y4O5jqS0",
+	"kJ39jfPSgmn+SalpFbnR0pO5/LlPULsn27au77acVWudBMeyFzF9UtsnKgnEta+7tjSfrYXDt/TQrsjt",
+	"lpw3XWE3a1kZj/4I/rpD8psOWddd9YC2FLgWjWCtLLjgvu5cti9H4DXS2UIZ0PXOyhJ9+qS2i8pS9Hlt",
+	"H5jXpqsr28uYtVPbQsr1IucuLtiQcrzuIkqoNl1Za7rq4VhWQLghAL7SFgELcqDUsjKmIiYMnbFVtlPT",
+	"TuoqLNzz8S/Zbhr/GXaTbyPUC6iPFFC9IdULub+iXdVV7rPLGIrZJJ/VTSJFbzvjLQdwAyYd7rimaNq1",
+	"9Y9kkqfCV/902QpK3g5dIiBYOFIwm9rA3mVS23ZqcHWWT5KgUbZirtEWtY1uz12WOV5L45QLYuQ1ExYL",
+	"NGFUMeW/Ea43HUSl6A3lCdYm5YJkSsa57Zzbnnh9Tm/vWKv/C43i0Djm8BNNzhTM0nCm/Sjcu+QEfLmt",
+	"4qRYOHLNFnaJ3NLi7JCpPe7PZNeZfO3gZ1L5MxnuWJQjKhdo71c2ZS8tPiyyw6JccbPAU4qEvgQ6D3Z+",
+	"/uX9LyErhDxtjITQW8+cCnYjbytMMfQ9rRl5pkIKwEy6TtuOCVYeFMSaLYgykWJGjAT+SCIppnyW2/IU",
+	"4L0hl3Pg6rqaXI1/uQRsqUiumbYoyoQDTWEcZJLzJK68mmQ8umbK9u2QuSFzquJRJJEl2zaBbSwRQmXf",
+	"VUjxkXyp6AL+8+DN94Ph4IKLGc2kYoPhYJ8uUirIkU6oiPXgl2EZi66xx9URZ9cXvUr+1jhg54WtW+GR",
+	"BZOu3BEIVq0sACa4g2uz4q2rFO+HtUHPZ+kFDH7nsNAYFCv+tngGV0Idy2WTKY0Q1aAZVdGcaB6zCVXQ",
+	"Bj7h14xUZ0PMHJu8ThOM6gVCO6aG7rQTp9hC5TCwHrdtpdm2ixCvuO+vfrGoZed/erhC+MJ9izlZvYU8",
+	"s7Erjo1Uq5l/PT7oo/FB+w6g7TdStiy9snoQFdMyuWGdJ/FEqpQm/HdGqCBUTbhRVC1qSbYiyw15UCqn",
+	"10LeCl+94iExEo9jydqRU7b41HEodyk6EV5rn4oZvvBieKufXHvvJD+KbvWyYLODXHBgB9dczGKZfgJN",
+	"c91zh0TK3aI2Nkv5K8w9T0zvv1hTybSbp9hsDX8mbqRasRd7U+VwKZZJZfQjQU2u2EhOR5Ncc8H0mrLO",
+	"3gciy99H5mCXThafQuBN0TcDI+ZitkF+sH2OQQzNlMwzBk4YChAZMA+tSNsNnuIlGG0ZNqrnODAqXCdV",
+	"KdgQH46igMVls+dcoLciT5J15N4Jvux0+sJTdgWTwNbTUY1FYkkGXo2O7hYFd2CUNIpY5lNKcmF5mmsF",
+	"bQE8LO7gLL9WWdid27Dfr9iu0+tjRHdzoXsOs4LDvHFbp9d6PoPW07Y/W9gzOvHikesFz7qZ87fAB3WT",
+	"o7p+8Qw7ypugZItXl2LyIKLaBq9u59wwndGIES40E5pDwt/DshU9GqrWQeS+8yBCAd/5/pg24GXn4Cub",
+	"ZHTGBTUs3nHOQ8Aq8ukCZBoycYKmCYgYFjuGnCS+1kkgIbQkinLNiPWwEEEVeK44yBubCIDWjGZGd3j9",
+	"MHwS79kJnOMoV7Hm140mVSWFYY0saLMgytSFDuzEjCQTT45OVpzyKgeO2ZSCXrSzNRy46hf42fFCLgyb",
+	"MXUPrHllHKpGqa54VFzMHmfd89t1I1KwjwN9rme4H81wa/tRBRvX3tV2xA/YDUtkBu91zx4MB7lKBjuD",
+	"uTHZzqNHiYxoMpfa7Hw9/no8eP/L+/8/AAoJI52/bQEA",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
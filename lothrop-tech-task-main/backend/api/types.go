@@ -9,25 +9,55 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
-// Defines values for CompanyJurisdiction.
 const (
-	CompanyJurisdictionCaymens   CompanyJurisdiction = "Caymens"
-	CompanyJurisdictionSingapore CompanyJurisdiction = "Singapore"
-	CompanyJurisdictionUK        CompanyJurisdiction = "UK"
+	AdminTokenScopes = "adminToken.Scopes"
 )
 
-// Defines values for CreateCompanyRequestJurisdiction.
+// Defines values for AuditEntryAction.
 const (
-	CreateCompanyRequestJurisdictionCaymens   CreateCompanyRequestJurisdiction = "Caymens"
-	CreateCompanyRequestJurisdictionSingapore CreateCompanyRequestJurisdiction = "Singapore"
-	CreateCompanyRequestJurisdictionUK        CreateCompanyRequestJurisdiction = "UK"
+	AddDirector        AuditEntryAction = "add_director"
+	AddShareholder     AuditEntryAction = "add_shareholder"
+	Create             AuditEntryAction = "create"
+	Delete             AuditEntryAction = "delete"
+	JurisdictionChange AuditEntryAction = "jurisdiction_change"
+	RemoveDirector     AuditEntryAction = "remove_director"
+	RemoveShareholder  AuditEntryAction = "remove_shareholder"
+	Restore            AuditEntryAction = "restore"
+	Update             AuditEntryAction = "update"
+	UpdateShareholder  AuditEntryAction = "update_shareholder"
 )
 
-// Defines values for GetCompaniesParamsJurisdiction.
+// Defines values for ImportRowReportStatus.
 const (
-	Caymens   GetCompaniesParamsJurisdiction = "Caymens"
-	Singapore GetCompaniesParamsJurisdiction = "Singapore"
-	UK        GetCompaniesParamsJurisdiction = "UK"
+	Created ImportRowReportStatus = "created"
+	Invalid ImportRowReportStatus = "invalid"
+	Skipped ImportRowReportStatus = "skipped"
+)
+
+// Defines values for GetCompaniesParamsPagination.
+const (
+	Envelope GetCompaniesParamsPagination = "envelope"
+	Header   GetCompaniesParamsPagination = "header"
+)
+
+// Defines values for GetCompaniesParamsSort.
+const (
+	CompanyName  GetCompaniesParamsSort = "company_name"
+	DateCreated  GetCompaniesParamsSort = "date_created"
+	DateUpdated  GetCompaniesParamsSort = "date_updated"
+	Jurisdiction GetCompaniesParamsSort = "jurisdiction"
+)
+
+// Defines values for GetCompaniesParamsOrder.
+const (
+	Asc  GetCompaniesParamsOrder = "asc"
+	Desc GetCompaniesParamsOrder = "desc"
+)
+
+// Defines values for ImportCompaniesParamsMode.
+const (
+	Lenient ImportCompaniesParamsMode = "lenient"
+	Strict  ImportCompaniesParamsMode = "strict"
 )
 
 // ApiResponse defines model for ApiResponse.
@@ -36,65 +66,683 @@ type ApiResponse struct {
 	Msg   string `json:"msg"`
 }
 
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	Action AuditEntryAction `json:"action"`
+
+	// Actor JWT subject that made the change; null when write routes are unauthenticated, "admin" for admin routes
+	Actor       *string            `json:"actor"`
+	CompanyId   openapi_types.UUID `json:"company_id"`
+	DateCreated time.Time          `json:"date_created"`
+	Id          int64              `json:"id"`
+}
+
+// AuditEntryAction defines model for AuditEntry.Action.
+type AuditEntryAction string
+
+// BatchCreateResponse defines model for BatchCreateResponse.
+type BatchCreateResponse struct {
+	Companies []Company `json:"companies"`
+
+	// DryRun Set on dry runs, whose companies were rolled back rather than saved
+	DryRun *bool            `json:"dry_run,omitempty"`
+	Errors []BatchItemError `json:"errors"`
+}
+
+// BatchDeleteResponse defines model for BatchDeleteResponse.
+type BatchDeleteResponse struct {
+	// Deleted Number of companies deleted
+	Deleted int `json:"deleted"`
+
+	// NotFound Requested IDs that matched no live company, in request order
+	NotFound []openapi_types.UUID `json:"not_found"`
+}
+
+// BatchItemError defines model for BatchItemError.
+type BatchItemError struct {
+	// Field Offending field, when the error is specific to one
+	Field *string `json:"field,omitempty"`
+
+	// Index Position of the invalid company in the request array
+	Index int    `json:"index"`
+	Msg   string `json:"msg"`
+}
+
 // CompaniesResponse defines model for CompaniesResponse.
 type CompaniesResponse struct {
 	Companies []Company `json:"companies"`
-	Limit     int       `json:"limit"`
-	Offset    int       `json:"offset"`
-	Total     int       `json:"total"`
+
+	// CurrentPage 1-based page containing offset; 0 when limit is 0 or a cursor is given
+	CurrentPage int `json:"current_page"`
+
+	// Debug The list query that was executed, returned when debug_query=true
+	Debug *QueryDebug `json:"debug,omitempty"`
+
+	// HasNext Whether companies remain after this page, in offset or cursor pagination
+	HasNext bool `json:"has_next"`
+
+	// HasPrev Whether this page starts after the first company, i.e. offset is greater than 0
+	HasPrev bool `json:"has_prev"`
+	Limit   int  `json:"limit"`
+
+	// NextCursor Opaque cursor for the next page, present when companies remain after this page and the default sort is used. Pass it back as the cursor parameter.
+	NextCursor *string `json:"next_cursor"`
+	Offset     int     `json:"offset"`
+
+	// Total Number of companies matching the filters; -1 when include_total is false
+	Total int `json:"total"`
+
+	// TotalPages Number of pages of limit companies matching the filters; 0 when limit is 0 or a cursor is given and -1 when include_total is false
+	TotalPages int `json:"total_pages"`
 }
 
 // Company defines model for Company.
 type Company struct {
-	CompanyAddress       string              `json:"company_address"`
-	CompanyName          string              `json:"company_name"`
-	DateCreated          time.Time           `json:"date_created"`
-	DateUpdated          time.Time           `json:"date_updated"`
-	Id                   openapi_types.UUID  `json:"id"`
-	Jurisdiction         CompanyJurisdiction `json:"jurisdiction"`
-	NatureOfBusiness     *string             `json:"nature_of_business"`
-	NumberOfDirectors    *int                `json:"number_of_directors"`
-	NumberOfShareholders *int                `json:"number_of_shareholders"`
-	SecCode              *string             `json:"sec_code"`
+	CompanyAddress string    `json:"company_address"`
+	CompanyName    string    `json:"company_name"`
+	DateCreated    time.Time `json:"date_created"`
+	DateUpdated    time.Time `json:"date_updated"`
+
+	// DeletedAt When the company was soft-deleted; only deleted companies listed with include_deleted have it set
+	DeletedAt *time.Time `json:"deleted_at"`
+
+	// Directors The company's directors, oldest first; only present when requested with ?embed=directors
+	Directors *[]Director        `json:"directors,omitempty"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Jurisdiction Canonical name of one of the deployment's allowed jurisdictions, configured with VALID_JURISDICTIONS (UK, Singapore and Cayman Islands by default) and listed by GET /api/v1/jurisdictions
+	Jurisdiction         string  `json:"jurisdiction"`
+	NatureOfBusiness     *string `json:"nature_of_business"`
+	NumberOfDirectors    *int    `json:"number_of_directors"`
+	NumberOfShareholders *int    `json:"number_of_shareholders"`
+
+	// RegistryNumber Company number of record in the external registry
+	RegistryNumber *string `json:"registry_number"`
+
+	// RegistrySource External registry the registry number belongs to
+	RegistrySource *string `json:"registry_source"`
+	SecCode        *string `json:"sec_code"`
+
+	// Shareholders The company's shareholders, oldest first; only present when requested with ?embed=shareholders
+	Shareholders *[]Shareholder `json:"shareholders,omitempty"`
+
+	// Warnings Non-fatal adjustments made while processing a write, e.g. truncated fields
+	Warnings *[]string `json:"warnings,omitempty"`
 }
 
-// CompanyJurisdiction defines model for Company.Jurisdiction.
-type CompanyJurisdiction string
+// CompanyChecksumResponse defines model for CompanyChecksumResponse.
+type CompanyChecksumResponse struct {
+	// Algorithm Identifies the checksum computation, changed if it ever is
+	Algorithm string `json:"algorithm"`
+
+	// Checksum Sum of the row hashes modulo 2^64 as 16 lowercase hex digits
+	Checksum string `json:"checksum"`
+	Total    int    `json:"total"`
+}
+
+// CompanyCountResponse defines model for CompanyCountResponse.
+type CompanyCountResponse struct {
+	Total int `json:"total"`
+}
+
+// CompanyIdsResponse defines model for CompanyIdsResponse.
+type CompanyIdsResponse struct {
+	// HasNext Whether companies remain after this page, in offset or cursor pagination
+	HasNext bool                 `json:"has_next"`
+	Ids     []openapi_types.UUID `json:"ids"`
+	Limit   int                  `json:"limit"`
+	Offset  int                  `json:"offset"`
+
+	// Total Number of companies matching the filters; -1 when include_total is false
+	Total int `json:"total"`
+}
+
+// CountAdjustmentRequest defines model for CountAdjustmentRequest.
+type CountAdjustmentRequest struct {
+	// Delta Amount to add to the count, non-zero and between -1000 and 1000; negative to subtract
+	Delta int `json:"delta"`
+}
 
 // CreateCompanyRequest defines model for CreateCompanyRequest.
 type CreateCompanyRequest struct {
-	CompanyAddress       string                           `json:"company_address"`
-	CompanyName          string                           `json:"company_name"`
-	Jurisdiction         CreateCompanyRequestJurisdiction `json:"jurisdiction"`
-	NatureOfBusiness     *string                          `json:"nature_of_business"`
-	NumberOfDirectors    *int                             `json:"number_of_directors"`
-	NumberOfShareholders *int                             `json:"number_of_shareholders"`
-	SecCode              *string                          `json:"sec_code"`
+	// CompanyAddress Required, at most COMPANY_ADDRESS_MAX_LENGTH characters once whitespace is collapsed
+	CompanyAddress string `json:"company_address"`
+
+	// CompanyName 1 to 255 characters once surrounding whitespace is trimmed
+	CompanyName string `json:"company_name"`
+
+	// Jurisdiction One of the deployment's allowed jurisdictions, configured with VALID_JURISDICTIONS (UK, Singapore and Cayman Islands by default) and listed by GET /api/v1/jurisdictions. Case-insensitive; known aliases (e.g. "gb", "sg", and the legacy "Caymens" spelling) are normalized to the canonical value.
+	Jurisdiction     string  `json:"jurisdiction"`
+	NatureOfBusiness *string `json:"nature_of_business"`
+
+	// NumberOfDirectors Between 1 and 100. Once the company has director records an update must repeat their number, which is then not held to the range or the jurisdiction's minimum; anything else is rejected with a 422.
+	NumberOfDirectors *int `json:"number_of_directors"`
+
+	// NumberOfShareholders Between 1 and 1000, or the jurisdiction's MAX_SHAREHOLDERS_BY_JURISDICTION limit. Once the company has shareholder records an update must repeat their number; anything else is rejected with a 422.
+	NumberOfShareholders *int `json:"number_of_shareholders"`
+
+	// RegistryNumber Company number of record in the external registry
+	RegistryNumber *string `json:"registry_number"`
+
+	// RegistrySource External registry the number belongs to (companies_house for UK, acra for Singapore, cayman_registry for Cayman Islands); must be set together with registry_number
+	RegistrySource *string `json:"registry_source"`
+
+	// SecCode Non-empty values must match the jurisdiction's SEC code format, by default 2 to 4 uppercase letters followed by digits; a mismatch is rejected with a 422 on sec_code
+	SecCode *string `json:"sec_code"`
+}
+
+// CreateDirectorRequest defines model for CreateDirectorRequest.
+type CreateDirectorRequest struct {
+	// Name 1 to 255 characters
+	Name string `json:"name"`
+
+	// Role 1 to 100 characters
+	Role string `json:"role"`
+}
+
+// CreateShareholderRequest defines model for CreateShareholderRequest.
+type CreateShareholderRequest struct {
+	// Name 1 to 255 characters
+	Name string `json:"name"`
+
+	// OwnershipPercentage Greater than 0 and at most 100, with up to two decimal places
+	OwnershipPercentage float64 `json:"ownership_percentage"`
 }
 
-// CreateCompanyRequestJurisdiction defines model for CreateCompanyRequest.Jurisdiction.
-type CreateCompanyRequestJurisdiction string
+// Director defines model for Director.
+type Director struct {
+	CompanyId   openapi_types.UUID `json:"company_id"`
+	DateCreated time.Time          `json:"date_created"`
+	Id          openapi_types.UUID `json:"id"`
+	Name        string             `json:"name"`
+	Role        string             `json:"role"`
+}
 
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
-	Error bool   `json:"error"`
-	Msg   string `json:"msg"`
+	// ConflictingId ID of the existing company a rejected create duplicates, when DUPLICATE_POLICY is reject
+	ConflictingId *openapi_types.UUID `json:"conflicting_id,omitempty"`
+	Error         bool                `json:"error"`
+
+	// Fields Localized message for each invalid request field, when validation found field-level violations
+	Fields *map[string]string `json:"fields,omitempty"`
+	Msg    string             `json:"msg"`
+
+	// RequestId ID of the request, also logged with every server log line for it and returned in X-Request-Id
+	RequestId *string `json:"request_id,omitempty"`
+}
+
+// ExtractResponse defines model for ExtractResponse.
+type ExtractResponse struct {
+	Companies []Company `json:"companies"`
+
+	// Watermark Largest date_updated returned, or the since value when nothing changed; pass back as since
+	Watermark *time.Time `json:"watermark"`
+
+	// WatermarkId ID of the last company returned, or the since_id value when nothing changed; pass back as since_id
+	WatermarkId *openapi_types.UUID `json:"watermark_id"`
+}
+
+// ImportReport defines model for ImportReport.
+type ImportReport struct {
+	Created int `json:"created"`
+
+	// DryRun Set on dry runs, whose created rows were rolled back rather than saved
+	DryRun *bool             `json:"dry_run,omitempty"`
+	Failed int               `json:"failed"`
+	Rows   []ImportRowReport `json:"rows"`
+}
+
+// ImportRowReport defines model for ImportRowReport.
+type ImportRowReport struct {
+	// Field Offending field, when the error is specific to one
+	Field *string `json:"field,omitempty"`
+
+	// Id ID of the created company
+	Id *openapi_types.UUID `json:"id,omitempty"`
+
+	// Line Line number in the file, counting the header as line 1
+	Line int `json:"line"`
+
+	// Msg Validation error for an invalid row
+	Msg *string `json:"msg,omitempty"`
+
+	// Status skipped marks a valid row not created because a strict import had invalid rows
+	Status ImportRowReportStatus `json:"status"`
+}
+
+// ImportRowReportStatus skipped marks a valid row not created because a strict import had invalid rows
+type ImportRowReportStatus string
+
+// JsonApiError defines model for JsonApiError.
+type JsonApiError struct {
+	Detail *string `json:"detail,omitempty"`
+
+	// Id ID of the request, as in X-Request-Id
+	Id   *string           `json:"id,omitempty"`
+	Meta *JsonApiErrorMeta `json:"meta,omitempty"`
+
+	// Status HTTP status code, as a string per JSON:API
+	Status string `json:"status"`
+	Title  string `json:"title"`
+}
+
+// JsonApiErrorDocument JSON:API error document, returned instead of ErrorResponse when the client sends Accept application/vnd.api+json. The first error describes the failure; field-level violations follow, one error each.
+type JsonApiErrorDocument struct {
+	Errors []JsonApiError `json:"errors"`
+}
+
+// JsonApiErrorMeta defines model for JsonApiErrorMeta.
+type JsonApiErrorMeta struct {
+	// ConflictingId ID of the existing company a rejected create duplicates, when DUPLICATE_POLICY is reject
+	ConflictingId *openapi_types.UUID `json:"conflicting_id,omitempty"`
+
+	// Field The invalid request field of a field-level violation, named as in ErrorResponse fields: a body field, possibly dotted for nested values, or a query parameter
+	Field *string `json:"field,omitempty"`
+}
+
+// JurisdictionCount defines model for JurisdictionCount.
+type JurisdictionCount struct {
+	// Count Number of live companies in the jurisdiction
+	Count        int    `json:"count"`
+	Jurisdiction string `json:"jurisdiction"`
+}
+
+// JurisdictionResolution defines model for JurisdictionResolution.
+type JurisdictionResolution struct {
+	// Canonical Canonical jurisdiction value, present only when matched
+	Canonical *string `json:"canonical,omitempty"`
+	Matched   bool    `json:"matched"`
+}
+
+// NatureOfBusinessCount defines model for NatureOfBusinessCount.
+type NatureOfBusinessCount struct {
+	// Count Number of live companies with this nature of business
+	Count int `json:"count"`
+
+	// NatureOfBusiness The stored nature of business, null for companies without one
+	NatureOfBusiness *string `json:"nature_of_business"`
+}
+
+// PatchCompanyRequest Partial company update; at least one field must be present
+type PatchCompanyRequest struct {
+	// CompanyAddress Non-empty, at most COMPANY_ADDRESS_MAX_LENGTH characters once whitespace is collapsed
+	CompanyAddress *string `json:"company_address,omitempty"`
+
+	// CompanyName 1 to 255 characters once surrounding whitespace is trimmed
+	CompanyName *string `json:"company_name,omitempty"`
+
+	// Jurisdiction One of the deployment's allowed jurisdictions, configured with VALID_JURISDICTIONS (UK, Singapore and Cayman Islands by default) and listed by GET /api/v1/jurisdictions. Case-insensitive; known aliases (e.g. "gb", "sg", and the legacy "Caymens" spelling) are normalized to the canonical value.
+	Jurisdiction     *string `json:"jurisdiction,omitempty"`
+	NatureOfBusiness *string `json:"nature_of_business,omitempty"`
+
+	// NumberOfDirectors Between 1 and 100. Once the company has director records it can only be set to their number; anything else is rejected with a 422.
+	NumberOfDirectors *int `json:"number_of_directors,omitempty"`
+
+	// NumberOfShareholders Between 1 and 1000, or the jurisdiction's MAX_SHAREHOLDERS_BY_JURISDICTION limit. Once the company has shareholder records it can only be set to their number; anything else is rejected with a 422.
+	NumberOfShareholders *int    `json:"number_of_shareholders,omitempty"`
+	RegistryNumber       *string `json:"registry_number,omitempty"`
+
+	// RegistrySource Must be set together with registry_number unless the company already has both
+	RegistrySource *string `json:"registry_source,omitempty"`
+
+	// SecCode Non-empty values must match the jurisdiction's SEC code format, by default 2 to 4 uppercase letters followed by digits; a mismatch is rejected with a 422 on sec_code
+	SecCode *string `json:"sec_code,omitempty"`
+}
+
+// PoolResetResponse defines model for PoolResetResponse.
+type PoolResetResponse struct {
+	// ClosedIdleConnections Number of idle connections that were closed
+	ClosedIdleConnections int `json:"closed_idle_connections"`
+}
+
+// ProblemDetails RFC 7807 error body, returned instead of ErrorResponse when the client sends Accept application/problem+json
+type ProblemDetails struct {
+	// ConflictingId ID of the existing company a rejected create duplicates, when DUPLICATE_POLICY is reject
+	ConflictingId *openapi_types.UUID `json:"conflicting_id,omitempty"`
+	Detail        *string             `json:"detail,omitempty"`
+
+	// Fields Localized message for each invalid request field, when validation found field-level violations
+	Fields   *map[string]string `json:"fields,omitempty"`
+	Instance *string            `json:"instance,omitempty"`
+
+	// RequestId ID of the request, also logged with every server log line for it and returned in X-Request-Id
+	RequestId *string `json:"request_id,omitempty"`
+	Status    int     `json:"status"`
+	Title     string  `json:"title"`
+	Type      string  `json:"type"`
+}
+
+// PurgeResponse defines model for PurgeResponse.
+type PurgeResponse struct {
+	// Cutoff Companies soft-deleted before this time were purged
+	Cutoff time.Time `json:"cutoff"`
+
+	// Purged Number of companies permanently deleted
+	Purged int64 `json:"purged"`
+}
+
+// QueryDebug The list query that was executed, returned when debug_query=true
+type QueryDebug struct {
+	ArgCount int    `json:"arg_count"`
+	Sql      string `json:"sql"`
+}
+
+// SharedAddressGroup defines model for SharedAddressGroup.
+type SharedAddressGroup struct {
+	// Address Normalized (lowercased, whitespace-collapsed) address
+	Address   string    `json:"address"`
+	Companies []Company `json:"companies"`
+	Count     int       `json:"count"`
+}
+
+// SharedAddressReport defines model for SharedAddressReport.
+type SharedAddressReport struct {
+	Groups []SharedAddressGroup `json:"groups"`
+}
+
+// Shareholder defines model for Shareholder.
+type Shareholder struct {
+	CompanyId   openapi_types.UUID `json:"company_id"`
+	DateCreated time.Time          `json:"date_created"`
+	Id          openapi_types.UUID `json:"id"`
+	Name        string             `json:"name"`
+
+	// OwnershipPercentage Share of the company owned, in percent with up to two decimal places
+	OwnershipPercentage float64 `json:"ownership_percentage"`
+}
+
+// SnapshotPage defines model for SnapshotPage.
+type SnapshotPage struct {
+	Companies []Company `json:"companies"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// HasMore False on the last page, after which the snapshot is closed
+	HasMore bool `json:"has_more"`
+}
+
+// SnapshotResponse defines model for SnapshotResponse.
+type SnapshotResponse struct {
+	// ExpiresAt When the snapshot is closed regardless of activity; it is also closed after an idle timeout
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// SnapshotId Handle passed to the next and close snapshot endpoints
+	SnapshotId string `json:"snapshot_id"`
+}
+
+// TransferJurisdictionRequest defines model for TransferJurisdictionRequest.
+type TransferJurisdictionRequest struct {
+	// Jurisdiction The jurisdiction to transfer the company to
+	Jurisdiction string `json:"jurisdiction"`
 }
 
 // GetCompaniesParams defines parameters for GetCompanies.
 type GetCompaniesParams struct {
-	// Limit Maximum number of companies to return
+	// Limit Maximum number of companies to return, at most the server's LIST_MAX_LIMIT (100 by default); the
+	// default page size is LIST_DEFAULT_LIMIT (20 by default). When the server enables ALLOW_LIMIT_ZERO,
+	// limit=0 returns no companies but an accurate total (count-only).
 	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 
-	// Offset Number of companies to skip for pagination
+	// Offset Number of companies to skip for pagination. Offsets above the server's LIST_MAX_OFFSET (10000 by default) are rejected with 400; page further with cursor instead.
 	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
 
-	// Jurisdiction Filter companies by jurisdiction
-	Jurisdiction *GetCompaniesParamsJurisdiction `form:"jurisdiction,omitempty" json:"jurisdiction,omitempty"`
+	// Jurisdiction Filter companies by jurisdiction. Repeat the parameter to match any of several jurisdictions (e.g. ?jurisdiction=UK&jurisdiction=Singapore). Matching is case-insensitive and accepts the same aliases as /jurisdictions/resolve, including the legacy "Caymens" spelling; unknown values are rejected.
+	Jurisdiction *[]string `form:"jurisdiction,omitempty" json:"jurisdiction,omitempty"`
+
+	// Cursor Opaque keyset cursor from a previous response's next_cursor. Returns the companies after that position in the default (newest first) order; cannot be combined with offset or sort.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Search Return only companies whose name contains this text (case-insensitive). Blank values are ignored.
+	Search *string `form:"search,omitempty" json:"search,omitempty"`
+
+	// Q Full-text search over company name and nature of business in web search syntax (quoted phrases, "or", and -word to exclude). Matches are ordered by relevance, name matches ranking above nature of business matches, unless sort is given. Cannot be combined with cursor. Blank values are ignored.
+	Q *string `form:"q,omitempty" json:"q,omitempty"`
+
+	// CreatedAfter Return only companies created at or after this RFC3339 timestamp
+	CreatedAfter *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+
+	// CreatedBefore Return only companies created at or before this RFC3339 timestamp
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+
+	// RecentMinutes Return only companies created within the last N minutes by the database clock, a shortcut for created_after. Must be between 1 and 43200 (30 days).
+	RecentMinutes *int `form:"recent_minutes,omitempty" json:"recent_minutes,omitempty"`
+
+	// Fields Comma-separated company fields to return, e.g. "id,company_name"; other fields are omitted from each company. Allowed: id, company_name, company_address, jurisdiction, nature_of_business, number_of_directors, number_of_shareholders, sec_code, registry_source, registry_number, date_created, date_updated, deleted_at. Unknown names are rejected with 400. Cannot be combined with id_only.
+	Fields *string `form:"fields,omitempty" json:"fields,omitempty"`
+
+	// IdOnly Return only company IDs (CompanyIdsResponse) instead of full company objects
+	IdOnly *bool `form:"id_only,omitempty" json:"id_only,omitempty"`
+
+	// Pagination Pagination style. "envelope" wraps results with total/limit/offset; "header" returns a bare array and reports the total through X-Total-Count. Both styles set RFC 5988 Link headers.
+	Pagination *GetCompaniesParamsPagination `form:"pagination,omitempty" json:"pagination,omitempty"`
+
+	// Sort Column to sort by. Without sort, companies are returned by relevance when q is given and newest first (date_created desc) otherwise.
+	Sort *GetCompaniesParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order Sort direction; defaults to desc for date columns and asc otherwise
+	Order *GetCompaniesParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// IncludeDeleted Admin only (requires the admin bearer token) - also list soft-deleted companies, which have deleted_at set
+	IncludeDeleted *bool `form:"include_deleted,omitempty" json:"include_deleted,omitempty"`
+
+	// IncludeTotal Whether to count every company matching the filters. Set to false to skip the count query, e.g. for infinite scrolling; total and total_pages are then -1, X-Total-Count and the last page link are omitted, and has_next is still accurate. Cannot be combined with limit=0.
+	IncludeTotal *bool `form:"include_total,omitempty" json:"include_total,omitempty"`
+
+	// DebugQuery Debug only: include the composed list SQL (with placeholders) and its argument count as `debug` in the envelope response. Nothing extra is executed. Requires the admin bearer token, is never available when APP_ENV=production, and cannot be combined with id_only or pagination=header.
+	DebugQuery *bool `form:"debug_query,omitempty" json:"debug_query,omitempty"`
+
+	// Explain Debug only: return the EXPLAIN (ANALYZE, BUFFERS) plan for the list query as text/plain instead of results. Requires the admin bearer token and is never available when APP_ENV=production.
+	Explain *bool `form:"explain,omitempty" json:"explain,omitempty"`
+
+	// IfNoneMatch ETag from a previous list response; a 304 is returned while the same request still yields it
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
 }
 
-// GetCompaniesParamsJurisdiction defines parameters for GetCompanies.
-type GetCompaniesParamsJurisdiction string
+// GetCompaniesParamsPagination defines parameters for GetCompanies.
+type GetCompaniesParamsPagination string
+
+// GetCompaniesParamsSort defines parameters for GetCompanies.
+type GetCompaniesParamsSort string
+
+// GetCompaniesParamsOrder defines parameters for GetCompanies.
+type GetCompaniesParamsOrder string
+
+// CreateCompanyParams defines parameters for CreateCompany.
+type CreateCompanyParams struct {
+	// IdempotencyKey Client-chosen key (1-255 printable ASCII characters) making retries safe. Keys expire after IDEMPOTENCY_KEY_TTL; reusing an unexpired key with a different request body returns 422.
+	IdempotencyKey *string `json:"Idempotency-Key,omitempty"`
+}
+
+// ExportCompaniesCsvParams defines parameters for ExportCompaniesCsv.
+type ExportCompaniesCsvParams struct {
+	Jurisdiction  *[]string  `form:"jurisdiction,omitempty" json:"jurisdiction,omitempty"`
+	Search        *string    `form:"search,omitempty" json:"search,omitempty"`
+	CreatedAfter  *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+	RecentMinutes *int       `form:"recent_minutes,omitempty" json:"recent_minutes,omitempty"`
+}
+
+// CreateCompaniesJSONBody defines parameters for CreateCompanies.
+type CreateCompaniesJSONBody = []CreateCompanyRequest
+
+// CreateCompaniesParams defines parameters for CreateCompanies.
+type CreateCompaniesParams struct {
+	// DryRun Run the operation in a transaction that is rolled back, reporting what would be created without creating it
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
+// DeleteCompaniesJSONBody defines parameters for DeleteCompanies.
+type DeleteCompaniesJSONBody = []string
+
+// GetCompanyByRegistryParams defines parameters for GetCompanyByRegistry.
+type GetCompanyByRegistryParams struct {
+	// Source External registry (companies_house, acra or cayman_registry)
+	Source string `form:"source" json:"source"`
+
+	// Number Company number in the external registry
+	Number string `form:"number" json:"number"`
+}
+
+// ChecksumCompaniesParams defines parameters for ChecksumCompanies.
+type ChecksumCompaniesParams struct {
+	Jurisdiction  *[]string  `form:"jurisdiction,omitempty" json:"jurisdiction,omitempty"`
+	Search        *string    `form:"search,omitempty" json:"search,omitempty"`
+	CreatedAfter  *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+	RecentMinutes *int       `form:"recent_minutes,omitempty" json:"recent_minutes,omitempty"`
+}
+
+// CountCompaniesParams defines parameters for CountCompanies.
+type CountCompaniesParams struct {
+	Jurisdiction  *[]string  `form:"jurisdiction,omitempty" json:"jurisdiction,omitempty"`
+	Search        *string    `form:"search,omitempty" json:"search,omitempty"`
+	CreatedAfter  *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+	RecentMinutes *int       `form:"recent_minutes,omitempty" json:"recent_minutes,omitempty"`
+}
+
+// ExportCompaniesNdjsonParams defines parameters for ExportCompaniesNdjson.
+type ExportCompaniesNdjsonParams struct {
+	Jurisdiction  *[]string  `form:"jurisdiction,omitempty" json:"jurisdiction,omitempty"`
+	Search        *string    `form:"search,omitempty" json:"search,omitempty"`
+	CreatedAfter  *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+	RecentMinutes *int       `form:"recent_minutes,omitempty" json:"recent_minutes,omitempty"`
+}
+
+// ExtractCompaniesParams defines parameters for ExtractCompanies.
+type ExtractCompaniesParams struct {
+	// Since RFC3339 watermark from the previous extract; omit to start from the beginning
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// SinceId watermark_id from the previous extract; requires since
+	SinceId *openapi_types.UUID `form:"since_id,omitempty" json:"since_id,omitempty"`
+
+	// Limit Maximum number of companies to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ImportCompaniesMultipartBody defines parameters for ImportCompanies.
+type ImportCompaniesMultipartBody struct {
+	File openapi_types.File `json:"file"`
+}
+
+// ImportCompaniesParams defines parameters for ImportCompanies.
+type ImportCompaniesParams struct {
+	// DryRun Run the operation in a transaction that is rolled back, reporting what would be created without creating it
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// Mode strict or lenient; defaults to the server's IMPORT_MODE
+	Mode *ImportCompaniesParamsMode `form:"mode,omitempty" json:"mode,omitempty"`
+}
+
+// ImportCompaniesParamsMode defines parameters for ImportCompanies.
+type ImportCompaniesParamsMode string
+
+// OpenSnapshotParams defines parameters for OpenSnapshot.
+type OpenSnapshotParams struct {
+	Jurisdiction  *[]string  `form:"jurisdiction,omitempty" json:"jurisdiction,omitempty"`
+	Search        *string    `form:"search,omitempty" json:"search,omitempty"`
+	CreatedAfter  *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+	RecentMinutes *int       `form:"recent_minutes,omitempty" json:"recent_minutes,omitempty"`
+}
+
+// NextSnapshotPageParams defines parameters for NextSnapshotPage.
+type NextSnapshotPageParams struct {
+	// Limit Maximum number of companies to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetCompanyByIdParams defines parameters for GetCompanyById.
+type GetCompanyByIdParams struct {
+	// Fields Comma-separated company fields to return, e.g. "id,company_name"; other fields are omitted from each company. Allowed: id, company_name, company_address, jurisdiction, nature_of_business, number_of_directors, number_of_shareholders, sec_code, registry_source, registry_number, date_created, date_updated, deleted_at. Unknown names are rejected with 400.
+	Fields *string `form:"fields,omitempty" json:"fields,omitempty"`
+
+	// Embed Comma-separated related collections to include inline, saving a request per collection. Allowed: directors, shareholders. Unknown names are rejected with 400. Embedded collections are kept when combined with fields.
+	Embed *string `form:"embed,omitempty" json:"embed,omitempty"`
+
+	// IfNoneMatch ETag from a previous response; a 304 is returned while it still matches
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+
+	// IfModifiedSince HTTP date, e.g. a previous Last-Modified; a 304 is returned unless date_updated is newer. Ignored when If-None-Match is sent.
+	IfModifiedSince *string `json:"If-Modified-Since,omitempty"`
+}
+
+// HeadCompanyByIdParams defines parameters for HeadCompanyById.
+type HeadCompanyByIdParams struct {
+	// IfNoneMatch ETag from a previous response; a 304 is returned while it still matches
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+
+	// IfModifiedSince HTTP date, e.g. a previous Last-Modified; a 304 is returned unless date_updated is newer. Ignored when If-None-Match is sent.
+	IfModifiedSince *string `json:"If-Modified-Since,omitempty"`
+}
+
+// PatchCompanyParams defines parameters for PatchCompany.
+type PatchCompanyParams struct {
+	// ExpectedVersion The company's date_updated as last read; the update fails with 412 if the company has changed since
+	ExpectedVersion *time.Time `form:"expected_version,omitempty" json:"expected_version,omitempty"`
+}
+
+// UpdateCompanyParams defines parameters for UpdateCompany.
+type UpdateCompanyParams struct {
+	// ExpectedVersion The company's date_updated as last read; the update fails with 412 if the company has changed since
+	ExpectedVersion *time.Time `form:"expected_version,omitempty" json:"expected_version,omitempty"`
+}
+
+// ResolveJurisdictionParams defines parameters for ResolveJurisdiction.
+type ResolveJurisdictionParams struct {
+	// Value Jurisdiction value or alias to resolve
+	Value string `form:"value" json:"value"`
+}
+
+// CountCompaniesByNatureOfBusinessParams defines parameters for CountCompaniesByNatureOfBusiness.
+type CountCompaniesByNatureOfBusinessParams struct {
+	// Jurisdiction Only count companies in this jurisdiction; aliases are accepted and unknown values rejected
+	Jurisdiction *string `form:"jurisdiction,omitempty" json:"jurisdiction,omitempty"`
+}
+
+// GetSharedAddressReportParams defines parameters for GetSharedAddressReport.
+type GetSharedAddressReportParams struct {
+	// Min Minimum number of companies sharing an address for the group to be reported
+	Min *int `form:"min,omitempty" json:"min,omitempty"`
+}
 
 // CreateCompanyJSONRequestBody defines body for CreateCompany for application/json ContentType.
 type CreateCompanyJSONRequestBody = CreateCompanyRequest
+
+// CreateCompanyFormdataRequestBody defines body for CreateCompany for application/x-www-form-urlencoded ContentType.
+type CreateCompanyFormdataRequestBody = CreateCompanyRequest
+
+// CreateCompaniesJSONRequestBody defines body for CreateCompanies for application/json ContentType.
+type CreateCompaniesJSONRequestBody = CreateCompaniesJSONBody
+
+// DeleteCompaniesJSONRequestBody defines body for DeleteCompanies for application/json ContentType.
+type DeleteCompaniesJSONRequestBody = DeleteCompaniesJSONBody
+
+// ImportCompaniesMultipartRequestBody defines body for ImportCompanies for multipart/form-data ContentType.
+type ImportCompaniesMultipartRequestBody ImportCompaniesMultipartBody
+
+// PatchCompanyJSONRequestBody defines body for PatchCompany for application/json ContentType.
+type PatchCompanyJSONRequestBody = PatchCompanyRequest
+
+// UpdateCompanyJSONRequestBody defines body for UpdateCompany for application/json ContentType.
+type UpdateCompanyJSONRequestBody = CreateCompanyRequest
+
+// AddDirectorJSONRequestBody defines body for AddDirector for application/json ContentType.
+type AddDirectorJSONRequestBody = CreateDirectorRequest
+
+// TransferJurisdictionJSONRequestBody defines body for TransferJurisdiction for application/json ContentType.
+type TransferJurisdictionJSONRequestBody = TransferJurisdictionRequest
+
+// AdjustDirectorCountJSONRequestBody defines body for AdjustDirectorCount for application/json ContentType.
+type AdjustDirectorCountJSONRequestBody = CountAdjustmentRequest
+
+// AdjustShareholderCountJSONRequestBody defines body for AdjustShareholderCount for application/json ContentType.
+type AdjustShareholderCountJSONRequestBody = CountAdjustmentRequest
+
+// AddShareholderJSONRequestBody defines body for AddShareholder for application/json ContentType.
+type AddShareholderJSONRequestBody = CreateShareholderRequest
+
+// UpdateShareholderJSONRequestBody defines body for UpdateShareholder for application/json ContentType.
+type UpdateShareholderJSONRequestBody = CreateShareholderRequest
@@ -0,0 +1,62 @@
+// Command normalize-sec-codes rewrites stored sec_code values into their canonical
+// (trimmed, uppercase) form so legacy rows match what the API returns.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"backend/internal/config"
+	"backend/internal/database"
+
+	"go.uber.org/zap"
+)
+
+// nonCanonicalCondition matches rows whose sec_code differs from its trimmed, uppercase form
+const nonCanonicalCondition = `sec_code IS NOT NULL
+	AND sec_code <> UPPER(regexp_replace(sec_code, '^\s+|\s+$', '', 'g'))`
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Only report how many rows would be updated")
+	flag.Parse()
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	}
+	defer logger.Sync()
+
+	db, err := database.NewPostgresConnection(config.Load(), logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	if *dryRun {
+		var count int
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM companies WHERE "+nonCanonicalCondition).Scan(&count); err != nil {
+			logger.Fatal("Failed to count non-canonical SEC codes", zap.Error(err))
+		}
+		logger.Info("Dry run: SEC codes that would be normalized", zap.Int("rows", count))
+		return
+	}
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE companies
+		SET sec_code = UPPER(regexp_replace(sec_code, '^\s+|\s+$', '', 'g')),
+		    date_updated = CURRENT_TIMESTAMP
+		WHERE `+nonCanonicalCondition)
+	if err != nil {
+		logger.Fatal("Failed to normalize SEC codes", zap.Error(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.Fatal("Failed to read affected rows", zap.Error(err))
+	}
+
+	logger.Info("Normalized SEC codes", zap.Int64("rows", rowsAffected))
+}
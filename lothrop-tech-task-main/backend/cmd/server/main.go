@@ -1,86 +1,459 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"backend/api"
+	"backend/internal/buildinfo"
 	"backend/internal/config"
 	"backend/internal/database"
 	"backend/internal/handlers"
+	"backend/internal/metrics"
+	appmiddleware "backend/internal/middleware"
 	"backend/internal/repository"
+	"backend/internal/response"
 	"backend/internal/service"
+	"backend/migrations"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func main() {
+	// Load configuration
+	cfg := config.Load()
+
 	// Initialize logger
-	logger, err := zap.NewDevelopment()
+	logger, err := newLogger(cfg)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
 	}
 	defer logger.Sync()
 
-	// Load configuration
-	cfg := config.Load()
-	logger.Info("Starting server", zap.String("port", cfg.Port))
+	logger.Info("Starting server", zap.String("port", cfg.Port), zap.String("environment", cfg.Environment),
+		zap.String("version", buildinfo.Version), zap.String("commit", buildinfo.Commit))
 
 	// Initialize database connection
-	db, err := database.NewPostgresConnection(cfg)
+	db, err := database.NewPostgresConnection(cfg, logger)
 	if err != nil {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
-	defer db.Close()
+
+	if cfg.MigrateOnStartup {
+		runMigrations(db, logger)
+	} else {
+		checkMigrations(db, cfg.RequireMigrations, logger)
+	}
+
+	if cfg.IndexAdvisories {
+		logIndexAdvisories(db, logger)
+	}
 
 	// Initialize repository, service, and handlers
-	companyRepo := repository.NewPostgresCompanyRepository(db)
-	companyService := service.NewCompanyService(companyRepo)
-	companyHandlers := handlers.NewCompanyHandlers(companyService, logger)
+	var companyRepo repository.CompanyRepository = repository.NewPostgresCompanyRepository(db, cfg.DBRetryMaxAttempts, cfg.SlowQueryThreshold, logger)
+	if cfg.CompanyCacheSize > 0 {
+		if cfg.CompanyCacheTTL <= 0 {
+			logger.Fatal("COMPANY_CACHE_TTL must be positive when COMPANY_CACHE_SIZE is set")
+		}
+		companyRepo = repository.NewCachingCompanyRepository(companyRepo, cfg.CompanyCacheSize, cfg.CompanyCacheTTL)
+	}
+	createDefaults, err := service.ParseFieldDefaults(cfg.CreateDefaults)
+	if err != nil {
+		logger.Fatal("Invalid CREATE_DEFAULTS_BY_JURISDICTION", zap.Error(err))
+	}
+	addressRules, err := service.ParseAddressRules(cfg.AddressRules)
+	if err != nil {
+		logger.Fatal("Invalid ADDRESS_RULES_BY_JURISDICTION", zap.Error(err))
+	}
+	secCodeRules, err := service.ParseSecCodeRules(cfg.SecCodePattern, cfg.SecCodePatterns)
+	if err != nil {
+		logger.Fatal("Invalid SEC_CODE_PATTERN or SEC_CODE_PATTERNS_BY_JURISDICTION", zap.Error(err))
+	}
+	serviceOpts := service.Options{
+		Jurisdictions:                cfg.ValidJurisdictions,
+		CanonicalizeSecCodes:         cfg.CanonicalizeSecCodes,
+		AddressMaxLength:             cfg.AddressMaxLength,
+		NatureOfBusinessMaxLength:    cfg.NatureOfBusinessMaxLength,
+		TruncateNatureOfBusiness:     cfg.NatureOfBusinessOverflow == "truncate",
+		EnforceUniqueRegistryNumbers: cfg.EnforceUniqueRegistryNumbers,
+		DuplicatePolicy:              cfg.DuplicatePolicy,
+		AllowLimitZero:               cfg.AllowLimitZero,
+		DefaultLimit:                 cfg.ListDefaultLimit,
+		MaxLimit:                     cfg.ListMaxLimit,
+		MaxOffset:                    cfg.ListMaxOffset,
+		AccentInsensitiveSearch:      cfg.AccentInsensitiveSearch,
+		MaxFilters:                   cfg.MaxFilters,
+		MinDirectors:                 cfg.MinDirectors,
+		MaxShareholders:              cfg.MaxShareholders,
+		CreateDefaults:               createDefaults,
+		AddressRules:                 addressRules,
+		SecCodeRules:                 secCodeRules,
+		AddressCountryCheck:          cfg.AddressCountryCheck,
+		MaxCreatesPerMinute:          cfg.MaxCreatesPerMinute,
+		IdempotencyTTL:               cfg.IdempotencyKeyTTL,
+		SoftDeleteRetention:          cfg.SoftDeleteRetention,
+		MaxSnapshots:                 cfg.SnapshotMaxOpen,
+		SnapshotIdleTimeout:          cfg.SnapshotIdleTimeout,
+		SnapshotLifetime:             cfg.SnapshotMaxLifetime,
+	}
+	if err := serviceOpts.Validate(); err != nil {
+		logger.Fatal("Invalid service configuration", zap.Error(err))
+	}
+	companyService := service.NewCompanyService(companyRepo, serviceOpts)
+	handlerOpts := handlers.Options{
+		StrictUUIDs:  cfg.StrictUUIDs,
+		AdminToken:   cfg.AdminToken,
+		ImportStrict: cfg.ImportMode == "strict",
+
+		MaxBodyBytes:   int64(cfg.MaxBodyBytes),
+		MaxImportBytes: int64(cfg.MaxImportBytes),
+
+		ListCacheMaxAge: cfg.ListCacheMaxAge,
+		ListMaxOffset:   cfg.ListMaxOffset,
+	}
+	if cfg.ListCacheMaxAge < 0 {
+		logger.Fatal("LIST_CACHE_MAX_AGE cannot be negative")
+	}
+	if !cfg.IsProduction() {
+		handlerOpts.DebugToken = cfg.AdminToken
+	}
+	companyHandlers := handlers.NewCompanyHandlers(companyService, logger, handlerOpts)
+	adminHandlers := handlers.NewAdminHandlers(db, cfg.MaxIdleConns, logger)
+	dbHealth := database.NewHealth()
+	metrics.RegisterDBHealth(dbHealth.Healthy)
+	metrics.RegisterDBStats(db)
+	healthHandlers := handlers.NewHealthHandlers(dbHealth, logger)
+	docsHandlers, err := handlers.NewDocsHandlers(logger)
+	if err != nil {
+		logger.Fatal("Failed to prepare API docs", zap.Error(err))
+	}
+
+	jwtVerifier, err := newJWTVerifier(cfg)
+	if err != nil {
+		logger.Fatal("Invalid JWT configuration", zap.Error(err))
+	}
 
 	// Create router
 	r := chi.NewRouter()
 
+	// Answer a known path with an unsupported method with a JSON 405 listing the supported methods; the API
+	// subrouter inherits it
+	r.MethodNotAllowed(methodNotAllowed(r))
+
 	// Middleware
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	r.Use(middleware.RequestID)
+	r.Use(appmiddleware.RequestIDHeader)
+	r.Use(metrics.Middleware)
+	requestLogger, err := appmiddleware.RequestLogger(logger, appmiddleware.AccessLog{
+		Fields:     cfg.AccessLogFields,
+		LogSuccess: cfg.AccessLogSuccess,
+	})
+	if err != nil {
+		logger.Fatal("Invalid ACCESS_LOG_FIELDS", zap.Error(err))
+	}
+	r.Use(requestLogger)
+	r.Use(appmiddleware.Recoverer(logger))
+	if cfg.LogBodies {
+		if cfg.LogBodyMaxBytes <= 0 {
+			logger.Fatal("LOG_BODY_MAX_BYTES must be positive when LOG_BODIES is enabled")
+		}
+		logger.Warn("Logging request and response bodies", zap.Strings("redacted_fields", cfg.LogRedactedFields))
+		r.Use(appmiddleware.LogBodies(appmiddleware.NewRedactor(cfg.LogRedactedFields), cfg.LogBodyMaxBytes, logger))
+	}
 	r.Use(middleware.Heartbeat("/health"))
 
-	// CORS middleware for development
-	r.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization")
+	// Trailing slash handling, so /api/v1/companies/ resolves like /api/v1/companies
+	if slashes, ok := trailingSlashMiddleware(cfg.TrailingSlash); !ok {
+		logger.Warn("Unknown trailing slash mode, leaving routes unchanged", zap.String("mode", cfg.TrailingSlash))
+	} else if slashes != nil {
+		r.Use(slashes)
+	}
 
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
-				return
+	// Alternate collection names for integrators with fixed path expectations, rewritten before routing
+	if len(cfg.RouteAliases) > 0 {
+		if err := validateRouteAliases(cfg.RouteAliases); err != nil {
+			logger.Fatal("Invalid ROUTE_ALIASES", zap.Error(err))
+		}
+		r.Use(appmiddleware.RouteAliases("/api/v1", cfg.RouteAliases))
+	}
+
+	// Readiness probe: not ready while starting up, once shutdown begins, or when the database is unreachable.
+	// /health above stays a pure liveness check.
+	r.Get("/ready", healthHandlers.Ready)
+
+	// Build identification for deploy verification; /health stays the bare liveness check
+	r.Get("/version", healthHandlers.Version)
+
+	// Prometheus scrape endpoint
+	r.Handle("/metrics", metrics.Handler())
+
+	// Runtime profiles for operators, outside the API's CORS and auth policy, so only mounted when enabled
+	if cfg.EnablePprof {
+		logger.Warn("pprof enabled: runtime profiles are served unauthenticated under /debug/pprof")
+		r.Route("/debug/pprof", mountPprof)
+	}
+
+	// The API's browser-facing policy; the probes, metrics and profiles above are outside it
+	r.Group(func(r chi.Router) {
+		// Server-Timing on every API response, covering the rejections below as well as the handlers
+		r.Use(appmiddleware.ServerTiming(logger))
+
+		// CORS for the configured browser origins
+		r.Use(appmiddleware.CORS(cfg.CORSAllowedOrigins))
+
+		// Only reads may be cached; even a rejected write's response must not be replayed from a cache
+		r.Use(appmiddleware.NoStoreWrites)
+
+		// Read-only deployment posture, e.g. when only a read replica is available
+		if cfg.ReadOnly {
+			logger.Warn("Read-only mode enabled: mutating requests will be rejected")
+			r.Use(appmiddleware.ReadOnly(logger))
+		}
+
+		// API routes
+		r.Route("/api/v1", func(r chi.Router) {
+			// Per-IP rate limits, stricter for writes; the probes and metrics above are never limited
+			readLimit := appmiddleware.RateLimit{Rate: cfg.RateLimitReadRPS, Burst: cfg.RateLimitReadBurst}
+			writeLimit := appmiddleware.RateLimit{Rate: cfg.RateLimitWriteRPS, Burst: cfg.RateLimitWriteBurst}
+			if readLimit.Rate > 0 || writeLimit.Rate > 0 {
+				if (readLimit.Rate > 0 && readLimit.Burst < 1) || (writeLimit.Rate > 0 && writeLimit.Burst < 1) {
+					logger.Fatal("RATE_LIMIT_READ_BURST and RATE_LIMIT_WRITE_BURST must be at least 1 when their rate is set")
+				}
+				r.Use(appmiddleware.RateLimitByIP(appmiddleware.NewMemoryRateLimitStore(), readLimit, writeLimit, logger))
+			}
+
+			// Shed load with 503s rather than queue requests behind an exhausted connection pool
+			r.Use(appmiddleware.PoolGuard(db, cfg.DBPoolAcquireTimeout, cfg.DBPoolRetryAfter, logger))
+
+			// Reject parameters and bodies that break the spec centrally, before any handler parses them
+			if cfg.OpenAPIValidation {
+				spec, err := api.GetSwagger()
+				if err != nil {
+					logger.Fatal("Failed to load embedded OpenAPI spec", zap.Error(err))
+				}
+				validate, err := appmiddleware.ValidateRequests(spec, int64(cfg.MaxBodyBytes), logger)
+				if err != nil {
+					logger.Fatal("Failed to build OpenAPI request validation", zap.Error(err))
+				}
+				r.Use(validate)
 			}
 
-			next.ServeHTTP(w, r)
+			// Compress API responses for clients sending Accept-Encoding; health checks and metrics stay uncompressed
+			if cfg.CompressionLevel > 0 {
+				r.Use(middleware.Compress(cfg.CompressionLevel,
+					response.ContentTypeJSON, response.ContentTypeProblemJSON, response.ContentTypeMsgPack, "text/csv", "application/x-ndjson", "text/plain"))
+			}
+
+			// JWT-protected routes; the debug and admin routes below use their own tokens
+			if jwtVerifier == nil {
+				logger.Warn("JWT_SECRET and JWT_PUBLIC_KEY_FILE not set, write routes are unauthenticated")
+			}
+			requireJWT := func(r chi.Router) {
+				if jwtVerifier != nil {
+					r.Use(appmiddleware.RequireJWT(jwtVerifier, cfg.JWTProtectReads, logger))
+				}
+				r.Use(auditActor(jwtSubject))
+			}
+
+			// Streaming routes write their 200 before the rows are read, so a request deadline would cut the body
+			// off mid-file with no way to report it; DB_STATEMENT_TIMEOUT bounds their queries instead
+			r.Group(func(r chi.Router) {
+				requireJWT(r)
+				r.Get("/companies.csv", companyHandlers.ExportCompaniesCSV)
+				if cfg.SnapshotMaxOpen > 0 {
+					r.Post("/companies/snapshots", companyHandlers.OpenSnapshot)
+					r.Get("/companies/snapshots/{snapshotId}", companyHandlers.NextSnapshotPage)
+					r.Delete("/companies/snapshots/{snapshotId}", companyHandlers.CloseSnapshot)
+				}
+			})
+
+			// Full data lake export, guarded by the admin token rather than JWT as it exposes internal columns
+			if cfg.AdminToken != "" {
+				r.With(appmiddleware.RequireAdminToken(cfg.AdminToken, logger)).
+					Get("/companies/export.ndjson", companyHandlers.ExportCompaniesNDJSON)
+			}
+
+			// Every other route is bounded by the request deadline
+			r.Group(func(r chi.Router) {
+				r.Use(appmiddleware.Timeout(cfg.RequestTimeout))
+
+				r.Get("/", handleApiStatus(logger))
+
+				// The spec and its explorer are public, so clients can be generated without credentials
+				r.Get("/openapi.json", docsHandlers.OpenAPI)
+				if cfg.SwaggerUI {
+					r.Get("/docs", docsHandlers.SwaggerUI)
+				}
+
+				r.Group(func(r chi.Router) {
+					requireJWT(r)
+
+					// Company routes
+					r.Get("/companies", companyHandlers.GetCompanies)
+					r.Get("/companies/count", companyHandlers.CountCompanies)
+					r.Get("/companies/checksum", companyHandlers.ChecksumCompanies)
+					r.Post("/companies", companyHandlers.CreateCompany)
+					r.Post("/companies/batch", companyHandlers.CreateCompanies)
+					r.Post("/companies/batch-delete", companyHandlers.DeleteCompanies)
+					r.Post("/companies/import", companyHandlers.ImportCompanies)
+					r.Get("/companies/extract", companyHandlers.ExtractCompanies)
+					r.Get("/companies/by-registry", companyHandlers.GetCompanyByRegistry)
+					r.Get("/companies/{id}", companyHandlers.GetCompanyByID)
+					r.Get("/companies/{id}.pdf", companyHandlers.ExportCompanyPDF)
+					r.Head("/companies/{id}", companyHandlers.HeadCompany)
+					r.Put("/companies/{id}", companyHandlers.UpdateCompany)
+					r.Patch("/companies/{id}", companyHandlers.PatchCompany)
+					r.Delete("/companies/{id}", companyHandlers.DeleteCompany)
+					r.Put("/companies/{id}/jurisdiction", companyHandlers.TransferJurisdiction)
+					r.Patch("/companies/{id}/number_of_directors", companyHandlers.AdjustDirectorCount)
+					r.Patch("/companies/{id}/number_of_shareholders", companyHandlers.AdjustShareholderCount)
+					r.Get("/companies/{id}/history", companyHandlers.GetCompanyHistory)
+					r.Get("/companies/{id}/directors", companyHandlers.ListDirectors)
+					r.Post("/companies/{id}/directors", companyHandlers.AddDirector)
+					r.Delete("/companies/{id}/directors/{directorId}", companyHandlers.RemoveDirector)
+					r.Get("/companies/{id}/shareholders", companyHandlers.ListShareholders)
+					r.Post("/companies/{id}/shareholders", companyHandlers.AddShareholder)
+					r.Put("/companies/{id}/shareholders/{shareholderId}", companyHandlers.UpdateShareholder)
+					r.Delete("/companies/{id}/shareholders/{shareholderId}", companyHandlers.RemoveShareholder)
+
+					// Report routes
+					r.Get("/reports/shared-addresses", companyHandlers.GetSharedAddressReport)
+					r.Get("/reports/nature-of-business", companyHandlers.CountCompaniesByNatureOfBusiness)
+
+					// Jurisdiction routes
+					r.Get("/jurisdictions", companyHandlers.ListJurisdictions)
+					r.Get("/jurisdictions/resolve", companyHandlers.ResolveJurisdiction)
+					r.Get("/jurisdictions/counts", companyHandlers.CountCompaniesByJurisdiction)
+				})
+
+				// Debug routes, guarded by the debug token which is never set in production
+				r.Get("/debug/companies/{id}/raw", companyHandlers.GetRawCompany)
+
+				// Admin routes, only mounted when an admin token is configured
+				if cfg.AdminToken != "" {
+					r.Route("/admin", func(r chi.Router) {
+						r.Use(appmiddleware.RequireAdminToken(cfg.AdminToken, logger))
+						r.Use(auditActor(func(*http.Request) string { return "admin" }))
+						r.Post("/db/reset-pool", adminHandlers.ResetPool)
+						r.Post("/companies/purge", companyHandlers.PurgeDeletedCompanies)
+						r.Post("/companies/{id}/restore", companyHandlers.RestoreCompany)
+					})
+				} else {
+					logger.Info("ADMIN_TOKEN not set, admin routes disabled")
+				}
+			})
 		})
 	})
 
-	// API routes
-	r.Route("/api/v1", func(r chi.Router) {
-		r.Get("/", handleApiStatus(logger))
+	server := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: r,
+	}
 
-		// Company routes
-		r.Get("/companies", companyHandlers.GetCompanies)
-		r.Post("/companies", companyHandlers.CreateCompany)
-		r.Get("/companies/{id}", companyHandlers.GetCompanyByID)
-		r.Delete("/companies/{id}", companyHandlers.DeleteCompany)
-	})
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Track database health until shutdown begins, so /ready reflects an outage and stale connections are dropped
+	if cfg.DBHealthInterval <= 0 {
+		logger.Fatal("DB_HEALTH_INTERVAL must be positive", zap.Duration("interval", cfg.DBHealthInterval))
+	}
+	go dbHealth.Run(ctx, db, cfg.DBHealthInterval, cfg.MaxIdleConns, logger)
+
+	// Only one of the TLS files is almost certainly a misconfiguration, so refuse to fall back to plain HTTP
+	useTLS := cfg.TLSCertFile != "" && cfg.TLSKeyFile != ""
+	if !useTLS && (cfg.TLSCertFile != "" || cfg.TLSKeyFile != "") {
+		logger.Fatal("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
 
-	// Start server
-	logger.Info("Server starting", zap.String("port", cfg.Port))
-	if err := http.ListenAndServe(":"+cfg.Port, r); err != nil {
+	// Start server; net/http negotiates HTTP/2 automatically over TLS
+	serverErr := make(chan error, 1)
+	go func() {
+		var err error
+		if useTLS {
+			logger.Info("Server starting with TLS", zap.String("port", cfg.Port), zap.String("cert_file", cfg.TLSCertFile))
+			err = server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			logger.Info("Server starting with plain HTTP", zap.String("port", cfg.Port))
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+	healthHandlers.SetReady(true)
+
+	select {
+	case err := <-serverErr:
 		logger.Fatal("Server failed to start", zap.Error(err))
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Stop receiving new traffic before draining what is already in flight
+	healthHandlers.SetReady(false)
+	logger.Info("Shutdown signal received, draining in-flight requests", zap.Duration("timeout", cfg.ShutdownTimeout))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("HTTP server did not shut down cleanly", zap.Error(err))
+	} else {
+		logger.Info("HTTP server stopped")
 	}
+
+	// Roll back snapshots still open, releasing their connections, then close the database only once
+	// outstanding requests have completed
+	companyService.CloseSnapshots()
+	if err := db.Close(); err != nil {
+		logger.Error("Failed to close database connection", zap.Error(err))
+	} else {
+		logger.Info("Database connection closed")
+	}
+
+	logger.Info("Shutdown complete")
+}
+
+// newLogger builds JSON logs at info level for aggregation in production, and human-readable console logs at
+// debug level elsewhere. A configured LOG_LEVEL overrides the environment's level.
+func newLogger(cfg *config.Config) (*zap.Logger, error) {
+	zapCfg := zap.NewDevelopmentConfig()
+	if cfg.IsProduction() {
+		zapCfg = zap.NewProductionConfig()
+	}
+
+	if cfg.LogLevel != "" {
+		level, err := zapcore.ParseLevel(cfg.LogLevel)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+		}
+		zapCfg.Level = zap.NewAtomicLevelAt(level)
+	}
+
+	return zapCfg.Build()
+}
+
+// mountPprof serves the net/http/pprof index, its named profiles such as heap and goroutine, and the CPU profile
+// and execution trace endpoints
+func mountPprof(r chi.Router) {
+	r.HandleFunc("/cmdline", pprof.Cmdline)
+	r.HandleFunc("/profile", pprof.Profile)
+	r.HandleFunc("/symbol", pprof.Symbol)
+	r.HandleFunc("/trace", pprof.Trace)
+	r.HandleFunc("/*", pprof.Index)
 }
 
 func handleApiStatus(logger *zap.Logger) http.HandlerFunc {
@@ -102,3 +475,150 @@ func handleApiStatus(logger *zap.Logger) http.HandlerFunc {
 		}
 	}
 }
+
+// allowableMethods are the methods methodNotAllowed checks a path against when listing the ones it supports
+var allowableMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+	http.MethodOptions,
+}
+
+// methodNotAllowed returns a 405 handler that writes the standard error body and an Allow header listing the
+// methods router serves for the request path
+func methodNotAllowed(router chi.Routes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// StripSlashes only strips the path chi routes by, so match without the trailing slash too
+		path := r.URL.Path
+		if len(path) > 1 {
+			path = strings.TrimSuffix(path, "/")
+		}
+
+		var allowed []string
+		for _, method := range allowableMethods {
+			if router.Match(chi.NewRouteContext(), method, path) {
+				allowed = append(allowed, method)
+			}
+		}
+
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		response.WriteError(w, r, http.StatusMethodNotAllowed, fmt.Sprintf("Method %s is not allowed for this resource", r.Method))
+	}
+}
+
+// auditActor attributes the writes made while handling a request to actor(r) in the audit log
+func auditActor(actor func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(repository.WithActor(r.Context(), actor(r))))
+		})
+	}
+}
+
+// jwtSubject returns the subject of the JWT that authenticated the request, or "" when unauthenticated
+func jwtSubject(r *http.Request) string {
+	claims, _ := appmiddleware.ClaimsFromContext(r.Context())
+	return claims.Subject()
+}
+
+// trailingSlashMiddleware returns the middleware for a TRAILING_SLASH_MODE, nil for "off", and false for an
+// unknown mode
+func trailingSlashMiddleware(mode string) (func(http.Handler) http.Handler, bool) {
+	switch mode {
+	case "strip":
+		return middleware.StripSlashes, true
+	case "redirect":
+		return middleware.RedirectSlashes, true
+	case "off":
+		return nil, true
+	default:
+		return nil, false
+	}
+}
+
+// aliasableCollections lists the collections ROUTE_ALIASES may point at
+var aliasableCollections = map[string]bool{"companies": true}
+
+// validateRouteAliases checks each alias is a single path segment naming no existing route and maps to an aliasable collection
+func validateRouteAliases(aliases map[string]string) error {
+	reserved := map[string]bool{
+		"companies": true, "reports": true, "jurisdictions": true, "debug": true, "admin": true,
+		"openapi.json": true, "docs": true,
+	}
+	for alias, canonical := range aliases {
+		if alias == "" || strings.ContainsAny(alias, "/?#") {
+			return fmt.Errorf("alias %q must be a single path segment", alias)
+		}
+		if reserved[alias] {
+			return fmt.Errorf("alias %q would shadow an existing route", alias)
+		}
+		if !aliasableCollections[canonical] {
+			return fmt.Errorf("alias %q maps to %q, which is not an aliasable collection", alias, canonical)
+		}
+	}
+	return nil
+}
+
+// runMigrations deploys the pending changes of the embedded sqitch plan, stopping startup if one fails
+func runMigrations(db *sql.DB, logger *zap.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	deployed, err := database.RunMigrations(ctx, db, migrations.Plan, migrations.Scripts)
+	if len(deployed) > 0 {
+		logger.Info("Deployed database migrations", zap.Strings("changes", deployed))
+	}
+	if err != nil {
+		logger.Fatal("Failed to deploy database migrations", zap.Error(err))
+	}
+}
+
+// checkMigrations compares the embedded sqitch plan with the deployed changes, logging the pending ones. With
+// required set, pending changes or a failed check stop startup.
+func checkMigrations(db *sql.DB, required bool, logger *zap.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	report := logger.Warn
+	if required {
+		report = logger.Fatal
+	}
+
+	pending, err := database.PendingMigrations(ctx, db, migrations.Plan)
+	if err != nil {
+		report("Failed to check database migrations", zap.Error(err))
+		return
+	}
+
+	if len(pending) > 0 {
+		report("Database migrations are pending, run sqitch deploy or enable MIGRATE_ON_STARTUP", zap.Strings("changes", pending))
+	}
+}
+
+// logIndexAdvisories logs a warning for each index the company queries expect but the database lacks
+func logIndexAdvisories(db *sql.DB, logger *zap.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	advice, err := database.IndexAdvisories(ctx, db)
+	if err != nil {
+		logger.Warn("Failed to check for missing indexes", zap.Error(err))
+		return
+	}
+
+	for _, a := range advice {
+		logger.Warn("Recommended index is missing",
+			zap.String("feature", a.Feature),
+			zap.String("suggestion", a.Suggestion))
+	}
+}
+
+// newJWTVerifier builds the JWT verifier from the configured secret or public key file; nil disables JWT auth
+func newJWTVerifier(cfg *config.Config) (*appmiddleware.JWTVerifier, error) {
+	var publicKey []byte
+	if cfg.JWTPublicKeyFile != "" {
+		var err error
+		if publicKey, err = os.ReadFile(cfg.JWTPublicKeyFile); err != nil {
+			return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+		}
+	}
+	return appmiddleware.NewJWTVerifier(cfg.JWTSecret, publicKey)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestValidateRouteAliases(t *testing.T) {
+	tests := []struct {
+		alias string
+		ok    bool
+	}{
+		{"organisations", true},
+		{"companies", false},
+		{"reports", false},
+		{"jurisdictions", false},
+		{"debug", false},
+		{"admin", false},
+		{"openapi.json", false},
+		{"docs", false},
+		{"", false},
+		{"a/b", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			err := validateRouteAliases(map[string]string{tt.alias: "companies"})
+			if tt.ok != (err == nil) {
+				t.Errorf("err = %v, want accepted = %v", err, tt.ok)
+			}
+		})
+	}
+
+	if err := validateRouteAliases(map[string]string{"people": "reports"}); err == nil {
+		t.Error("alias of a non-aliasable collection accepted")
+	}
+}
+
+func TestTrailingSlashMiddleware(t *testing.T) {
+	tests := []struct {
+		mode     string
+		status   int
+		location string
+	}{
+		{"strip", http.StatusOK, ""},
+		{"redirect", http.StatusMovedPermanently, "/api/v1/companies"},
+		{"off", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			slashes, ok := trailingSlashMiddleware(tt.mode)
+			if !ok {
+				t.Fatalf("mode %q not recognized", tt.mode)
+			}
+
+			r := chi.NewRouter()
+			if slashes != nil {
+				r.Use(slashes)
+			}
+			r.Get("/api/v1/companies", func(w http.ResponseWriter, r *http.Request) {})
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/companies/", nil))
+			if rec.Code != tt.status {
+				t.Errorf("GET with a trailing slash: status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Location"); got != tt.location {
+				t.Errorf("Location = %q, want %q", got, tt.location)
+			}
+
+			rec = httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/companies", nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("GET without a trailing slash: status = %d, want 200", rec.Code)
+			}
+		})
+	}
+
+	if _, ok := trailingSlashMiddleware("keep"); ok {
+		t.Error("unknown mode accepted")
+	}
+}
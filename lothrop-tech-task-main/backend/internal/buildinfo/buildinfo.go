@@ -0,0 +1,40 @@
+// Package buildinfo identifies the running build. Version and Commit are set at link time:
+//
+//	go build -ldflags "-X backend/internal/buildinfo.Version=v1.2.0 -X backend/internal/buildinfo.Commit=$(git rev-parse HEAD)" ./cmd/server
+package buildinfo
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+var (
+	// Version is the release version, "dev" for builds without -ldflags
+	Version = "dev"
+	// Commit is the git commit built; without -ldflags it falls back to the VCS revision Go stamps into
+	// binaries built from a checkout, if any
+	Commit = ""
+)
+
+// started approximates the process start time, as package variables are initialized before main runs
+var started = time.Now()
+
+func init() {
+	if Commit != "" {
+		return
+	}
+
+	Commit = "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				Commit = setting.Value
+			}
+		}
+	}
+}
+
+// Uptime returns how long the process has been running
+func Uptime() time.Duration {
+	return time.Since(started)
+}
@@ -1,30 +1,360 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Config struct {
-	Port         string
-	PostgresDB   string
-	PostgresPass string
-	PostgresUser string
-	PostgresHost string
-	PostgresPort string
+	// Environment is the deployment environment, e.g. "development" or "production"
+	Environment string
+	// LogLevel is the minimum level logged, e.g. "debug", "info" or "warn"; empty uses the environment's default
+	LogLevel string
+	// AccessLogFields selects the fields of the per-request access log line
+	AccessLogFields []string
+	// AccessLogSuccess also writes access log lines for requests answered below 400, not only errors
+	AccessLogSuccess bool
+	// LogBodies logs JSON request and response bodies at debug level, with LogRedactedFields redacted
+	LogBodies bool
+	// LogRedactedFields names the body fields whose values are replaced in logs, at any depth
+	LogRedactedFields []string
+	// LogBodyMaxBytes caps the bytes of each body captured for logging; longer bodies are omitted
+	LogBodyMaxBytes int
+	Port            string
+	PostgresDB      string
+	PostgresPass    string
+	PostgresUser    string
+	PostgresHost    string
+	PostgresPort    string
+	// PostgresAppName is reported as application_name in pg_stat_activity
+	PostgresAppName string
+	// InstanceName optionally identifies this instance (e.g. pod name) in application_name
+	InstanceName string
+	// TrailingSlash controls how trailing slashes on routes are handled: "strip", "redirect" or "off"
+	TrailingSlash string
+	// CORSAllowedOrigins lists the browser origins allowed to call the API with credentials; "*" allows any origin without them
+	CORSAllowedOrigins []string
+	// RouteAliases maps alternate collection names to the canonical one, e.g. "company=companies,organisations=companies"
+	RouteAliases map[string]string
+	// TLSCertFile and TLSKeyFile are PEM files for serving HTTPS; plain HTTP is served unless both are set
+	TLSCertFile string
+	TLSKeyFile  string
+	// ReadOnly rejects every mutating request with 503 while still serving reads
+	ReadOnly bool
+	// EnablePprof serves net/http/pprof under /debug/pprof, unauthenticated, for profiling under load
+	EnablePprof bool
+	// SwaggerUI serves an interactive Swagger UI page at /api/v1/docs, loading its assets from a CDN
+	SwaggerUI bool
+	// OpenAPIValidation rejects API requests whose parameters or JSON body break openapi.yaml before they reach a handler
+	OpenAPIValidation bool
+	// CanonicalizeSecCodes returns sec_code uppercased and trimmed even for inconsistently stored rows
+	CanonicalizeSecCodes bool
+	// AdminToken is the bearer token required by /api/v1/admin routes; admin routes are disabled when empty
+	AdminToken string
+	// JWTSecret is the HS256 shared secret verifying bearer JWTs on write routes
+	JWTSecret string
+	// JWTPublicKeyFile is a PEM RS256 public key verifying bearer JWTs, used instead of JWTSecret
+	JWTPublicKeyFile string
+	// JWTProtectReads requires a JWT on GET routes too, not only on writes
+	JWTProtectReads bool
+	// AddressMaxLength caps company_address in characters; 0 disables the limit
+	AddressMaxLength int
+	// NatureOfBusinessMaxLength caps nature_of_business in characters; 0 disables the limit
+	NatureOfBusinessMaxLength int
+	// NatureOfBusinessOverflow is "reject" (validation error) or "truncate" (store truncated with a warning)
+	NatureOfBusinessOverflow string
+	// StrictUUIDs requires company IDs in canonical lowercase hyphenated form
+	StrictUUIDs bool
+	// EnforceUniqueRegistryNumbers rejects a registry source and number already linked to another company
+	EnforceUniqueRegistryNumbers bool
+	// DuplicatePolicy is "reject", "warn" or "allow": how creates treat a name normalizing like a live company's in the jurisdiction
+	DuplicatePolicy string
+	// AllowLimitZero treats limit=0 on the list endpoint as a count-only request instead of a validation error
+	AllowLimitZero bool
+	// ListDefaultLimit is the page size of list requests without a limit; ListMaxLimit is the largest limit accepted
+	ListDefaultLimit int
+	ListMaxLimit     int
+	// ListMaxOffset is the largest offset accepted before clients are pointed at cursor pagination; 0 disables the cap
+	ListMaxOffset int
+	// ListCacheMaxAge is sent as Cache-Control max-age on company list responses; 0 disables it
+	ListCacheMaxAge time.Duration
+	// ValidJurisdictions lists the jurisdictions companies may belong to
+	ValidJurisdictions []string
+	// MinDirectors maps jurisdictions to the minimum number_of_directors they require, e.g. "UK=1,Singapore=1"
+	MinDirectors map[string]int
+	// MaxFilters caps the filter conditions combined in one list request; 0 disables the cap
+	MaxFilters int
+	// AccentInsensitiveSearch folds accents when matching name searches
+	AccentInsensitiveSearch bool
+	// MaxShareholders maps jurisdictions to their maximum number_of_shareholders, e.g. "Singapore=50"
+	MaxShareholders map[string]int
+	// MaxBodyBytes caps JSON request bodies on create, batch, update and patch; 0 disables the cap
+	MaxBodyBytes int
+	// MaxImportBytes caps CSV import uploads; 0 disables the cap
+	MaxImportBytes int
+	// ImportMode is the default CSV import mode: "lenient" (create valid rows) or "strict" (all or nothing)
+	ImportMode string
+	// AddressCountryCheck maps jurisdictions to an address country check mode, e.g. "UK=error,Singapore=warn"
+	AddressCountryCheck map[string]string
+	// MaxCreatesPerMinute caps companies created per minute across all clients; 0 disables the cap
+	MaxCreatesPerMinute int
+	// SecCodePattern is the regular expression a non-empty sec_code must match, by default 2 to 4 uppercase
+	// letters followed by digits; empty disables the check
+	SecCodePattern string
+	// SecCodePatterns is a JSON object mapping jurisdictions to their own sec_code pattern, overriding SecCodePattern
+	SecCodePatterns string
+	// AddressRules is a JSON object mapping jurisdictions to company_address requiredness and format rules
+	AddressRules string
+	// CreateDefaults is a JSON object mapping jurisdictions to default values for optional fields omitted on create
+	CreateDefaults string
+	// MaxOpenConns caps open database connections; 0 means unlimited
+	MaxOpenConns int
+	// MaxIdleConns caps idle pooled database connections
+	MaxIdleConns int
+	// ConnMaxLifetime recycles database connections older than this; 0 keeps them indefinitely
+	ConnMaxLifetime time.Duration
+	// DBPoolAcquireTimeout is how long an API request waits for a connection when the pool is exhausted before
+	// it is rejected with 503; 0 lets requests queue for a connection until they time out
+	DBPoolAcquireTimeout time.Duration
+	// DBPoolRetryAfter is the Retry-After sent with those 503s
+	DBPoolRetryAfter time.Duration
+	// DBRetryMaxAttempts is how many times a read or transaction failing with a transient error is attempted; 1 disables retries
+	DBRetryMaxAttempts int
+	// DBConnectMaxAttempts is how many times the database is pinged at startup before giving up; 1 fails fast
+	DBConnectMaxAttempts int
+	// DBConnectTimeout bounds how long startup waits for the database to answer; 0 waits for DBConnectMaxAttempts only
+	DBConnectTimeout time.Duration
+	// DBHealthInterval is how often the database is pinged to track connection health for /ready and metrics
+	DBHealthInterval time.Duration
+	// SlowQueryThreshold is how long a database operation may take before it is logged as slow; 0 disables the logging
+	SlowQueryThreshold time.Duration
+	// DBStatementTimeout is the Postgres statement_timeout of every connection, cancelling longer statements; 0 disables it
+	DBStatementTimeout time.Duration
+	// CompanyCacheSize is how many companies GetByID caches in process memory; 0 disables the cache
+	CompanyCacheSize int
+	// CompanyCacheTTL bounds how long a cached company is served, and so how stale a write from another instance can be
+	CompanyCacheTTL time.Duration
+	// MigrateOnStartup deploys pending changes from the embedded sqitch plan before serving
+	MigrateOnStartup bool
+	// RequireMigrations stops startup when sqitch changes from the plan are not deployed, instead of logging a warning
+	RequireMigrations bool
+	// IdempotencyKeyTTL is how long an Idempotency-Key on company creation replays the original response
+	IdempotencyKeyTTL time.Duration
+	// SoftDeleteRetention is how long soft-deleted companies are kept before the admin purge may remove them
+	SoftDeleteRetention time.Duration
+	// SnapshotMaxOpen caps the consistent snapshots open at once; 0 disables the snapshot endpoints
+	SnapshotMaxOpen int
+	// SnapshotIdleTimeout closes a snapshot that has not been read for this long
+	SnapshotIdleTimeout time.Duration
+	// SnapshotMaxLifetime closes a snapshot this long after it was opened
+	SnapshotMaxLifetime time.Duration
+	// IndexAdvisories logs recommended indexes missing from the database at startup
+	IndexAdvisories bool
+	// CompressionLevel is the gzip level for API responses, 1 (fastest) to 9 (smallest); 0 disables compression
+	CompressionLevel int
+	// RateLimitReadRPS is the per-IP request rate allowed for GET, HEAD and OPTIONS API requests; 0 disables it
+	RateLimitReadRPS float64
+	// RateLimitReadBurst is how many read requests an IP may make at once before RateLimitReadRPS applies
+	RateLimitReadBurst int
+	// RateLimitWriteRPS is the per-IP request rate allowed for other API requests; 0 disables it
+	RateLimitWriteRPS float64
+	// RateLimitWriteBurst is how many write requests an IP may make at once before RateLimitWriteRPS applies
+	RateLimitWriteBurst int
+	// RequestTimeout bounds each API request's context, cancelling its database calls; 0 disables the deadline
+	RequestTimeout time.Duration
+	// ShutdownTimeout bounds how long in-flight requests may drain after SIGINT/SIGTERM
+	ShutdownTimeout time.Duration
 }
 
 func Load() *Config {
 	return &Config{
+		Environment:  getEnv("APP_ENV", "development"),
+		LogLevel:     getEnv("LOG_LEVEL", ""),
 		Port:         getEnv("PORT", "8080"),
 		PostgresDB:   getEnv("POSTGRES_DB", "lothrop_db"),
 		PostgresPass: getEnv("POSTGRES_PASSWORD", "password"),
 		PostgresUser: getEnv("POSTGRES_USER", "postgres"),
 		PostgresHost: getEnv("POSTGRES_HOST", "localhost"),
 		PostgresPort: getEnv("POSTGRES_PORT", "5432"),
+
+		TLSCertFile: getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:  getEnv("TLS_KEY_FILE", ""),
+
+		MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 5),
+		ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+
+		DBPoolAcquireTimeout: getEnvDuration("DB_POOL_ACQUIRE_TIMEOUT", 250*time.Millisecond),
+		DBPoolRetryAfter:     getEnvDuration("DB_POOL_RETRY_AFTER", 2*time.Second),
+
+		DBRetryMaxAttempts:   getEnvInt("DB_RETRY_MAX_ATTEMPTS", 3),
+		DBConnectMaxAttempts: getEnvInt("DB_CONNECT_MAX_ATTEMPTS", 10),
+		DBConnectTimeout:     getEnvDuration("DB_CONNECT_TIMEOUT", time.Minute),
+		DBHealthInterval:     getEnvDuration("DB_HEALTH_INTERVAL", 10*time.Second),
+		SlowQueryThreshold:   time.Duration(getEnvInt("SLOW_QUERY_MS", 500)) * time.Millisecond,
+		DBStatementTimeout:   getEnvDuration("DB_STATEMENT_TIMEOUT", 0),
+		CompanyCacheSize:     getEnvInt("COMPANY_CACHE_SIZE", 0),
+		CompanyCacheTTL:      getEnvDuration("COMPANY_CACHE_TTL", 30*time.Second),
+
+		PostgresAppName: getEnv("POSTGRES_APPLICATION_NAME", "lothrop-backend"),
+		InstanceName:    getEnv("INSTANCE_NAME", ""),
+
+		TrailingSlash: getEnv("TRAILING_SLASH_MODE", "strip"),
+		ReadOnly:      getEnvBool("READ_ONLY", false),
+		EnablePprof:   getEnvBool("ENABLE_PPROF", false),
+
+		OpenAPIValidation: getEnvBool("OPENAPI_VALIDATION", true),
+		SwaggerUI:         getEnvBool("SWAGGER_UI", false),
+
+		CanonicalizeSecCodes: getEnvBool("CANONICALIZE_SEC_CODES", true),
+		AdminToken:           getEnv("ADMIN_TOKEN", ""),
+
+		JWTSecret:        getEnv("JWT_SECRET", ""),
+		JWTPublicKeyFile: getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		JWTProtectReads:  getEnvBool("JWT_PROTECT_READS", false),
+
+		AddressMaxLength:             getEnvInt("COMPANY_ADDRESS_MAX_LENGTH", 1000),
+		NatureOfBusinessMaxLength:    getEnvInt("NATURE_OF_BUSINESS_MAX_LENGTH", 1000),
+		NatureOfBusinessOverflow:     getEnv("NATURE_OF_BUSINESS_OVERFLOW", "reject"),
+		StrictUUIDs:                  getEnvBool("STRICT_UUIDS", false),
+		EnforceUniqueRegistryNumbers: getEnvBool("ENFORCE_UNIQUE_REGISTRY_NUMBERS", false),
+		DuplicatePolicy:              getEnv("DUPLICATE_POLICY", "warn"),
+		AllowLimitZero:               getEnvBool("ALLOW_LIMIT_ZERO", false),
+		ListDefaultLimit:             getEnvInt("LIST_DEFAULT_LIMIT", 20),
+		ListMaxLimit:                 getEnvInt("LIST_MAX_LIMIT", 100),
+		ListMaxOffset:                getEnvInt("LIST_MAX_OFFSET", 10000),
+		AccentInsensitiveSearch:      getEnvBool("ACCENT_INSENSITIVE_SEARCH", true),
+		MaxFilters:                   getEnvInt("MAX_LIST_FILTERS", 10),
+		ListCacheMaxAge:              getEnvDuration("LIST_CACHE_MAX_AGE", 0),
+
+		MinDirectors:    getEnvIntMap("MIN_DIRECTORS_BY_JURISDICTION"),
+		MaxShareholders: getEnvIntMap("MAX_SHAREHOLDERS_BY_JURISDICTION"),
+		CreateDefaults:  getEnv("CREATE_DEFAULTS_BY_JURISDICTION", ""),
+		AddressRules:    getEnv("ADDRESS_RULES_BY_JURISDICTION", ""),
+		SecCodePattern:  getEnv("SEC_CODE_PATTERN", `^[A-Z]{2,4}[0-9]+$`),
+		SecCodePatterns: getEnv("SEC_CODE_PATTERNS_BY_JURISDICTION", ""),
+		ImportMode:      getEnv("IMPORT_MODE", "lenient"),
+		MaxBodyBytes:    getEnvInt("MAX_BODY_BYTES", 1<<20),
+		MaxImportBytes:  getEnvInt("MAX_IMPORT_BYTES", 10<<20),
+
+		AddressCountryCheck: getEnvStringMap("ADDRESS_COUNTRY_CHECK"),
+		RouteAliases:        getEnvStringMap("ROUTE_ALIASES"),
+		CORSAllowedOrigins:  getEnvList("CORS_ALLOWED_ORIGINS", []string{"http://localhost:5173", "http://localhost:5174"}),
+		ValidJurisdictions:  getEnvList("VALID_JURISDICTIONS", []string{"UK", "Singapore", "Cayman Islands"}),
+		MaxCreatesPerMinute: getEnvInt("MAX_CREATES_PER_MINUTE", 0),
+		RateLimitReadRPS:    getEnvFloat("RATE_LIMIT_READ_RPS", 0),
+		RateLimitReadBurst:  getEnvInt("RATE_LIMIT_READ_BURST", 20),
+		RateLimitWriteRPS:   getEnvFloat("RATE_LIMIT_WRITE_RPS", 0),
+		RateLimitWriteBurst: getEnvInt("RATE_LIMIT_WRITE_BURST", 5),
+		RequestTimeout:      getEnvDuration("REQUEST_TIMEOUT", 10*time.Second),
+		LogBodies:           getEnvBool("LOG_BODIES", false),
+		AccessLogFields:     getEnvList("ACCESS_LOG_FIELDS", []string{"method", "path", "status", "bytes", "duration", "remote_ip", "request_id"}),
+		AccessLogSuccess:    getEnvBool("ACCESS_LOG_SUCCESS", true),
+		LogRedactedFields:   getEnvList("LOG_REDACTED_FIELDS", []string{"company_address"}),
+		LogBodyMaxBytes:     getEnvInt("LOG_BODY_MAX_BYTES", 64<<10),
+		CompressionLevel:    getEnvInt("COMPRESSION_LEVEL", 5),
+		ShutdownTimeout:     getEnvDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+		MigrateOnStartup:    getEnvBool("MIGRATE_ON_STARTUP", true),
+		RequireMigrations:   getEnvBool("REQUIRE_MIGRATIONS", false),
+		IndexAdvisories:     getEnvBool("INDEX_ADVISORIES", false),
+		IdempotencyKeyTTL:   getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		SoftDeleteRetention: getEnvDuration("SOFT_DELETE_RETENTION", 30*24*time.Hour),
+		SnapshotMaxOpen:     getEnvInt("SNAPSHOT_MAX_OPEN", 4),
+		SnapshotIdleTimeout: getEnvDuration("SNAPSHOT_IDLE_TIMEOUT", 30*time.Second),
+		SnapshotMaxLifetime: getEnvDuration("SNAPSHOT_MAX_LIFETIME", 5*time.Minute),
 	}
 }
 
+// IsProduction reports whether the service is running in the production environment
+func (c *Config) IsProduction() bool {
+	return c.Environment == "production"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList parses a comma-separated list, skipping blank entries, or returns defaultValue when unset
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvStringMap parses a comma-separated list of key=value pairs, skipping malformed entries
+func getEnvStringMap(key string) map[string]string {
+	values := map[string]string{}
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		values[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return values
+}
+
+// getEnvIntMap parses a comma-separated list of key=integer pairs, skipping malformed entries
+func getEnvIntMap(key string) map[string]int {
+	values := map[string]int{}
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		if parsed, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			values[strings.TrimSpace(name)] = parsed
+		}
+	}
+	return values
+}
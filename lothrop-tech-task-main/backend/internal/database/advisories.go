@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// indexAdvice describes an index a query feature relies on. An existing companies index satisfies it when its
+// definition, lowercased, contains match.
+type indexAdvice struct {
+	feature    string
+	match      string
+	suggestion string
+}
+
+// expectedIndexes lists the indexes the company queries are written to use
+var expectedIndexes = []indexAdvice{
+	{
+		feature:    "jurisdiction filter",
+		match:      "(lower((jurisdiction)::text))",
+		suggestion: "CREATE INDEX idx_companies_jurisdiction_lower ON companies(LOWER(jurisdiction))",
+	},
+	{
+		feature:    "default list order and cursor pagination",
+		match:      "(date_created desc, id desc)",
+		suggestion: "CREATE INDEX idx_companies_date_created_id ON companies(date_created DESC, id DESC)",
+	},
+	{
+		feature:    "incremental extract",
+		match:      "(date_updated, id)",
+		suggestion: "CREATE INDEX idx_companies_date_updated_id ON companies(date_updated, id)",
+	},
+	{
+		feature:    "registry number lookup",
+		match:      "(registry_source, registry_number)",
+		suggestion: "CREATE INDEX idx_companies_registry ON companies(registry_source, registry_number)",
+	},
+	{
+		feature:    "name search",
+		match:      "gin_trgm_ops",
+		suggestion: "CREATE EXTENSION pg_trgm; CREATE INDEX idx_companies_search_name_trgm ON companies USING gin (search_name gin_trgm_ops)",
+	},
+}
+
+// IndexAdvice is a recommended index missing from the companies table
+type IndexAdvice struct {
+	Feature    string
+	Suggestion string
+}
+
+// IndexAdvisories inspects the companies table's indexes and returns advice for each expected index that is
+// missing. It only reads the catalog; no index is created.
+func IndexAdvisories(ctx context.Context, db *sql.DB) ([]IndexAdvice, error) {
+	rows, err := db.QueryContext(ctx, "SELECT indexdef FROM pg_indexes WHERE tablename = 'companies'")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var definitions []string
+	for rows.Next() {
+		var definition string
+		if err := rows.Scan(&definition); err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return missingIndexes(definitions), nil
+}
+
+// missingIndexes returns advice for each expected index not satisfied by any of the index definitions
+func missingIndexes(definitions []string) []IndexAdvice {
+	var advice []IndexAdvice
+	for _, expected := range expectedIndexes {
+		found := false
+		for _, definition := range definitions {
+			if strings.Contains(strings.ToLower(definition), expected.match) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			advice = append(advice, IndexAdvice{Feature: expected.feature, Suggestion: expected.suggestion})
+		}
+	}
+	return advice
+}
@@ -0,0 +1,45 @@
+package database
+
+import "testing"
+
+// fullSchema is the companies indexes as pg_indexes reports them once every migration is deployed
+var fullSchema = []string{
+	"CREATE UNIQUE INDEX companies_pkey ON public.companies USING btree (id)",
+	"CREATE INDEX idx_companies_jurisdiction_lower ON public.companies USING btree (lower((jurisdiction)::text))",
+	"CREATE INDEX idx_companies_date_created_id ON public.companies USING btree (date_created DESC, id DESC)",
+	"CREATE INDEX idx_companies_date_updated_id ON public.companies USING btree (date_updated, id)",
+	"CREATE INDEX idx_companies_registry ON public.companies USING btree (registry_source, registry_number)",
+	"CREATE INDEX idx_companies_search_name_trgm ON public.companies USING gin (search_name gin_trgm_ops)",
+}
+
+func TestMissingIndexes(t *testing.T) {
+	if advice := missingIndexes(fullSchema); len(advice) != 0 {
+		t.Errorf("advice for the full schema = %v, want none", advice)
+	}
+
+	// Only the primary key and a plain jurisdiction index, which the case-insensitive filter cannot use
+	advice := missingIndexes([]string{
+		fullSchema[0],
+		"CREATE INDEX idx_companies_jurisdiction ON public.companies USING btree (jurisdiction)",
+	})
+	if len(advice) != len(expectedIndexes) {
+		t.Fatalf("advice = %v, want one entry per expected index", advice)
+	}
+	for i, expected := range expectedIndexes {
+		if advice[i].Feature != expected.feature || advice[i].Suggestion != expected.suggestion {
+			t.Errorf("advice[%d] = %+v, want the advice for %s", i, advice[i], expected.feature)
+		}
+	}
+
+	// Dropping a single index reports just that one
+	var withoutExtract []string
+	for _, definition := range fullSchema {
+		if definition != fullSchema[3] {
+			withoutExtract = append(withoutExtract, definition)
+		}
+	}
+	advice = missingIndexes(withoutExtract)
+	if len(advice) != 1 || advice[0].Feature != "incremental extract" {
+		t.Errorf("advice = %v, want only the incremental extract index", advice)
+	}
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// healthCheckTimeout bounds each ping made by the health loop
+const healthCheckTimeout = 2 * time.Second
+
+// Health is the database connection health recorded by the health loop. It is safe for concurrent use, so the
+// readiness probe and metrics can read it while Run updates it.
+type Health struct {
+	mu        sync.RWMutex
+	healthy   bool
+	failures  int
+	lastCheck time.Time
+	lastErr   error
+}
+
+// NewHealth returns a Health that starts healthy, since NewPostgresConnection has just pinged the database
+func NewHealth() *Health {
+	return &Health{healthy: true, lastCheck: time.Now()}
+}
+
+// Healthy reports whether the database answered the most recent health check
+func (h *Health) Healthy() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.healthy
+}
+
+// Status returns the outcome of the most recent health check: whether it succeeded, when it ran, and the error
+// it failed with, nil when healthy
+func (h *Health) Status() (bool, time.Time, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.healthy, h.lastCheck, h.lastErr
+}
+
+// record stores a check's outcome and returns the number of consecutive failures, 0 after a success
+func (h *Health) record(err error) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.healthy = err == nil
+	h.lastCheck = time.Now()
+	h.lastErr = err
+	if err == nil {
+		h.failures = 0
+	} else {
+		h.failures++
+	}
+	return h.failures
+}
+
+// Run pings db every interval until ctx is done, recording each outcome. When the database stops answering,
+// idle pooled connections are closed, so that once Postgres is back queries open fresh connections instead of
+// reusing ones the server dropped when it restarted.
+func (h *Health) Run(ctx context.Context, db *sql.DB, interval time.Duration, maxIdleConns int, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := db.PingContext(pingCtx)
+		cancel()
+
+		// A ping cut short by shutdown says nothing about the database
+		if ctx.Err() != nil {
+			return
+		}
+
+		wasHealthy := h.Healthy()
+		failures := h.record(err)
+		switch {
+		case err != nil:
+			closed := ResetIdleConnections(db, maxIdleConns)
+			logger.Warn("Database health check failed",
+				zap.Int("consecutive_failures", failures), zap.Int("idle_connections_closed", closed), zap.Error(err))
+		case !wasHealthy:
+			logger.Info("Database connection recovered")
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// migrationLockID is the advisory lock key held while migrating, so instances starting together deploy each
+// change once
+const migrationLockID = 7_241_513_960
+
+// querier runs queries on a pool or a single connection
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// PlannedChanges returns the change names of a sqitch plan in deploy order, skipping pragmas, comments and tags
+func PlannedChanges(plan string) []string {
+	var changes []string
+	for _, line := range strings.Split(plan, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '%' || line[0] == '#' || line[0] == '@' {
+			continue
+		}
+		changes = append(changes, strings.Fields(line)[0])
+	}
+	return changes
+}
+
+// PlanProject returns the project named by a sqitch plan's %project pragma, or "" when it has none
+func PlanProject(plan string) string {
+	for _, line := range strings.Split(plan, "\n") {
+		if project, ok := strings.CutPrefix(strings.TrimSpace(line), "%project="); ok {
+			return strings.TrimSpace(project)
+		}
+	}
+	return ""
+}
+
+// PendingMigrations returns the changes in the sqitch plan that are not deployed, in deploy order. A change is
+// deployed when the sqitch registry records it for the plan's project, or when RunMigrations applied it; every
+// change is pending while neither table exists.
+func PendingMigrations(ctx context.Context, db querier, plan string) ([]string, error) {
+	deployed := make(map[string]bool)
+	err := collectChanges(ctx, db, deployed, "sqitch.changes",
+		"SELECT change FROM sqitch.changes WHERE project = $1", PlanProject(plan))
+	if err != nil {
+		return nil, err
+	}
+	if err := collectChanges(ctx, db, deployed, "schema_migrations", "SELECT change FROM schema_migrations"); err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, change := range PlannedChanges(plan) {
+		if !deployed[change] {
+			pending = append(pending, change)
+		}
+	}
+	return pending, nil
+}
+
+// collectChanges adds the changes query returns to deployed, doing nothing when table does not exist
+func collectChanges(ctx context.Context, db querier, deployed map[string]bool, table, query string, args ...any) error {
+	var exists sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass($1)::text", table).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists.Valid {
+		return nil
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var change string
+		if err := rows.Scan(&change); err != nil {
+			return err
+		}
+		deployed[change] = true
+	}
+
+	return rows.Err()
+}
+
+// RunMigrations deploys the pending changes of the sqitch plan in order, running each deploy/<change>.sql script
+// from scripts in a transaction that also records the change in schema_migrations. It holds an advisory lock
+// while it runs and returns the changes it deployed; on failure the failed change and those after it stay
+// pending.
+func RunMigrations(ctx context.Context, db *sql.DB, plan string, scripts fs.FS) ([]string, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return nil, fmt.Errorf("failed to take the migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		change TEXT PRIMARY KEY,
+		deployed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	pending, err := PendingMigrations(ctx, conn, plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending migrations: %w", err)
+	}
+
+	var deployed []string
+	for _, change := range pending {
+		script, err := fs.ReadFile(scripts, "deploy/"+change+".sql")
+		if err != nil {
+			return deployed, fmt.Errorf("migration %s: %w", change, err)
+		}
+		if err := deployChange(ctx, conn, change, string(script)); err != nil {
+			return deployed, fmt.Errorf("migration %s: %w", change, err)
+		}
+		deployed = append(deployed, change)
+	}
+	return deployed, nil
+}
+
+// deployChange runs a deploy script and records its change in one transaction
+func deployChange(ctx context.Context, conn *sql.Conn, change, script string) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, scriptBody(script)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (change) VALUES ($1)", change); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// scriptBody drops the BEGIN; and COMMIT; lines sqitch deploy scripts wrap themselves in, as the runner
+// supplies the transaction
+func scriptBody(script string) string {
+	var body []string
+	for _, line := range strings.Split(script, "\n") {
+		switch strings.ToUpper(strings.TrimSpace(line)) {
+		case "BEGIN;", "COMMIT;":
+			continue
+		}
+		body = append(body, line)
+	}
+	return strings.Join(body, "\n")
+}
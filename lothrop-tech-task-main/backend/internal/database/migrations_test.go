@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"backend/internal/repository/repositorytest"
+	"backend/migrations"
+)
+
+func TestPlannedChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		plan string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"pragmas only", "%syntax-version=1.0.0\n%project=lothrop-backend\n", nil},
+		{
+			"changes in order",
+			"companies 2025-10-16T10:16:04Z Kyle <kyle@example.com> # Create companies\n" +
+				"directors 2026-10-16T19:00:00Z Kyle <kyle@example.com> # Store directors\n",
+			[]string{"companies", "directors"},
+		},
+		{
+			"dependencies are not changes",
+			"companies_name_key [companies_soft_delete companies_search_name] 2026-10-16T17:00:00Z Kyle <kyle@example.com>",
+			[]string{"companies_name_key"},
+		},
+		{
+			"comments, tags and blank lines skipped",
+			"# A comment\n\ncompanies 2025-10-16T10:16:04Z Kyle <kyle@example.com>\n" +
+				"@v1.0 2025-10-17T00:00:00Z Kyle <kyle@example.com> # Tag the first release\n" +
+				"  \ndirectors [companies] 2026-10-16T19:00:00Z Kyle <kyle@example.com>\n",
+			[]string{"companies", "directors"},
+		},
+		{"indented lines", "  companies 2025-10-16T10:16:04Z Kyle <kyle@example.com>\r\n", []string{"companies"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlannedChanges(tt.plan); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("PlannedChanges = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanProject(t *testing.T) {
+	if got := PlanProject(migrations.Plan); got != "lothrop-backend" {
+		t.Errorf("PlanProject(sqitch.plan) = %q, want lothrop-backend", got)
+	}
+	if got := PlanProject("companies 2025-10-16T10:16:04Z Kyle <kyle@example.com>"); got != "" {
+		t.Errorf("PlanProject without a pragma = %q, want empty", got)
+	}
+}
+
+// testPlan is a three-change plan for the pending and runner tests
+const testPlan = `%syntax-version=1.0.0
+%project=lothrop-backend
+
+companies 2025-10-16T10:16:04Z Kyle <kyle@example.com> # Create companies
+directors [companies] 2026-10-16T19:00:00Z Kyle <kyle@example.com> # Store directors
+@v1.0 2026-10-16T19:30:00Z Kyle <kyle@example.com> # Tag
+shareholders [companies] 2026-10-16T22:00:00Z Kyle <kyle@example.com> # Store shareholders
+`
+
+// registry stands in for a database's migration tables: sqitch maps each project to the changes the sqitch
+// registry records, schemaMigrations lists the changes RunMigrations recorded, and a nil map or slice is a
+// table that does not exist. It records every statement it is sent.
+type registry struct {
+	sqitch           map[string][]string
+	schemaMigrations []string
+	fail             string
+
+	mu         sync.Mutex
+	statements []string
+}
+
+// respond answers the queries PendingMigrations and RunMigrations send, failing any statement containing fail
+func (r *registry) respond(query string, args []driver.NamedValue) (*repositorytest.Rows, error) {
+	r.mu.Lock()
+	r.statements = append(r.statements, query)
+	r.mu.Unlock()
+
+	changes := func(names []string) *repositorytest.Rows {
+		rows := &repositorytest.Rows{Columns: []string{"change"}}
+		for _, name := range names {
+			rows.Values = append(rows.Values, []driver.Value{name})
+		}
+		return rows
+	}
+
+	switch {
+	case r.fail != "" && strings.Contains(query, r.fail):
+		return nil, errors.New("syntax error")
+	case strings.Contains(query, "to_regclass"):
+		exists := map[string]bool{"sqitch.changes": r.sqitch != nil, "schema_migrations": r.schemaMigrations != nil}
+		if exists[args[0].Value.(string)] {
+			return &repositorytest.Rows{Columns: []string{"to_regclass"}, Values: [][]driver.Value{{args[0].Value}}}, nil
+		}
+		return &repositorytest.Rows{Columns: []string{"to_regclass"}, Values: [][]driver.Value{{nil}}}, nil
+	case strings.Contains(query, "FROM sqitch.changes"):
+		return changes(r.sqitch[args[0].Value.(string)]), nil
+	case strings.Contains(query, "FROM schema_migrations"):
+		return changes(r.schemaMigrations), nil
+	}
+	return &repositorytest.Rows{}, nil
+}
+
+func TestPendingMigrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry *registry
+		want     []string
+	}{
+		{"fresh database", &registry{}, []string{"companies", "directors", "shareholders"}},
+		{
+			"deployed with sqitch",
+			&registry{sqitch: map[string][]string{"lothrop-backend": {"companies", "directors"}}},
+			[]string{"shareholders"},
+		},
+		{
+			"another project's changes ignored",
+			&registry{sqitch: map[string][]string{"billing": {"companies", "directors", "shareholders"}}},
+			[]string{"companies", "directors", "shareholders"},
+		},
+		{"deployed by the runner", &registry{schemaMigrations: []string{"companies"}}, []string{"directors", "shareholders"}},
+		{
+			"deployed by both",
+			&registry{
+				sqitch:           map[string][]string{"lothrop-backend": {"companies"}},
+				schemaMigrations: []string{"shareholders"},
+			},
+			[]string{"directors"},
+		},
+		{
+			"up to date",
+			&registry{sqitch: map[string][]string{"lothrop-backend": {"companies", "directors", "shareholders"}}},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := repositorytest.OpenDB(t, tt.registry.respond)
+			got, err := PendingMigrations(context.Background(), db, testPlan)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("pending = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// testScripts are the deploy scripts of testPlan, wrapped in a transaction as sqitch writes them
+var testScripts = fstest.MapFS{
+	"deploy/companies.sql":    {Data: []byte("-- Deploy companies\n\nBEGIN;\n\nCREATE TABLE companies (id UUID);\n\nCOMMIT;\n")},
+	"deploy/directors.sql":    {Data: []byte("BEGIN;\nCREATE TABLE directors (id UUID);\ncommit;\n")},
+	"deploy/shareholders.sql": {Data: []byte("BEGIN;\nCREATE TABLE shareholders (id UUID);\nCOMMIT;\n")},
+}
+
+func TestRunMigrations(t *testing.T) {
+	reg := &registry{sqitch: map[string][]string{"lothrop-backend": {"companies"}}}
+	db, fake := repositorytest.OpenDB(t, reg.respond)
+
+	deployed, err := RunMigrations(context.Background(), db, testPlan, testScripts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(deployed, ",") != "directors,shareholders" {
+		t.Errorf("deployed = %v, want the changes sqitch has not deployed, in plan order", deployed)
+	}
+	if open := fake.OpenTxs(); open != 0 {
+		t.Errorf("open transactions = %d, want every change committed", open)
+	}
+
+	// Each script runs without its own BEGIN and COMMIT, followed by its schema_migrations row
+	var scripts []string
+	for i, statement := range reg.statements {
+		if strings.Contains(statement, "CREATE TABLE") && !strings.Contains(statement, "schema_migrations") {
+			scripts = append(scripts, statement)
+			if next := reg.statements[i+1]; !strings.HasPrefix(next, "INSERT INTO schema_migrations") {
+				t.Errorf("script %q followed by %q, want its change recorded", statement, next)
+			}
+		}
+	}
+	if len(scripts) != 2 || !strings.Contains(scripts[0], "directors") || !strings.Contains(scripts[1], "shareholders") {
+		t.Fatalf("ran scripts %q, want directors then shareholders", scripts)
+	}
+	for _, script := range scripts {
+		if upper := strings.ToUpper(script); strings.Contains(upper, "BEGIN;") || strings.Contains(upper, "COMMIT;") {
+			t.Errorf("script %q still opens or commits its own transaction", script)
+		}
+	}
+
+	// The advisory lock is released
+	if last := reg.statements[len(reg.statements)-1]; !strings.Contains(last, "pg_advisory_unlock") {
+		t.Errorf("last statement = %q, want the migration lock released", last)
+	}
+}
+
+func TestRunMigrationsStopsAtAFailedChange(t *testing.T) {
+	reg := &registry{fail: "CREATE TABLE directors"}
+	db, fake := repositorytest.OpenDB(t, reg.respond)
+
+	deployed, err := RunMigrations(context.Background(), db, testPlan, testScripts)
+	if err == nil || !strings.Contains(err.Error(), "migration directors") {
+		t.Errorf("err = %v, want the failed change named", err)
+	}
+	if strings.Join(deployed, ",") != "companies" {
+		t.Errorf("deployed = %v, want only the change before the failure", deployed)
+	}
+	if open := fake.OpenTxs(); open != 0 {
+		t.Errorf("open transactions = %d, want the failed change rolled back", open)
+	}
+	for _, statement := range reg.statements {
+		if strings.Contains(statement, "shareholders") {
+			t.Errorf("ran %q after the failed change", statement)
+		}
+	}
+
+	if _, err := RunMigrations(context.Background(), db, testPlan, fstest.MapFS{}); err == nil {
+		t.Error("missing deploy script: err = nil, want an error")
+	}
+}
+
+func TestEmbeddedScriptsCoverThePlan(t *testing.T) {
+	for _, change := range PlannedChanges(migrations.Plan) {
+		if _, err := migrations.Scripts.Open("deploy/" + change + ".sql"); err != nil {
+			t.Errorf("change %s has no embedded deploy script: %v", change, err)
+		}
+	}
+}
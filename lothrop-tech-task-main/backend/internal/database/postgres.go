@@ -1,28 +1,118 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"backend/internal/config"
 
 	_ "github.com/lib/pq"
+	"go.uber.org/zap"
 )
 
-// NewPostgresConnection creates a new PostgreSQL database connection
-func NewPostgresConnection(cfg *config.Config) (*sql.DB, error) {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		cfg.PostgresHost, cfg.PostgresPort, cfg.PostgresUser, cfg.PostgresPass, cfg.PostgresDB)
+const (
+	// connectBaseDelay is the wait after the first failed startup ping; each later wait is twice as long
+	connectBaseDelay = 500 * time.Millisecond
+	// connectMaxDelay caps the wait between startup pings
+	connectMaxDelay = 10 * time.Second
+	// connectPingTimeout bounds a single startup ping, e.g. to a host that drops packets
+	connectPingTimeout = 5 * time.Second
+)
 
-	db, err := sql.Open("postgres", connStr)
+// NewPostgresConnection creates a new PostgreSQL database connection, waiting for the database to answer. A
+// database that is not up yet, e.g. while an orchestrator starts it alongside the server, is pinged again with
+// exponential backoff until cfg.DBConnectMaxAttempts pings have failed or cfg.DBConnectTimeout has passed.
+func NewPostgresConnection(cfg *config.Config, logger *zap.Logger) (*sql.DB, error) {
+	db, err := sql.Open("postgres", ConnString(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
-	// Test the connection
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	if err := waitForDatabase(db, cfg.DBConnectMaxAttempts, cfg.DBConnectTimeout, logger); err != nil {
+		db.Close()
+		return nil, err
 	}
 
 	return db, nil
 }
+
+// waitForDatabase pings db until it answers, logging each failed attempt. It gives up after maxAttempts pings,
+// or rather than wait past timeout when timeout is positive.
+func waitForDatabase(db *sql.DB, maxAttempts int, timeout time.Duration, logger *zap.Logger) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	delay := connectBaseDelay
+	for attempt := 1; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, connectPingTimeout)
+		err := db.PingContext(pingCtx)
+		cancel()
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("Connected to database", zap.Int("attempts", attempt))
+			}
+			return nil
+		}
+
+		if attempt >= maxAttempts {
+			return fmt.Errorf("failed to ping database after %d attempts: %w", attempt, err)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return fmt.Errorf("failed to ping database within %s: %w", timeout, err)
+		}
+
+		logger.Warn("Database not available, retrying",
+			zap.Int("attempt", attempt), zap.Int("max_attempts", maxAttempts), zap.Duration("retry_in", delay), zap.Error(err))
+		time.Sleep(delay)
+		delay = min(delay*2, connectMaxDelay)
+	}
+}
+
+// ConnString builds the lib/pq connection string for the given configuration. A statement timeout is passed as
+// the statement_timeout run-time parameter, so Postgres cancels any statement running longer on every connection.
+func ConnString(cfg *config.Config) string {
+	conn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable application_name=%s",
+		cfg.PostgresHost, cfg.PostgresPort, cfg.PostgresUser, cfg.PostgresPass, cfg.PostgresDB,
+		quoteConnValue(applicationName(cfg)))
+	if cfg.DBStatementTimeout > 0 {
+		conn += fmt.Sprintf(" statement_timeout=%d", cfg.DBStatementTimeout.Milliseconds())
+	}
+	return conn
+}
+
+// applicationName returns the application_name reported to Postgres, suffixed with the instance name when set
+func applicationName(cfg *config.Config) string {
+	if cfg.InstanceName == "" {
+		return cfg.PostgresAppName
+	}
+	return cfg.PostgresAppName + "/" + cfg.InstanceName
+}
+
+// quoteConnValue quotes a key/value connection string value so spaces and quotes survive parsing
+func quoteConnValue(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return "'" + escaped + "'"
+}
+
+// ResetIdleConnections closes every idle pooled connection so subsequent queries open fresh ones,
+// then restores the idle pool size to maxIdleConns. It returns the number of idle connections that were closed.
+func ResetIdleConnections(db *sql.DB, maxIdleConns int) int {
+	closed := db.Stats().Idle
+
+	// Shrinking the idle pool to zero closes idle connections immediately
+	db.SetMaxIdleConns(0)
+	db.SetMaxIdleConns(maxIdleConns)
+
+	return closed
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/config"
+
+	"github.com/lib/pq"
+)
+
+func TestConnStringApplicationName(t *testing.T) {
+	tests := []struct {
+		name     string
+		appName  string
+		instance string
+		want     string
+	}{
+		{"app name only", "lothrop-backend", "", `application_name='lothrop-backend'`},
+		{"with instance", "lothrop-backend", "api-1", `application_name='lothrop-backend/api-1'`},
+		{"space", "lothrop backend", "", `application_name='lothrop backend'`},
+		{"quote", "kyle's backend", "", `application_name='kyle\'s backend'`},
+		{"backslash", `lothrop\backend`, `api\1`, `application_name='lothrop\\backend/api\\1'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				PostgresHost:    "localhost",
+				PostgresPort:    "5432",
+				PostgresUser:    "postgres",
+				PostgresPass:    "secret",
+				PostgresDB:      "lothrop",
+				PostgresAppName: tt.appName,
+				InstanceName:    tt.instance,
+			}
+
+			conn := ConnString(cfg)
+			if !strings.Contains(conn, " "+tt.want) {
+				t.Errorf("ConnString = %q, want it to contain %s", conn, tt.want)
+			}
+			if _, err := pq.NewConnector(conn); err != nil {
+				t.Errorf("lib/pq cannot parse %q: %v", conn, err)
+			}
+		})
+	}
+}
+
+func TestConnStringStatementTimeout(t *testing.T) {
+	cfg := &config.Config{PostgresAppName: "lothrop-backend"}
+	if conn := ConnString(cfg); strings.Contains(conn, "statement_timeout") {
+		t.Errorf("ConnString without a timeout = %q, want no statement_timeout", conn)
+	}
+
+	cfg.DBStatementTimeout = 1500 * time.Millisecond
+	if conn := ConnString(cfg); !strings.HasSuffix(conn, " statement_timeout=1500") {
+		t.Errorf("ConnString = %q, want statement_timeout=1500", conn)
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"backend/api"
+	"backend/internal/database"
+	"backend/internal/response"
+
+	"go.uber.org/zap"
+)
+
+// AdminHandlers contains the HTTP handlers for operational admin endpoints
+type AdminHandlers struct {
+	db           *sql.DB
+	maxIdleConns int
+	logger       *zap.Logger
+}
+
+// NewAdminHandlers creates a new admin handlers instance. maxIdleConns is the configured idle pool
+// size restored after a pool reset.
+func NewAdminHandlers(db *sql.DB, maxIdleConns int, logger *zap.Logger) *AdminHandlers {
+	return &AdminHandlers{
+		db:           db,
+		maxIdleConns: maxIdleConns,
+		logger:       logger,
+	}
+}
+
+// ResetPool handles POST /api/v1/admin/db/reset-pool
+func (h *AdminHandlers) ResetPool(w http.ResponseWriter, r *http.Request) {
+	closed := database.ResetIdleConnections(h.db, h.maxIdleConns)
+	requestLogger(h.logger, r).Warn("Database connection pool reset", zap.Int("closed_idle_connections", closed))
+
+	if err := response.WriteJSON(w, http.StatusOK, api.PoolResetResponse{ClosedIdleConnections: closed}); err != nil {
+		requestLogger(h.logger, r).Error("Failed to encode JSON response", zap.Error(err))
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/api"
+	"backend/internal/repository/repositorytest"
+
+	"go.uber.org/zap"
+)
+
+func TestResetPool(t *testing.T) {
+	db, pool := repositorytest.OpenDB(t, nil)
+	db.SetMaxIdleConns(5)
+
+	// Check out three connections at once, then return them to the pool as idle connections
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+	if idle := db.Stats().Idle; idle != 3 {
+		t.Fatalf("idle connections before the reset = %d, want 3", idle)
+	}
+
+	rec := httptest.NewRecorder()
+	NewAdminHandlers(db, 5, zap.NewNop()).ResetPool(rec, httptest.NewRequest(http.MethodPost, "/api/v1/admin/db/reset-pool", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var body api.PoolResetResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.ClosedIdleConnections != 3 {
+		t.Errorf("closed_idle_connections = %d, want 3", body.ClosedIdleConnections)
+	}
+	if open := pool.OpenConns(); open != 0 {
+		t.Errorf("open connections after the reset = %d, want 0", open)
+	}
+
+	// The idle pool size is restored, so a returned connection is kept for reuse again
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if idle := db.Stats().Idle; idle != 1 {
+		t.Errorf("idle connections after reuse = %d, want 1", idle)
+	}
+}
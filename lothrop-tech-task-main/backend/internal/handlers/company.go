@@ -1,11 +1,21 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"backend/api"
+	"backend/internal/i18n"
+	"backend/internal/metrics"
+	appmiddleware "backend/internal/middleware"
+	"backend/internal/response"
 	"backend/internal/service"
 
 	"github.com/google/uuid"
@@ -15,157 +25,1210 @@ import (
 	"go.uber.org/zap"
 )
 
+// Options configures optional company handler behaviour
+type Options struct {
+	// StrictUUIDs only accepts company IDs in canonical lowercase hyphenated form,
+	// rejecting braced, URN-prefixed, unhyphenated and uppercase variants
+	StrictUUIDs bool
+
+	// ImportStrict makes CSV imports strict by default, creating nothing if any row is invalid
+	ImportStrict bool
+
+	// MaxBodyBytes caps JSON request bodies on create, batch, update and patch; 0 disables the cap
+	MaxBodyBytes int64
+
+	// MaxImportBytes caps CSV import uploads; 0 disables the cap
+	MaxImportBytes int64
+
+	// AdminToken is the bearer token unlocking admin-only list options such as include_deleted;
+	// they are rejected when empty
+	AdminToken string
+
+	// DebugToken is the bearer token unlocking debug features such as explain and the raw row view;
+	// debug features are rejected when empty, which must always be the case in production
+	DebugToken string
+
+	// ListCacheMaxAge is sent as Cache-Control max-age on company list responses; 0 sends no Cache-Control
+	ListCacheMaxAge time.Duration
+
+	// ListMaxOffset is the largest list offset the service accepts, so Link headers leave out pages past it;
+	// 0 means offsets are not capped
+	ListMaxOffset int
+}
+
 // CompanyHandlers contains the HTTP handlers for company operations
 type CompanyHandlers struct {
 	service service.CompanyService
 	logger  *zap.Logger
+	opts    Options
 }
 
 // NewCompanyHandlers creates a new company handlers instance
-func NewCompanyHandlers(service service.CompanyService, logger *zap.Logger) *CompanyHandlers {
+func NewCompanyHandlers(service service.CompanyService, logger *zap.Logger, opts Options) *CompanyHandlers {
 	return &CompanyHandlers{
 		service: service,
 		logger:  logger,
+		opts:    opts,
 	}
 }
 
 // GetCompanies handles GET /api/v1/companies
 func (h *CompanyHandlers) GetCompanies(w http.ResponseWriter, r *http.Request) {
-	h.logger.Info("Getting companies list")
+	h.log(r).Info("Getting companies list")
 
 	// Parse query parameters
 	params := api.GetCompaniesParams{}
 
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			params.Limit = &limit
+	var ok bool
+	if params.Limit, ok = h.parsePageParam(w, r, "limit"); !ok {
+		return
+	}
+	if params.Offset, ok = h.parsePageParam(w, r, "offset"); !ok {
+		return
+	}
+
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		params.Cursor = &cursor
+	}
+
+	if !h.parseFilterParams(w, r, &params) {
+		return
+	}
+
+	// q is list-only, since its matches are ranked by relevance
+	if q := r.URL.Query().Get("q"); q != "" {
+		params.Q = &q
+	}
+
+	if idOnlyStr := r.URL.Query().Get("id_only"); idOnlyStr != "" {
+		if idOnly, err := strconv.ParseBool(idOnlyStr); err == nil {
+			params.IdOnly = &idOnly
+		} else {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid id_only parameter")
+			return
+		}
+	}
+
+	if paginationStr := r.URL.Query().Get("pagination"); paginationStr != "" {
+		pagination := api.GetCompaniesParamsPagination(paginationStr)
+		if pagination != api.Envelope && pagination != api.Header {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid pagination parameter: must be envelope or header")
+			return
+		}
+		params.Pagination = &pagination
+	}
+	headerPagination := params.Pagination != nil && *params.Pagination == api.Header
+
+	if sortStr := r.URL.Query().Get("sort"); sortStr != "" {
+		sort := api.GetCompaniesParamsSort(sortStr)
+		switch sort {
+		case api.CompanyName, api.Jurisdiction, api.DateCreated, api.DateUpdated:
+			params.Sort = &sort
+		default:
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid sort parameter: must be company_name, jurisdiction, date_created or date_updated")
+			return
+		}
+	}
+
+	if orderStr := r.URL.Query().Get("order"); orderStr != "" {
+		order := api.GetCompaniesParamsOrder(strings.ToLower(orderStr))
+		if order != api.Asc && order != api.Desc {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid order parameter: must be asc or desc")
+			return
+		}
+		if params.Sort == nil {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "The order parameter requires sort")
+			return
+		}
+		params.Order = &order
+	}
+
+	if includeDeletedStr := r.URL.Query().Get("include_deleted"); includeDeletedStr != "" {
+		if includeDeleted, err := strconv.ParseBool(includeDeletedStr); err == nil {
+			params.IncludeDeleted = &includeDeleted
 		} else {
-			h.sendErrorResponse(w, http.StatusBadRequest, "Invalid limit parameter")
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid include_deleted parameter")
 			return
 		}
 	}
 
-	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil {
-			params.Offset = &offset
+	if params.IncludeDeleted != nil && *params.IncludeDeleted && !appmiddleware.HasAdminToken(r, h.opts.AdminToken) {
+		h.sendErrorResponse(w, r, http.StatusForbidden, "include_deleted requires the admin token")
+		return
+	}
+
+	if includeTotalStr := r.URL.Query().Get("include_total"); includeTotalStr != "" {
+		if includeTotal, err := strconv.ParseBool(includeTotalStr); err == nil {
+			params.IncludeTotal = &includeTotal
 		} else {
-			h.sendErrorResponse(w, http.StatusBadRequest, "Invalid offset parameter")
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid include_total parameter")
 			return
 		}
 	}
 
-	if jurisdictionStr := r.URL.Query().Get("jurisdiction"); jurisdictionStr != "" {
-		jurisdiction := api.GetCompaniesParamsJurisdiction(jurisdictionStr)
-		params.Jurisdiction = &jurisdiction
+	if debugQueryStr := r.URL.Query().Get("debug_query"); debugQueryStr != "" {
+		if debugQuery, err := strconv.ParseBool(debugQueryStr); err == nil {
+			params.DebugQuery = &debugQuery
+		} else {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid debug_query parameter")
+			return
+		}
+	}
+
+	if params.DebugQuery != nil && *params.DebugQuery {
+		if !appmiddleware.HasAdminToken(r, h.opts.DebugToken) {
+			h.sendErrorResponse(w, r, http.StatusForbidden, "debug_query is not available")
+			return
+		}
+		if headerPagination || (params.IdOnly != nil && *params.IdOnly) {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "debug_query is only available with the envelope response")
+			return
+		}
+	}
+
+	if explainStr := r.URL.Query().Get("explain"); explainStr != "" {
+		if explain, err := strconv.ParseBool(explainStr); err == nil {
+			params.Explain = &explain
+		} else {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid explain parameter")
+			return
+		}
+	}
+
+	if params.Explain != nil && *params.Explain {
+		h.explainCompanies(w, r, params)
+		return
+	}
+
+	fields, err := parseFields(r)
+	if err != nil {
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid fields parameter: "+err.Error())
+		return
+	}
+
+	if params.IdOnly != nil && *params.IdOnly {
+		if fields != nil {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "The fields parameter cannot be combined with id_only")
+			return
+		}
+
+		response, err := h.service.ListCompanyIDs(r.Context(), params)
+		if err != nil {
+			h.sendServiceError(w, r, err, "Failed to get company IDs", "Failed to retrieve companies")
+			return
+		}
+
+		h.setPageLinks(w, r, params, response.Limit, response.Offset, response.Total, response.HasNext)
+
+		if headerPagination {
+			setTotalCount(w, response.Total)
+			h.sendListResponse(w, r, response.Ids)
+			return
+		}
+
+		h.sendListResponse(w, r, response)
+		return
 	}
 
 	// Call service
 	response, err := h.service.ListCompanies(r.Context(), params)
 	if err != nil {
-		h.logger.Error("Failed to get companies", zap.Error(err))
-		h.sendErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve companies")
+		h.sendServiceError(w, r, err, "Failed to get companies", "Failed to retrieve companies")
 		return
 	}
 
-	h.sendJSONResponse(w, http.StatusOK, response)
+	h.setPageLinks(w, r, params, response.Limit, response.Offset, response.Total, response.HasNext)
+
+	if headerPagination {
+		companies := response.Companies
+		if companies == nil {
+			companies = []api.Company{}
+		}
+
+		setTotalCount(w, response.Total)
+		if fields != nil {
+			sparse, err := fields.sparseCompanies(companies)
+			if err != nil {
+				h.sendServiceError(w, r, err, "Failed to select company fields", "Failed to retrieve companies")
+				return
+			}
+			h.sendListResponse(w, r, sparse)
+			return
+		}
+		h.sendListResponse(w, r, companies)
+		return
+	}
+
+	if fields != nil {
+		sparse, err := fields.sparseCompanies(response.Companies)
+		if err != nil {
+			h.sendServiceError(w, r, err, "Failed to select company fields", "Failed to retrieve companies")
+			return
+		}
+		h.sendListResponse(w, r, sparseCompaniesResponse{CompaniesResponse: response, Companies: sparse})
+		return
+	}
+
+	h.sendListResponse(w, r, response)
+}
+
+// sendListResponse sends a 200 company list response with an ETag over its body, and a Cache-Control max-age when
+// ListCacheMaxAge is set. A request whose If-None-Match still matches gets a bodiless 304 instead.
+func (h *CompanyHandlers) sendListResponse(w http.ResponseWriter, r *http.Request, data interface{}) {
+	if h.opts.ListCacheMaxAge > 0 {
+		w.Header().Set("Cache-Control", "max-age="+strconv.Itoa(int(h.opts.ListCacheMaxAge.Seconds())))
+	}
+
+	etag, err := companyETag(data)
+	if err != nil {
+		h.log(r).Error("Failed to compute company list ETag", zap.Error(err))
+	} else {
+		w.Header().Set("ETag", etag)
+		if etagMatches(r, etag) {
+			response.WriteNotModified(w)
+			return
+		}
+	}
+
+	h.sendResponse(w, r, http.StatusOK, data)
+}
+
+// parseFilterParams parses the list filter query parameters shared by the JSON list and CSV export into params.
+// It writes a 400 and returns false if any is malformed.
+func (h *CompanyHandlers) parseFilterParams(w http.ResponseWriter, r *http.Request, params *api.GetCompaniesParams) bool {
+	var jurisdictions []string
+	for _, jurisdiction := range r.URL.Query()["jurisdiction"] {
+		if jurisdiction = strings.TrimSpace(jurisdiction); jurisdiction != "" {
+			jurisdictions = append(jurisdictions, jurisdiction)
+		}
+	}
+	if len(jurisdictions) > 0 {
+		params.Jurisdiction = &jurisdictions
+	}
+
+	if search := r.URL.Query().Get("search"); search != "" {
+		params.Search = &search
+	}
+
+	if createdAfterStr := r.URL.Query().Get("created_after"); createdAfterStr != "" {
+		if createdAfter, err := time.Parse(time.RFC3339, createdAfterStr); err == nil {
+			params.CreatedAfter = &createdAfter
+		} else {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid created_after parameter: must be an RFC3339 timestamp")
+			return false
+		}
+	}
+
+	if createdBeforeStr := r.URL.Query().Get("created_before"); createdBeforeStr != "" {
+		if createdBefore, err := time.Parse(time.RFC3339, createdBeforeStr); err == nil {
+			params.CreatedBefore = &createdBefore
+		} else {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid created_before parameter: must be an RFC3339 timestamp")
+			return false
+		}
+	}
+
+	if recentStr := r.URL.Query().Get("recent_minutes"); recentStr != "" {
+		if recent, err := strconv.Atoi(recentStr); err == nil {
+			params.RecentMinutes = &recent
+		} else {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid recent_minutes parameter")
+			return false
+		}
+	}
+
+	return true
+}
+
+// CountCompanies handles GET /api/v1/companies/count
+func (h *CompanyHandlers) CountCompanies(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Counting companies")
+
+	params := api.GetCompaniesParams{}
+	if !h.parseFilterParams(w, r, &params) {
+		return
+	}
+
+	count, err := h.service.CountCompanies(r.Context(), params)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to count companies", "Failed to count companies")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, count)
+}
+
+// ChecksumCompanies handles GET /api/v1/companies/checksum
+func (h *CompanyHandlers) ChecksumCompanies(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Checksumming companies")
+
+	params := api.GetCompaniesParams{}
+	if !h.parseFilterParams(w, r, &params) {
+		return
+	}
+
+	checksum, err := h.service.ChecksumCompanies(r.Context(), params)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to checksum companies", "Failed to checksum companies")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, checksum)
+}
+
+// explainCompanies writes the list query plan as plain text instead of running the list
+func (h *CompanyHandlers) explainCompanies(w http.ResponseWriter, r *http.Request, params api.GetCompaniesParams) {
+	if !appmiddleware.HasAdminToken(r, h.opts.DebugToken) {
+		h.sendErrorResponse(w, r, http.StatusForbidden, "explain is not available")
+		return
+	}
+
+	plan, err := h.service.ExplainListCompanies(r.Context(), params)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to explain companies query", "Failed to explain companies query")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(plan)); err != nil {
+		h.log(r).Error("Failed to write query plan", zap.Error(err))
+	}
+}
+
+// setPageLinks sets the RFC 5988 Link header for offset pagination. Count-only (limit=0) and
+// cursor-paginated responses have no offset pages to link to.
+func (h *CompanyHandlers) setPageLinks(w http.ResponseWriter, r *http.Request, params api.GetCompaniesParams, limit, offset, total int, hasNext bool) {
+	if limit == 0 || params.Cursor != nil {
+		return
+	}
+	setLinkHeader(w, r, limit, paginationLinks(limit, offset, total, h.opts.ListMaxOffset, hasNext))
+}
+
+// setTotalCount sets the X-Total-Count header, unless the total was not counted (include_total=false)
+func setTotalCount(w http.ResponseWriter, total int) {
+	if total < 0 {
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 }
 
 // GetCompanyByID handles GET /api/v1/companies/{id}
 func (h *CompanyHandlers) GetCompanyByID(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
-	h.logger.Info("Getting company by ID", zap.String("id", idStr))
+	h.log(r).Info("Getting company by ID", zap.String("id", idStr))
 
 	// Parse UUID
-	parsedID, err := uuid.Parse(idStr)
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	fields, err := parseFields(r)
+	if err != nil {
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid fields parameter: "+err.Error())
+		return
+	}
+
+	embed, err := parseEmbed(r)
 	if err != nil {
-		h.logger.Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
-		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid company ID format")
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid embed parameter: "+err.Error())
 		return
 	}
-	id := openapi_types.UUID(parsedID)
+
+	// Embedded collections are part of the selection, whatever fields names
+	if fields != nil {
+		for _, relation := range embed {
+			fields[relation] = true
+		}
+	}
 
 	// Call service
+	company, err := h.service.GetCompanyWithRelations(r.Context(), id, embed)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to get company", "Failed to retrieve company")
+		return
+	}
+
+	// Polling clients revalidate with If-None-Match or If-Modified-Since and get a bodiless 304 while the
+	// company is unchanged
+	if h.setCompanyValidators(w, r, company) {
+		response.WriteNotModified(w)
+		return
+	}
+
+	if fields != nil {
+		sparse, err := fields.sparseCompany(*company)
+		if err != nil {
+			h.sendServiceError(w, r, err, "Failed to select company fields", "Failed to retrieve company")
+			return
+		}
+		h.sendResponse(w, r, http.StatusOK, sparse)
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, company)
+}
+
+// HeadCompany handles HEAD /api/v1/companies/{id}, a bodiless existence check answering with the same
+// ETag and Last-Modified as a plain GET, e.g. before issuing a PUT
+func (h *CompanyHandlers) HeadCompany(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Checking company exists", zap.String("id", idStr))
+
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
 	company, err := h.service.GetCompanyByID(r.Context(), id)
 	if err != nil {
-		if err.Error() == "company not found" {
-			h.sendErrorResponse(w, http.StatusNotFound, "Company not found")
+		h.sendServiceError(w, r, err, "Failed to check company", "Failed to retrieve company")
+		return
+	}
+
+	if h.setCompanyValidators(w, r, company) {
+		response.WriteNotModified(w)
+		return
+	}
+	response.Vary(w)
+	w.WriteHeader(http.StatusOK)
+}
+
+// setCompanyValidators sets the company's ETag and Last-Modified headers and reports whether the request's
+// If-None-Match still matches the ETag or, without one, whether If-Modified-Since is not before date_updated
+func (h *CompanyHandlers) setCompanyValidators(w http.ResponseWriter, r *http.Request, company *api.Company) bool {
+	w.Header().Set("Last-Modified", company.DateUpdated.UTC().Format(http.TimeFormat))
+
+	etag, err := companyETag(company)
+	if err != nil {
+		h.log(r).Error("Failed to compute company ETag", zap.Error(err))
+		return notModifiedSince(r, company.DateUpdated)
+	}
+	w.Header().Set("ETag", etag)
+	return etagMatches(r, etag) || notModifiedSince(r, company.DateUpdated)
+}
+
+// GetRawCompany handles GET /api/v1/debug/companies/{id}/raw
+func (h *CompanyHandlers) GetRawCompany(w http.ResponseWriter, r *http.Request) {
+	if !appmiddleware.HasAdminToken(r, h.opts.DebugToken) {
+		h.sendErrorResponse(w, r, http.StatusForbidden, "raw company view is not available")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Getting raw company row", zap.String("id", idStr))
+
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	row, err := h.service.GetRawCompany(r.Context(), id)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to get raw company", "Failed to retrieve company")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, row)
+}
+
+// ExtractCompanies handles GET /api/v1/companies/extract
+func (h *CompanyHandlers) ExtractCompanies(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Extracting changed companies")
+
+	params := api.ExtractCompaniesParams{}
+
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		if since, err := time.Parse(time.RFC3339, sinceStr); err == nil {
+			params.Since = &since
+		} else {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid since parameter: must be an RFC3339 timestamp")
 			return
 		}
-		h.logger.Error("Failed to get company", zap.Error(err))
-		h.sendErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve company")
+	}
+
+	if sinceIDStr := r.URL.Query().Get("since_id"); sinceIDStr != "" {
+		if sinceID, err := uuid.Parse(sinceIDStr); err == nil {
+			params.SinceId = &sinceID
+		} else {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid since_id parameter")
+			return
+		}
+	}
+
+	var ok bool
+	if params.Limit, ok = h.parsePageParam(w, r, "limit"); !ok {
+		return
+	}
+
+	response, err := h.service.ExtractCompanies(r.Context(), params)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to extract companies", "Failed to extract companies")
 		return
 	}
 
-	h.sendJSONResponse(w, http.StatusOK, company)
+	h.sendResponse(w, r, http.StatusOK, response)
+}
+
+// GetCompanyByRegistry handles GET /api/v1/companies/by-registry
+func (h *CompanyHandlers) GetCompanyByRegistry(w http.ResponseWriter, r *http.Request) {
+	source := r.URL.Query().Get("source")
+	number := r.URL.Query().Get("number")
+	h.log(r).Info("Getting company by registry number", zap.String("source", source), zap.String("number", number))
+
+	if strings.TrimSpace(source) == "" || strings.TrimSpace(number) == "" {
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Query parameters source and number are required")
+		return
+	}
+
+	company, err := h.service.GetCompanyByRegistry(r.Context(), source, number)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to get company by registry number", "Failed to retrieve company")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, company)
 }
 
 // CreateCompany handles POST /api/v1/companies
 func (h *CompanyHandlers) CreateCompany(w http.ResponseWriter, r *http.Request) {
-	h.logger.Info("Creating new company")
+	h.log(r).Info("Creating new company", subject(r))
 
-	// Parse request body
+	// Parse request body; legacy tools post HTML forms, everything else sends JSON
 	var req api.CreateCompanyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("Failed to decode request body", zap.Error(err))
-		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+	var decoded bool
+	if isFormRequest(r) {
+		decoded = h.decodeCreateForm(w, r, &req)
+	} else {
+		decoded = h.decodeBody(w, r, &req)
+	}
+	if !decoded {
+		return
+	}
+
+	// Call service; with an Idempotency-Key a retried request gets the company created the first time
+	var company *api.Company
+	var err error
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		var replayed bool
+		company, replayed, err = h.service.CreateCompanyIdempotent(r.Context(), key, req)
+		if replayed {
+			h.log(r).Info("Replayed idempotent create", zap.String("id", company.Id.String()))
+			w.Header().Set("Idempotent-Replayed", "true")
+		}
+	} else {
+		company, err = h.service.CreateCompany(r.Context(), req)
+	}
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to create company", "Failed to create company")
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/companies/"+company.Id.String())
+	h.sendResponse(w, r, http.StatusCreated, company)
+}
+
+// CreateCompanies handles POST /api/v1/companies/batch
+func (h *CompanyHandlers) CreateCompanies(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Creating company batch", subject(r))
+
+	dryRun, ok := h.parseDryRun(w, r)
+	if !ok {
+		return
+	}
+
+	// Parse request body
+	var reqs []api.CreateCompanyRequest
+	if !h.decodeBody(w, r, &reqs) {
+		return
+	}
+
+	// Call service
+	companies, err := h.service.CreateCompanies(r.Context(), reqs, dryRun)
+	var batchErr *service.BatchValidationError
+	if errors.As(err, &batchErr) {
+		metrics.RecordValidationFailure()
+		h.sendResponse(w, r, http.StatusUnprocessableEntity, api.BatchCreateResponse{
+			Companies: []api.Company{},
+			Errors:    batchItemErrors(r, batchErr),
+		})
+		return
+	}
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to create companies", "Failed to create companies")
+		return
+	}
+
+	if dryRun {
+		h.sendResponse(w, r, http.StatusOK, api.BatchCreateResponse{
+			Companies: companies,
+			Errors:    []api.BatchItemError{},
+			DryRun:    &dryRun,
+		})
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusCreated, api.BatchCreateResponse{
+		Companies: companies,
+		Errors:    []api.BatchItemError{},
+	})
+}
+
+// parseExpectedVersion reads the expected_version query parameter, the date_updated of the company as the
+// client last saw it, responding with 400 and returning false when it is not an RFC3339 timestamp
+func (h *CompanyHandlers) parseExpectedVersion(w http.ResponseWriter, r *http.Request) (*time.Time, bool) {
+	versionStr := r.URL.Query().Get("expected_version")
+	if versionStr == "" {
+		return nil, true
+	}
+
+	version, err := time.Parse(time.RFC3339Nano, versionStr)
+	if err != nil {
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid expected_version parameter: must be the company's date_updated as an RFC3339 timestamp")
+		return nil, false
+	}
+	return &version, true
+}
+
+// parseDryRun reads the dry_run query parameter of bulk endpoints, responding with 400 and returning false
+// when it is not a boolean
+func (h *CompanyHandlers) parseDryRun(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	dryRunStr := r.URL.Query().Get("dry_run")
+	if dryRunStr == "" {
+		return false, true
+	}
+
+	dryRun, err := strconv.ParseBool(dryRunStr)
+	if err != nil {
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid dry_run parameter")
+		return false, false
+	}
+	return dryRun, true
+}
+
+// batchItemErrors converts batch validation failures to their API form, localizing each message
+func batchItemErrors(r *http.Request, batchErr *service.BatchValidationError) []api.BatchItemError {
+	locale := i18n.Negotiate(r)
+	items := make([]api.BatchItemError, len(batchErr.Items))
+	for i, item := range batchErr.Items {
+		items[i] = api.BatchItemError{
+			Index: item.Index,
+			Msg:   i18n.Sprintf(locale, item.Err.Format, item.Err.Args...),
+		}
+		if item.Err.Field != "" {
+			field := item.Err.Field
+			items[i].Field = &field
+		}
+	}
+	return items
+}
+
+// UpdateCompany handles PUT /api/v1/companies/{id}
+func (h *CompanyHandlers) UpdateCompany(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Updating company", zap.String("id", idStr), subject(r))
+
+	// Parse UUID
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	expected, ok := h.parseExpectedVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Parse request body
+	var req api.UpdateCompanyJSONRequestBody
+	if !h.decodeBody(w, r, &req) {
+		return
+	}
+
+	// Call service
+	company, err := h.service.UpdateCompany(r.Context(), id, req, expected)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to update company", "Failed to update company")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, company)
+}
+
+// PatchCompany handles PATCH /api/v1/companies/{id}
+func (h *CompanyHandlers) PatchCompany(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Patching company", zap.String("id", idStr), subject(r))
+
+	// Parse UUID
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	expected, ok := h.parseExpectedVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Parse request body
+	var req api.PatchCompanyJSONRequestBody
+	if !h.decodeBody(w, r, &req) {
+		return
+	}
+
+	if req == (api.PatchCompanyRequest{}) {
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Patch body must contain at least one field")
 		return
 	}
 
 	// Call service
-	company, err := h.service.CreateCompany(r.Context(), req)
+	company, err := h.service.PatchCompany(r.Context(), id, req, expected)
 	if err != nil {
-		h.logger.Error("Failed to create company", zap.Error(err))
-		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		h.sendServiceError(w, r, err, "Failed to patch company", "Failed to update company")
 		return
 	}
 
-	h.sendJSONResponse(w, http.StatusCreated, company)
+	h.sendResponse(w, r, http.StatusOK, company)
 }
 
 // DeleteCompany handles DELETE /api/v1/companies/{id}
 func (h *CompanyHandlers) DeleteCompany(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
-	h.logger.Info("Deleting company", zap.String("id", idStr))
+	h.log(r).Info("Deleting company", zap.String("id", idStr), subject(r))
 
 	// Parse UUID
-	parsedID, err := uuid.Parse(idStr)
+	id, err := h.parseCompanyID(idStr)
 	if err != nil {
-		h.logger.Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
-		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid company ID format")
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
 		return
 	}
-	id := openapi_types.UUID(parsedID)
 
 	// Call service
 	err = h.service.DeleteCompany(r.Context(), id)
 	if err != nil {
-		if err.Error() == "company not found" {
-			h.sendErrorResponse(w, http.StatusNotFound, "Company not found")
+		h.sendServiceError(w, r, err, "Failed to delete company", "Failed to delete company")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteCompanies handles POST /api/v1/companies/batch-delete. Every ID is parsed before anything is deleted,
+// so one malformed ID fails the whole request with a 400.
+func (h *CompanyHandlers) DeleteCompanies(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Deleting company batch", subject(r))
+
+	// Parse request body
+	var idStrs api.DeleteCompaniesJSONRequestBody
+	if !h.decodeBody(w, r, &idStrs) {
+		return
+	}
+
+	ids := make([]openapi_types.UUID, len(idStrs))
+	for i, idStr := range idStrs {
+		id, err := h.parseCompanyID(idStr)
+		if err != nil {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid company ID format at index %d", i))
 			return
 		}
-		h.logger.Error("Failed to delete company", zap.Error(err))
-		h.sendErrorResponse(w, http.StatusInternalServerError, "Failed to delete company")
+		ids[i] = id
+	}
+
+	// Call service
+	response, err := h.service.DeleteCompanies(r.Context(), ids)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to delete companies", "Failed to delete companies")
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	h.log(r).Info("Deleted company batch", zap.Int("deleted", response.Deleted), zap.Int("not_found", len(response.NotFound)))
+	h.sendResponse(w, r, http.StatusOK, response)
 }
 
-// sendJSONResponse sends a JSON response
-func (h *CompanyHandlers) sendJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+// TransferJurisdiction handles PUT /api/v1/companies/{id}/jurisdiction
+func (h *CompanyHandlers) TransferJurisdiction(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Transferring company jurisdiction", zap.String("id", idStr), subject(r))
+
+	// Parse UUID
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
 
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		h.logger.Error("Failed to encode JSON response", zap.Error(err))
+	// Parse request body
+	var req api.TransferJurisdictionJSONRequestBody
+	if !h.decodeBody(w, r, &req) {
+		return
 	}
+
+	// Call service
+	company, err := h.service.TransferJurisdiction(r.Context(), id, req.Jurisdiction)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to transfer company jurisdiction", "Failed to transfer company")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, company)
+}
+
+// AdjustDirectorCount handles PATCH /api/v1/companies/{id}/number_of_directors
+func (h *CompanyHandlers) AdjustDirectorCount(w http.ResponseWriter, r *http.Request) {
+	h.adjustCount(w, r, "number_of_directors", h.service.AdjustDirectorCount)
+}
+
+// AdjustShareholderCount handles PATCH /api/v1/companies/{id}/number_of_shareholders
+func (h *CompanyHandlers) AdjustShareholderCount(w http.ResponseWriter, r *http.Request) {
+	h.adjustCount(w, r, "number_of_shareholders", h.service.AdjustShareholderCount)
 }
 
-// sendErrorResponse sends an error response
-func (h *CompanyHandlers) sendErrorResponse(w http.ResponseWriter, statusCode int, message string) {
-	response := api.ErrorResponse{
-		Error: true,
-		Msg:   message,
+// adjustCount applies the request's delta to the company's count named field with adjust
+func (h *CompanyHandlers) adjustCount(w http.ResponseWriter, r *http.Request, field string,
+	adjust func(ctx context.Context, id openapi_types.UUID, delta int) (*api.Company, error)) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Adjusting company count", zap.String("id", idStr), zap.String("field", field), subject(r))
+
+	// Parse UUID
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	// Parse request body
+	var req api.CountAdjustmentRequest
+	if !h.decodeBody(w, r, &req) {
+		return
+	}
+
+	// Call service
+	company, err := adjust(r.Context(), id, req.Delta)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to adjust company count", "Failed to adjust company count")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, company)
+}
+
+// PurgeDeletedCompanies handles POST /api/v1/admin/companies/purge
+func (h *CompanyHandlers) PurgeDeletedCompanies(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Purging soft-deleted companies")
+
+	// Call service
+	result, err := h.service.PurgeDeletedCompanies(r.Context())
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to purge deleted companies", "Failed to purge deleted companies")
+		return
+	}
+
+	h.log(r).Warn("Purged soft-deleted companies", zap.Int64("purged", result.Purged), zap.Time("cutoff", result.Cutoff))
+	h.sendResponse(w, r, http.StatusOK, result)
+}
+
+// RestoreCompany handles POST /api/v1/admin/companies/{id}/restore
+func (h *CompanyHandlers) RestoreCompany(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Restoring company", zap.String("id", idStr))
+
+	// Parse UUID
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	// Call service
+	company, err := h.service.RestoreCompany(r.Context(), id)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to restore company", "Failed to restore company")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, company)
+}
+
+// GetCompanyHistory handles GET /api/v1/companies/{id}/history
+func (h *CompanyHandlers) GetCompanyHistory(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Getting company history", zap.String("id", idStr))
+
+	// Parse UUID
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	// Call service
+	entries, err := h.service.GetCompanyHistory(r.Context(), id)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to get company history", "Failed to get company history")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, entries)
+}
+
+// GetSharedAddressReport handles GET /api/v1/reports/shared-addresses
+func (h *CompanyHandlers) GetSharedAddressReport(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Getting shared address report")
+
+	params := api.GetSharedAddressReportParams{}
+
+	if minStr := r.URL.Query().Get("min"); minStr != "" {
+		if minSize, err := strconv.Atoi(minStr); err == nil && minSize >= 2 {
+			params.Min = &minSize
+		} else {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid min parameter: must be an integer of at least 2")
+			return
+		}
+	}
+
+	minSize := 2
+	if params.Min != nil {
+		minSize = *params.Min
+	}
+
+	report, err := h.service.GetSharedAddressReport(r.Context(), minSize)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to get shared address report", "Failed to retrieve shared address report")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, report)
+}
+
+// CountCompaniesByNatureOfBusiness handles GET /api/v1/reports/nature-of-business
+func (h *CompanyHandlers) CountCompaniesByNatureOfBusiness(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Counting companies by nature of business")
+
+	params := api.CountCompaniesByNatureOfBusinessParams{}
+	if r.URL.Query().Has("jurisdiction") {
+		jurisdiction := r.URL.Query().Get("jurisdiction")
+		params.Jurisdiction = &jurisdiction
+	}
+
+	counts, err := h.service.CountCompaniesByNatureOfBusiness(r.Context(), params.Jurisdiction)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to count companies by nature of business", "Failed to count companies by nature of business")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, counts)
+}
+
+// ResolveJurisdiction handles GET /api/v1/jurisdictions/resolve
+func (h *CompanyHandlers) ResolveJurisdiction(w http.ResponseWriter, r *http.Request) {
+	value := r.URL.Query().Get("value")
+	h.log(r).Info("Resolving jurisdiction", zap.String("value", value))
+
+	if strings.TrimSpace(value) == "" {
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "value parameter is required")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, h.service.ResolveJurisdiction(value))
+}
+
+// ListJurisdictions handles GET /api/v1/jurisdictions
+func (h *CompanyHandlers) ListJurisdictions(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Listing jurisdictions")
+	h.sendResponse(w, r, http.StatusOK, h.service.ListJurisdictions())
+}
+
+// CountCompaniesByJurisdiction handles GET /api/v1/jurisdictions/counts
+func (h *CompanyHandlers) CountCompaniesByJurisdiction(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Counting companies by jurisdiction")
+
+	counts, err := h.service.CountCompaniesByJurisdiction(r.Context())
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to count companies by jurisdiction", "Failed to count companies by jurisdiction")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, counts)
+}
+
+// parseCompanyID parses a company ID, requiring canonical form when strict UUIDs are enabled. The nil UUID is
+// rejected, as it is never assigned and could only lead to a 404 after a wasted query.
+func (h *CompanyHandlers) parseCompanyID(idStr string) (openapi_types.UUID, error) {
+	parsedID, err := uuid.Parse(idStr)
+	if err != nil {
+		return openapi_types.UUID{}, err
+	}
+
+	if parsedID == uuid.Nil {
+		return openapi_types.UUID{}, fmt.Errorf("company ID cannot be the nil UUID")
+	}
+
+	if h.opts.StrictUUIDs && parsedID.String() != idStr {
+		return openapi_types.UUID{}, fmt.Errorf("company ID must be a canonical lowercase hyphenated UUID")
+	}
+
+	return openapi_types.UUID(parsedID), nil
+}
+
+// decodeBody decodes the JSON request body into v, reading at most MaxBodyBytes. It responds with 413 when the
+// body is too large and 400 when it is empty, malformed or has a field v does not define, returning false in all
+// cases. Syntax errors report the byte offset they occurred at.
+func (h *CompanyHandlers) decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if h.opts.MaxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.opts.MaxBodyBytes)
+	}
+
+	// Misspelled fields would otherwise be dropped silently, surfacing as a confusing "required" error
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.log(r).Warn("Request body too large", zap.Int64("limit", tooLarge.Limit))
+			h.sendErrorResponse(w, r, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("Request body cannot exceed %d bytes", tooLarge.Limit))
+			return false
+		}
+		// encoding/json has no typed error for unknown fields, only this message
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			field, _ = strconv.Unquote(field)
+			h.sendUnknownField(w, r, field)
+			return false
+		}
+		if errors.Is(err, io.EOF) {
+			h.log(r).Info("Rejected empty request body")
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Request body is required")
+			return false
+		}
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			h.log(r).Info("Rejected malformed request body", zap.Int64("offset", syntaxErr.Offset), zap.Error(err))
+			h.sendErrorResponse(w, r, http.StatusBadRequest,
+				fmt.Sprintf("Invalid JSON at offset %d: %s", syntaxErr.Offset, syntaxErr.Error()))
+			return false
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			h.log(r).Info("Rejected truncated request body")
+			h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid JSON: request body ended unexpectedly")
+			return false
+		}
+		h.log(r).Error("Failed to decode request body", zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+	return true
+}
+
+// sendUnknownField rejects a request body naming a field the endpoint does not accept
+func (h *CompanyHandlers) sendUnknownField(w http.ResponseWriter, r *http.Request, field string) {
+	h.log(r).Info("Rejected unknown request body field", zap.String("field", field))
+	if err := response.WriteFieldErrors(w, r, http.StatusBadRequest, fmt.Sprintf("Unknown field %q in request body", field),
+		map[string]string{field: "unknown field"}); err != nil {
+		h.log(r).Error("Failed to encode error response", zap.Error(err))
+	}
+}
+
+// subject returns a log field naming the JWT subject that authenticated the request, empty when unauthenticated
+func subject(r *http.Request) zap.Field {
+	claims, _ := appmiddleware.ClaimsFromContext(r.Context())
+	return zap.String("subject", claims.Subject())
+}
+
+// sendResponse sends a response in the format negotiated from the request, JSON unless MessagePack is accepted
+func (h *CompanyHandlers) sendResponse(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}) {
+	if err := response.Write(w, r, statusCode, data); err != nil {
+		h.log(r).Error("Failed to encode JSON response", zap.Error(err))
+	}
+}
+
+// sendErrorResponse sends an error response in the format negotiated from the request
+func (h *CompanyHandlers) sendErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	if err := response.WriteError(w, r, statusCode, message); err != nil {
+		h.log(r).Error("Failed to encode error response", zap.Error(err))
+	}
+}
+
+// sendServiceError maps service errors to HTTP statuses; anything unrecognized is logged as logMsg and
+// returned as a 500 with the given message
+func (h *CompanyHandlers) sendServiceError(w http.ResponseWriter, r *http.Request, err error, logMsg, message string) {
+	var validationErr *service.ValidationError
+	var duplicateErr *service.DuplicateNameError
+
+	switch {
+	case errors.Is(err, service.ErrCompanyNotFound):
+		h.sendErrorResponse(w, r, http.StatusNotFound, "Company not found")
+	case errors.Is(err, service.ErrDirectorNotFound):
+		h.sendErrorResponse(w, r, http.StatusNotFound, "Director not found")
+	case errors.Is(err, service.ErrShareholderNotFound):
+		h.sendErrorResponse(w, r, http.StatusNotFound, "Shareholder not found")
+	case errors.Is(err, service.ErrSnapshotNotFound):
+		h.sendErrorResponse(w, r, http.StatusNotFound, "Snapshot not found; it may have been closed or expired")
+	case errors.Is(err, service.ErrSnapshotLimit):
+		h.log(r).Warn(logMsg, zap.Error(err))
+		w.Header().Set("Retry-After", "10")
+		h.sendErrorResponse(w, r, http.StatusTooManyRequests, "Too many snapshots are open, try again later")
+	case errors.Is(err, service.ErrIdempotencyKeyReused):
+		h.sendErrorResponse(w, r, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+	case errors.As(err, &duplicateErr):
+		if err := response.WriteConflict(w, r, "A company with a matching name already exists in this jurisdiction",
+			duplicateErr.ExistingID); err != nil {
+			h.log(r).Error("Failed to encode error response", zap.Error(err))
+		}
+	case errors.Is(err, service.ErrCompanyAlreadyExists):
+		h.sendErrorResponse(w, r, http.StatusConflict, "A company with this name already exists in this jurisdiction")
+	case errors.Is(err, service.ErrSecCodeTaken):
+		h.sendErrorResponse(w, r, http.StatusConflict, "Another company already has this sec_code")
+	case errors.Is(err, service.ErrSameJurisdiction):
+		h.sendErrorResponse(w, r, http.StatusConflict, "The company is already in this jurisdiction")
+	case errors.Is(err, service.ErrCountOutOfRange):
+		h.sendErrorResponse(w, r, http.StatusConflict, "The adjustment would take the count outside its allowed range")
+	case errors.Is(err, service.ErrCompanyModified):
+		h.sendErrorResponse(w, r, http.StatusPreconditionFailed, "The company was modified since expected_version; fetch it again and retry")
+	case errors.Is(err, service.ErrCreateRateExceeded):
+		h.log(r).Warn(logMsg, zap.Error(err))
+		w.Header().Set("Retry-After", "60")
+		h.sendErrorResponse(w, r, http.StatusTooManyRequests, "Company creation rate limit exceeded, try again later")
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(r.Context().Err(), context.DeadlineExceeded):
+		// The driver may surface the cancelled query as its own error, so the request deadline is checked too
+		h.log(r).Warn(logMsg, zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusGatewayTimeout, "Request timed out")
+	case errors.As(err, &validationErr):
+		metrics.RecordValidationFailure()
+		status := http.StatusBadRequest
+		if validationErr.Field != "" {
+			status = http.StatusUnprocessableEntity
+		}
+		locale := i18n.Negotiate(r)
+		fields := map[string]string{}
+		for _, violation := range validationErr.Fields {
+			if _, ok := fields[violation.Field]; !ok {
+				fields[violation.Field] = i18n.Sprintf(locale, violation.Format, violation.Args...)
+			}
+		}
+		if err := response.WriteFieldErrors(w, r, status,
+			i18n.Sprintf(locale, validationErr.Format, validationErr.Args...), fields); err != nil {
+			h.log(r).Error("Failed to encode error response", zap.Error(err))
+		}
+	default:
+		metrics.RecordInternalError()
+		h.log(r).Error(logMsg, zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusInternalServerError, message)
 	}
-	h.sendJSONResponse(w, statusCode, response)
 }
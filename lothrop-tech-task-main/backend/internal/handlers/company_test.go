@@ -0,0 +1,1031 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/api"
+	appmiddleware "backend/internal/middleware"
+	"backend/internal/repository/repositorytest"
+	"backend/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// newTestHandlers returns handlers over a service with the default configuration, backed by repo
+func newTestHandlers(t *testing.T, repo *repositorytest.CompanyRepository) *CompanyHandlers {
+	t.Helper()
+
+	rules, err := service.ParseSecCodeRules(`^[A-Z]{2,4}[0-9]+$`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := service.NewCompanyService(repo, service.Options{
+		Jurisdictions:             []string{"UK", "Singapore", "Cayman Islands"},
+		DefaultLimit:              20,
+		MaxLimit:                  100,
+		MaxOffset:                 10000,
+		SecCodeRules:              rules,
+		IdempotencyTTL:            time.Hour,
+		SoftDeleteRetention:       time.Hour,
+		AddressMaxLength:          500,
+		NatureOfBusinessMaxLength: 500,
+	})
+	return NewCompanyHandlers(svc, zap.NewNop(), Options{MaxBodyBytes: 1 << 20, ListMaxOffset: 10000})
+}
+
+// newTestRouter serves the company routes under /api/v1 as main does, backed by the in-memory repository and
+// behind the OpenAPI request validation
+func newTestRouter(t *testing.T, repo *repositorytest.CompanyRepository) http.Handler {
+	t.Helper()
+
+	h := newTestHandlers(t, repo)
+	spec, err := api.GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validate, err := appmiddleware.ValidateRequests(spec, 1<<20, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := chi.NewRouter()
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(validate)
+		r.Get("/companies", h.GetCompanies)
+		r.Post("/companies", h.CreateCompany)
+		r.Get("/companies/{id}", h.GetCompanyByID)
+		r.Patch("/companies/{id}", h.PatchCompany)
+		r.Get("/companies/{id}.pdf", h.ExportCompanyPDF)
+		r.Get("/reports/nature-of-business", h.CountCompaniesByNatureOfBusiness)
+	})
+	return r
+}
+
+// serve sends a request through handler and returns the recorded response
+func serve(handler http.Handler, method, target, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// postCompany creates a company from fields, sent as JSON or as a form post
+func postCompany(handler http.Handler, fields map[string]interface{}, form bool) *httptest.ResponseRecorder {
+	if form {
+		values := url.Values{}
+		for field, value := range fields {
+			values.Set(field, fmt.Sprint(value))
+		}
+		return serve(handler, http.MethodPost, "/api/v1/companies", "application/x-www-form-urlencoded", values.Encode())
+	}
+	body, _ := json.Marshal(fields)
+	return serve(handler, http.MethodPost, "/api/v1/companies", "application/json", string(body))
+}
+
+// errorFields decodes the field map of an error response
+func errorFields(t *testing.T, rec *httptest.ResponseRecorder) map[string]string {
+	t.Helper()
+	var body struct {
+		Fields map[string]string `json:"fields"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding error body %q: %v", rec.Body.String(), err)
+	}
+	return body.Fields
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCreateCompanyValueRulesAreLeftToTheService(t *testing.T) {
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"company_name":    "Example Corp Ltd",
+			"company_address": "123 Business Street, London",
+			"jurisdiction":    "UK",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(map[string]interface{})
+		status int
+		field  string
+	}{
+		{"empty name", func(f map[string]interface{}) { f["company_name"] = "" }, http.StatusUnprocessableEntity, "company_name"},
+		{"name over 255", func(f map[string]interface{}) { f["company_name"] = strings.Repeat("a", 256) }, http.StatusUnprocessableEntity, "company_name"},
+		{"padded name at 255", func(f map[string]interface{}) { f["company_name"] = "  " + strings.Repeat("a", 255) + "  " }, http.StatusCreated, ""},
+		{"empty address", func(f map[string]interface{}) { f["company_address"] = "" }, http.StatusUnprocessableEntity, "company_address"},
+		{"no directors", func(f map[string]interface{}) { f["number_of_directors"] = 0 }, http.StatusUnprocessableEntity, "number_of_directors"},
+		{"too many shareholders", func(f map[string]interface{}) { f["number_of_shareholders"] = 1001 }, http.StatusUnprocessableEntity, "number_of_shareholders"},
+	}
+
+	for _, tt := range tests {
+		for _, form := range []bool{false, true} {
+			name := tt.name + "/json"
+			if form {
+				name = tt.name + "/form"
+			}
+			t.Run(name, func(t *testing.T) {
+				handler := newTestRouter(t, repositorytest.NewCompanyRepository())
+				fields := valid()
+				tt.change(fields)
+
+				rec := postCompany(handler, fields, form)
+				if rec.Code != tt.status {
+					t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.status, rec.Body.String())
+				}
+				if tt.field != "" {
+					if _, ok := errorFields(t, rec)[tt.field]; !ok {
+						t.Errorf("fields = %v, want an entry for %s", errorFields(t, rec), tt.field)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestListPaginationParametersAreRejected(t *testing.T) {
+	h := newTestHandlers(t, repositorytest.NewCompanyRepository())
+	direct := http.HandlerFunc(h.GetCompanies)
+	routed := newTestRouter(t, repositorytest.NewCompanyRepository())
+
+	for _, query := range []string{
+		"limit=99999999999999999999",
+		"limit=2147483648",
+		"limit=-1",
+		"limit=1e3",
+		"offset=-1",
+		"offset=99999999999999999999",
+		"offset=+5",
+	} {
+		t.Run(query, func(t *testing.T) {
+			if rec := serve(direct, http.MethodGet, "/api/v1/companies?"+query, "", ""); rec.Code != http.StatusBadRequest {
+				t.Errorf("handler: status = %d, want 400; body %s", rec.Code, rec.Body.String())
+			}
+			if rec := serve(routed, http.MethodGet, "/api/v1/companies?"+query, "", ""); rec.Code != http.StatusBadRequest {
+				t.Errorf("validated route: status = %d, want 400; body %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	if rec := serve(direct, http.MethodGet, "/api/v1/companies?limit=5&offset=0", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("valid limit and offset: status = %d, want 200; body %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDuplicateSecCodeIsAConflict(t *testing.T) {
+	handler := newTestRouter(t, repositorytest.NewCompanyRepository())
+
+	first := map[string]interface{}{
+		"company_name":    "First Ltd",
+		"company_address": "1 High Street",
+		"jurisdiction":    "UK",
+		"sec_code":        "SEC123",
+	}
+	if rec := postCompany(handler, first, false); rec.Code != http.StatusCreated {
+		t.Fatalf("first create: status = %d; body %s", rec.Code, rec.Body.String())
+	}
+
+	for _, secCode := range []string{"SEC123", " SEC123 "} {
+		second := map[string]interface{}{
+			"company_name":    "Second Pte",
+			"company_address": "2 Orchard Road",
+			"jurisdiction":    "Singapore",
+			"sec_code":        secCode,
+		}
+		if rec := postCompany(handler, second, false); rec.Code != http.StatusConflict {
+			t.Errorf("sec_code %q: status = %d, want 409; body %s", secCode, rec.Code, rec.Body.String())
+		}
+	}
+
+	third := map[string]interface{}{
+		"company_name":    "Third Ltd",
+		"company_address": "3 High Street",
+		"jurisdiction":    "UK",
+		"sec_code":        "SEC456",
+	}
+	if rec := postCompany(handler, third, false); rec.Code != http.StatusCreated {
+		t.Errorf("distinct sec_code: status = %d, want 201; body %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDuplicateNameKeyIsAConflict(t *testing.T) {
+	handler := newTestRouter(t, repositorytest.NewCompanyRepository())
+
+	company := func(name, jurisdiction string) map[string]interface{} {
+		return map[string]interface{}{
+			"company_name":    name,
+			"company_address": "1 High Street",
+			"jurisdiction":    jurisdiction,
+		}
+	}
+	if rec := postCompany(handler, company("Acme Ltd", "UK"), false); rec.Code != http.StatusCreated {
+		t.Fatalf("first create: status = %d; body %s", rec.Code, rec.Body.String())
+	}
+
+	// Names differing only in case, whitespace or accents share a name key
+	for _, name := range []string{"Acme Ltd", "acme ltd", "ACME  LTD", " Acme\tLtd ", "Ácme Ltd"} {
+		if rec := postCompany(handler, company(name, "UK"), false); rec.Code != http.StatusConflict {
+			t.Errorf("company_name %q: status = %d, want 409; body %s", name, rec.Code, rec.Body.String())
+		}
+	}
+
+	tests := []struct{ name, jurisdiction string }{
+		{"Acme Ltd", "Singapore"},   // Names are unique per jurisdiction
+		{"Acme Ltd.", "UK"},         // Punctuation is part of the key
+		{"Acme Holdings Ltd", "UK"}, // A different name altogether
+	}
+	for _, tt := range tests {
+		if rec := postCompany(handler, company(tt.name, tt.jurisdiction), false); rec.Code != http.StatusCreated {
+			t.Errorf("%q in %s: status = %d, want 201; body %s", tt.name, tt.jurisdiction, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestListOffsetCap(t *testing.T) {
+	handler := newTestRouter(t, repositorytest.NewCompanyRepository())
+
+	if rec := serve(handler, http.MethodGet, "/api/v1/companies?offset=10000", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("offset at the cap: status = %d, want 200; body %s", rec.Code, rec.Body.String())
+	}
+
+	rec := serve(handler, http.MethodGet, "/api/v1/companies?offset=10001", "", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("offset over the cap: status = %d, want 400; body %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "cursor") {
+		t.Errorf("body %s does not point to cursor pagination", rec.Body.String())
+	}
+}
+
+func TestListLinkHeader(t *testing.T) {
+	repo := repositorytest.NewCompanyRepository()
+	handler := newTestRouter(t, repo)
+	for i := 1; i <= 5; i++ {
+		fields := map[string]interface{}{"company_name": fmt.Sprintf("Company %d Ltd", i), "company_address": "1 High Street", "jurisdiction": "UK"}
+		if rec := postCompany(handler, fields, false); rec.Code != http.StatusCreated {
+			t.Fatalf("creating company %d: status = %d; body %s", i, rec.Code, rec.Body.String())
+		}
+	}
+
+	tests := []struct {
+		name   string
+		offset int
+		want   map[string]string // Offset linked by each relation
+	}{
+		{"first page", 0, map[string]string{"first": "0", "next": "2", "last": "4"}},
+		{"middle page", 2, map[string]string{"first": "0", "prev": "0", "next": "4", "last": "4"}},
+		{"last page", 4, map[string]string{"first": "0", "prev": "2", "last": "4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(handler, http.MethodGet, fmt.Sprintf("/api/v1/companies?jurisdiction=UK&limit=2&offset=%d", tt.offset), "", "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+			}
+
+			got := map[string]string{}
+			for _, value := range strings.Split(rec.Header().Get("Link"), ", ") {
+				target, rel, ok := strings.Cut(value, "; rel=")
+				if !ok {
+					t.Fatalf("malformed link %q", value)
+				}
+				u, err := url.Parse(strings.Trim(target, "<>"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				query := u.Query()
+				if u.Path != "/api/v1/companies" || query.Get("limit") != "2" || query.Get("jurisdiction") != "UK" {
+					t.Errorf("%s link %s does not keep the path, limit and filters", rel, u)
+				}
+				got[strings.Trim(rel, `"`)] = query.Get("offset")
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("link offsets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, target := range []string{"/api/v1/companies?limit=0", "/api/v1/companies?limit=2&cursor=x"} {
+		if rec := serve(handler, http.MethodGet, target, "", ""); rec.Header().Get("Link") != "" {
+			t.Errorf("%s: Link = %q, want none", target, rec.Header().Get("Link"))
+		}
+	}
+}
+
+func TestPageLinksStopAtOffsetCap(t *testing.T) {
+	rels := func(links []pageLink) map[string]int {
+		got := map[string]int{}
+		for _, link := range links {
+			got[link.rel] = link.offset
+		}
+		return got
+	}
+
+	// 100 pages of 100, so the last page starts at 9900 and the next after it would be past the cap
+	got := rels(paginationLinks(100, 9900, 100000, 10000, true))
+	if got["next"] != 10000 {
+		t.Errorf("next from 9900 = %d, want 10000, the cap itself", got["next"])
+	}
+	if _, ok := got["last"]; ok {
+		t.Errorf("last = %d, want no last link past the cap", got["last"])
+	}
+
+	got = rels(paginationLinks(100, 10000, 100000, 10000, true))
+	if offset, ok := got["next"]; ok {
+		t.Errorf("next from the cap = %d, want no next link", offset)
+	}
+	if got["prev"] != 9900 {
+		t.Errorf("prev from the cap = %d, want 9900", got["prev"])
+	}
+
+	got = rels(paginationLinks(100, 10000, 100000, 0, true))
+	if got["next"] != 10100 || got["last"] != 99900 {
+		t.Errorf("uncapped links = %v, want next 10100 and last 99900", got)
+	}
+}
+
+func TestParseCompanyID(t *testing.T) {
+	const canonical = "123e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name    string
+		id      string
+		lenient bool
+		strict  bool
+	}{
+		{"canonical", canonical, true, true},
+		{"uppercase", strings.ToUpper(canonical), true, false},
+		{"braced", "{" + canonical + "}", true, false},
+		{"URN", "urn:uuid:" + canonical, true, false},
+		{"unhyphenated", strings.ReplaceAll(canonical, "-", ""), true, false},
+		{"nil UUID", "00000000-0000-0000-0000-000000000000", false, false},
+		{"not a UUID", "acme", false, false},
+	}
+
+	lenient := &CompanyHandlers{}
+	strict := &CompanyHandlers{opts: Options{StrictUUIDs: true}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := lenient.parseCompanyID(tt.id)
+			if (err == nil) != tt.lenient {
+				t.Errorf("lenient: err = %v, want accepted = %v", err, tt.lenient)
+			}
+			if err == nil && id.String() != canonical {
+				t.Errorf("lenient: id = %s, want %s", id, canonical)
+			}
+
+			if _, err := strict.parseCompanyID(tt.id); (err == nil) != tt.strict {
+				t.Errorf("strict: err = %v, want accepted = %v", err, tt.strict)
+			}
+		})
+	}
+}
+
+func TestExplainRequiresDebugToken(t *testing.T) {
+	h := newTestHandlers(t, repositorytest.NewCompanyRepository())
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+	}{
+		{"debug disabled", "", "Bearer "},
+		{"no token", "debug-secret", ""},
+		{"wrong token", "debug-secret", "Bearer admin-secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h.opts.DebugToken = tt.token
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies?explain=true", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.GetCompanies(rec, req)
+
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want 403; body %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestValidationMessagesAreLocalized(t *testing.T) {
+	handler := newTestRouter(t, repositorytest.NewCompanyRepository())
+	body := `{"company_name": "", "company_address": "1 High Street", "jurisdiction": "UK"}`
+
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{"", "company name is required"},
+		{"fr-FR, en;q=0.5", "le nom de la société est obligatoire"},
+		{"de", "company name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/companies", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.language != "" {
+				req.Header.Set("Accept-Language", tt.language)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("status = %d, want 422; body %s", rec.Code, rec.Body.String())
+			}
+			if got := errorFields(t, rec)["company_name"]; got != tt.want {
+				t.Errorf("company_name message = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRawCompanyRequiresDebugToken(t *testing.T) {
+	repo := repositorytest.NewCompanyRepository()
+	company, err := repo.Create(context.Background(), api.CreateCompanyRequest{
+		CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		id     string
+		status int
+	}{
+		{"debug disabled", "", "Bearer ", company.Id.String(), http.StatusForbidden},
+		{"no token", "debug-secret", "", company.Id.String(), http.StatusForbidden},
+		{"wrong token", "debug-secret", "Bearer admin-secret", company.Id.String(), http.StatusForbidden},
+		{"debug token", "debug-secret", "Bearer debug-secret", company.Id.String(), http.StatusOK},
+		{"unknown company", "debug-secret", "Bearer debug-secret", "123e4567-e89b-12d3-a456-426614174000", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandlers(t, repo)
+			h.opts.DebugToken = tt.token
+			r := chi.NewRouter()
+			r.Get("/api/v1/debug/companies/{id}/raw", h.GetRawCompany)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/debug/companies/"+tt.id+"/raw", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var row map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &row); err != nil {
+				t.Fatal(err)
+			}
+			if row["name_key"] != "acme ltd" {
+				t.Errorf("name_key = %v, want the stored column", row["name_key"])
+			}
+		})
+	}
+}
+
+func TestDebugQuery(t *testing.T) {
+	h := newTestHandlers(t, repositorytest.NewCompanyRepository())
+	h.opts.DebugToken = "debug-secret"
+
+	tests := []struct {
+		name   string
+		query  string
+		token  string
+		status int
+		debug  bool
+	}{
+		{"with the debug token", "debug_query=true&limit=5&offset=10", "debug-secret", http.StatusOK, true},
+		{"without a token", "debug_query=true", "", http.StatusForbidden, false},
+		{"with another token", "debug_query=true", "admin-secret", http.StatusForbidden, false},
+		{"not asked for", "debug_query=false", "debug-secret", http.StatusOK, false},
+		{"with id_only", "debug_query=true&id_only=true", "debug-secret", http.StatusBadRequest, false},
+		{"with header pagination", "debug_query=true&pagination=header", "debug-secret", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies?"+tt.query, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			h.GetCompanies(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var body api.CompaniesResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if (body.Debug != nil) != tt.debug {
+				t.Fatalf("debug = %+v, want present = %v", body.Debug, tt.debug)
+			}
+			if tt.debug && body.Debug.Sql != "in-memory scan: LIMIT 5 OFFSET 10" {
+				t.Errorf("debug sql = %q, want the page's query", body.Debug.Sql)
+			}
+		})
+	}
+}
+
+func TestCreateCompanyFormMatchesJSON(t *testing.T) {
+	fields := map[string]interface{}{
+		"company_name":           "Example Corp Ltd",
+		"company_address":        "123 Business Street, London",
+		"jurisdiction":           "UK",
+		"nature_of_business":     "Software Development",
+		"number_of_directors":    3,
+		"number_of_shareholders": 5,
+		"registry_source":        "companies_house",
+		"registry_number":        "01234567",
+		"sec_code":               "SEC123",
+	}
+
+	created := func(form bool) api.Company {
+		t.Helper()
+		rec := postCompany(newTestRouter(t, repositorytest.NewCompanyRepository()), fields, form)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("form %v: status = %d, want 201; body %s", form, rec.Code, rec.Body.String())
+		}
+		var company api.Company
+		if err := json.Unmarshal(rec.Body.Bytes(), &company); err != nil {
+			t.Fatal(err)
+		}
+		// Only the generated fields may differ
+		company.Id = api.Company{}.Id
+		company.DateCreated, company.DateUpdated = time.Time{}, time.Time{}
+		return company
+	}
+
+	fromJSON, fromForm := created(false), created(true)
+	if !reflect.DeepEqual(fromJSON, fromForm) {
+		t.Errorf("form created %+v, JSON created %+v", fromForm, fromJSON)
+	}
+
+	t.Run("empty optional fields are absent", func(t *testing.T) {
+		body := "company_name=Blank+Ltd&company_address=1+High+Street&jurisdiction=UK&nature_of_business=&sec_code=&number_of_directors="
+		rec := serve(newTestRouter(t, repositorytest.NewCompanyRepository()), http.MethodPost, "/api/v1/companies",
+			"application/x-www-form-urlencoded", body)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want 201; body %s", rec.Code, rec.Body.String())
+		}
+		var company api.Company
+		if err := json.Unmarshal(rec.Body.Bytes(), &company); err != nil {
+			t.Fatal(err)
+		}
+		if company.NatureOfBusiness != nil || company.SecCode != nil || company.NumberOfDirectors != nil {
+			t.Errorf("empty fields stored as values: %+v", company)
+		}
+	})
+
+	for name, extra := range map[string]map[string]interface{}{
+		"unknown field": {"website": "example.com"},
+		"non-integer":   {"number_of_directors": "three"},
+	} {
+		for _, form := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/form=%v", name, form), func(t *testing.T) {
+				body := map[string]interface{}{}
+				for field, value := range fields {
+					body[field] = value
+				}
+				for field, value := range extra {
+					body[field] = value
+				}
+				if rec := postCompany(newTestRouter(t, repositorytest.NewCompanyRepository()), body, form); rec.Code != http.StatusBadRequest {
+					t.Errorf("status = %d, want 400; body %s", rec.Code, rec.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestExportCompanyPDF(t *testing.T) {
+	handler := newTestRouter(t, repositorytest.NewCompanyRepository())
+
+	rec := postCompany(handler, map[string]interface{}{
+		"company_name":    "Example Corp Ltd",
+		"company_address": "123 Business Street, London",
+		"jurisdiction":    "UK",
+		"sec_code":        "SEC123",
+	}, false)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	var company api.Company
+	if err := json.Unmarshal(rec.Body.Bytes(), &company); err != nil {
+		t.Fatal(err)
+	}
+
+	rec = serve(handler, http.MethodGet, "/api/v1/companies/"+company.Id.String()+".pdf", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Errorf("Content-Type = %q, want application/pdf", got)
+	}
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, "%PDF") {
+		t.Errorf("body starts %q, want %%PDF", body[:min(len(body), 8)])
+	}
+	for _, want := range []string{"(Example Corp Ltd)", "(" + company.Id.String() + ")", "(123 Business Street, London)", "(SEC123)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("PDF does not contain %s", want)
+		}
+	}
+
+	missing := "/api/v1/companies/123e4567-e89b-12d3-a456-426614174000.pdf"
+	if rec := serve(handler, http.MethodGet, missing, "", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("missing company: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestCountCompaniesByNatureOfBusiness(t *testing.T) {
+	handler := newTestRouter(t, repositorytest.NewCompanyRepository())
+	for i, nature := range []string{"Software", "Software", ""} {
+		fields := map[string]interface{}{
+			"company_name":    fmt.Sprintf("Company %d", i),
+			"company_address": "1 High Street",
+			"jurisdiction":    "UK",
+		}
+		if nature != "" {
+			fields["nature_of_business"] = nature
+		}
+		if rec := postCompany(handler, fields, false); rec.Code != http.StatusCreated {
+			t.Fatalf("create: status = %d; body %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := serve(handler, http.MethodGet, "/api/v1/reports/nature-of-business?jurisdiction=uk", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body %s", rec.Code, rec.Body.String())
+	}
+	var counts []map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &counts); err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]interface{}{
+		{"nature_of_business": "Software", "count": float64(2)},
+		{"nature_of_business": nil, "count": float64(1)},
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("counts = %v, want %v with the null bucket as an explicit null", counts, want)
+	}
+
+	if rec := serve(handler, http.MethodGet, "/api/v1/reports/nature-of-business?jurisdiction=Atlantis", "", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown jurisdiction: status = %d, want 400", rec.Code)
+	}
+}
+
+// createTestCompany posts a valid company through handler and returns it as created
+func createTestCompany(t *testing.T, handler http.Handler) api.Company {
+	t.Helper()
+	rec := postCompany(handler, map[string]interface{}{
+		"company_name":    "Acme Ltd",
+		"company_address": "1 High Street",
+		"jurisdiction":    "UK",
+	}, false)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	var company api.Company
+	if err := json.Unmarshal(rec.Body.Bytes(), &company); err != nil {
+		t.Fatalf("decoding created company: %v", err)
+	}
+	return company
+}
+
+func TestOversizedBodyIsRejected(t *testing.T) {
+	h := newTestHandlers(t, repositorytest.NewCompanyRepository())
+	body := `{"company_name":"` + strings.Repeat("a", 1<<20) + `"}`
+
+	rec := serve(http.HandlerFunc(h.CreateCompany), http.MethodPost, "/api/v1/companies", "application/json", body)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413; body %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetCompanyRevalidation(t *testing.T) {
+	handler := newTestRouter(t, repositorytest.NewCompanyRepository())
+	company := createTestCompany(t, handler)
+	target := "/api/v1/companies/" + company.Id.String()
+
+	rec := serve(handler, http.MethodGet, target, "", "")
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" {
+		t.Fatalf("GET: status = %d, ETag = %q, want 200 with an ETag", rec.Code, etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("matching If-None-Match: status = %d, want 304", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("304 with body %q", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("stale If-None-Match: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestPatchExpectedVersion(t *testing.T) {
+	handler := newTestRouter(t, repositorytest.NewCompanyRepository())
+	company := createTestCompany(t, handler)
+	target := "/api/v1/companies/" + company.Id.String() + "?expected_version="
+
+	stale := url.QueryEscape(company.DateUpdated.Add(-time.Second).Format(time.RFC3339Nano))
+	rec := serve(handler, http.MethodPatch, target+stale, "application/json", `{"company_address":"2 High Street"}`)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("stale version: status = %d, want 412; body %s", rec.Code, rec.Body.String())
+	}
+
+	current := url.QueryEscape(company.DateUpdated.Format(time.RFC3339Nano))
+	rec = serve(handler, http.MethodPatch, target+current, "application/json", `{"company_address":"2 High Street"}`)
+	if rec.Code != http.StatusOK {
+		t.Errorf("current version: status = %d, want 200; body %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestExportCompaniesNDJSON(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.NewCompanyRepository()
+	for _, req := range []api.CreateCompanyRequest{
+		{CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK"},
+		{CompanyName: "Beta Ltd", CompanyAddress: "2 High Street", Jurisdiction: "UK", NatureOfBusiness: ptr("Retail"), NumberOfDirectors: ptr(2), SecCode: ptr("AB1")},
+		{CompanyName: "Gamma Pte", CompanyAddress: "3 Orchard Road", Jurisdiction: "Singapore"},
+	} {
+		if _, err := repo.Create(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	export := http.HandlerFunc(newTestHandlers(t, repo).ExportCompaniesNDJSON)
+
+	rec := serve(export, http.MethodGet, "/api/v1/companies/export.ndjson?jurisdiction=UK", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", got)
+	}
+
+	// Every line is one JSON object with the same keys in the same order, nulls included
+	wantKeys := []string{
+		"id", "jurisdiction", "company_name", "company_address", "nature_of_business", "number_of_directors",
+		"number_of_shareholders", "sec_code", "registry_source", "registry_number", "date_created", "date_updated", "version",
+	}
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want the 2 UK companies; body %s", len(lines), rec.Body.String())
+	}
+	for _, line := range lines {
+		decoder := json.NewDecoder(strings.NewReader(line))
+		if _, err := decoder.Token(); err != nil {
+			t.Fatal(err)
+		}
+		var keys []string
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys = append(keys, key.(string))
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if strings.Join(keys, ",") != strings.Join(wantKeys, ",") {
+			t.Errorf("keys = %v, want %v", keys, wantKeys)
+		}
+
+		var record ndjsonCompany
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		if record.Version != record.DateUpdated.UTC().Format(time.RFC3339Nano) {
+			t.Errorf("version = %q, want date_updated %s", record.Version, record.DateUpdated)
+		}
+	}
+
+	rec = serve(export, http.MethodGet, "/api/v1/companies/export.ndjson?jurisdiction=Cayman+Islands", "", "")
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("no matches: status = %d, body %q, want 200 with an empty body", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(export, http.MethodGet, "/api/v1/companies/export.ndjson?created_after=yesterday", "", "")
+	if rec.Code != http.StatusBadRequest || rec.Header().Get("Content-Type") == "application/x-ndjson" {
+		t.Errorf("invalid filter: status = %d, Content-Type %q, want a 400 JSON error", rec.Code, rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestDeleteCompaniesRejectsInvalidIDsFirst(t *testing.T) {
+	repo := repositorytest.NewCompanyRepository()
+	company, err := repo.Create(context.Background(), api.CreateCompanyRequest{CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleteCompanies := http.HandlerFunc(newTestHandlers(t, repo).DeleteCompanies)
+
+	body := `["` + company.Id.String() + `", "not-a-uuid"]`
+	rec := serve(deleteCompanies, http.MethodPost, "/api/v1/companies/batch-delete", "application/json", body)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "index 1") {
+		t.Errorf("status = %d, body %s, want a 400 naming index 1", rec.Code, rec.Body.String())
+	}
+	if got, err := repo.GetByID(context.Background(), company.Id); err != nil || got == nil {
+		t.Fatalf("company after the rejected batch = %v (%v), want it still live", got, err)
+	}
+
+	body = `["` + company.Id.String() + `", "` + company.Id.String() + `"]`
+	rec = serve(deleteCompanies, http.MethodPost, "/api/v1/companies/batch-delete", "application/json", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	var resp api.BatchDeleteResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Deleted != 1 || len(resp.NotFound) != 0 {
+		t.Errorf("response = %+v, want one deleted and none not found", resp)
+	}
+}
+
+func TestCacheableResponsesVaryOnNegotiation(t *testing.T) {
+	repo := repositorytest.NewCompanyRepository()
+	handler := newTestRouter(t, repo)
+	company := createTestCompany(t, handler)
+
+	h := newTestHandlers(t, repo)
+	h.opts.ListCacheMaxAge = time.Minute
+	list := http.HandlerFunc(h.GetCompanies)
+
+	get := func(handler http.Handler, target, accept, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept", accept)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, tt := range []struct {
+		name    string
+		handler http.Handler
+		target  string
+	}{
+		{"list", list, "/api/v1/companies"},
+		{"company", handler, "/api/v1/companies/" + company.Id.String()},
+	} {
+		for _, accept := range []string{"application/json", "application/msgpack"} {
+			rec := get(tt.handler, tt.target, accept, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("%s as %s: status = %d; body %s", tt.name, accept, rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept, Accept-Language" {
+				t.Errorf("%s as %s: Vary = %q, want Accept, Accept-Language", tt.name, accept, got)
+			}
+
+			rec = get(tt.handler, tt.target, accept, rec.Header().Get("ETag"))
+			if rec.Code != http.StatusNotModified || rec.Header().Get("Vary") != "Accept, Accept-Language" {
+				t.Errorf("%s as %s revalidated: status = %d, Vary = %q, want a 304 with the same Vary", tt.name, accept, rec.Code, rec.Header().Get("Vary"))
+			}
+		}
+	}
+	if got := get(list, "/api/v1/companies", "application/json", "").Header().Get("Cache-Control"); got != "max-age=60" {
+		t.Errorf("list Cache-Control = %q, want max-age=60", got)
+	}
+}
+
+func TestResolveJurisdiction(t *testing.T) {
+	resolve := http.HandlerFunc(newTestHandlers(t, repositorytest.NewCompanyRepository()).ResolveJurisdiction)
+
+	tests := []struct {
+		name  string
+		query string
+		want  string // Canonical value; "" when unmatched
+	}{
+		{"exact", "value=Singapore", "Singapore"},
+		{"alias", "value=gb", "UK"},
+		{"alias with case and spacing", "value=%20Cayman%20%20ISLANDS%20", "Cayman Islands"},
+		{"legacy spelling", "value=Caymens", "Cayman Islands"},
+		{"unknown", "value=Narnia", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(resolve, http.MethodGet, "/api/v1/jurisdictions/resolve?"+tt.query, "", "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				if body["matched"] != false || body["canonical"] != nil {
+					t.Errorf("body = %v, want matched false and no canonical value", body)
+				}
+				return
+			}
+			if body["matched"] != true || body["canonical"] != tt.want {
+				t.Errorf("body = %v, want matched true with canonical %q", body, tt.want)
+			}
+		})
+	}
+
+	for _, query := range []string{"", "value=", "value=%20%20"} {
+		if rec := serve(resolve, http.MethodGet, "/api/v1/jurisdictions/resolve?"+query, "", ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("query %q: status = %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestRouteAliasesReachTheCanonicalHandlers(t *testing.T) {
+	repo := repositorytest.NewCompanyRepository()
+	handler := appmiddleware.RouteAliases("/api/v1", map[string]string{"organisations": "companies"})(newTestRouter(t, repo))
+
+	// Creating through the alias answers with the canonical Location
+	rec := serve(handler, http.MethodPost, "/api/v1/organisations", "application/json",
+		`{"company_name": "Acme Ltd", "company_address": "1 High Street", "jurisdiction": "UK"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST through the alias: status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	var company api.Company
+	if err := json.Unmarshal(rec.Body.Bytes(), &company); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rec.Header().Get("Location"), "/api/v1/companies/"+company.Id.String(); got != want {
+		t.Errorf("Location = %q, want %q", got, want)
+	}
+	if rec := serve(handler, http.MethodPost, "/api/v1/companies", "application/json",
+		`{"company_name": "Beta Ltd", "company_address": "2 High Street", "jurisdiction": "UK"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("POST to the canonical path: status = %d; body %s", rec.Code, rec.Body.String())
+	}
+
+	// The aliased and canonical item paths return the same company
+	aliased := serve(handler, http.MethodGet, "/api/v1/organisations/"+company.Id.String(), "", "")
+	canonical := serve(handler, http.MethodGet, "/api/v1/companies/"+company.Id.String(), "", "")
+	if aliased.Code != http.StatusOK || aliased.Body.String() != canonical.Body.String() || aliased.Header().Get("ETag") != canonical.Header().Get("ETag") {
+		t.Errorf("aliased GET = %d %s, want the canonical response %d %s", aliased.Code, aliased.Body.String(), canonical.Code, canonical.Body.String())
+	}
+
+	// A tail after the alias segment is kept
+	if rec := serve(handler, http.MethodGet, "/api/v1/organisations/"+company.Id.String()+".pdf", "", ""); rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("aliased PDF: status = %d, Content-Type = %q, want the company PDF", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	// Page links point at the canonical collection
+	rec = serve(handler, http.MethodGet, "/api/v1/organisations?limit=1", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("aliased list: status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	links := rec.Header().Get("Link")
+	if links == "" || strings.Contains(links, "organisations") || !strings.Contains(links, "</api/v1/companies?") {
+		t.Errorf("Link = %q, want links to /api/v1/companies only", links)
+	}
+}
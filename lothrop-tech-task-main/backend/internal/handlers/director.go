@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/api"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// ListDirectors handles GET /api/v1/companies/{id}/directors
+func (h *CompanyHandlers) ListDirectors(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Listing directors", zap.String("id", idStr))
+
+	// Parse UUID
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	// Call service
+	directors, err := h.service.ListDirectors(r.Context(), id)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to list directors", "Failed to list directors")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, directors)
+}
+
+// AddDirector handles POST /api/v1/companies/{id}/directors
+func (h *CompanyHandlers) AddDirector(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Adding director", zap.String("id", idStr), subject(r))
+
+	// Parse UUID
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	// Parse request body
+	var req api.CreateDirectorRequest
+	if !h.decodeBody(w, r, &req) {
+		return
+	}
+
+	// Call service
+	director, err := h.service.AddDirector(r.Context(), id, req)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to add director", "Failed to add director")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusCreated, director)
+}
+
+// RemoveDirector handles DELETE /api/v1/companies/{id}/directors/{directorId}
+func (h *CompanyHandlers) RemoveDirector(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	directorIDStr := chi.URLParam(r, "directorId")
+	h.log(r).Info("Removing director", zap.String("id", idStr), zap.String("director_id", directorIDStr), subject(r))
+
+	// Parse UUIDs
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	directorID, err := h.parseCompanyID(directorIDStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("director_id", directorIDStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid director ID format")
+		return
+	}
+
+	// Call service
+	err = h.service.RemoveDirector(r.Context(), id, directorID)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to remove director", "Failed to remove director")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"backend/api"
+	"backend/internal/response"
+
+	"go.uber.org/zap"
+)
+
+// swaggerUIPage loads Swagger UI from a CDN and points it at the spec served alongside it
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Companies API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// DocsHandlers contains the HTTP handlers serving the API's own documentation
+type DocsHandlers struct {
+	spec   []byte
+	logger *zap.Logger
+}
+
+// NewDocsHandlers creates a new docs handlers instance, encoding the OpenAPI spec embedded in the api package
+// as JSON once up front
+func NewDocsHandlers(logger *zap.Logger) (*DocsHandlers, error) {
+	spec, err := api.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded OpenAPI spec: %w", err)
+	}
+
+	encoded, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI spec: %w", err)
+	}
+
+	return &DocsHandlers{spec: encoded, logger: logger}, nil
+}
+
+// OpenAPI handles GET /api/v1/openapi.json with the spec the server was built from
+func (h *DocsHandlers) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", response.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(h.spec); err != nil {
+		requestLogger(h.logger, r).Error("Failed to write OpenAPI spec", zap.Error(err))
+	}
+}
+
+// SwaggerUI handles GET /api/v1/docs with an interactive page for exploring the API
+func (h *DocsHandlers) SwaggerUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(swaggerUIPage)); err != nil {
+		requestLogger(h.logger, r).Error("Failed to write Swagger UI page", zap.Error(err))
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// companyETag returns a weak entity tag derived from the JSON encoding of a company or company list, which
+// includes date_updated. It is weak because the same body may be served as JSON or MessagePack, compressed or not.
+func companyETag(company interface{}) (string, error) {
+	raw, err := json.Marshal(company)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(raw)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// etagMatches reports whether the request's If-None-Match header lists etag, using weak comparison
+func etagMatches(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// notModifiedSince reports whether the request's If-Modified-Since is no earlier than modified, compared at the
+// one-second resolution of HTTP dates. Per RFC 9110 the header is ignored when If-None-Match is present, and
+// an unparseable date is ignored rather than rejected.
+func notModifiedSince(r *http.Request, modified time.Time) bool {
+	if r.Header.Get("If-None-Match") != "" {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/api"
+	"backend/internal/pdf"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// csvHeader lists the exported company fields in column order
+var csvHeader = []string{
+	"id", "jurisdiction", "company_name", "company_address", "nature_of_business",
+	"number_of_directors", "number_of_shareholders", "sec_code", "registry_source", "registry_number",
+	"date_created", "date_updated",
+}
+
+// ExportCompaniesCSV handles GET /api/v1/companies.csv
+func (h *CompanyHandlers) ExportCompaniesCSV(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Exporting companies as CSV")
+
+	params := api.GetCompaniesParams{}
+	if !h.parseFilterParams(w, r, &params) {
+		return
+	}
+
+	// Headers are written with the first row, so filter errors can still be reported as a JSON error
+	var writer *csv.Writer
+	start := func() {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="companies.csv"`)
+		w.WriteHeader(http.StatusOK)
+		writer = csv.NewWriter(w)
+		_ = writer.Write(csvHeader)
+	}
+
+	err := h.service.ExportCompanies(r.Context(), params, func(company *api.Company) error {
+		if writer == nil {
+			start()
+		}
+		return writer.Write(csvRecord(company))
+	})
+	if err != nil {
+		if writer == nil {
+			h.sendServiceError(w, r, err, "Failed to export companies", "Failed to export companies")
+			return
+		}
+		// The status is already sent, so the client sees a truncated file
+		h.log(r).Error("Failed to stream companies CSV", zap.Error(err))
+	}
+
+	if writer == nil {
+		start()
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		h.log(r).Error("Failed to write companies CSV", zap.Error(err))
+	}
+}
+
+// csvRecord returns the CSV cells for a company in csvHeader order
+func csvRecord(company *api.Company) []string {
+	return []string{
+		company.Id.String(),
+		company.Jurisdiction,
+		csvText(company.CompanyName),
+		csvText(company.CompanyAddress),
+		csvOptionalText(company.NatureOfBusiness),
+		csvOptionalInt(company.NumberOfDirectors),
+		csvOptionalInt(company.NumberOfShareholders),
+		csvOptionalText(company.SecCode),
+		csvOptionalText(company.RegistrySource),
+		csvOptionalText(company.RegistryNumber),
+		company.DateCreated.Format(time.RFC3339),
+		company.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// csvText guards free text against spreadsheet formula injection by prefixing formula-leading characters with a quote
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// csvOptionalText returns the guarded text, or an empty cell for nil
+func csvOptionalText(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return csvText(*value)
+}
+
+// csvOptionalInt returns the decimal value, or an empty cell for nil
+func csvOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+// ndjsonCompany is one line of the NDJSON export; fields are declared in a fixed order and always present so
+// downstream schema inference sees the same shape on every line
+type ndjsonCompany struct {
+	ID                   string    `json:"id"`
+	Jurisdiction         string    `json:"jurisdiction"`
+	CompanyName          string    `json:"company_name"`
+	CompanyAddress       string    `json:"company_address"`
+	NatureOfBusiness     *string   `json:"nature_of_business"`
+	NumberOfDirectors    *int      `json:"number_of_directors"`
+	NumberOfShareholders *int      `json:"number_of_shareholders"`
+	SecCode              *string   `json:"sec_code"`
+	RegistrySource       *string   `json:"registry_source"`
+	RegistryNumber       *string   `json:"registry_number"`
+	DateCreated          time.Time `json:"date_created"`
+	DateUpdated          time.Time `json:"date_updated"`
+	Version              string    `json:"version"`
+}
+
+// ExportCompaniesNDJSON handles GET /api/v1/companies/export.ndjson
+func (h *CompanyHandlers) ExportCompaniesNDJSON(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Exporting companies as NDJSON")
+
+	params := api.GetCompaniesParams{}
+	if !h.parseFilterParams(w, r, &params) {
+		return
+	}
+
+	// As with the CSV export, headers wait for the first row so filter errors are still sent as JSON
+	var encoder *json.Encoder
+	start := func() {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="companies.ndjson"`)
+		w.WriteHeader(http.StatusOK)
+		encoder = json.NewEncoder(w)
+	}
+
+	err := h.service.ExportCompanies(r.Context(), params, func(company *api.Company) error {
+		if encoder == nil {
+			start()
+		}
+		return encoder.Encode(ndjsonRecord(company))
+	})
+	if err != nil {
+		if encoder == nil {
+			h.sendServiceError(w, r, err, "Failed to export companies", "Failed to export companies")
+			return
+		}
+		h.log(r).Error("Failed to stream companies NDJSON", zap.Error(err))
+		return
+	}
+
+	if encoder == nil {
+		start()
+	}
+}
+
+// ndjsonRecord converts a company to its NDJSON line; version is the expected_version token accepted by PUT and PATCH
+func ndjsonRecord(company *api.Company) ndjsonCompany {
+	return ndjsonCompany{
+		ID:                   company.Id.String(),
+		Jurisdiction:         company.Jurisdiction,
+		CompanyName:          company.CompanyName,
+		CompanyAddress:       company.CompanyAddress,
+		NatureOfBusiness:     company.NatureOfBusiness,
+		NumberOfDirectors:    company.NumberOfDirectors,
+		NumberOfShareholders: company.NumberOfShareholders,
+		SecCode:              company.SecCode,
+		RegistrySource:       company.RegistrySource,
+		RegistryNumber:       company.RegistryNumber,
+		DateCreated:          company.DateCreated,
+		DateUpdated:          company.DateUpdated,
+		Version:              company.DateUpdated.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// ExportCompanyPDF handles GET /api/v1/companies/{id}.pdf with a printable one-page summary of the company
+func (h *CompanyHandlers) ExportCompanyPDF(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Exporting company as PDF", zap.String("id", idStr))
+
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	company, err := h.service.GetCompanyByID(r.Context(), id)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to get company", "Failed to retrieve company")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="company-%s.pdf"`, company.Id))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(pdf.CompanySummary(company)); err != nil {
+		h.log(r).Error("Failed to write company PDF", zap.Error(err))
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"backend/api"
+)
+
+// selectableFields is the allowlist of company fields a ?fields= parameter may name
+var selectableFields = map[string]bool{
+	"id":                     true,
+	"company_name":           true,
+	"company_address":        true,
+	"jurisdiction":           true,
+	"nature_of_business":     true,
+	"number_of_directors":    true,
+	"number_of_shareholders": true,
+	"sec_code":               true,
+	"registry_source":        true,
+	"registry_number":        true,
+	"date_created":           true,
+	"date_updated":           true,
+	"deleted_at":             true,
+}
+
+// embeddableRelations is the allowlist of related collections an ?embed= parameter may name
+var embeddableRelations = map[string]bool{
+	"directors":    true,
+	"shareholders": true,
+}
+
+// fieldSet is a sparse fieldset requested with ?fields=; nil means every field
+type fieldSet map[string]bool
+
+// parseFields parses the comma-separated fields query parameter, returning nil when it is absent or empty.
+// Unknown field names are rejected rather than ignored, so a typo doesn't silently return an empty object.
+func parseFields(r *http.Request) (fieldSet, error) {
+	raw := r.URL.Query().Get("fields")
+	if raw == "" {
+		return nil, nil
+	}
+
+	fields := fieldSet{}
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !selectableFields[field] {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		fields[field] = true
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// parseEmbed parses the comma-separated embed query parameter, returning nil when it is absent or empty.
+// Unknown relations are rejected like unknown fields.
+func parseEmbed(r *http.Request) ([]string, error) {
+	raw := r.URL.Query().Get("embed")
+	if raw == "" {
+		return nil, nil
+	}
+
+	var relations []string
+	seen := map[string]bool{}
+	for _, relation := range strings.Split(raw, ",") {
+		relation = strings.TrimSpace(relation)
+		if relation == "" || seen[relation] {
+			continue
+		}
+		if !embeddableRelations[relation] {
+			return nil, fmt.Errorf("unknown relation %q", relation)
+		}
+		seen[relation] = true
+		relations = append(relations, relation)
+	}
+	return relations, nil
+}
+
+// sparseCompany returns the company's JSON representation restricted to the selected fields
+func (f fieldSet) sparseCompany(company api.Company) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(company)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]json.RawMessage, len(f))
+	for field, value := range all {
+		if f[field] {
+			selected[field] = value
+		}
+	}
+	return selected, nil
+}
+
+// sparseCompanies applies sparseCompany to each company
+func (f fieldSet) sparseCompanies(companies []api.Company) ([]map[string]json.RawMessage, error) {
+	selected := make([]map[string]json.RawMessage, 0, len(companies))
+	for _, company := range companies {
+		sparse, err := f.sparseCompany(company)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, sparse)
+	}
+	return selected, nil
+}
+
+// sparseCompaniesResponse is a list envelope whose companies are restricted to a sparse fieldset; the shallower
+// Companies field takes precedence over the embedded one when marshalled
+type sparseCompaniesResponse struct {
+	*api.CompaniesResponse
+	Companies []map[string]json.RawMessage `json:"companies"`
+}
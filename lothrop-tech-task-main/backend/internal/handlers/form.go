@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"backend/api"
+	"backend/internal/response"
+
+	"go.uber.org/zap"
+)
+
+// createFormFields are the form fields a create accepts, named as in the JSON body
+var createFormFields = map[string]bool{
+	"company_name":           true,
+	"company_address":        true,
+	"jurisdiction":           true,
+	"nature_of_business":     true,
+	"number_of_directors":    true,
+	"number_of_shareholders": true,
+	"registry_source":        true,
+	"registry_number":        true,
+	"sec_code":               true,
+}
+
+// isFormRequest reports whether the request body is declared as an HTML form post
+func isFormRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/x-www-form-urlencoded"
+}
+
+// decodeCreateForm parses a URL-encoded form body into a create request, holding it to the same rules as a JSON
+// one: the body is capped at MaxBodyBytes and unknown fields are rejected. HTML forms submit every input, so an
+// empty optional field is treated as absent rather than as an empty value; only the first value of a repeated
+// field is used.
+func (h *CompanyHandlers) decodeCreateForm(w http.ResponseWriter, r *http.Request, req *api.CreateCompanyRequest) bool {
+	if h.opts.MaxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.opts.MaxBodyBytes)
+	}
+
+	if err := r.ParseForm(); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.log(r).Warn("Request body too large", zap.Int64("limit", tooLarge.Limit))
+			h.sendErrorResponse(w, r, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("Request body cannot exceed %d bytes", tooLarge.Limit))
+			return false
+		}
+		h.log(r).Info("Rejected malformed form body", zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid form body")
+		return false
+	}
+
+	// Only the body: a query parameter named like a field must not fill it in
+	form := r.PostForm
+	for field := range form {
+		if !createFormFields[field] {
+			h.sendUnknownField(w, r, field)
+			return false
+		}
+	}
+
+	text := func(field string) *string {
+		if value := form.Get(field); value != "" {
+			return &value
+		}
+		return nil
+	}
+
+	req.CompanyName = form.Get("company_name")
+	req.CompanyAddress = form.Get("company_address")
+	req.Jurisdiction = form.Get("jurisdiction")
+	req.NatureOfBusiness = text("nature_of_business")
+	req.RegistrySource = text("registry_source")
+	req.RegistryNumber = text("registry_number")
+	req.SecCode = text("sec_code")
+
+	for field, target := range map[string]**int{
+		"number_of_directors":    &req.NumberOfDirectors,
+		"number_of_shareholders": &req.NumberOfShareholders,
+	} {
+		value := form.Get(field)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			h.log(r).Info("Rejected non-integer form field", zap.String("field", field))
+			if err := response.WriteFieldErrors(w, r, http.StatusBadRequest, fmt.Sprintf("Form field %q must be an integer", field),
+				map[string]string{field: "must be an integer"}); err != nil {
+				h.log(r).Error("Failed to encode error response", zap.Error(err))
+			}
+			return false
+		}
+		*target = &n
+	}
+
+	return true
+}
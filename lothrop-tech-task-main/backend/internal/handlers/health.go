@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"backend/api"
+	"backend/internal/buildinfo"
+	"backend/internal/database"
+	"backend/internal/response"
+
+	"go.uber.org/zap"
+)
+
+// HealthHandlers contains the HTTP handlers for orchestrator probes
+type HealthHandlers struct {
+	dbHealth *database.Health
+	ready    atomic.Bool
+	logger   *zap.Logger
+}
+
+// NewHealthHandlers creates a new health handlers instance, initially not ready
+func NewHealthHandlers(dbHealth *database.Health, logger *zap.Logger) *HealthHandlers {
+	return &HealthHandlers{
+		dbHealth: dbHealth,
+		logger:   logger,
+	}
+}
+
+// SetReady marks whether the instance should receive traffic
+func (h *HealthHandlers) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Ready handles GET /ready. Unlike the /health liveness check it reports 503 while the
+// instance is starting up or shutting down, or when the database failed its latest health check.
+func (h *HealthHandlers) Ready(w http.ResponseWriter, r *http.Request) {
+	if !h.ready.Load() {
+		h.sendNotReady(w, r, "not ready")
+		return
+	}
+
+	if healthy, lastCheck, err := h.dbHealth.Status(); !healthy {
+		requestLogger(h.logger, r).Warn("Readiness check failed: database unhealthy",
+			zap.Time("last_check", lastCheck), zap.Error(err))
+		h.sendNotReady(w, r, "database unavailable")
+		return
+	}
+
+	if err := response.WriteJSON(w, http.StatusOK, api.ApiResponse{Error: false, Msg: "ready"}); err != nil {
+		requestLogger(h.logger, r).Error("Failed to encode JSON response", zap.Error(err))
+	}
+}
+
+// versionResponse identifies the running build for deploy verification
+type versionResponse struct {
+	Version       string `json:"version"`
+	Commit        string `json:"commit"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+}
+
+// Version handles GET /version with the build version, git commit and process uptime. It does not touch the
+// database, so unlike /ready it answers whenever the process does.
+func (h *HealthHandlers) Version(w http.ResponseWriter, r *http.Request) {
+	body := versionResponse{
+		Version:       buildinfo.Version,
+		Commit:        buildinfo.Commit,
+		UptimeSeconds: int64(buildinfo.Uptime().Seconds()),
+	}
+	if err := response.WriteJSON(w, http.StatusOK, body); err != nil {
+		requestLogger(h.logger, r).Error("Failed to encode JSON response", zap.Error(err))
+	}
+}
+
+// sendNotReady writes a 503 readiness failure
+func (h *HealthHandlers) sendNotReady(w http.ResponseWriter, r *http.Request, message string) {
+	if err := response.WriteError(w, r, http.StatusServiceUnavailable, message); err != nil {
+		requestLogger(h.logger, r).Error("Failed to encode error response", zap.Error(err))
+	}
+}
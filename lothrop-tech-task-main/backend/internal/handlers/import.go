@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"backend/api"
+	"backend/internal/i18n"
+	"backend/internal/metrics"
+	"backend/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// ImportCompanies handles POST /api/v1/companies/import
+func (h *CompanyHandlers) ImportCompanies(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Importing companies from CSV", subject(r))
+
+	strict := h.opts.ImportStrict
+	switch api.ImportCompaniesParamsMode(r.URL.Query().Get("mode")) {
+	case "":
+	case api.Strict:
+		strict = true
+	case api.Lenient:
+		strict = false
+	default:
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid mode parameter: must be strict or lenient")
+		return
+	}
+
+	dryRun, ok := h.parseDryRun(w, r)
+	if !ok {
+		return
+	}
+
+	if h.opts.MaxImportBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.opts.MaxImportBytes)
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.log(r).Warn("Import upload too large", zap.Int64("limit", tooLarge.Limit))
+			h.sendErrorResponse(w, r, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("Import upload cannot exceed %d bytes", tooLarge.Limit))
+			return
+		}
+		h.log(r).Error("Failed to read import upload", zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "A CSV file must be uploaded in the file form field")
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.ImportCompaniesCSV(r.Context(), file, strict, dryRun)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to import companies", "Failed to import companies")
+		return
+	}
+
+	report := importReport(r, result)
+	status := http.StatusOK
+	if report.Failed > 0 {
+		metrics.RecordValidationFailure()
+		if result.Strict {
+			status = http.StatusUnprocessableEntity
+		}
+	}
+
+	h.sendResponse(w, r, status, report)
+}
+
+// importReport converts an import result to its API form, localizing each row's validation message
+func importReport(r *http.Request, result *service.ImportResult) api.ImportReport {
+	locale := i18n.Negotiate(r)
+	report := api.ImportReport{Rows: make([]api.ImportRowReport, len(result.Rows))}
+
+	for i, row := range result.Rows {
+		item := api.ImportRowReport{Line: row.Line}
+		switch {
+		case row.Err != nil:
+			item.Status = api.Invalid
+			msg := i18n.Sprintf(locale, row.Err.Format, row.Err.Args...)
+			item.Msg = &msg
+			if row.Err.Field != "" {
+				field := row.Err.Field
+				item.Field = &field
+			}
+			report.Failed++
+		case row.Company != nil:
+			item.Status = api.Created
+			item.Id = &row.Company.Id
+			report.Created++
+		default:
+			item.Status = api.Skipped
+		}
+		report.Rows[i] = item
+	}
+
+	if result.DryRun {
+		report.DryRun = &result.DryRun
+	}
+
+	return report
+}
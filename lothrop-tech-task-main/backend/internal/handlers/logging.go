@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/logctx"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// requestLogger returns the request-scoped logger stored by the RequestLogger middleware, which carries the
+// request's ID, method, path and remote IP. Without it, logger is annotated with the request ID alone, so log
+// lines can still be matched to the request_id a client reports from an error response.
+func requestLogger(logger *zap.Logger, r *http.Request) *zap.Logger {
+	if reqLogger := logctx.FromContext(r.Context(), nil); reqLogger != nil {
+		return reqLogger
+	}
+	if id := middleware.GetReqID(r.Context()); id != "" {
+		return logger.With(zap.String("request_id", id))
+	}
+	return logger
+}
+
+// log returns the handler's request-scoped logger
+func (h *CompanyHandlers) log(r *http.Request) *zap.Logger {
+	return requestLogger(h.logger, r)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// parsePageParam reads the named limit or offset query parameter, nil when absent. It accepts only a plain run of
+// decimal digits no greater than math.MaxInt32, so signs, whitespace and values that would overflow get a 400
+// before reaching the service, which still checks each parameter's own range.
+func (h *CompanyHandlers) parsePageParam(w http.ResponseWriter, r *http.Request, name string) (*int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, true
+	}
+
+	for _, c := range raw {
+		if c < '0' || c > '9' {
+			h.sendErrorResponse(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid %s parameter: must be a non-negative integer", name))
+			return nil, false
+		}
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		h.sendErrorResponse(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid %s parameter: must be at most %d", name, math.MaxInt32))
+		return nil, false
+	}
+
+	parsed := int(value)
+	return &parsed, true
+}
+
+// pageLink is a single RFC 5988 link relation pointing at another page of a list
+type pageLink struct {
+	rel    string
+	offset int
+}
+
+// paginationLinks computes first, prev, next and last page links for a list response.
+// prev is omitted on the first page and next on the last page. A negative total was not counted, so there is
+// no last link. Pages past maxOffset, which the service would reject, are not linked; clients continue from
+// there with next_cursor. A maxOffset of 0 means offsets are not capped.
+func paginationLinks(limit, offset, total, maxOffset int, hasNext bool) []pageLink {
+	links := []pageLink{{rel: "first", offset: 0}}
+	allowed := func(offset int) bool { return maxOffset == 0 || offset <= maxOffset }
+
+	if offset > 0 {
+		prevOffset := offset - limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		links = append(links, pageLink{rel: "prev", offset: prevOffset})
+	}
+
+	if hasNext && allowed(offset+limit) {
+		links = append(links, pageLink{rel: "next", offset: offset + limit})
+	}
+
+	if total < 0 {
+		return links
+	}
+
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = ((total - 1) / limit) * limit
+	}
+	if !allowed(lastOffset) {
+		return links
+	}
+	return append(links, pageLink{rel: "last", offset: lastOffset})
+}
+
+// setLinkHeader sets the Link header for the given page links, preserving every other query parameter of the request
+func setLinkHeader(w http.ResponseWriter, r *http.Request, limit int, links []pageLink) {
+	values := make([]string, 0, len(links))
+	for _, link := range links {
+		values = append(values, fmt.Sprintf(`<%s>; rel="%s"`, pageURL(r.URL, limit, link.offset), link.rel))
+	}
+
+	w.Header().Set("Link", strings.Join(values, ", "))
+}
+
+// pageURL returns the request path and query with limit and offset replaced
+func pageURL(u *url.URL, limit, offset int) string {
+	query := u.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+
+	return u.Path + "?" + query.Encode()
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/api"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// ListShareholders handles GET /api/v1/companies/{id}/shareholders
+func (h *CompanyHandlers) ListShareholders(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Listing shareholders", zap.String("id", idStr))
+
+	// Parse UUID
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	// Call service
+	shareholders, err := h.service.ListShareholders(r.Context(), id)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to list shareholders", "Failed to list shareholders")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, shareholders)
+}
+
+// AddShareholder handles POST /api/v1/companies/{id}/shareholders
+func (h *CompanyHandlers) AddShareholder(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.log(r).Info("Adding shareholder", zap.String("id", idStr), subject(r))
+
+	// Parse UUID
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	// Parse request body
+	var req api.CreateShareholderRequest
+	if !h.decodeBody(w, r, &req) {
+		return
+	}
+
+	// Call service
+	shareholder, err := h.service.AddShareholder(r.Context(), id, req)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to add shareholder", "Failed to add shareholder")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusCreated, shareholder)
+}
+
+// UpdateShareholder handles PUT /api/v1/companies/{id}/shareholders/{shareholderId}
+func (h *CompanyHandlers) UpdateShareholder(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	shareholderIDStr := chi.URLParam(r, "shareholderId")
+	h.log(r).Info("Updating shareholder", zap.String("id", idStr), zap.String("shareholder_id", shareholderIDStr), subject(r))
+
+	// Parse UUIDs
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	shareholderID, err := h.parseCompanyID(shareholderIDStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("shareholder_id", shareholderIDStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid shareholder ID format")
+		return
+	}
+
+	// Parse request body
+	var req api.CreateShareholderRequest
+	if !h.decodeBody(w, r, &req) {
+		return
+	}
+
+	// Call service
+	shareholder, err := h.service.UpdateShareholder(r.Context(), id, shareholderID, req)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to update shareholder", "Failed to update shareholder")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, shareholder)
+}
+
+// RemoveShareholder handles DELETE /api/v1/companies/{id}/shareholders/{shareholderId}
+func (h *CompanyHandlers) RemoveShareholder(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	shareholderIDStr := chi.URLParam(r, "shareholderId")
+	h.log(r).Info("Removing shareholder", zap.String("id", idStr), zap.String("shareholder_id", shareholderIDStr), subject(r))
+
+	// Parse UUIDs
+	id, err := h.parseCompanyID(idStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("id", idStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid company ID format")
+		return
+	}
+
+	shareholderID, err := h.parseCompanyID(shareholderIDStr)
+	if err != nil {
+		h.log(r).Error("Invalid UUID format", zap.String("shareholder_id", shareholderIDStr), zap.Error(err))
+		h.sendErrorResponse(w, r, http.StatusBadRequest, "Invalid shareholder ID format")
+		return
+	}
+
+	// Call service
+	err = h.service.RemoveShareholder(r.Context(), id, shareholderID)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to remove shareholder", "Failed to remove shareholder")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/api"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// OpenSnapshot handles POST /api/v1/companies/snapshots
+func (h *CompanyHandlers) OpenSnapshot(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Info("Opening company snapshot")
+
+	params := api.GetCompaniesParams{}
+	if !h.parseFilterParams(w, r, &params) {
+		return
+	}
+
+	snapshot, err := h.service.OpenSnapshot(r.Context(), params)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to open snapshot", "Failed to open snapshot")
+		return
+	}
+
+	h.log(r).Info("Opened company snapshot", zap.String("snapshot_id", snapshot.SnapshotId))
+	h.sendResponse(w, r, http.StatusCreated, snapshot)
+}
+
+// NextSnapshotPage handles GET /api/v1/companies/snapshots/{snapshotId}
+func (h *CompanyHandlers) NextSnapshotPage(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "snapshotId")
+	h.log(r).Info("Reading company snapshot page", zap.String("snapshot_id", id))
+
+	limit, ok := h.parsePageParam(w, r, "limit")
+	if !ok {
+		return
+	}
+
+	page, err := h.service.NextSnapshotPage(r.Context(), id, limit)
+	if err != nil {
+		h.sendServiceError(w, r, err, "Failed to read snapshot", "Failed to read snapshot")
+		return
+	}
+
+	h.sendResponse(w, r, http.StatusOK, page)
+}
+
+// CloseSnapshot handles DELETE /api/v1/companies/snapshots/{snapshotId}
+func (h *CompanyHandlers) CloseSnapshot(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "snapshotId")
+	h.log(r).Info("Closing company snapshot", zap.String("snapshot_id", id))
+
+	if err := h.service.CloseSnapshot(id); err != nil {
+		h.sendServiceError(w, r, err, "Failed to close snapshot", "Failed to close snapshot")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
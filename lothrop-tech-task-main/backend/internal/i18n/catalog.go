@@ -0,0 +1,97 @@
+package i18n
+
+// messages maps a locale to translations keyed by the English message format. Translations must keep
+// the same verbs in the same order as the English format.
+var messages = map[string]map[string]string{
+	"fr": {
+		"company name is required":                                      "le nom de la société est obligatoire",
+		"company name cannot exceed 255 characters":                     "le nom de la société ne peut pas dépasser 255 caractères",
+		"company address is required":                                   "l'adresse de la société est obligatoire",
+		"invalid jurisdiction: must be one of %v":                       "juridiction invalide : doit être l'une de %v",
+		"company address cannot exceed %d characters":                   "l'adresse de la société ne peut pas dépasser %d caractères",
+		"nature of business cannot exceed %d characters":                "la nature de l'activité ne peut pas dépasser %d caractères",
+		"number of directors is required for %s":                        "le nombre de dirigeants est obligatoire pour %s",
+		"%s requires at least %d directors":                             "%s exige au moins %d dirigeants",
+		"number of directors must be between 1 and 100":                 "le nombre de dirigeants doit être compris entre 1 et 100",
+		"number of shareholders for %s must be between 1 and %d":        "le nombre d'actionnaires pour %s doit être compris entre 1 et %d",
+		"number of shareholders must be between 1 and 1000":             "le nombre d'actionnaires doit être compris entre 1 et 1000",
+		"registry source and registry number must be provided together": "la source et le numéro de registre doivent être fournis ensemble",
+		"registry source for %s must be %s":                             "la source de registre pour %s doit être %s",
+		"invalid registry number format for %s":                         "format de numéro de registre invalide pour %s",
+		"registry number %s is already linked to another company":       "le numéro de registre %s est déjà associé à une autre société",
+		"company name matches existing company %s":                      "le nom de la société correspond à la société existante %s",
+		"registry source and number are required":                       "la source et le numéro de registre sont obligatoires",
+		"limit must be between %d and %d":                               "la limite doit être comprise entre %d et %d",
+		"offset must be non-negative":                                   "le décalage ne peut pas être négatif",
+		"offset must be at most %d; use cursor=next_cursor instead":     "le décalage ne peut pas dépasser %d ; utilisez plutôt cursor=next_cursor",
+		"include_total=false cannot be combined with limit=0":           "include_total=false ne peut pas être combiné avec limit=0",
+		"order requires sort":                                           "le paramètre order nécessite sort",
+		"invalid sort column: %s":                                       "colonne de tri invalide : %s",
+		"invalid sort order: %s":                                        "ordre de tri invalide : %s",
+		"invalid cursor":                                                "curseur invalide",
+		"cursor cannot be combined with sort":                           "le curseur ne peut pas être combiné avec sort",
+		"cursor cannot be combined with offset":                         "le curseur ne peut pas être combiné avec offset",
+		"cursor cannot be combined with q":                              "le curseur ne peut pas être combiné avec q",
+		"created_after cannot be later than created_before":             "created_after ne peut pas être postérieur à created_before",
+		"invalid jurisdiction filter: %s":                               "filtre de juridiction invalide : %s",
+		"batch must contain at least one company":                       "le lot doit contenir au moins une société",
+		"batch must contain at least one company ID":                    "le lot doit contenir au moins un identifiant de société",
+		"delta must be a non-zero integer between %d and %d":            "delta doit être un entier non nul compris entre %d et %d",
+		"batch cannot exceed %d companies":                              "le lot ne peut pas dépasser %d sociétés",
+		"registry number %s appears more than once in the batch":        "le numéro de registre %s apparaît plusieurs fois dans le lot",
+		"company name %s appears more than once for %s in the batch":    "le nom de société %s apparaît plusieurs fois pour %s dans le lot",
+		"sec code %s appears more than once in the batch":               "le code SEC %s apparaît plusieurs fois dans le lot",
+		"too many filters: at most %d may be combined":                  "trop de filtres : %d au maximum peuvent être combinés",
+		"extract limit must be between 1 and 1000":                      "la limite d'extraction doit être comprise entre 1 et 1000",
+		"since_id requires since":                                       "since_id nécessite since",
+		"company address appears to be outside %s":                      "l'adresse de la société semble se trouver hors de %s",
+		"company rejected: %s":                                          "société refusée : %s",
+		"company address for %s must be a full address in the required format or a registered agent reference": "l'adresse de la société pour %s doit être une adresse complète au format requis ou une référence d'agent agréé",
+		"company address for %s is not in the required format":                                                 "l'adresse de la société pour %s n'est pas au format requis",
+		"recent_minutes must be between 1 and %d":                                                              "recent_minutes doit être compris entre 1 et %d",
+		"director name is required":                                                                            "le nom du dirigeant est obligatoire",
+		"director name cannot exceed 255 characters":                                                           "le nom du dirigeant ne peut pas dépasser 255 caractères",
+		"director role is required":                                                                            "la fonction du dirigeant est obligatoire",
+		"director role cannot exceed 100 characters":                                                           "la fonction du dirigeant ne peut pas dépasser 100 caractères",
+		"shareholder name is required":                                                                         "le nom de l'actionnaire est obligatoire",
+		"shareholder name cannot exceed 255 characters":                                                        "le nom de l'actionnaire ne peut pas dépasser 255 caractères",
+		"ownership percentage must be greater than 0 and at most 100":                                          "le pourcentage de détention doit être supérieur à 0 et au plus égal à 100",
+		"ownership percentage cannot have more than 2 decimal places":                                          "le pourcentage de détention ne peut pas avoir plus de 2 décimales",
+		"the company already has the most shareholders its jurisdiction allows":                                "la société a déjà le nombre maximal d'actionnaires autorisé par sa juridiction",
+		"a company must keep at least one shareholder":                                                         "une société doit conserver au moins un actionnaire",
+		"total ownership across a company's shareholders cannot exceed 100%%":                                  "la détention totale des actionnaires d'une société ne peut pas dépasser 100 %%",
+		"a company cannot have more than %d directors":                                                         "une société ne peut pas avoir plus de %d dirigeants",
+		"Idempotency-Key must be between 1 and %d characters":                                                  "Idempotency-Key doit comporter entre 1 et %d caractères",
+		"Idempotency-Key must contain only printable ASCII characters":                                         "Idempotency-Key ne doit contenir que des caractères ASCII imprimables",
+		"sec code does not match the required format for %s":                                                   "le code SEC ne respecte pas le format requis pour %s",
+		"CSV file is empty":                   "le fichier CSV est vide",
+		"invalid CSV header":                  "en-tête CSV invalide",
+		"CSV header is missing the %s column": "l'en-tête CSV ne contient pas la colonne %s",
+		"malformed CSV row":                   "ligne CSV mal formée",
+		"CSV file cannot exceed %d rows":      "le fichier CSV ne peut pas dépasser %d lignes",
+		"CSV file has no data rows":           "le fichier CSV ne contient aucune ligne de données",
+		"%s must be a whole number":           "%s doit être un nombre entier",
+		"min must be at least 2":              "min doit être au moins égal à 2",
+		"number of directors is set by the company's %d director records; add or remove directors instead":          "le nombre de dirigeants est fixé par les %d dirigeants enregistrés de la société ; ajoutez ou supprimez plutôt des dirigeants",
+		"removing the director would leave the company fewer directors than its jurisdiction requires":              "supprimer ce dirigeant laisserait à la société moins de dirigeants que sa juridiction n'en exige",
+		"number of shareholders is set by the company's %d shareholder records; add or remove shareholders instead": "le nombre d'actionnaires est fixé par les %d actionnaires enregistrés de la société ; ajoutez ou supprimez plutôt des actionnaires",
+	},
+}
+
+// statusTexts maps a locale to HTTP status texts, used as problem details titles
+var statusTexts = map[string]map[int]string{
+	"fr": {
+		400: "Requête incorrecte",
+		401: "Non autorisé",
+		403: "Interdit",
+		404: "Introuvable",
+		405: "Méthode non autorisée",
+		409: "Conflit",
+		413: "Contenu trop volumineux",
+		415: "Type de média non pris en charge",
+		422: "Entité non traitable",
+		429: "Trop de requêtes",
+		500: "Erreur interne du serveur",
+		503: "Service indisponible",
+	},
+}
@@ -0,0 +1,73 @@
+package i18n
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is used when the client accepts no supported locale
+const DefaultLocale = "en"
+
+// Negotiate returns the preferred supported locale from the request's Accept-Language header,
+// falling back to DefaultLocale
+func Negotiate(r *http.Request) string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+
+	var prefs []preference
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		if fields[0] == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+		if quality > 0 {
+			prefs = append(prefs, preference{tag: strings.ToLower(fields[0]), quality: quality})
+		}
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].quality > prefs[j].quality })
+
+	for _, pref := range prefs {
+		// Match on the primary language subtag, so fr-CA is served fr
+		language, _, _ := strings.Cut(pref.tag, "-")
+		if language == DefaultLocale {
+			return DefaultLocale
+		}
+		if _, ok := messages[language]; ok {
+			return language
+		}
+	}
+
+	return DefaultLocale
+}
+
+// Sprintf formats an English message format in the given locale, falling back to English when the
+// locale has no translation for it
+func Sprintf(locale, format string, args ...interface{}) string {
+	if translated, ok := messages[locale][format]; ok {
+		format = translated
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// StatusText returns the HTTP status text for code in the given locale, falling back to English
+func StatusText(locale string, code int) string {
+	if text, ok := statusTexts[locale][code]; ok {
+		return text
+	}
+	return http.StatusText(code)
+}
@@ -0,0 +1,88 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"fr", "fr"},
+		{"FR", "fr"},
+		{"fr-CA", "fr"},
+		{"de", "en"},
+		{"de, fr;q=0.8", "fr"},
+		{"en;q=0.5, fr;q=0.9", "fr"},
+		{"fr;q=0.5, en", "en"},
+		{"fr;q=0", "en"},
+		{"en-GB, fr", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Accept-Language", tt.header)
+			}
+			if got := Negotiate(req); got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	format := "%s requires at least %d directors"
+
+	if got := Sprintf("fr", format, "Singapore", 2); got != "Singapore exige au moins 2 dirigeants" {
+		t.Errorf("fr = %q", got)
+	}
+	if got := Sprintf("en", format, "Singapore", 2); got != "Singapore requires at least 2 directors" {
+		t.Errorf("en = %q", got)
+	}
+	if got := Sprintf("fr", "an untranslated %s", "message"); got != "an untranslated message" {
+		t.Errorf("untranslated = %q, want the English message", got)
+	}
+}
+
+func TestStatusText(t *testing.T) {
+	if got := StatusText("fr", http.StatusNotFound); got != "Introuvable" {
+		t.Errorf("fr 404 = %q", got)
+	}
+	if got := StatusText("en", http.StatusNotFound); got != "Not Found" {
+		t.Errorf("en 404 = %q", got)
+	}
+	if got := StatusText("fr", http.StatusTeapot); got != http.StatusText(http.StatusTeapot) {
+		t.Errorf("untranslated status = %q, want the English text", got)
+	}
+}
+
+// verbs matches the formatting verbs of a message format, ignoring escaped percent signs
+var verbs = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogKeepsVerbs(t *testing.T) {
+	formatVerbs := func(format string) string {
+		var found []string
+		for _, verb := range verbs.FindAllString(format, -1) {
+			if verb != "%%" {
+				found = append(found, verb)
+			}
+		}
+		return strings.Join(found, " ")
+	}
+
+	for locale, catalog := range messages {
+		for english, translated := range catalog {
+			if got, want := formatVerbs(translated), formatVerbs(english); got != want {
+				t.Errorf("%s translation of %q has verbs %q, want %q", locale, english, got, want)
+			}
+		}
+	}
+}
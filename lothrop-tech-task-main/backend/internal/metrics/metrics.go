@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route pattern and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	companyErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "company_handler_errors_total",
+		Help: "Company handler errors by kind (validation or internal).",
+	}, []string{"kind"})
+
+	dbRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_retries_total",
+		Help: "Database operations retried after a transient error, by operation.",
+	}, []string{"operation"})
+
+	dbPoolRejectionsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "db_pool_rejections_total",
+		Help: "Requests rejected with 503 because no database connection came free in time.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(requestsTotal, requestDuration, companyErrorsTotal, dbRetriesTotal, dbPoolRejectionsTotal)
+}
+
+// Handler serves the registered metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Middleware records request count and latency labeled by chi route pattern, so
+// /api/v1/companies/{id} is one series rather than one per company
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		// The pattern is only known once routing has happened; unmatched paths share one label
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+
+		labels := prometheus.Labels{"method": r.Method, "route": route, "status": strconv.Itoa(status)}
+		requestsTotal.With(labels).Inc()
+		requestDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// RecordValidationFailure counts a company request rejected by validation
+func RecordValidationFailure() {
+	companyErrorsTotal.WithLabelValues("validation").Inc()
+}
+
+// RecordInternalError counts a company request that failed with an internal error
+func RecordInternalError() {
+	companyErrorsTotal.WithLabelValues("internal").Inc()
+}
+
+// RegisterDBHealth exports the database health reported by healthy as the db_up gauge, 1 when the latest
+// health check succeeded and 0 otherwise
+func RegisterDBHealth(healthy func() bool) {
+	prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "db_up",
+		Help: "Whether the database answered its latest health check (1) or not (0).",
+	}, func() float64 {
+		if healthy() {
+			return 1
+		}
+		return 0
+	}))
+}
+
+// RegisterDBStats exports db's connection pool statistics, e.g. go_sql_in_use_connections and
+// go_sql_wait_count_total, labeled db_name="companies"
+func RegisterDBStats(db *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, "companies"))
+}
+
+// RecordDBPoolRejection counts a request rejected because the database connection pool was exhausted
+func RecordDBPoolRejection() {
+	dbPoolRejectionsTotal.Inc()
+}
+
+// RecordDBRetry counts a database operation retried after a transient error
+func RecordDBRetry(operation string) {
+	dbRetriesTotal.WithLabelValues(operation).Inc()
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"backend/internal/logctx"
+	"backend/internal/response"
+
+	"go.uber.org/zap"
+)
+
+// RequireAdminToken rejects requests that do not present the admin token as a bearer token
+func RequireAdminToken(token string, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !HasAdminToken(r, token) {
+				logctx.FromContext(r.Context(), logger).Warn("Rejected admin request")
+				response.WriteError(w, r, http.StatusUnauthorized, "Admin token required")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// HasAdminToken reports whether the request presents the admin token as a bearer token
+func HasAdminToken(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+
+	presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1
+}
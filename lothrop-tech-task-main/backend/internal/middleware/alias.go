@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RouteAliases rewrites request paths under prefix whose first segment is an alias to use the canonical
+// segment instead, e.g. /api/v1/organisations/{id} to /api/v1/companies/{id}, before routing. Handlers then
+// only see canonical paths, so links they build from the request URL use the canonical path too.
+func RouteAliases(prefix string, aliases map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rest, ok := strings.CutPrefix(r.URL.Path, prefix+"/")
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			segment, tail, found := strings.Cut(rest, "/")
+			canonical, ok := aliases[segment]
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			path := prefix + "/" + canonical
+			if found {
+				path += "/" + tail
+			}
+
+			// Rewrite a copy of the request, as http.StripPrefix does
+			r2 := new(http.Request)
+			*r2 = *r
+			r2.URL = new(url.URL)
+			*r2.URL = *r.URL
+			r2.URL.Path = path
+			r2.URL.RawPath = ""
+			next.ServeHTTP(w, r2)
+		})
+	}
+}
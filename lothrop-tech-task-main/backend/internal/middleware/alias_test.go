@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouteAliases(t *testing.T) {
+	var seen *http.Request
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { seen = r })
+	handler := RouteAliases("/api/v1", map[string]string{"organisations": "companies"})(next)
+
+	tests := []struct {
+		name   string
+		target string
+		want   string // Path the next handler sees
+	}{
+		{"aliased collection", "/api/v1/organisations", "/api/v1/companies"},
+		{"aliased collection with trailing slash", "/api/v1/organisations/", "/api/v1/companies/"},
+		{"aliased item", "/api/v1/organisations/123", "/api/v1/companies/123"},
+		{"tail kept", "/api/v1/organisations/123/history", "/api/v1/companies/123/history"},
+		{"canonical path", "/api/v1/companies/123", "/api/v1/companies/123"},
+		{"alias as a prefix of a segment", "/api/v1/organisationsx", "/api/v1/organisationsx"},
+		{"alias below the first segment", "/api/v1/reports/organisations", "/api/v1/reports/organisations"},
+		{"outside the prefix", "/organisations/123", "/organisations/123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target+"?limit=5", nil)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if seen.URL.Path != tt.want {
+				t.Errorf("path = %q, want %q", seen.URL.Path, tt.want)
+			}
+			if seen.URL.RawQuery != "limit=5" {
+				t.Errorf("query = %q, want it kept", seen.URL.RawQuery)
+			}
+			if req.URL.Path != tt.target {
+				t.Errorf("original request path changed to %q", req.URL.Path)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"backend/internal/logctx"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// redactedValue replaces the value of every sensitive field in logged bodies
+const redactedValue = "[REDACTED]"
+
+// Redactor rewrites JSON bodies for logging with the values of sensitive fields replaced. It is the single place
+// bodies are prepared for logs, so new logging of bodies should go through it rather than log them directly.
+type Redactor struct {
+	fields map[string]bool
+}
+
+// NewRedactor returns a Redactor for the named fields, matched case-insensitively at any depth of a body
+func NewRedactor(fields []string) *Redactor {
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[strings.ToLower(strings.TrimSpace(field))] = true
+	}
+	return &Redactor{fields: set}
+}
+
+// Redact returns body re-encoded with sensitive field values replaced, and false if body is not valid JSON.
+// Callers must not log a body Redact rejects, since it cannot say what the body contains.
+func (rd *Redactor) Redact(body []byte) (string, bool) {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "", false
+	}
+
+	redacted, err := json.Marshal(rd.redact(value))
+	if err != nil {
+		return "", false
+	}
+	return string(redacted), true
+}
+
+// redact replaces sensitive field values in a generically decoded JSON value
+func (rd *Redactor) redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if rd.fields[strings.ToLower(key)] {
+				v[key] = redactedValue
+			} else {
+				v[key] = rd.redact(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = rd.redact(item)
+		}
+	}
+	return value
+}
+
+// cappedBuffer keeps the first limit bytes written to it and remembers whether more were discarded
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		p = p[:max(room, 0)]
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+// LogBodies logs the JSON request and response bodies of each request at debug level, passed through redactor.
+// Bodies are captured as the handler reads and writes them, up to maxBytes each. A body that is not JSON, or was
+// cut off at maxBytes, cannot be redacted reliably and is logged as omitted instead. It must run after
+// RequestLogger, whose request-scoped logger it uses.
+func LogBodies(redactor *Redactor, maxBytes int, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqBody := &cappedBuffer{limit: maxBytes}
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.TeeReader(r.Body, reqBody), r.Body}
+			}
+
+			respBody := &cappedBuffer{limit: maxBytes}
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(respBody)
+
+			next.ServeHTTP(ww, r)
+
+			log := logctx.FromContext(r.Context(), logger)
+			logBody(log, redactor, "Request body", r.Header.Get("Content-Type"), reqBody)
+			logBody(log, redactor, "Response body", ww.Header().Get("Content-Type"), respBody)
+		})
+	}
+}
+
+// logBody logs a captured body redacted, or why it was omitted; empty bodies are not logged
+func logBody(log *zap.Logger, redactor *Redactor, msg, contentType string, body *cappedBuffer) {
+	if body.buf.Len() == 0 {
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json"):
+		log.Debug(msg, zap.String("omitted", "not JSON"), zap.String("content_type", contentType))
+	case body.truncated:
+		log.Debug(msg, zap.String("omitted", "larger than the logging limit"))
+	default:
+		redacted, ok := redactor.Redact(body.buf.Bytes())
+		if !ok {
+			log.Debug(msg, zap.String("omitted", "malformed JSON"))
+			return
+		}
+		log.Debug(msg, zap.String("body", redacted))
+	}
+}
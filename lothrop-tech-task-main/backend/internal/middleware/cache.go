@@ -0,0 +1,14 @@
+package middleware
+
+import "net/http"
+
+// NoStoreWrites marks responses to mutating requests Cache-Control: no-store, so no cache keeps a write's
+// response however the route sets its other headers
+func NoStoreWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isSafeMethod(r.Method) {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
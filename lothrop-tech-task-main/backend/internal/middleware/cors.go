@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// corsAllowedMethods and corsAllowedHeaders are advertised to browsers in preflight responses
+const (
+	corsAllowedMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowedHeaders = "Accept, Accept-Language, Content-Type, Content-Length, Accept-Encoding, Authorization, Idempotency-Key, If-Modified-Since, If-None-Match"
+	corsExposedHeaders = "ETag, Idempotent-Replayed, Link, Location, Retry-After, Server-Timing, X-Request-Id, X-Total-Count"
+)
+
+// CORS allows cross-origin requests from the listed origins. A listed request Origin is echoed back with
+// credentials allowed; other origins get no CORS headers, so browsers block the response. The single entry "*"
+// allows every origin, but without credentials, which browsers never combine with a wildcard.
+func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[origin] = true
+	}
+	wildcard := allowed["*"]
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// The response depends on the Origin, so caches must not share it across origins
+			w.Header().Add("Vary", "Origin")
+
+			origin := r.Header.Get("Origin")
+			switch {
+			case origin == "":
+			case allowed[origin]:
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			case wildcard:
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			default:
+				origin = ""
+			}
+
+			if origin != "" {
+				w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+				w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+				w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			}
+
+			// Answer preflights here; without CORS headers a disallowed origin's preflight fails in the browser
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	tests := []struct {
+		name        string
+		allowed     []string
+		origin      string
+		preflight   bool
+		allowOrigin string
+		credentials bool
+		status      int
+	}{
+		{"listed origin", []string{"https://app.example.com"}, "https://app.example.com", false, "https://app.example.com", true, http.StatusOK},
+		{"unlisted origin", []string{"https://app.example.com"}, "https://evil.example.com", false, "", false, http.StatusOK},
+		{"listed origin as a prefix", []string{"https://app.example.com"}, "https://app.example.com.evil.com", false, "", false, http.StatusOK},
+		{"different scheme", []string{"https://app.example.com"}, "http://app.example.com", false, "", false, http.StatusOK},
+		{"null origin", []string{"https://app.example.com"}, "null", false, "", false, http.StatusOK},
+		{"no origin", []string{"https://app.example.com"}, "", false, "", false, http.StatusOK},
+		{"empty allowlist", nil, "https://app.example.com", false, "", false, http.StatusOK},
+		{"wildcard", []string{"*"}, "https://any.example.com", false, "*", false, http.StatusOK},
+		{"listed origin preflight", []string{"https://app.example.com"}, "https://app.example.com", true, "https://app.example.com", true, http.StatusNoContent},
+		{"unlisted origin preflight", []string{"https://app.example.com"}, "https://evil.example.com", true, "", false, http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := http.MethodGet
+			if tt.preflight {
+				method = http.MethodOptions
+			}
+			req := httptest.NewRequest(method, "/api/v1/companies", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			CORS(tt.allowed)(next).ServeHTTP(rec, req)
+
+			header := rec.Header()
+			if got := header.Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			if got := header.Get("Access-Control-Allow-Credentials") == "true"; got != tt.credentials {
+				t.Errorf("credentials allowed = %v, want %v", got, tt.credentials)
+			}
+			if tt.allowOrigin == "" && (header.Get("Access-Control-Allow-Methods") != "" || header.Get("Access-Control-Expose-Headers") != "") {
+				t.Errorf("disallowed origin got CORS headers %v", header)
+			}
+			if header.Get("Vary") != "Origin" {
+				t.Errorf("Vary = %q, want Origin", header.Get("Vary"))
+			}
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
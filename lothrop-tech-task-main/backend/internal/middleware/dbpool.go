@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/logctx"
+	"backend/internal/metrics"
+	"backend/internal/response"
+
+	"go.uber.org/zap"
+)
+
+// PoolGuard sheds load when the database connection pool is exhausted. While every connection is in use, each
+// request first waits up to acquireTimeout for one to come free, and is rejected with 503 and Retry-After if
+// none does, so clients back off rather than queue until their request times out. The probe connection goes
+// straight back to the pool for the handler to use; while the pool has room no probe is made. A zero
+// acquireTimeout disables the guard.
+func PoolGuard(db *sql.DB, acquireTimeout, retryAfter time.Duration, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if acquireTimeout <= 0 {
+			return next
+		}
+
+		retryAfterSeconds := strconv.Itoa(max(int(retryAfter.Seconds()), 1))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			stats := db.Stats()
+			if stats.MaxOpenConnections == 0 || stats.InUse < stats.MaxOpenConnections {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), acquireTimeout)
+			conn, err := db.Conn(ctx)
+			cancel()
+			if err != nil {
+				metrics.RecordDBPoolRejection()
+				logctx.FromContext(r.Context(), logger).Warn("Rejected request: database connection pool exhausted",
+					zap.Int("in_use", stats.InUse), zap.Int("max_open", stats.MaxOpenConnections),
+					zap.Int64("wait_count", stats.WaitCount), zap.Error(err))
+				w.Header().Set("Retry-After", retryAfterSeconds)
+				response.WriteError(w, r, http.StatusServiceUnavailable, "The service is busy, try again later")
+				return
+			}
+			conn.Close()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
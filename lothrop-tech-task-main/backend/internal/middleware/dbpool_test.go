@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend/internal/repository/repositorytest"
+
+	"go.uber.org/zap"
+)
+
+// newStubDB returns a pool of at most maxOpen fake connections
+func newStubDB(t *testing.T, maxOpen int) *sql.DB {
+	t.Helper()
+	db, _ := repositorytest.OpenDB(t, nil)
+	db.SetMaxOpenConns(maxOpen)
+	return db
+}
+
+func TestPoolGuard(t *testing.T) {
+	db := newStubDB(t, 1)
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := PoolGuard(db, 20*time.Millisecond, 2500*time.Millisecond, zap.NewNop())(ok)
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/companies", nil))
+		return rec
+	}
+
+	if rec := get(); rec.Code != http.StatusOK {
+		t.Fatalf("pool with room: status = %d, want 200", rec.Code)
+	}
+
+	// Hold the only connection
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := get()
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("exhausted pool: status = %d, want 503", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want whole seconds %q", got, "2")
+	}
+
+	// A connection freed within the acquire timeout lets the request through
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		conn.Close()
+	}()
+	handler = PoolGuard(db, time.Second, 2*time.Second, zap.NewNop())(ok)
+	if rec := get(); rec.Code != http.StatusOK {
+		t.Errorf("connection freed while waiting: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestPoolGuardDisabled(t *testing.T) {
+	db := newStubDB(t, 1)
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	rec := httptest.NewRecorder()
+	PoolGuard(db, 0, time.Second, zap.NewNop())(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 with a zero acquire timeout", rec.Code)
+	}
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/logctx"
+	"backend/internal/response"
+
+	"go.uber.org/zap"
+)
+
+// Claims holds the payload of a verified JWT
+type Claims map[string]any
+
+// Subject returns the token's sub claim, or "" when absent
+func (c Claims) Subject() string {
+	sub, _ := c["sub"].(string)
+	return sub
+}
+
+type claimsKey struct{}
+
+// ClaimsFromContext returns the claims of the JWT that authenticated the request, if any
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(Claims)
+	return claims, ok
+}
+
+// JWTVerifier checks JWT signatures with either an HS256 shared secret or an RS256 public key
+type JWTVerifier struct {
+	secret    []byte
+	publicKey *rsa.PublicKey
+}
+
+// NewJWTVerifier returns a verifier for HS256 tokens signed with secret or, when publicKeyPEM is set, RS256
+// tokens signed by the matching private key. It returns nil when neither is configured.
+func NewJWTVerifier(secret string, publicKeyPEM []byte) (*JWTVerifier, error) {
+	if len(publicKeyPEM) > 0 {
+		if secret != "" {
+			return nil, errors.New("configure either a JWT secret or a public key, not both")
+		}
+
+		block, _ := pem.Decode(publicKeyPEM)
+		if block == nil {
+			return nil, errors.New("JWT public key is not PEM encoded")
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT public key: %w", err)
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("JWT public key is not an RSA key")
+		}
+		return &JWTVerifier{publicKey: rsaKey}, nil
+	}
+
+	if secret == "" {
+		return nil, nil
+	}
+	return &JWTVerifier{secret: []byte(secret)}, nil
+}
+
+// Verify checks the token's signature and its exp and nbf claims, returning its claims. Only the algorithm
+// matching the configured key is accepted, so a token cannot choose how it is verified.
+func (v *JWTVerifier) Verify(token string, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed header: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature: %w", err)
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	if v.publicKey != nil {
+		if header.Alg != "RS256" {
+			return nil, fmt.Errorf("unexpected algorithm %q", header.Alg)
+		}
+		digest := sha256.Sum256(signed)
+		if err := rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, errors.New("invalid signature")
+		}
+	} else {
+		if header.Alg != "HS256" {
+			return nil, fmt.Errorf("unexpected algorithm %q", header.Alg)
+		}
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write(signed)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, errors.New("invalid signature")
+		}
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed claims: %w", err)
+	}
+
+	if exp, ok := claims["exp"].(float64); ok && now.Unix() >= int64(exp) {
+		return nil, errors.New("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Unix() < int64(nbf) {
+		return nil, errors.New("token not yet valid")
+	}
+
+	return claims, nil
+}
+
+// decodeSegment decodes a base64url JSON token segment into v
+func decodeSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// RequireJWT rejects requests without a valid bearer JWT with 401 and attaches the token's claims to the
+// request context. Safe methods pass through unauthenticated unless protectReads is set.
+func RequireJWT(verifier *JWTVerifier, protectReads bool, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !protectReads && isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				response.WriteError(w, r, http.StatusUnauthorized, "Bearer token required")
+				return
+			}
+
+			claims, err := verifier.Verify(token, time.Now())
+			if err != nil {
+				logctx.FromContext(r.Context(), logger).Warn("Rejected invalid JWT", zap.Error(err))
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				response.WriteError(w, r, http.StatusUnauthorized, "Invalid bearer token")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// signToken builds a JWT with the given header algorithm and claims, signed by sign over "header.claims"
+func signToken(t *testing.T, alg string, claims map[string]any, sign func(signed []byte) []byte) string {
+	t.Helper()
+	segment := func(v any) string {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	signed := segment(map[string]string{"alg": alg, "typ": "JWT"}) + "." + segment(claims)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signed)))
+}
+
+func hs256(secret []byte) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+}
+
+func rs256(t *testing.T, key *rsa.PrivateKey) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signature
+	}
+}
+
+func unsigned([]byte) []byte { return nil }
+
+func TestJWTVerifierRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	verifier, err := NewJWTVerifier("", publicPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	valid := map[string]any{"sub": "user-123", "exp": now.Add(time.Hour).Unix()}
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"valid", signToken(t, "RS256", valid, rs256(t, key)), true},
+		{"alg none", signToken(t, "none", valid, unsigned), false},
+		{"HS256 signed with the public key", signToken(t, "HS256", valid, hs256(publicPEM)), false},
+		{"expired", signToken(t, "RS256", map[string]any{"sub": "user-123", "exp": now.Add(-time.Second).Unix()}, rs256(t, key)), false},
+		{"not yet valid", signToken(t, "RS256", map[string]any{"sub": "user-123", "nbf": now.Add(time.Hour).Unix()}, rs256(t, key)), false},
+		{"malformed", "not-a-token", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(tt.token, now)
+			if tt.ok && err != nil {
+				t.Fatalf("err = %v, want the token accepted", err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatalf("token accepted with claims %v, want it rejected", claims)
+			}
+			if tt.ok && claims.Subject() != "user-123" {
+				t.Errorf("subject = %q, want user-123", claims.Subject())
+			}
+		})
+	}
+
+	t.Run("tampered claims", func(t *testing.T) {
+		genuine := strings.Split(signToken(t, "RS256", valid, rs256(t, key)), ".")
+		forged := strings.Split(signToken(t, "RS256", map[string]any{"sub": "admin", "exp": now.Add(time.Hour).Unix()}, unsigned), ".")
+		// The forged claims carrying the genuine signature
+		tampered := genuine[0] + "." + forged[1] + "." + genuine[2]
+		if _, err := verifier.Verify(tampered, now); err == nil {
+			t.Error("token with altered claims accepted")
+		}
+	})
+}
+
+func TestJWTVerifierHS256(t *testing.T) {
+	secret := []byte("test-secret")
+	verifier, err := NewJWTVerifier(string(secret), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	valid := map[string]any{"sub": "user-123", "exp": now.Add(time.Hour).Unix()}
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"valid", signToken(t, "HS256", valid, hs256(secret)), true},
+		{"alg none", signToken(t, "none", valid, unsigned), false},
+		{"wrong secret", signToken(t, "HS256", valid, hs256([]byte("other-secret"))), false},
+		{"HS512 header", signToken(t, "HS512", valid, hs256(secret)), false},
+		{"expired", signToken(t, "HS256", map[string]any{"exp": now.Add(-time.Second).Unix()}, hs256(secret)), false},
+		{"expiring now", signToken(t, "HS256", map[string]any{"exp": now.Unix()}, hs256(secret)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(tt.token, now)
+			if tt.ok != (err == nil) {
+				t.Errorf("err = %v, want accepted = %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNewJWTVerifier(t *testing.T) {
+	if verifier, err := NewJWTVerifier("", nil); verifier != nil || err != nil {
+		t.Errorf("unconfigured: verifier, err = %v, %v, want nil, nil", verifier, err)
+	}
+	if _, err := NewJWTVerifier("secret", []byte("-----BEGIN PUBLIC KEY-----")); err == nil {
+		t.Error("both a secret and a public key accepted")
+	}
+	if _, err := NewJWTVerifier("", []byte("not PEM")); err == nil {
+		t.Error("non-PEM public key accepted")
+	}
+}
+
+func TestRequireJWT(t *testing.T) {
+	secret := []byte("test-secret")
+	verifier, err := NewJWTVerifier(string(secret), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := signToken(t, "HS256", map[string]any{"sub": "user-123", "exp": time.Now().Add(time.Hour).Unix()}, hs256(secret))
+
+	var subject string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := ClaimsFromContext(r.Context())
+		subject = claims.Subject()
+	})
+
+	tests := []struct {
+		name         string
+		method       string
+		auth         string
+		protectReads bool
+		status       int
+		subject      string
+	}{
+		{"read without token", http.MethodGet, "", false, http.StatusOK, ""},
+		{"protected read without token", http.MethodGet, "", true, http.StatusUnauthorized, ""},
+		{"write without token", http.MethodPost, "", false, http.StatusUnauthorized, ""},
+		{"write with invalid token", http.MethodPost, "Bearer " + token + "x", false, http.StatusUnauthorized, ""},
+		{"write with basic auth", http.MethodPost, "Basic dXNlcjpwYXNz", false, http.StatusUnauthorized, ""},
+		{"write with valid token", http.MethodPost, "Bearer " + token, false, http.StatusOK, "user-123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject = ""
+			req := httptest.NewRequest(tt.method, "/api/v1/companies", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			RequireJWT(verifier, tt.protectReads, zap.NewNop())(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.status == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without WWW-Authenticate")
+			}
+			if subject != tt.subject {
+				t.Errorf("subject = %q, want %q", subject, tt.subject)
+			}
+		})
+	}
+}
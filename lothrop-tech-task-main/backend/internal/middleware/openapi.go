@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"backend/internal/logctx"
+	"backend/internal/response"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"go.uber.org/zap"
+)
+
+// ValidateRequests rejects requests whose path or query parameters, or JSON body, break the OpenAPI spec with
+// a 400 naming the offending field, before they reach a handler. Requests the spec does not describe pass
+// through unvalidated, as do non-JSON bodies such as CSV uploads. JSON bodies are read whole to be validated,
+// so they are capped at maxBodyBytes here rather than in the handler; 0 leaves them uncapped.
+func ValidateRequests(spec *openapi3.T, maxBodyBytes int64, logger *zap.Logger) (func(http.Handler) http.Handler, error) {
+	// Match paths on whatever host serves the API, not just the spec's development server
+	spec.Servers = nil
+
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI router: %w", err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r) // Not in the spec; routing reports unknown paths and methods
+				return
+			}
+
+			jsonBody := isJSONRequest(r)
+			if jsonBody && maxBodyBytes > 0 && r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+			}
+
+			err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					ExcludeRequestBody: !jsonBody,
+					// Authentication is enforced by the JWT and admin token middleware
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				},
+			})
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					response.WriteError(w, r, http.StatusRequestEntityTooLarge,
+						fmt.Sprintf("Request body cannot exceed %d bytes", tooLarge.Limit))
+					return
+				}
+
+				if isUndecodableBody(err) {
+					next.ServeHTTP(w, r) // The handler reports an empty or malformed body more precisely
+					return
+				}
+
+				field, reason := describeValidationError(err)
+				logctx.FromContext(r.Context(), logger).Info("Rejected request failing OpenAPI validation",
+					zap.String("field", field), zap.String("reason", reason))
+				response.WriteFieldErrors(w, r, http.StatusBadRequest, "Request does not match the API specification",
+					map[string]string{field: reason})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// isJSONRequest reports whether the request body is declared as JSON
+func isJSONRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// isUndecodableBody reports whether err rejects a JSON body for being missing or not parseable, rather than for
+// breaking the schema. kin-openapi restores the body it read, so the handler can decode it again.
+func isUndecodableBody(err error) bool {
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) || reqErr.RequestBody == nil || reqErr.Parameter != nil {
+		return false
+	}
+	return errors.Is(reqErr.Err, openapi3filter.ErrInvalidRequired) || reqErr.Reason == "failed to decode request body"
+}
+
+// describeValidationError returns the field a validation error concerns, as a parameter name or a dotted path
+// into the body, and the reason it was rejected, without the schema dump kin-openapi's messages include
+func describeValidationError(err error) (string, string) {
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) {
+		return "request", err.Error()
+	}
+
+	field := "body"
+	if reqErr.Parameter != nil {
+		field = reqErr.Parameter.Name
+	}
+
+	reason := reqErr.Reason
+	var schemaErr *openapi3.SchemaError
+	if errors.As(reqErr.Err, &schemaErr) {
+		reason = schemaErr.Reason
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 && reqErr.Parameter == nil {
+			field = strings.Join(pointer, ".")
+		}
+	} else if reason == "" && reqErr.Err != nil {
+		reason = reqErr.Err.Error()
+	}
+
+	return field, reason
+}
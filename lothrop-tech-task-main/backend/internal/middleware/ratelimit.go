@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"backend/internal/logctx"
+	"backend/internal/response"
+
+	"go.uber.org/zap"
+)
+
+// rateLimitSweepInterval is how often the memory store forgets buckets that have refilled completely
+const rateLimitSweepInterval = time.Minute
+
+// RateLimit is a token bucket refilled at Rate tokens per second up to Burst tokens. A zero Rate disables it.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitStore holds the token buckets of RateLimitByIP, one per key. It must be safe for concurrent use;
+// a shared store such as Redis lets several instances enforce one limit.
+type RateLimitStore interface {
+	// Take removes a token from key's bucket, creating it full if it does not exist. When the bucket is empty it
+	// takes nothing and returns false with the time until a token is available.
+	Take(ctx context.Context, key string, limit RateLimit) (bool, time.Duration, error)
+}
+
+// RateLimitByIP limits each client IP with a token bucket, using the read limit for GET, HEAD and OPTIONS
+// requests and the write limit for everything else. Requests over the limit get a 429 with Retry-After.
+// If the store fails the request is let through, so an outage of a shared store does not take the API down.
+func RateLimitByIP(store RateLimitStore, read, write RateLimit, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			class, limit := "write", write
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				class, limit = "read", read
+			}
+			if limit.Rate <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+
+			allowed, retryAfter, err := store.Take(r.Context(), class+":"+ip, limit)
+			if err != nil {
+				logctx.FromContext(r.Context(), logger).Error("Rate limit store failed, allowing request", zap.Error(err))
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				logctx.FromContext(r.Context(), logger).Warn("Rate limit exceeded",
+					zap.String("class", class), zap.String("ip", ip))
+				w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+				response.WriteError(w, r, http.StatusTooManyRequests, "Rate limit exceeded, try again later")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// tokenBucket is one key's bucket in a MemoryRateLimitStore
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	limit   RateLimit
+}
+
+// refill adds the tokens accrued since the bucket was last updated
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*b.limit.Rate)
+	b.updated = now
+}
+
+// MemoryRateLimitStore keeps token buckets in process memory, so each instance enforces its own limit
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimitStore creates an empty in-memory store
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:   map[string]*tokenBucket{},
+		lastSweep: time.Now(),
+	}
+}
+
+// Take implements RateLimitStore
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit RateLimit) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= rateLimitSweepInterval {
+		s.sweep(now)
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), updated: now, limit: limit}
+		s.buckets[key] = bucket
+	}
+	bucket.refill(now)
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / limit.Rate * float64(time.Second))
+		return false, wait, nil
+	}
+	bucket.tokens--
+	return true, 0, nil
+}
+
+// sweep forgets buckets that have refilled completely; a new full bucket behaves identically
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	for key, bucket := range s.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= float64(bucket.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"backend/internal/logctx"
+	"backend/internal/response"
+
+	"go.uber.org/zap"
+)
+
+// ReadOnly allows safe methods through and rejects every mutating request with 503
+func ReadOnly(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Read-Only", "true")
+
+			if isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			logctx.FromContext(r.Context(), logger).Warn("Rejected write in read-only mode")
+			response.WriteError(w, r, http.StatusServiceUnavailable, "API is in read-only mode")
+		})
+	}
+}
+
+// isSafeMethod reports whether the HTTP method does not modify state
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestReadOnly(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	tests := []struct {
+		method string
+		status int
+	}{
+		{http.MethodGet, http.StatusOK},
+		{http.MethodHead, http.StatusOK},
+		{http.MethodOptions, http.StatusOK},
+		{http.MethodPost, http.StatusServiceUnavailable},
+		{http.MethodPut, http.StatusServiceUnavailable},
+		{http.MethodPatch, http.StatusServiceUnavailable},
+		{http.MethodDelete, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ReadOnly(zap.NewNop())(next).ServeHTTP(rec, httptest.NewRequest(tt.method, "/api/v1/companies", nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("X-Read-Only"); got != "true" {
+				t.Errorf("X-Read-Only = %q, want true", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"backend/internal/logctx"
+	"backend/internal/response"
+
+	"go.uber.org/zap"
+)
+
+// Recoverer turns a panic in a later handler into a 500 with the API's usual JSON error body, logging the
+// panic and its stack with the request-scoped logger so it carries the request ID. Like chi's Recoverer it
+// re-panics http.ErrAbortHandler, which net/http uses to abort a response deliberately.
+func Recoverer(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(rec)
+				}
+
+				logctx.FromContext(r.Context(), logger).Error("Panic while handling request",
+					zap.Any("panic", rec), zap.StackSkip("stack", 1))
+
+				// An upgraded connection has no response to write to
+				if r.Header.Get("Connection") != "Upgrade" {
+					response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
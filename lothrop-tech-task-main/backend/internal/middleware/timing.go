@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/logctx"
+
+	"go.uber.org/zap"
+)
+
+// ServerTiming reports the time the app spent on each response in a Server-Timing header, e.g.
+// "app;dur=12.3" in milliseconds, measured until the response headers are sent, so browsers can attribute
+// latency without external tracing. When a proxy stamps the request with X-Request-Start, the time it spent
+// queued between the proxy and the app is also logged and reported as a "queue" metric.
+func ServerTiming(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			tw := &timingWriter{ResponseWriter: w, start: start}
+			if queued, ok := parseRequestStart(r.Header.Get("X-Request-Start")); ok {
+				queue := start.Sub(queued)
+				if queue < 0 {
+					queue = 0 // Proxy and app clocks disagree slightly
+				}
+				tw.queue, tw.queued = queue, true
+				logctx.FromContext(r.Context(), logger).Info("Request queue time", zap.Duration("queue_time", queue))
+			}
+
+			next.ServeHTTP(tw, r)
+
+			// A handler that writes nothing gets its headers sent by the server after it returns
+			tw.setHeader()
+		})
+	}
+}
+
+// parseRequestStart parses an X-Request-Start value as proxies send it: a Unix timestamp, optionally prefixed
+// "t=", in seconds with a fraction (nginx's ${msec}), milliseconds or microseconds, told apart by magnitude
+func parseRequestStart(value string) (time.Time, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "t=")
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return time.Time{}, false
+	}
+
+	switch {
+	case n > 1e15:
+		return time.UnixMicro(int64(n)), true
+	case n > 1e12:
+		return time.UnixMilli(int64(n)), true
+	default:
+		return time.Unix(0, int64(n*float64(time.Second))), true
+	}
+}
+
+// timingWriter adds the Server-Timing header just before the response headers are sent
+type timingWriter struct {
+	http.ResponseWriter
+	start  time.Time
+	queue  time.Duration
+	queued bool // Whether the request carried X-Request-Start, so queue was measured
+	done   bool
+}
+
+// setHeader adds the Server-Timing header once, timed from the start of the request
+func (w *timingWriter) setHeader() {
+	if w.done {
+		return
+	}
+	w.done = true
+
+	value := fmt.Sprintf("app;dur=%.1f", milliseconds(time.Since(w.start)))
+	if w.queued {
+		value = fmt.Sprintf("queue;dur=%.1f, %s", milliseconds(w.queue), value)
+	}
+	w.Header().Add("Server-Timing", value)
+}
+
+func (w *timingWriter) WriteHeader(statusCode int) {
+	// Informational responses such as 103 Early Hints precede the final headers
+	if statusCode >= http.StatusOK {
+		w.setHeader()
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *timingWriter) Write(b []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush lets streaming handlers flush through the wrapper; flushing sends the headers
+func (w *timingWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.setHeader()
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *timingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// milliseconds converts a duration to fractional milliseconds, the unit Server-Timing durations use
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestServerTiming(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		header  string
+		want    *regexp.Regexp
+	}{
+		{
+			name:    "written body",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) },
+			want:    regexp.MustCompile(`^app;dur=\d+\.\d$`),
+		},
+		{
+			name:    "explicit status",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) },
+			want:    regexp.MustCompile(`^app;dur=\d+\.\d$`),
+		},
+		{
+			name:    "nothing written",
+			handler: func(w http.ResponseWriter, r *http.Request) {},
+			want:    regexp.MustCompile(`^app;dur=\d+\.\d$`),
+		},
+		{
+			name:    "queued behind a proxy",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) },
+			header:  "t=" + strconv.FormatInt(time.Now().Add(-50*time.Millisecond).UnixMicro(), 10),
+			want:    regexp.MustCompile(`^queue;dur=(\d+)\.\d, app;dur=\d+\.\d$`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-Start", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			ServerTiming(zap.NewNop())(tt.handler).ServeHTTP(rec, req)
+
+			values := rec.Header().Values("Server-Timing")
+			if len(values) != 1 {
+				t.Fatalf("Server-Timing = %q, want one value", values)
+			}
+			match := tt.want.FindStringSubmatch(values[0])
+			if match == nil {
+				t.Fatalf("Server-Timing = %q, want a match for %s", values[0], tt.want)
+			}
+			if len(match) > 1 {
+				if queue, _ := strconv.Atoi(match[1]); queue < 50 {
+					t.Errorf("queue duration %sms, want at least the 50ms since X-Request-Start", match[1])
+				}
+			}
+		})
+	}
+}
+
+func TestServerTimingSentBeforeStreamedBody(t *testing.T) {
+	rec := httptest.NewRecorder()
+	handler := ServerTiming(zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		w.Write([]byte("rows"))
+	}))
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/companies.csv", nil))
+
+	if !rec.Flushed {
+		t.Error("flush did not reach the underlying writer")
+	}
+	if got := rec.Header().Values("Server-Timing"); len(got) != 1 {
+		t.Errorf("Server-Timing = %q, want one value set by the flush", got)
+	}
+}
+
+func TestParseRequestStart(t *testing.T) {
+	want := time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC)
+
+	tests := []struct {
+		value string
+		ok    bool
+	}{
+		{"1704164645.678", true},
+		{"t=1704164645.678", true},
+		{"1704164645678", true},
+		{"t=1704164645678000", true},
+		{" t=1704164645678 ", true},
+		{"", false},
+		{"t=", false},
+		{"yesterday", false},
+		{"-5", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRequestStart(tt.value)
+		if ok != tt.ok {
+			t.Errorf("parseRequestStart(%q) ok = %v, want %v", tt.value, ok, tt.ok)
+			continue
+		}
+		if ok && got.Sub(want).Abs() > time.Microsecond {
+			t.Errorf("parseRequestStart(%q) = %v, want %v", tt.value, got.UTC(), want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package pdf
+
+import (
+	"strconv"
+	"time"
+
+	"backend/api"
+)
+
+// CompanySummary renders a company's details as a one-page PDF for account managers, unset fields shown as "-"
+func CompanySummary(company *api.Company) []byte {
+	doc := New()
+	doc.Title(company.CompanyName)
+
+	doc.Field("Company ID", company.Id.String())
+	doc.Field("Jurisdiction", company.Jurisdiction)
+	doc.Field("Address", company.CompanyAddress)
+	doc.Field("Nature of business", text(company.NatureOfBusiness))
+	doc.Field("Directors", count(company.NumberOfDirectors))
+	doc.Field("Shareholders", count(company.NumberOfShareholders))
+	doc.Field("Registry source", text(company.RegistrySource))
+	doc.Field("Registry number", text(company.RegistryNumber))
+	doc.Field("SEC code", text(company.SecCode))
+	doc.Field("Created", company.DateCreated.UTC().Format(time.RFC1123))
+	doc.Field("Last updated", company.DateUpdated.UTC().Format(time.RFC1123))
+	if company.DeletedAt != nil {
+		doc.Field("Deleted", company.DeletedAt.UTC().Format(time.RFC1123))
+	}
+
+	return doc.Bytes()
+}
+
+// text returns an optional field's value, "-" when unset or empty
+func text(value *string) string {
+	if value == nil || *value == "" {
+		return "-"
+	}
+	return *value
+}
+
+// count returns an optional count, "-" when unset
+func count(value *int) string {
+	if value == nil {
+		return "-"
+	}
+	return strconv.Itoa(*value)
+}
@@ -0,0 +1,171 @@
+// Package pdf renders simple text documents as PDF without external dependencies: left-aligned lines of
+// Helvetica on A4 pages, which is all a printable record summary needs.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Page geometry in points, A4 with 2cm margins
+const (
+	pageWidth   = 595
+	pageHeight  = 842
+	margin      = 56
+	valueColumn = 200 // x of a field's value, leaving the label column to its left
+)
+
+// Font sizes and the leading between lines, in points
+const (
+	titleSize   = 18
+	bodySize    = 10
+	lineSpacing = 1.5
+)
+
+// titleWidth and valueWidth are the characters a title and a field value are wrapped at. Helvetica is
+// proportional, so these are estimates from its average width that keep ordinary text inside the right margin.
+const (
+	titleWidth = 45
+	valueWidth = 60
+)
+
+// Document is a PDF under construction, one text line at a time; a new page starts when one fills up
+type Document struct {
+	pages []*bytes.Buffer
+	y     float64 // Baseline of the next line on the last page
+}
+
+// New creates an empty document
+func New() *Document {
+	return &Document{}
+}
+
+// Title adds large bold text, wrapped if long
+func (d *Document) Title(text string) {
+	for _, line := range wrap(text, titleWidth) {
+		d.line(margin, "F2", titleSize, line)
+	}
+	d.y -= bodySize // Extra space below the title
+}
+
+// Field adds a bold label with its value beside it, wrapping long values onto further lines of the value column
+func (d *Document) Field(label, value string) {
+	lines := wrap(value, valueWidth)
+	d.line(margin, "F2", bodySize, label)
+	d.y += bodySize * lineSpacing // Value starts on the label's line
+	for _, text := range lines {
+		d.line(valueColumn, "F1", bodySize, text)
+	}
+}
+
+// line writes one line of text at x, starting a new page if it would cross the bottom margin
+func (d *Document) line(x float64, font string, size float64, text string) {
+	if len(d.pages) == 0 || d.y-size*lineSpacing < margin {
+		d.pages = append(d.pages, &bytes.Buffer{})
+		d.y = pageHeight - margin
+	}
+	d.y -= size * lineSpacing
+	fmt.Fprintf(d.pages[len(d.pages)-1], "BT /%s %g Tf %g %g Td (%s) Tj ET\n", font, size, x, d.y, encode(text))
+}
+
+// Bytes returns the finished PDF, with a single blank page if nothing was added
+func (d *Document) Bytes() []byte {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = []*bytes.Buffer{{}}
+	}
+
+	// Objects 1-4 are the catalog, page tree and fonts; each page is then a page object and its content stream
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// winAnsi maps the characters WinAnsiEncoding places in 0x80-0x9F, where it departs from Latin-1
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// encode converts text to a WinAnsi PDF string body, escaping the delimiters and replacing characters the
+// standard fonts cannot show with "?"
+func encode(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7F:
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		case winAnsi[r] != 0:
+			fmt.Fprintf(&b, "\\%03o", winAnsi[r])
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// wrap splits text into lines of at most width characters, breaking between words where it can; an empty text
+// is one empty line
+func wrap(text string, width int) []string {
+	var lines []string
+	var current string
+	for _, word := range strings.Fields(text) {
+		// Break words longer than a whole line, e.g. URLs
+		for utf8.RuneCountInString(word) > width {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			head := []rune(word)[:width]
+			lines = append(lines, string(head))
+			word = word[len(string(head)):]
+		}
+
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	return append(lines, current)
+}
@@ -0,0 +1,149 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/api"
+
+	"github.com/google/uuid"
+)
+
+// checkStructure verifies the file's header, trailer, cross-reference offsets and stream lengths
+func checkStructure(t *testing.T, doc []byte) {
+	t.Helper()
+
+	if !bytes.HasPrefix(doc, []byte("%PDF-")) {
+		t.Fatalf("document starts %q, want %%PDF-", doc[:min(len(doc), 8)])
+	}
+	if !bytes.HasSuffix(doc, []byte("%%EOF\n")) {
+		t.Errorf("document does not end with %%%%EOF")
+	}
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(doc)
+	if startxref == nil {
+		t.Fatal("no startxref")
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(doc[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(doc[xref:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if want := strconv.Itoa(i+1) + " 0 obj"; !bytes.HasPrefix(doc[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want %q", i+1, doc[offset:offset+len(want)], want)
+		}
+	}
+
+	for _, stream := range regexp.MustCompile(`(?s)/Length (\d+) >>\nstream\n(.*?)endstream`).FindAllSubmatch(doc, -1) {
+		if length, _ := strconv.Atoi(string(stream[1])); length != len(stream[2]) {
+			t.Errorf("stream /Length %d, but it holds %d bytes", length, len(stream[2]))
+		}
+	}
+}
+
+func TestCompanySummary(t *testing.T) {
+	natureOfBusiness := "Software (development) & consulting"
+	directors := 3
+	secCode := "SEC123"
+	company := &api.Company{
+		Id:                uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+		CompanyName:       "Café Example Ltd",
+		CompanyAddress:    "123 Business Street, London",
+		Jurisdiction:      "UK",
+		NatureOfBusiness:  &natureOfBusiness,
+		NumberOfDirectors: &directors,
+		SecCode:           &secCode,
+		DateCreated:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		DateUpdated:       time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+	}
+
+	doc := CompanySummary(company)
+	checkStructure(t, doc)
+
+	for _, want := range []string{
+		"(Caf\\351 Example Ltd)",
+		"(123e4567-e89b-12d3-a456-426614174000)",
+		"(UK)",
+		"(123 Business Street, London)",
+		"(Software \\(development\\) & consulting)",
+		"(3)",
+		"(SEC123)",
+		"(Tue, 02 Jan 2024 03:04:05 UTC)",
+	} {
+		if !bytes.Contains(doc, []byte(want)) {
+			t.Errorf("document does not contain %s", want)
+		}
+	}
+
+	// Unset fields are shown as "-", and a live company has no deletion date
+	if !bytes.Contains(doc, []byte("(-)")) {
+		t.Error("unset fields not shown as -")
+	}
+	if bytes.Contains(doc, []byte("(Deleted)")) {
+		t.Error("live company shown with a deletion date")
+	}
+}
+
+func TestDocumentPages(t *testing.T) {
+	doc := New()
+	doc.Title("Long document")
+	for i := 0; i < 100; i++ {
+		doc.Field("Line "+strconv.Itoa(i), strings.Repeat("word ", 30))
+	}
+	out := doc.Bytes()
+	checkStructure(t, out)
+
+	pages := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(out)
+	if n, _ := strconv.Atoi(string(pages[1])); n < 2 {
+		t.Errorf("/Count %d, want the fields to overflow onto further pages", n)
+	}
+
+	empty := New().Bytes()
+	checkStructure(t, empty)
+	if !bytes.Contains(empty, []byte("/Count 1")) {
+		t.Error("empty document is not a single blank page")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := map[string]string{
+		"plain":     "plain",
+		"(a) \\ b":  "\\(a\\) \\\\ b",
+		"é":         "\\351",
+		"€ — ‘x’":   "\\200 \\227 \\221x\\222",
+		"日本":        "??",
+		"tab\there": "tab?here",
+	}
+	for in, want := range tests {
+		if got := encode(in); got != want {
+			t.Errorf("encode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"", 10, []string{""}},
+		{"short", 10, []string{"short"}},
+		{"one two three four", 9, []string{"one two", "three", "four"}},
+		{"abcdefghijklmnop", 5, []string{"abcde", "fghij", "klmno", "p"}},
+		{"ééééééé", 3, []string{"ééé", "ééé", "é"}},
+	}
+	for _, tt := range tests {
+		got := wrap(tt.text, tt.width)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrap(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/api"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type actorKey struct{}
+
+// WithActor returns a context whose writes are attributed to actor in the audit log
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set with WithActor, or nil for an unauthenticated write
+func ActorFromContext(ctx context.Context) *string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return &actor
+	}
+	return nil
+}
+
+// recordAudit appends an audit entry for a company within the write's transaction, so the entry and the write
+// are committed or rolled back together
+func recordAudit(ctx context.Context, tx *sql.Tx, companyID openapi_types.UUID, action api.AuditEntryAction) error {
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO audit_log (company_id, action, actor) VALUES ($1, $2, $3)",
+		companyID, action, ActorFromContext(ctx))
+	return err
+}
+
+// GetHistory returns a company's audit entries, oldest first, or nil if no company has the ID and none ever
+// did. Soft-deleted and purged companies keep their history.
+func (r *PostgresCompanyRepository) GetHistory(ctx context.Context, companyID openapi_types.UUID) ([]api.AuditEntry, error) {
+	var exists bool
+	err := r.retry(ctx, "history", func() error {
+		return r.db.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM companies WHERE id = $1)
+				OR EXISTS (SELECT 1 FROM audit_log WHERE company_id = $1)`, companyID).Scan(&exists)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil // No company with this ID
+	}
+
+	query := `
+		SELECT id, company_id, action, actor, date_created
+		FROM audit_log
+		WHERE company_id = $1
+		ORDER BY id`
+
+	rows, err := r.query(ctx, "history", query, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []api.AuditEntry{}
+	for rows.Next() {
+		var entry api.AuditEntry
+		if err := rows.Scan(&entry.Id, &entry.CompanyId, &entry.Action, &entry.Actor, &entry.DateCreated); err != nil {
+			return nil, err
+		}
+		entry.DateCreated = entry.DateCreated.UTC() // See scanCompany
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
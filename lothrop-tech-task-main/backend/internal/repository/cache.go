@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"backend/api"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// CachingCompanyRepository wraps a CompanyRepository with an in-process LRU cache of GetByID results. Every
+// method that can change a live company's row evicts it, so a write routed through this process is never
+// followed by a stale read here; writes made by other instances are only picked up once an entry's TTL expires.
+type CachingCompanyRepository struct {
+	CompanyRepository
+
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[openapi_types.UUID]*list.Element
+	order   *list.List // front is most recently used
+	// generation is bumped by every eviction, so a GetByID that read the database before a concurrent write
+	// evicted its company does not cache what it read
+	generation uint64
+}
+
+// cacheEntry is a cached company and when it stops being served
+type cacheEntry struct {
+	id      openapi_types.UUID
+	company api.Company
+	expires time.Time
+}
+
+// NewCachingCompanyRepository caches up to size companies from next for ttl each
+func NewCachingCompanyRepository(next CompanyRepository, size int, ttl time.Duration) *CachingCompanyRepository {
+	return &CachingCompanyRepository{
+		CompanyRepository: next,
+		size:              size,
+		ttl:               ttl,
+		entries:           make(map[openapi_types.UUID]*list.Element, size),
+		order:             list.New(),
+	}
+}
+
+// Uncached returns repo without its caching decorator, if it has one. Reads that guard a write, such as the
+// expected-version check and the merge a patch is validated against, must see the latest committed row rather
+// than a copy up to the TTL old, possibly written by another instance.
+func Uncached(repo CompanyRepository) CompanyRepository {
+	if cached, ok := repo.(*CachingCompanyRepository); ok {
+		return cached.CompanyRepository
+	}
+	return repo
+}
+
+// GetByID returns the cached company when fresh, and otherwise reads it through and caches it. Missing
+// companies are not cached, so a create or restore is visible immediately.
+func (c *CachingCompanyRepository) GetByID(ctx context.Context, id openapi_types.UUID) (*api.Company, error) {
+	c.mu.Lock()
+	if company, ok := c.lookup(id); ok {
+		c.mu.Unlock()
+		return company, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	company, err := c.CompanyRepository.GetByID(ctx, id)
+	if err != nil || company == nil {
+		return company, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.store(*company)
+	}
+	c.mu.Unlock()
+	return company, nil
+}
+
+// lookup returns a copy of the fresh cached company with the ID, evicting it if expired; c.mu must be held
+func (c *CachingCompanyRepository) lookup(id openapi_types.UUID) (*api.Company, bool) {
+	element, ok := c.entries[id]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, id)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	// Callers may set fields such as Directors on the company they get, which must not leak into the cache
+	company := entry.company
+	return &company, true
+}
+
+// store caches company, evicting the least recently used entry when full; c.mu must be held
+func (c *CachingCompanyRepository) store(company api.Company) {
+	entry := &cacheEntry{id: company.Id, company: company, expires: time.Now().Add(c.ttl)}
+	if element, ok := c.entries[company.Id]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[company.Id] = c.order.PushFront(entry)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).id)
+	}
+}
+
+// evict drops the companies with the given IDs. It runs after the write whether or not it succeeded, as a
+// failed write may still have committed.
+func (c *CachingCompanyRepository) evict(ids ...openapi_types.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, id := range ids {
+		if element, ok := c.entries[id]; ok {
+			c.order.Remove(element)
+			delete(c.entries, id)
+		}
+	}
+}
+
+// Update evicts the company after replacing its fields
+func (c *CachingCompanyRepository) Update(ctx context.Context, id openapi_types.UUID, req api.CreateCompanyRequest, expected *time.Time) (*api.Company, error) {
+	defer c.evict(id)
+	return c.CompanyRepository.Update(ctx, id, req, expected)
+}
+
+// Patch evicts the company after updating its fields
+func (c *CachingCompanyRepository) Patch(ctx context.Context, id openapi_types.UUID, req api.PatchCompanyRequest, expected *time.Time) (*api.Company, error) {
+	defer c.evict(id)
+	return c.CompanyRepository.Patch(ctx, id, req, expected)
+}
+
+// Delete evicts the company after soft-deleting it
+func (c *CachingCompanyRepository) Delete(ctx context.Context, id openapi_types.UUID) error {
+	defer c.evict(id)
+	return c.CompanyRepository.Delete(ctx, id)
+}
+
+// DeleteMany evicts every requested company after soft-deleting them
+func (c *CachingCompanyRepository) DeleteMany(ctx context.Context, ids []openapi_types.UUID) ([]openapi_types.UUID, error) {
+	defer c.evict(ids...)
+	return c.CompanyRepository.DeleteMany(ctx, ids)
+}
+
+// Restore evicts the company after restoring it
+func (c *CachingCompanyRepository) Restore(ctx context.Context, id openapi_types.UUID) (*api.Company, error) {
+	defer c.evict(id)
+	return c.CompanyRepository.Restore(ctx, id)
+}
+
+// TransferJurisdiction evicts the company after moving it
+func (c *CachingCompanyRepository) TransferJurisdiction(ctx context.Context, id openapi_types.UUID, jurisdiction string) (*api.Company, error) {
+	defer c.evict(id)
+	return c.CompanyRepository.TransferJurisdiction(ctx, id, jurisdiction)
+}
+
+// AdjustCount evicts the company after changing its count
+func (c *CachingCompanyRepository) AdjustCount(ctx context.Context, id openapi_types.UUID, column CountColumn, delta int, bounds CountBounds) (*api.Company, error) {
+	defer c.evict(id)
+	return c.CompanyRepository.AdjustCount(ctx, id, column, delta, bounds)
+}
+
+// AddDirector evicts the company, whose number_of_directors changes
+func (c *CachingCompanyRepository) AddDirector(ctx context.Context, companyID openapi_types.UUID, req api.CreateDirectorRequest, bounds CountBounds) (*api.Director, error) {
+	defer c.evict(companyID)
+	return c.CompanyRepository.AddDirector(ctx, companyID, req, bounds)
+}
+
+// RemoveDirector evicts the company, whose number_of_directors changes
+func (c *CachingCompanyRepository) RemoveDirector(ctx context.Context, companyID, directorID openapi_types.UUID, bounds CountBounds) error {
+	defer c.evict(companyID)
+	return c.CompanyRepository.RemoveDirector(ctx, companyID, directorID, bounds)
+}
+
+// AddShareholder evicts the company, whose number_of_shareholders changes
+func (c *CachingCompanyRepository) AddShareholder(ctx context.Context, companyID openapi_types.UUID, req api.CreateShareholderRequest, bounds CountBounds) (*api.Shareholder, error) {
+	defer c.evict(companyID)
+	return c.CompanyRepository.AddShareholder(ctx, companyID, req, bounds)
+}
+
+// UpdateShareholder evicts the company, as shareholder writes may touch its row
+func (c *CachingCompanyRepository) UpdateShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID, req api.CreateShareholderRequest) (*api.Shareholder, error) {
+	defer c.evict(companyID)
+	return c.CompanyRepository.UpdateShareholder(ctx, companyID, shareholderID, req)
+}
+
+// RemoveShareholder evicts the company, whose number_of_shareholders changes
+func (c *CachingCompanyRepository) RemoveShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID, bounds CountBounds) error {
+	defer c.evict(companyID)
+	return c.CompanyRepository.RemoveShareholder(ctx, companyID, shareholderID, bounds)
+}
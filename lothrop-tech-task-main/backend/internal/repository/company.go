@@ -3,131 +3,707 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"backend/api"
+	"backend/internal/textfold"
 
+	"github.com/lib/pq"
 	openapi_types "github.com/oapi-codegen/runtime/types"
+	"go.uber.org/zap"
 )
 
+// ErrDuplicateName is returned when a write would give two companies in the same jurisdiction the same name
+var ErrDuplicateName = errors.New("company name already exists in jurisdiction")
+
+// ErrStaleVersion is returned when a conditional write finds the company's date_updated no longer matches the
+// expected version
+var ErrStaleVersion = errors.New("company was modified since the expected version")
+
+// ErrDuplicateSecCode is returned when a write would give two live companies the same canonical sec_code
+var ErrDuplicateSecCode = errors.New("sec_code already belongs to another company")
+
+// uniqueNameIndex is the unique index enforcing one live company per name_key within a jurisdiction
+const uniqueNameIndex = "idx_companies_jurisdiction_name_unique"
+
+// uniqueSecCodeIndex is the unique index enforcing one live company per non-blank canonical sec_code
+const uniqueSecCodeIndex = "idx_companies_sec_code_unique"
+
+// translateWriteError maps constraint violations to repository errors, returning other errors unchanged
+func translateWriteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		switch pqErr.Constraint {
+		case uniqueNameIndex:
+			return ErrDuplicateName
+		case uniqueSecCodeIndex:
+			return ErrDuplicateSecCode
+		}
+	}
+	return err
+}
+
 // CompanyRepository defines the interface for company data operations
 type CompanyRepository interface {
-	// GetAll retrieves companies with pagination and optional filtering
-	GetAll(ctx context.Context, limit, offset int, jurisdiction *string) ([]api.Company, int, error)
+	// GetAll retrieves companies with pagination, optional filtering and sorting, and the number matching the
+	// filter; without includeTotal the count query is skipped and the number is -1
+	GetAll(ctx context.Context, limit, offset int, filter Filter, sort Sort, includeTotal bool) ([]api.Company, int, error)
+
+	// GetAllIDs retrieves only company IDs with pagination and optional filtering, counting them as GetAll does
+	GetAllIDs(ctx context.Context, limit, offset int, filter Filter, sort Sort, includeTotal bool) ([]openapi_types.UUID, int, error)
+
+	// Count returns the number of companies matching the filter; Filter.After is ignored
+	Count(ctx context.Context, filter Filter) (int, error)
+
+	// Checksum returns the number of companies matching the filter and an order-independent checksum of their
+	// ids and date_updated values; Filter.After is ignored
+	Checksum(ctx context.Context, filter Filter) (int, uint64, error)
+
+	// StreamAll calls fn for every company matching the filter in sort order, without paginating or buffering the result set
+	StreamAll(ctx context.Context, filter Filter, sort Sort, fn func(*api.Company) error) error
+
+	// BeginSnapshot starts a read-only repeatable read snapshot for paging consistently across requests; it is
+	// rolled back after lifetime if not closed first
+	BeginSnapshot(lifetime time.Duration) (*Snapshot, error)
+
+	// GetChangedSince returns up to limit companies, soft-deleted ones included, changed after the (since, sinceID)
+	// position in (date_updated, id) order; a nil since starts from the beginning and a nil sinceID compares date_updated only
+	GetChangedSince(ctx context.Context, since *time.Time, sinceID *openapi_types.UUID, limit int) ([]api.Company, error)
+
+	// GetRawByID returns every stored column of a company row keyed by column name
+	GetRawByID(ctx context.Context, id openapi_types.UUID) (map[string]interface{}, error)
+
+	// DescribeGetAll returns the SQL GetAll would execute for the page, with placeholders, and its argument count
+	DescribeGetAll(limit, offset int, filter Filter, sort Sort) (string, int)
+
+	// ExplainGetAll returns the EXPLAIN (ANALYZE, BUFFERS) plan for the query GetAll would execute
+	ExplainGetAll(ctx context.Context, limit, offset int, filter Filter, sort Sort) (string, error)
 
 	// GetByID retrieves a company by its ID
 	GetByID(ctx context.Context, id openapi_types.UUID) (*api.Company, error)
 
+	// GetByRegistry retrieves a company by its external registry source and number
+	GetByRegistry(ctx context.Context, source, number string) (*api.Company, error)
+
+	// FindByNormalizedName retrieves the oldest live company in the jurisdiction whose name normalizes like
+	// name (see textfold.NormalizedName), or nil if there is none
+	FindByNormalizedName(ctx context.Context, jurisdiction, name string) (*api.Company, error)
+
 	// Create creates a new company and returns the created company with generated ID and timestamps
 	Create(ctx context.Context, req api.CreateCompanyRequest) (*api.Company, error)
 
-	// Delete removes a company by its ID
+	// GetIdempotencyRecord returns the record of an idempotent create made with key less than ttl ago, or nil
+	GetIdempotencyRecord(ctx context.Context, key string, ttl time.Duration) (*IdempotencyRecord, error)
+
+	// CreateIdempotent creates a company and records it under key, expiring keys older than ttl. If the key is
+	// already recorded it creates nothing and returns the existing record instead.
+	CreateIdempotent(ctx context.Context, key, requestHash string, ttl time.Duration, req api.CreateCompanyRequest) (*api.Company, *IdempotencyRecord, error)
+
+	// CreateMany creates all companies in a single transaction; if any insert fails none are created
+	CreateMany(ctx context.Context, reqs []api.CreateCompanyRequest) ([]api.Company, error)
+
+	// PreviewCreateMany runs CreateMany's inserts and rolls them back, returning the companies it would create
+	PreviewCreateMany(ctx context.Context, reqs []api.CreateCompanyRequest) ([]api.Company, error)
+
+	// Update replaces a company's fields and returns the updated company, or nil if it does not exist.
+	// A non-nil expected makes the write conditional on date_updated, returning ErrStaleVersion on a mismatch.
+	// It returns a *SyncedCountError, changing nothing, if a count would differ from the company's records.
+	Update(ctx context.Context, id openapi_types.UUID, req api.CreateCompanyRequest, expected *time.Time) (*api.Company, error)
+
+	// Patch updates only the non-nil fields of req and returns the updated company, or nil if it does not exist.
+	// A non-nil expected makes the write conditional on date_updated, returning ErrStaleVersion on a mismatch.
+	// It returns a *SyncedCountError, changing nothing, if a count would differ from the company's records.
+	Patch(ctx context.Context, id openapi_types.UUID, req api.PatchCompanyRequest, expected *time.Time) (*api.Company, error)
+
+	// Delete soft-deletes a company by its ID, setting deleted_at so it is hidden but recoverable
 	Delete(ctx context.Context, id openapi_types.UUID) error
+
+	// DeleteMany soft-deletes the live companies among ids in one transaction and returns the IDs it deleted
+	DeleteMany(ctx context.Context, ids []openapi_types.UUID) ([]openapi_types.UUID, error)
+
+	// Restore undoes a soft delete and returns the restored company, or nil if no deleted company has the ID
+	Restore(ctx context.Context, id openapi_types.UUID) (*api.Company, error)
+
+	// PurgeDeleted permanently deletes companies soft-deleted before olderThan, with their directors and
+	// shareholders, and returns how many were deleted. Their audit history survives and stays readable.
+	PurgeDeleted(ctx context.Context, olderThan time.Time) (int64, error)
+
+	// TransferJurisdiction moves a live company to another jurisdiction and returns the updated company, or nil
+	// if no live company has the ID
+	TransferJurisdiction(ctx context.Context, id openapi_types.UUID, jurisdiction string) (*api.Company, error)
+
+	// AdjustCount atomically adds delta to a live company's director or shareholder count and returns the updated
+	// company, or nil if no live company has the ID. It returns ErrCountOutOfRange, changing nothing, if the new
+	// count would fall outside bounds, and a *SyncedCountError if records keep the count.
+	AdjustCount(ctx context.Context, id openapi_types.UUID, column CountColumn, delta int, bounds CountBounds) (*api.Company, error)
+
+	// ListDirectors returns a live company's directors, oldest first, or nil if no live company has the ID
+	ListDirectors(ctx context.Context, companyID openapi_types.UUID) ([]api.Director, error)
+
+	// AddDirector adds a director to a live company and sets its number_of_directors to its director count.
+	// It returns nil if no live company has the ID and ErrDirectorLimit if the company already has the maximum
+	// bounds allow in its jurisdiction.
+	AddDirector(ctx context.Context, companyID openapi_types.UUID, req api.CreateDirectorRequest, bounds CountBounds) (*api.Director, error)
+
+	// RemoveDirector deletes a live company's director and sets its number_of_directors to its director count,
+	// returning sql.ErrNoRows if the company is not live or has no such director and ErrDirectorMinimum if the
+	// company would be left with fewer directors than bounds allow in its jurisdiction
+	RemoveDirector(ctx context.Context, companyID, directorID openapi_types.UUID, bounds CountBounds) error
+
+	// CountMembers returns how many director and shareholder records a company has
+	CountMembers(ctx context.Context, id openapi_types.UUID) (MemberCounts, error)
+
+	// ListShareholders returns a live company's shareholders, oldest first, or nil if no live company has the ID
+	ListShareholders(ctx context.Context, companyID openapi_types.UUID) ([]api.Shareholder, error)
+
+	// AddShareholder adds a shareholder to a live company and sets its number_of_shareholders to its shareholder
+	// count. It returns nil if no live company has the ID, ErrShareholderLimit if the company already has the
+	// maximum bounds allow in its jurisdiction and ErrOwnershipExceeded if the company's total ownership would
+	// pass 100%.
+	AddShareholder(ctx context.Context, companyID openapi_types.UUID, req api.CreateShareholderRequest, bounds CountBounds) (*api.Shareholder, error)
+
+	// UpdateShareholder replaces a live company's shareholder's name and ownership percentage, returning
+	// sql.ErrNoRows if the company is not live or has no such shareholder and ErrOwnershipExceeded if the
+	// company's total ownership would pass 100%
+	UpdateShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID, req api.CreateShareholderRequest) (*api.Shareholder, error)
+
+	// RemoveShareholder deletes a live company's shareholder and sets its number_of_shareholders to its shareholder
+	// count, returning sql.ErrNoRows if the company is not live or has no such shareholder and
+	// ErrShareholderMinimum if the company would be left with fewer shareholders than bounds allow in its jurisdiction
+	RemoveShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID, bounds CountBounds) error
+
+	// GetHistory returns a company's audit entries, oldest first, or nil if no company, live, soft-deleted or purged,
+	// has the ID
+	GetHistory(ctx context.Context, companyID openapi_types.UUID) ([]api.AuditEntry, error)
+
+	// GetSharedAddressGroups retrieves live companies grouped by normalized address, keeping groups of at least
+	// minSize companies. Every such group is returned in full; the report is not paginated.
+	GetSharedAddressGroups(ctx context.Context, minSize int) ([]api.SharedAddressGroup, error)
+
+	// CountByJurisdiction returns the number of live companies in each jurisdiction that has any, most first
+	CountByJurisdiction(ctx context.Context) ([]api.JurisdictionCount, error)
+
+	// CountByNatureOfBusiness returns the number of live companies with each nature of business, only counting
+	// those in jurisdiction when it is non-nil, most first
+	CountByNatureOfBusiness(ctx context.Context, jurisdiction *string) ([]api.NatureOfBusinessCount, error)
+}
+
+// companyColumns is the standard company column list, in the order scanCompany expects
+const companyColumns = `id, jurisdiction, company_name, company_address, nature_of_business,
+	number_of_directors, number_of_shareholders, sec_code, registry_source, registry_number,
+	date_created, date_updated, deleted_at`
+
+// Filter narrows a company list; nil fields do not filter
+type Filter struct {
+	// Jurisdictions matches any of the listed jurisdictions, case-insensitively
+	Jurisdictions []string
+	// Search matches company names containing it, case-insensitively
+	Search *string
+	// FoldedSearch matches Search against the accent-folded search_name column instead of company_name;
+	// Search must then already be folded with textfold.Fold
+	FoldedSearch bool
+	// TextQuery matches companies whose full-text search_vector (company name and nature of business) matches
+	// it in web search syntax. A query of only stop words matches every company rather than none.
+	TextQuery *string
+	// CreatedAfter and CreatedBefore bound date_created, inclusively
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// RecentMinutes, when positive, keeps companies created within that many minutes of the database's now()
+	RecentMinutes int
+	// IncludeDeleted lists soft-deleted companies alongside live ones
+	IncludeDeleted bool
+	// After restricts the list to companies after this position in DefaultSort order (keyset pagination).
+	// It is ignored when counting, so totals cover the whole filtered list.
+	After *Cursor
+}
+
+// Cursor is a keyset position in DefaultSort order
+type Cursor struct {
+	DateCreated time.Time
+	ID          openapi_types.UUID
+}
+
+// conditions renders the filter as SQL conditions to be AND-ed, binding every value through b
+func (f Filter) conditions(b *queryBuilder) []string {
+	var conditions []string
+
+	if !f.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	switch len(f.Jurisdictions) {
+	case 0:
+	case 1:
+		conditions = append(conditions, "LOWER(jurisdiction) = LOWER("+b.bind(f.Jurisdictions[0])+")")
+	default:
+		lowered := make([]string, len(f.Jurisdictions))
+		for i, j := range f.Jurisdictions {
+			lowered[i] = strings.ToLower(j)
+		}
+		conditions = append(conditions, "LOWER(jurisdiction) = ANY("+b.bind(pq.Array(lowered))+")")
+	}
+
+	if f.Search != nil {
+		search := b.bind(likeEscaper.Replace(*f.Search))
+		if f.FoldedSearch {
+			conditions = append(conditions, "search_name LIKE '%' || "+search+" || '%'")
+		} else {
+			conditions = append(conditions, "company_name ILIKE '%' || "+search+" || '%'")
+		}
+	}
+
+	if f.TextQuery != nil {
+		query := b.bind(*f.TextQuery)
+		conditions = append(conditions, "(numnode(websearch_to_tsquery('english', "+query+")) = 0 OR "+
+			"search_vector @@ websearch_to_tsquery('english', "+query+"))")
+	}
+
+	if f.CreatedAfter != nil {
+		conditions = append(conditions, "date_created >= "+b.bind(*f.CreatedAfter))
+	}
+
+	if f.CreatedBefore != nil {
+		conditions = append(conditions, "date_created <= "+b.bind(*f.CreatedBefore))
+	}
+
+	if f.RecentMinutes > 0 {
+		conditions = append(conditions, "date_created >= now() - make_interval(mins => "+b.bind(f.RecentMinutes)+")")
+	}
+
+	if f.After != nil {
+		conditions = append(conditions, "(date_created, id) < ("+b.bind(f.After.DateCreated)+", "+b.bind(f.After.ID)+")")
+	}
+
+	return conditions
+}
+
+// likeEscaper escapes LIKE wildcards so a search term matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Sort is a list ordering. Column must come from the service's sort allowlist; it is interpolated into SQL.
+// Relevance orders by ts_rank against the filter's TextQuery first, breaking ties by Column.
+type Sort struct {
+	Column     string
+	Descending bool
+	Relevance  bool
+}
+
+// DefaultSort lists the newest companies first
+var DefaultSort = Sort{Column: "date_created", Descending: true}
+
+// orderBy renders the ORDER BY expression, falling back to DefaultSort and breaking ties by id for stable paging
+func (s Sort) orderBy() string {
+	if s.Column == "" {
+		s = DefaultSort
+	}
+
+	direction := "ASC"
+	if s.Descending {
+		direction = "DESC"
+	}
+
+	return s.Column + " " + direction + ", id " + direction
+}
+
+// orderClause renders the ORDER BY expression for the filter's query, binding the text query through b when
+// ranking by relevance
+func orderClause(filter Filter, sort Sort, b *queryBuilder) string {
+	if !sort.Relevance || filter.TextQuery == nil {
+		return sort.orderBy()
+	}
+
+	return "ts_rank(search_vector, websearch_to_tsquery('english', " + b.bind(*filter.TextQuery) + ")) DESC, " + sort.orderBy()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanCompany scans the standard company column list, followed by any extra destinations. Timestamps are
+// returned in UTC: lib/pq gives timestamptz values the offset of the session time zone, which follows the
+// database server's TimeZone setting, so the same instant could otherwise serialize differently per deployment.
+func scanCompany(row rowScanner, extra ...interface{}) (*api.Company, error) {
+	var company api.Company
+	dest := []interface{}{
+		&company.Id,
+		&company.Jurisdiction,
+		&company.CompanyName,
+		&company.CompanyAddress,
+		&company.NatureOfBusiness,
+		&company.NumberOfDirectors,
+		&company.NumberOfShareholders,
+		&company.SecCode,
+		&company.RegistrySource,
+		&company.RegistryNumber,
+		&company.DateCreated,
+		&company.DateUpdated,
+		&company.DeletedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	company.DateCreated = company.DateCreated.UTC()
+	company.DateUpdated = company.DateUpdated.UTC()
+	if company.DeletedAt != nil {
+		deletedAt := company.DeletedAt.UTC()
+		company.DeletedAt = &deletedAt
+	}
+	return &company, nil
 }
 
 // PostgresCompanyRepository implements CompanyRepository using PostgreSQL
 type PostgresCompanyRepository struct {
-	db *sql.DB
+	db          *sql.DB
+	maxAttempts int
+	slowQuery   time.Duration
+	logger      *zap.Logger
 }
 
-// NewPostgresCompanyRepository creates a new PostgreSQL company repository
-func NewPostgresCompanyRepository(db *sql.DB) CompanyRepository {
-	return &PostgresCompanyRepository{db: db}
+// NewPostgresCompanyRepository creates a new PostgreSQL company repository. Reads and transactions failing with
+// a transient error are attempted up to maxAttempts times; values below 1 disable retries. Attempts taking
+// slowQuery or longer are logged as slow queries; 0 disables the logging.
+func NewPostgresCompanyRepository(db *sql.DB, maxAttempts int, slowQuery time.Duration, logger *zap.Logger) CompanyRepository {
+	return &PostgresCompanyRepository{db: db, maxAttempts: max(maxAttempts, 1), slowQuery: slowQuery, logger: logger}
 }
 
-// GetAll retrieves companies with pagination and optional filtering
-func (r *PostgresCompanyRepository) GetAll(ctx context.Context, limit, offset int, jurisdiction *string) ([]api.Company, int, error) {
+// GetAll retrieves companies with pagination, optional filtering and sorting
+func (r *PostgresCompanyRepository) GetAll(ctx context.Context, limit, offset int, filter Filter, sort Sort, includeTotal bool) ([]api.Company, int, error) {
 	var companies []api.Company
-	var total int
 
 	// First, get the total count
-	countQuery := "SELECT COUNT(*) FROM companies"
-	countArgs := []interface{}{}
-
-	if jurisdiction != nil {
-		countQuery += " WHERE jurisdiction = $1"
-		countArgs = append(countArgs, *jurisdiction)
+	total, err := r.countIf(ctx, filter, includeTotal)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	err := r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	// Then get the companies with pagination
+	query, args := listQuery(companyColumns, limit, offset, filter, sort)
+
+	rows, err := r.query(ctx, "list", query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
+	defer rows.Close()
 
-	// Then get the companies with pagination
-	query := `
-		SELECT id, jurisdiction, company_name, company_address, nature_of_business, 
-		       number_of_directors, number_of_shareholders, sec_code, date_created, date_updated
-		FROM companies`
+	for rows.Next() {
+		// Stop scanning once the client has gone, so the connection is released rather than drained
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
 
-	args := []interface{}{}
-	argIndex := 1
+		company, err := scanCompany(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		companies = append(companies, *company)
+	}
 
-	if jurisdiction != nil {
-		query += " WHERE jurisdiction = $" + fmt.Sprintf("%d", argIndex)
-		args = append(args, *jurisdiction)
-		argIndex++
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
 	}
 
-	query += " ORDER BY date_created DESC"
-	query += " LIMIT $" + fmt.Sprintf("%d", argIndex) + " OFFSET $" + fmt.Sprintf("%d", argIndex+1)
-	args = append(args, limit, offset)
+	return companies, total, nil
+}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+// GetAllIDs retrieves only company IDs with pagination and optional filtering
+func (r *PostgresCompanyRepository) GetAllIDs(ctx context.Context, limit, offset int, filter Filter, sort Sort, includeTotal bool) ([]openapi_types.UUID, int, error) {
+	ids := []openapi_types.UUID{}
+
+	total, err := r.countIf(ctx, filter, includeTotal)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args := listQuery("id", limit, offset, filter, sort)
+
+	rows, err := r.query(ctx, "list_ids", query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var company api.Company
-		err := rows.Scan(
-			&company.Id,
-			&company.Jurisdiction,
-			&company.CompanyName,
-			&company.CompanyAddress,
-			&company.NatureOfBusiness,
-			&company.NumberOfDirectors,
-			&company.NumberOfShareholders,
-			&company.SecCode,
-			&company.DateCreated,
-			&company.DateUpdated,
-		)
-		if err != nil {
+		var id openapi_types.UUID
+		if err := rows.Scan(&id); err != nil {
 			return nil, 0, err
 		}
-		companies = append(companies, company)
+		ids = append(ids, id)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, 0, err
 	}
 
-	return companies, total, nil
+	return ids, total, nil
+}
+
+// countIf counts the companies matching the filter when includeTotal is set, and otherwise returns -1 without
+// querying, as the count is the costly part of listing a large table
+func (r *PostgresCompanyRepository) countIf(ctx context.Context, filter Filter, includeTotal bool) (int, error) {
+	if !includeTotal {
+		return -1, nil
+	}
+	return r.Count(ctx, filter)
+}
+
+// ExplainGetAll runs EXPLAIN (ANALYZE, BUFFERS) on the query GetAll would execute and returns the plan text
+func (r *PostgresCompanyRepository) ExplainGetAll(ctx context.Context, limit, offset int, filter Filter, sort Sort) (string, error) {
+	query, args := listQuery(companyColumns, limit, offset, filter, sort)
+
+	rows, err := r.query(ctx, "explain", "EXPLAIN (ANALYZE, BUFFERS) "+query, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var plan []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", err
+		}
+		plan = append(plan, line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	return strings.Join(plan, "\n"), nil
+}
+
+// StreamAll calls fn for every company matching the filter in sort order, reading rows as fn consumes them.
+// It stops at the first error fn returns. Memory stays constant however many rows match, but a pooled connection
+// is held until fn has seen the last row, so a slow consumer ties it up for as long as it takes.
+func (r *PostgresCompanyRepository) StreamAll(ctx context.Context, filter Filter, sort Sort, fn func(*api.Company) error) error {
+	var b queryBuilder
+	b.write("SELECT ", companyColumns, " FROM companies")
+	b.where(filter.conditions(&b))
+	b.write(" ORDER BY ", orderClause(filter, sort, &b))
+	query, args := b.build()
+
+	rows, err := r.query(ctx, "stream", query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		company, err := scanCompany(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(company); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetChangedSince retrieves companies changed after a watermark in (date_updated, id) order, including soft-deleted ones
+func (r *PostgresCompanyRepository) GetChangedSince(ctx context.Context, since *time.Time, sinceID *openapi_types.UUID, limit int) ([]api.Company, error) {
+	companies := []api.Company{}
+
+	var b queryBuilder
+	b.write("SELECT ", companyColumns, " FROM companies")
+	switch {
+	case since != nil && sinceID != nil:
+		b.write(" WHERE (date_updated, id) > (", b.bind(*since), ", ", b.bind(*sinceID), ")")
+	case since != nil:
+		b.write(" WHERE date_updated > ", b.bind(*since))
+	}
+	b.write(" ORDER BY date_updated, id LIMIT ", b.bind(limit))
+	query, args := b.build()
+
+	rows, err := r.query(ctx, "changes", query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		company, err := scanCompany(rows)
+		if err != nil {
+			return nil, err
+		}
+		companies = append(companies, *company)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return companies, nil
+}
+
+// DescribeGetAll returns the paginated list query GetAll would execute, with placeholders, and its argument count
+func (r *PostgresCompanyRepository) DescribeGetAll(limit, offset int, filter Filter, sort Sort) (string, int) {
+	query, args := listQuery(companyColumns, limit, offset, filter, sort)
+	return query, len(args)
+}
+
+// listQuery composes the paginated, filtered list query selecting the given columns
+func listQuery(columns string, limit, offset int, filter Filter, sort Sort) (string, []interface{}) {
+	var b queryBuilder
+	b.write("SELECT ", columns, " FROM companies")
+	b.where(filter.conditions(&b))
+	b.write(" ORDER BY ", orderClause(filter, sort, &b))
+	b.write(" LIMIT ", b.bind(limit), " OFFSET ", b.bind(offset))
+	return b.build()
+}
+
+// Count returns the number of companies matching the filter; Filter.After is ignored
+func (r *PostgresCompanyRepository) Count(ctx context.Context, filter Filter) (int, error) {
+	var total int
+
+	filter.After = nil
+	var b queryBuilder
+	b.write("SELECT COUNT(*) FROM companies")
+	b.where(filter.conditions(&b))
+	countQuery, countArgs := b.build()
+
+	err := r.retry(ctx, "count", func() error {
+		return r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// rowHashExpression hashes one company row's id and date_updated (in Unix microseconds) to a non-negative 60-bit
+// integer: the first 15 hex digits of md5("<id>|<microseconds>")
+const rowHashExpression = `('x' || substr(md5(id::text || '|' ||
+	(extract(epoch FROM date_updated) * 1000000)::bigint::text), 1, 15))::bit(60)::bigint`
+
+// Checksum sums the row hashes of the matching companies modulo 2^64. Addition is commutative, so the checksum
+// does not depend on row order, and any created, updated or deleted row changes it.
+func (r *PostgresCompanyRepository) Checksum(ctx context.Context, filter Filter) (int, uint64, error) {
+	var total int
+	var sum string
+
+	filter.After = nil
+	var b queryBuilder
+	b.write("SELECT COUNT(*), (COALESCE(SUM(", rowHashExpression, "::numeric), 0) % 18446744073709551616)::text FROM companies")
+	b.where(filter.conditions(&b))
+	query, args := b.build()
+
+	err := r.retry(ctx, "checksum", func() error {
+		return r.db.QueryRowContext(ctx, query, args...).Scan(&total, &sum)
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	checksum, err := strconv.ParseUint(sum, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse checksum %q: %w", sum, err)
+	}
+
+	return total, checksum, nil
 }
 
 // GetByID retrieves a company by its ID
 func (r *PostgresCompanyRepository) GetByID(ctx context.Context, id openapi_types.UUID) (*api.Company, error) {
-	query := `
-		SELECT id, jurisdiction, company_name, company_address, nature_of_business, 
-		       number_of_directors, number_of_shareholders, sec_code, date_created, date_updated
-		FROM companies 
-		WHERE id = $1`
+	query := "SELECT " + companyColumns + " FROM companies WHERE id = $1 AND deleted_at IS NULL"
 
-	var company api.Company
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&company.Id,
-		&company.Jurisdiction,
-		&company.CompanyName,
-		&company.CompanyAddress,
-		&company.NatureOfBusiness,
-		&company.NumberOfDirectors,
-		&company.NumberOfShareholders,
-		&company.SecCode,
-		&company.DateCreated,
-		&company.DateUpdated,
-	)
+	var company *api.Company
+	err := r.retry(ctx, "get", func() error {
+		var err error
+		company, err = scanCompany(r.db.QueryRowContext(ctx, query, id))
+		return err
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Company not found
+		}
+		return nil, err
+	}
 
+	return company, nil
+}
+
+// GetRawByID retrieves every stored column of a company row, including ones not exposed by the API.
+// Byte values are returned as strings so the row serializes readably.
+func (r *PostgresCompanyRepository) GetRawByID(ctx context.Context, id openapi_types.UUID) (map[string]interface{}, error) {
+	rows, err := r.query(ctx, "get_raw", "SELECT * FROM companies WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err() // Company not found
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		if b, ok := values[i].([]byte); ok {
+			row[column] = string(b)
+		} else {
+			row[column] = values[i]
+		}
+	}
+
+	return row, rows.Err()
+}
+
+// FindByNormalizedName retrieves the oldest live company in the jurisdiction whose name normalizes like name
+func (r *PostgresCompanyRepository) FindByNormalizedName(ctx context.Context, jurisdiction, name string) (*api.Company, error) {
+	query := "SELECT " + companyColumns + ` FROM companies
+		WHERE jurisdiction = $1 AND normalized_name = $2 AND deleted_at IS NULL
+		ORDER BY date_created, id
+		LIMIT 1`
+
+	var company *api.Company
+	err := r.retry(ctx, "find_by_normalized_name", func() error {
+		var err error
+		company, err = scanCompany(r.db.QueryRowContext(ctx, query, jurisdiction, textfold.NormalizedName(name)))
+		return err
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No near-duplicate
+		}
+		return nil, err
+	}
+
+	return company, nil
+}
+
+// GetByRegistry retrieves a company by its external registry source and number
+func (r *PostgresCompanyRepository) GetByRegistry(ctx context.Context, source, number string) (*api.Company, error) {
+	query := "SELECT " + companyColumns + " FROM companies WHERE registry_source = $1 AND registry_number = $2 AND deleted_at IS NULL"
+
+	var company *api.Company
+	err := r.retry(ctx, "get_by_registry", func() error {
+		var err error
+		company, err = scanCompany(r.db.QueryRowContext(ctx, query, source, number))
+		return err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Company not found
@@ -135,19 +711,113 @@ func (r *PostgresCompanyRepository) GetByID(ctx context.Context, id openapi_type
 		return nil, err
 	}
 
-	return &company, nil
+	return company, nil
 }
 
 func (r *PostgresCompanyRepository) Create(ctx context.Context, req api.CreateCompanyRequest) (*api.Company, error) {
-	query := `
+	var company *api.Company
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		company, err = scanCompany(tx.QueryRowContext(ctx, insertCompanyQuery, insertCompanyArgs(req)...))
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, company.Id, api.Create)
+	})
+	if err != nil {
+		return nil, translateWriteError(err)
+	}
+
+	return company, nil
+}
+
+// errRollback aborts a transaction whose statements succeeded, so a preview is rolled back
+var errRollback = errors.New("rolled back")
+
+// CreateMany inserts all companies in a single transaction; if any insert fails none are created
+func (r *PostgresCompanyRepository) CreateMany(ctx context.Context, reqs []api.CreateCompanyRequest) ([]api.Company, error) {
+	return r.createMany(ctx, reqs, false)
+}
+
+// PreviewCreateMany runs CreateMany's inserts and rolls them back, so constraint violations such as duplicate
+// names are reported exactly as CreateMany would, but nothing is saved
+func (r *PostgresCompanyRepository) PreviewCreateMany(ctx context.Context, reqs []api.CreateCompanyRequest) ([]api.Company, error) {
+	return r.createMany(ctx, reqs, true)
+}
+
+// createMany inserts the companies in one transaction, rolling it back afterwards when rollback is set
+func (r *PostgresCompanyRepository) createMany(ctx context.Context, reqs []api.CreateCompanyRequest, rollback bool) ([]api.Company, error) {
+	companies := make([]api.Company, 0, len(reqs))
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		companies = companies[:0] // A retried transaction starts over
+		for _, req := range reqs {
+			company, err := scanCompany(tx.QueryRowContext(ctx, insertCompanyQuery, insertCompanyArgs(req)...))
+			if err != nil {
+				return err
+			}
+			if err := recordAudit(ctx, tx, company.Id, api.Create); err != nil {
+				return err
+			}
+			companies = append(companies, *company)
+		}
+		if rollback {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, translateWriteError(err)
+	}
+
+	return companies, nil
+}
+
+// withTx runs fn in a transaction, committing if it succeeds and rolling back if it returns an error or panics.
+// The transaction is bound to ctx, so cancellation aborts it and it is rolled back rather than committed.
+// A transaction failing with a transient error before COMMIT is rerun from the start, so fn must reset any
+// results it collects.
+func (r *PostgresCompanyRepository) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return r.retry(ctx, "transaction", func() error {
+		return r.runTx(ctx, fn)
+	})
+}
+
+// runTx makes one attempt at withTx's transaction
+func (r *PostgresCompanyRepository) runTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op once committed
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &errCommit{err: err}
+	}
+
+	return nil
+}
+
+// insertCompanyQuery inserts one company; its arguments come from insertCompanyArgs
+var insertCompanyQuery = `
 		INSERT INTO companies (jurisdiction, company_name, company_address, nature_of_business, 
-		                      number_of_directors, number_of_shareholders, sec_code)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, jurisdiction, company_name, company_address, nature_of_business, 
-		          number_of_directors, number_of_shareholders, sec_code, date_created, date_updated`
+		                      number_of_directors, number_of_shareholders, sec_code,
+		                      registry_source, registry_number, search_name, name_key, normalized_name)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING ` + companyColumns
 
-	var company api.Company
-	err := r.db.QueryRowContext(ctx, query,
+// insertCompanyArgs returns the insertCompanyQuery arguments for a create request. Omitted optional fields are
+// nil pointers, which database/sql binds as NULL; since insertCompanyQuery names every optional column, no column
+// default applies either, so they are stored as NULL rather than 0 or "" and scanCompany reads them back as nil.
+func insertCompanyArgs(req api.CreateCompanyRequest) []interface{} {
+	return []interface{}{
 		req.Jurisdiction,
 		req.CompanyName,
 		req.CompanyAddress,
@@ -155,42 +825,417 @@ func (r *PostgresCompanyRepository) Create(ctx context.Context, req api.CreateCo
 		req.NumberOfDirectors,
 		req.NumberOfShareholders,
 		req.SecCode,
-	).Scan(
-		&company.Id,
-		&company.Jurisdiction,
-		&company.CompanyName,
-		&company.CompanyAddress,
-		&company.NatureOfBusiness,
-		&company.NumberOfDirectors,
-		&company.NumberOfShareholders,
-		&company.SecCode,
-		&company.DateCreated,
-		&company.DateUpdated,
-	)
+		req.RegistrySource,
+		req.RegistryNumber,
+		textfold.Fold(req.CompanyName),
+		textfold.NameKey(req.CompanyName),
+		textfold.NormalizedName(req.CompanyName),
+	}
+}
 
+// Update replaces a company's fields and returns the updated company, or nil if it does not exist
+func (r *PostgresCompanyRepository) Update(ctx context.Context, id openapi_types.UUID, req api.CreateCompanyRequest, expected *time.Time) (*api.Company, error) {
+	query := `
+		UPDATE companies
+		SET jurisdiction = $2, company_name = $3, company_address = $4, nature_of_business = $5,
+		    number_of_directors = $6, number_of_shareholders = $7, sec_code = $8,
+		    registry_source = $9, registry_number = $10, search_name = $11, name_key = $12, normalized_name = $13,
+		    date_updated = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL AND ($14::timestamptz IS NULL OR date_updated = $14)
+		RETURNING ` + companyColumns
+
+	var company *api.Company
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		company, err = scanCompany(tx.QueryRowContext(ctx, query,
+			id,
+			req.Jurisdiction,
+			req.CompanyName,
+			req.CompanyAddress,
+			req.NatureOfBusiness,
+			req.NumberOfDirectors,
+			req.NumberOfShareholders,
+			req.SecCode,
+			req.RegistrySource,
+			req.RegistryNumber,
+			textfold.Fold(req.CompanyName),
+			textfold.NameKey(req.CompanyName),
+			textfold.NormalizedName(req.CompanyName),
+			expected,
+		))
+		if err != nil {
+			return err
+		}
+		if err := checkSyncedCounts(ctx, tx, company); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, id, api.Update)
+	})
 	if err != nil {
-		return nil, err
+		if err == sql.ErrNoRows {
+			return nil, r.noRowsUpdated(ctx, id, expected)
+		}
+		return nil, translateWriteError(err)
 	}
 
-	return &company, nil
+	return company, nil
 }
 
-// Delete removes a company by its ID
-func (r *PostgresCompanyRepository) Delete(ctx context.Context, id openapi_types.UUID) error {
-	query := "DELETE FROM companies WHERE id = $1"
-	result, err := r.db.ExecContext(ctx, query, id)
+// Patch updates only the non-nil fields of req and returns the updated company, or nil if it does not exist
+func (r *PostgresCompanyRepository) Patch(ctx context.Context, id openapi_types.UUID, req api.PatchCompanyRequest, expected *time.Time) (*api.Company, error) {
+	var b queryBuilder
+	conditions := []string{"id = " + b.bind(id), "deleted_at IS NULL"}
+
+	var sets []string
+	set := func(column string, value interface{}) {
+		sets = append(sets, column+" = "+b.bind(value))
+	}
+
+	if req.Jurisdiction != nil {
+		set("jurisdiction", *req.Jurisdiction)
+	}
+	if req.CompanyName != nil {
+		set("company_name", *req.CompanyName)
+		set("search_name", textfold.Fold(*req.CompanyName))
+		set("name_key", textfold.NameKey(*req.CompanyName))
+		set("normalized_name", textfold.NormalizedName(*req.CompanyName))
+	}
+	if req.CompanyAddress != nil {
+		set("company_address", *req.CompanyAddress)
+	}
+	if req.NatureOfBusiness != nil {
+		set("nature_of_business", *req.NatureOfBusiness)
+	}
+	if req.NumberOfDirectors != nil {
+		set("number_of_directors", *req.NumberOfDirectors)
+	}
+	if req.NumberOfShareholders != nil {
+		set("number_of_shareholders", *req.NumberOfShareholders)
+	}
+	if req.SecCode != nil {
+		set("sec_code", *req.SecCode)
+	}
+	if req.RegistrySource != nil {
+		set("registry_source", *req.RegistrySource)
+	}
+	if req.RegistryNumber != nil {
+		set("registry_number", *req.RegistryNumber)
+	}
+
+	// Nothing to change, so leave date_updated alone
+	if len(sets) == 0 {
+		company, err := r.GetByID(ctx, id)
+		if err == nil && company != nil && expected != nil && !company.DateUpdated.Equal(*expected) {
+			return nil, ErrStaleVersion
+		}
+		return company, err
+	}
+
+	if expected != nil {
+		conditions = append(conditions, "date_updated = "+b.bind(*expected))
+	}
+
+	b.write("UPDATE companies SET ", strings.Join(sets, ", "), ", date_updated = CURRENT_TIMESTAMP")
+	b.where(conditions)
+	b.write(" RETURNING ", companyColumns)
+	query, args := b.build()
+
+	var company *api.Company
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		company, err = scanCompany(tx.QueryRowContext(ctx, query, args...))
+		if err != nil {
+			return err
+		}
+		if err := checkSyncedCounts(ctx, tx, company); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, id, api.Update)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, r.noRowsUpdated(ctx, id, expected)
+		}
+		return nil, translateWriteError(err)
+	}
+
+	return company, nil
+}
+
+// noRowsUpdated explains an update that matched no row: the company does not exist (nil), or it exists but
+// its date_updated did not match the expected version (ErrStaleVersion)
+func (r *PostgresCompanyRepository) noRowsUpdated(ctx context.Context, id openapi_types.UUID, expected *time.Time) error {
+	if expected == nil {
+		return nil // Company not found
+	}
+
+	company, err := r.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
+	if company == nil {
+		return nil // Company not found
+	}
+	return ErrStaleVersion
+}
 
-	rowsAffected, err := result.RowsAffected()
+// Delete soft-deletes a company by its ID; date_updated is bumped so incremental readers see the tombstone
+func (r *PostgresCompanyRepository) Delete(ctx context.Context, id openapi_types.UUID) error {
+	query := `
+		UPDATE companies
+		SET deleted_at = CURRENT_TIMESTAMP, date_updated = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL`
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return sql.ErrNoRows // Company not found
+		}
+
+		return recordAudit(ctx, tx, id, api.Delete)
+	})
+}
+
+// DeleteMany soft-deletes every live company among ids with one statement in one transaction, auditing each as
+// Delete does, and returns the IDs it deleted. IDs of missing or already deleted companies are skipped.
+func (r *PostgresCompanyRepository) DeleteMany(ctx context.Context, ids []openapi_types.UUID) ([]openapi_types.UUID, error) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = id.String()
+	}
+
+	query := `
+		UPDATE companies
+		SET deleted_at = CURRENT_TIMESTAMP, date_updated = CURRENT_TIMESTAMP
+		WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+		RETURNING id`
+
+	var deleted []openapi_types.UUID
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		deleted = deleted[:0] // A retried transaction starts over
+
+		rows, err := tx.QueryContext(ctx, query, pq.Array(keys))
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var id openapi_types.UUID
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			deleted = append(deleted, id)
+		}
+		// The result set must be closed before the transaction's connection can run the audit inserts
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, id := range deleted {
+			if err := recordAudit(ctx, tx, id, api.Delete); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// Restore clears a company's deleted_at and returns the restored company, or nil if no deleted company has the ID
+func (r *PostgresCompanyRepository) Restore(ctx context.Context, id openapi_types.UUID) (*api.Company, error) {
+	query := `
+		UPDATE companies
+		SET deleted_at = NULL, date_updated = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING ` + companyColumns
+
+	var company *api.Company
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		company, err = scanCompany(tx.QueryRowContext(ctx, query, id))
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, id, api.Restore)
+	})
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No deleted company with this ID
+		}
+		return nil, translateWriteError(err)
+	}
+
+	return company, nil
+}
+
+// PurgeDeleted hard-deletes companies whose deleted_at is before olderThan; directors, shareholders and
+// idempotency keys referencing them are removed by their ON DELETE CASCADE foreign keys. Audit entries have no
+// foreign key and are kept, so a purged company's history outlives it.
+func (r *PostgresCompanyRepository) PurgeDeleted(ctx context.Context, olderThan time.Time) (int64, error) {
+	var purged int64
+	err := r.retry(ctx, "purge_deleted", func() error {
+		result, err := r.db.ExecContext(ctx, "DELETE FROM companies WHERE deleted_at < $1", olderThan)
+		if err != nil {
+			return err
+		}
+		purged, err = result.RowsAffected()
 		return err
+	})
+	return purged, err
+}
+
+// TransferJurisdiction sets a live company's jurisdiction and returns the updated company, or nil if no live
+// company has the ID. The change is audited as a jurisdiction_change rather than an update.
+func (r *PostgresCompanyRepository) TransferJurisdiction(ctx context.Context, id openapi_types.UUID, jurisdiction string) (*api.Company, error) {
+	query := `
+		UPDATE companies
+		SET jurisdiction = $2, date_updated = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING ` + companyColumns
+
+	var company *api.Company
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		company, err = scanCompany(tx.QueryRowContext(ctx, query, id, jurisdiction))
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, id, api.JurisdictionChange)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Company not found
+		}
+		return nil, translateWriteError(err)
 	}
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows // Company not found
+	return company, nil
+}
+
+// CountByJurisdiction returns the number of live companies in each jurisdiction that has any, ordered by count
+// descending and then by jurisdiction
+func (r *PostgresCompanyRepository) CountByJurisdiction(ctx context.Context) ([]api.JurisdictionCount, error) {
+	query := `
+		SELECT jurisdiction, COUNT(*)
+		FROM companies
+		WHERE deleted_at IS NULL
+		GROUP BY jurisdiction
+		ORDER BY COUNT(*) DESC, jurisdiction`
+
+	rows, err := r.query(ctx, "count_by_jurisdiction", query)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return nil
+	counts := []api.JurisdictionCount{}
+	for rows.Next() {
+		var count api.JurisdictionCount
+		if err := rows.Scan(&count.Jurisdiction, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// CountByNatureOfBusiness returns the number of live companies with each nature of business, only counting those
+// in jurisdiction (case-insensitively) when it is non-nil. Missing and empty values are counted together under
+// nil. Results are ordered by count descending and then by nature of business, nil last.
+func (r *PostgresCompanyRepository) CountByNatureOfBusiness(ctx context.Context, jurisdiction *string) ([]api.NatureOfBusinessCount, error) {
+	query := `
+		SELECT NULLIF(nature_of_business, ''), COUNT(*)
+		FROM companies
+		WHERE deleted_at IS NULL AND ($1::text IS NULL OR LOWER(jurisdiction) = LOWER($1))
+		GROUP BY NULLIF(nature_of_business, '')
+		ORDER BY COUNT(*) DESC, NULLIF(nature_of_business, '') NULLS LAST`
+
+	rows, err := r.query(ctx, "count_by_nature_of_business", query, jurisdiction)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []api.NatureOfBusinessCount{}
+	for rows.Next() {
+		var count api.NatureOfBusinessCount
+		if err := rows.Scan(&count.NatureOfBusiness, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// GetSharedAddressGroups retrieves live companies grouped by normalized address, keeping groups of at least
+// minSize companies, ordered by address and then by creation. Every group is returned, unpaginated.
+func (r *PostgresCompanyRepository) GetSharedAddressGroups(ctx context.Context, minSize int) ([]api.SharedAddressGroup, error) {
+	query := `
+		WITH normalized AS (
+			SELECT ` + companyColumns + `,
+			       LOWER(regexp_replace(TRIM(company_address), '\s+', ' ', 'g')) AS normalized_address
+			FROM companies
+			WHERE deleted_at IS NULL
+		)
+		SELECT ` + companyColumns + `, normalized_address
+		FROM normalized
+		WHERE normalized_address IN (
+			SELECT normalized_address
+			FROM normalized
+			GROUP BY normalized_address
+			HAVING COUNT(*) >= $1
+		)
+		ORDER BY normalized_address, date_created`
+
+	rows, err := r.query(ctx, "shared_addresses", query, minSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []api.SharedAddressGroup{}
+	for rows.Next() {
+		var address string
+		company, err := scanCompany(rows, &address)
+		if err != nil {
+			return nil, err
+		}
+
+		// Rows are ordered by address, so a new address starts a new group
+		if len(groups) == 0 || groups[len(groups)-1].Address != address {
+			groups = append(groups, api.SharedAddressGroup{Address: address, Companies: []api.Company{}})
+		}
+		group := &groups[len(groups)-1]
+		group.Companies = append(group.Companies, *company)
+		group.Count++
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
 }
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"backend/api"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+func TestTranslateWriteError(t *testing.T) {
+	other := errors.New("connection reset")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"duplicate name", &pq.Error{Code: "23505", Constraint: uniqueNameIndex}, ErrDuplicateName},
+		{"duplicate sec_code", &pq.Error{Code: "23505", Constraint: uniqueSecCodeIndex}, ErrDuplicateSecCode},
+		{"other unique index", &pq.Error{Code: "23505", Constraint: "companies_pkey"}, nil},
+		{"other violation on the sec_code index", &pq.Error{Code: "23503", Constraint: uniqueSecCodeIndex}, nil},
+		{"not a Postgres error", other, other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateWriteError(tt.err)
+			want := tt.want
+			if want == nil {
+				want = tt.err // Passed through unchanged
+			}
+			if got != want {
+				t.Errorf("translateWriteError(%v) = %v, want %v", tt.err, got, want)
+			}
+		})
+	}
+}
+
+func TestRecentMinutesCondition(t *testing.T) {
+	repo := NewPostgresCompanyRepository(nil, 0, 0, zap.NewNop())
+
+	query, argCount := repo.DescribeGetAll(20, 0, Filter{Jurisdictions: []string{"UK"}, RecentMinutes: 15}, DefaultSort)
+	if !strings.Contains(query, "date_created >= now() - make_interval(mins => $2)") {
+		t.Errorf("query %q, want recent_minutes bound as the second parameter against the database's now()", query)
+	}
+	if !strings.Contains(query, "LOWER(jurisdiction)") {
+		t.Errorf("query %q, want the jurisdiction filter kept alongside", query)
+	}
+	withoutRecent, withoutCount := repo.DescribeGetAll(20, 0, Filter{Jurisdictions: []string{"UK"}}, DefaultSort)
+	if strings.Contains(withoutRecent, "make_interval") || argCount != withoutCount+1 {
+		t.Errorf("without recent_minutes: query %q with %d arguments, want no interval and one argument fewer than %d", withoutRecent, withoutCount, argCount)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestOmittedOptionalFieldsBindAsNull(t *testing.T) {
+	optional := []string{"nature_of_business", "number_of_directors", "number_of_shareholders", "sec_code", "registry_source", "registry_number"}
+	for _, column := range optional {
+		if !strings.Contains(insertCompanyQuery, column) {
+			t.Errorf("insert does not name %s, so its column default would apply", column)
+		}
+	}
+
+	omitted := insertCompanyArgs(api.CreateCompanyRequest{CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK"})
+	given := insertCompanyArgs(api.CreateCompanyRequest{
+		CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK",
+		NatureOfBusiness: ptr(""), NumberOfDirectors: ptr(0), NumberOfShareholders: ptr(0),
+		SecCode: ptr(""), RegistrySource: ptr(""), RegistryNumber: ptr(""),
+	})
+	// Arguments $4 to $9 are the optional fields; zero values are bound as given, omitted ones as NULL
+	for i, column := range optional {
+		value, err := driver.DefaultParameterConverter.ConvertValue(omitted[3+i])
+		if err != nil || value != nil {
+			t.Errorf("omitted %s binds as %#v (%v), want NULL", column, value, err)
+		}
+		if value, err := driver.DefaultParameterConverter.ConvertValue(given[3+i]); err != nil || value == nil {
+			t.Errorf("zero %s binds as %#v (%v), want the zero value", column, value, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"backend/api"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// ErrCountOutOfRange is returned when adjusting a company count would take it outside its bounds
+var ErrCountOutOfRange = errors.New("company count would leave its allowed range")
+
+// CountColumn names a company count column AdjustCount can change
+type CountColumn string
+
+const (
+	DirectorCount    CountColumn = "number_of_directors"
+	ShareholderCount CountColumn = "number_of_shareholders"
+)
+
+// CountBounds is the inclusive range a company count must stay within, with per-jurisdiction overrides
+type CountBounds struct {
+	Min               int
+	Max               int
+	MinByJurisdiction map[string]int
+	MaxByJurisdiction map[string]int
+}
+
+// Limits returns the inclusive minimum and maximum that apply in jurisdiction
+func (b CountBounds) Limits(jurisdiction string) (int, int) {
+	lower, upper := b.Min, b.Max
+	if minimum, ok := b.MinByJurisdiction[jurisdiction]; ok && minimum > lower {
+		lower = minimum
+	}
+	if maximum, ok := b.MaxByJurisdiction[jurisdiction]; ok {
+		upper = maximum
+	}
+	return lower, upper
+}
+
+// SyncedCountError is returned when a write would set a count column away from the number of records the company
+// has for it. Once a company has director or shareholder records, number_of_directors or number_of_shareholders
+// is kept equal to their number.
+type SyncedCountError struct {
+	Column  CountColumn
+	Records int
+}
+
+func (e *SyncedCountError) Error() string {
+	return fmt.Sprintf("%s is kept equal to the company's %d records", e.Column, e.Records)
+}
+
+// MemberCounts is how many director and shareholder records a company has
+type MemberCounts struct {
+	Directors    int
+	Shareholders int
+}
+
+// memberCountsQuery counts a company's director and shareholder records
+const memberCountsQuery = `
+		SELECT (SELECT COUNT(*) FROM directors WHERE company_id = $1),
+		       (SELECT COUNT(*) FROM shareholders WHERE company_id = $1)`
+
+// CountMembers returns how many director and shareholder records a company has
+func (r *PostgresCompanyRepository) CountMembers(ctx context.Context, id openapi_types.UUID) (MemberCounts, error) {
+	var counts MemberCounts
+	err := r.retry(ctx, "count_members", func() error {
+		return r.db.QueryRowContext(ctx, memberCountsQuery, id).Scan(&counts.Directors, &counts.Shareholders)
+	})
+	return counts, err
+}
+
+// checkSyncedCounts returns a *SyncedCountError if the company, as written so far in the transaction, has a count
+// that differs from its number of records of that kind while it has any
+func checkSyncedCounts(ctx context.Context, tx *sql.Tx, company *api.Company) error {
+	var counts MemberCounts
+	if err := tx.QueryRowContext(ctx, memberCountsQuery, company.Id).Scan(&counts.Directors, &counts.Shareholders); err != nil {
+		return err
+	}
+	return counts.Check(company)
+}
+
+// Check returns a *SyncedCountError if the company has a count that differs from its number of records of that
+// kind while it has any
+func (c MemberCounts) Check(company *api.Company) error {
+	if c.Directors > 0 && (company.NumberOfDirectors == nil || *company.NumberOfDirectors != c.Directors) {
+		return &SyncedCountError{Column: DirectorCount, Records: c.Directors}
+	}
+	if c.Shareholders > 0 && (company.NumberOfShareholders == nil || *company.NumberOfShareholders != c.Shareholders) {
+		return &SyncedCountError{Column: ShareholderCount, Records: c.Shareholders}
+	}
+	return nil
+}
+
+// AdjustCount adds delta to a live company's count column in a single UPDATE, so concurrent adjustments never
+// lose each other's change; a NULL count counts as 0. It returns nil if no live company has the ID,
+// ErrCountOutOfRange, leaving the company unchanged, if the new count would fall outside bounds for the
+// company's jurisdiction, and a *SyncedCountError if records keep the count.
+func (r *PostgresCompanyRepository) AdjustCount(ctx context.Context, id openapi_types.UUID, column CountColumn, delta int, bounds CountBounds) (*api.Company, error) {
+	if column != DirectorCount && column != ShareholderCount {
+		return nil, errors.New("unknown count column " + string(column))
+	}
+
+	query := `
+		UPDATE companies
+		SET ` + string(column) + ` = COALESCE(` + string(column) + `, 0) + $2, date_updated = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING ` + companyColumns
+
+	var company *api.Company
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		company, err = scanCompany(tx.QueryRowContext(ctx, query, id, delta))
+		if err != nil {
+			return err
+		}
+
+		count := company.NumberOfDirectors
+		if column == ShareholderCount {
+			count = company.NumberOfShareholders
+		}
+		lower, upper := bounds.Limits(company.Jurisdiction)
+		if *count < lower || *count > upper {
+			return ErrCountOutOfRange // Rolls the update back
+		}
+		if err := checkSyncedCounts(ctx, tx, company); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, id, api.Update)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No live company with this ID
+		}
+		return nil, err
+	}
+
+	return company, nil
+}
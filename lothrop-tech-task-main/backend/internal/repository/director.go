@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"backend/api"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// ErrDirectorLimit is returned when adding a director would take a company past the allowed number of directors
+var ErrDirectorLimit = errors.New("company has the maximum number of directors")
+
+// ErrDirectorMinimum is returned when removing a director would leave a company fewer than the allowed number of
+// directors
+var ErrDirectorMinimum = errors.New("company has the minimum number of directors")
+
+// directorColumns is the standard director column list, in the order scanDirector expects
+const directorColumns = `id, company_id, name, role, date_created`
+
+// scanDirector scans a row of directorColumns
+func scanDirector(row rowScanner) (*api.Director, error) {
+	var director api.Director
+	if err := row.Scan(&director.Id, &director.CompanyId, &director.Name, &director.Role, &director.DateCreated); err != nil {
+		return nil, err
+	}
+	director.DateCreated = director.DateCreated.UTC() // See scanCompany
+	return &director, nil
+}
+
+// syncDirectorCountQuery sets a company's number_of_directors to its number of director records
+const syncDirectorCountQuery = `
+		UPDATE companies
+		SET number_of_directors = (SELECT COUNT(*) FROM directors WHERE company_id = $1),
+		    date_updated = CURRENT_TIMESTAMP
+		WHERE id = $1`
+
+// lockLiveCompany locks a live company row for the rest of the transaction and returns its jurisdiction, or
+// sql.ErrNoRows if there is none
+func lockLiveCompany(ctx context.Context, tx *sql.Tx, companyID openapi_types.UUID) (string, error) {
+	var jurisdiction string
+	err := tx.QueryRowContext(ctx,
+		"SELECT jurisdiction FROM companies WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", companyID).Scan(&jurisdiction)
+	return jurisdiction, err
+}
+
+// ListDirectors returns a live company's directors, oldest first, or nil if no live company has the ID
+func (r *PostgresCompanyRepository) ListDirectors(ctx context.Context, companyID openapi_types.UUID) ([]api.Director, error) {
+	var exists bool
+	err := r.retry(ctx, "list_directors", func() error {
+		return r.db.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM companies WHERE id = $1 AND deleted_at IS NULL)", companyID).Scan(&exists)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil // No live company with this ID
+	}
+
+	query := `
+		SELECT ` + directorColumns + `
+		FROM directors
+		WHERE company_id = $1
+		ORDER BY date_created, id`
+
+	rows, err := r.query(ctx, "list_directors", query, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	directors := []api.Director{}
+	for rows.Next() {
+		director, err := scanDirector(rows)
+		if err != nil {
+			return nil, err
+		}
+		directors = append(directors, *director)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return directors, nil
+}
+
+// AddDirector adds a director to a live company and syncs its number_of_directors in one transaction. It returns
+// nil if no live company has the ID, and ErrDirectorLimit if the company already has the maximum bounds allow in
+// its jurisdiction.
+func (r *PostgresCompanyRepository) AddDirector(ctx context.Context, companyID openapi_types.UUID, req api.CreateDirectorRequest, bounds CountBounds) (*api.Director, error) {
+	var director *api.Director
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		jurisdiction, err := lockLiveCompany(ctx, tx, companyID)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM directors WHERE company_id = $1", companyID).Scan(&count); err != nil {
+			return err
+		}
+		if _, upper := bounds.Limits(jurisdiction); count >= upper {
+			return ErrDirectorLimit
+		}
+
+		query := `
+		INSERT INTO directors (company_id, name, role)
+		VALUES ($1, $2, $3)
+		RETURNING ` + directorColumns
+
+		director, err = scanDirector(tx.QueryRowContext(ctx, query, companyID, req.Name, req.Role))
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, syncDirectorCountQuery, companyID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, companyID, api.AddDirector)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No live company with this ID
+		}
+		return nil, err
+	}
+
+	return director, nil
+}
+
+// RemoveDirector removes a live company's director and syncs its number_of_directors in one transaction,
+// returning sql.ErrNoRows if the company is not live or has no director with the ID, and ErrDirectorMinimum if
+// the company would be left with fewer directors than bounds allow in its jurisdiction
+func (r *PostgresCompanyRepository) RemoveDirector(ctx context.Context, companyID, directorID openapi_types.UUID, bounds CountBounds) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		jurisdiction, err := lockLiveCompany(ctx, tx, companyID)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM directors WHERE id = $1 AND company_id = $2", directorID, companyID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return sql.ErrNoRows // Director not found
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM directors WHERE company_id = $1", companyID).Scan(&count); err != nil {
+			return err
+		}
+		if lower, _ := bounds.Limits(jurisdiction); count < lower {
+			return ErrDirectorMinimum // Rolls the delete back
+		}
+
+		if _, err := tx.ExecContext(ctx, syncDirectorCountQuery, companyID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, companyID, api.RemoveDirector)
+	})
+}
@@ -0,0 +1,6 @@
+package repository
+
+import "strings"
+
+// CompanyColumns lists the columns a company query selects, in order, for tests standing in for its rows
+var CompanyColumns = strings.Split(strings.Join(strings.Fields(companyColumns), ""), ",")
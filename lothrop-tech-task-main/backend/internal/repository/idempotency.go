@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"backend/api"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// IdempotencyRecord is an unexpired idempotency key: the fingerprint of the request first sent with it and the
+// company that request created
+type IdempotencyRecord struct {
+	RequestHash string
+	Company     *api.Company
+}
+
+// queryRower runs single-row queries on a database or within a transaction
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// getIdempotencyRecord returns the record for key if it is younger than ttl and its company still exists, or nil.
+// Soft-deleted companies are returned, so a retry still gets the original response.
+func getIdempotencyRecord(ctx context.Context, q queryRower, key string, ttl time.Duration) (*IdempotencyRecord, error) {
+	var record IdempotencyRecord
+	var companyID openapi_types.UUID
+	err := q.QueryRowContext(ctx, `
+		SELECT request_hash, company_id
+		FROM idempotency_keys
+		WHERE key = $1 AND company_id IS NOT NULL AND date_created > now() - make_interval(secs => $2)`,
+		key, ttl.Seconds()).Scan(&record.RequestHash, &companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No unexpired key
+		}
+		return nil, err
+	}
+
+	record.Company, err = scanCompany(q.QueryRowContext(ctx, "SELECT "+companyColumns+" FROM companies WHERE id = $1", companyID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// GetIdempotencyRecord returns the record for key if it is younger than ttl, or nil
+func (r *PostgresCompanyRepository) GetIdempotencyRecord(ctx context.Context, key string, ttl time.Duration) (*IdempotencyRecord, error) {
+	var record *IdempotencyRecord
+	err := r.retry(ctx, "get_idempotency_key", func() error {
+		var err error
+		record, err = getIdempotencyRecord(ctx, r.db, key, ttl)
+		return err
+	})
+	return record, err
+}
+
+// CreateIdempotent creates a company and records it under key in one transaction, first expiring keys older
+// than ttl. When a concurrent request recorded the key first, nothing is created and that request's record is
+// returned instead of a company.
+func (r *PostgresCompanyRepository) CreateIdempotent(ctx context.Context, key, requestHash string, ttl time.Duration, req api.CreateCompanyRequest) (*api.Company, *IdempotencyRecord, error) {
+	var company *api.Company
+	var existing *IdempotencyRecord
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		company, existing = nil, nil // A retried transaction starts over
+
+		_, err := tx.ExecContext(ctx,
+			"DELETE FROM idempotency_keys WHERE date_created <= now() - make_interval(secs => $1)", ttl.Seconds())
+		if err != nil {
+			return err
+		}
+
+		// Claiming the key first makes a concurrent request with the same key wait for this transaction
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO idempotency_keys (key, request_hash)
+			VALUES ($1, $2)
+			ON CONFLICT (key) DO NOTHING`, key, requestHash)
+		if err != nil {
+			return err
+		}
+
+		claimed, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if claimed == 0 {
+			existing, err = getIdempotencyRecord(ctx, tx, key, ttl)
+			return err
+		}
+
+		company, err = scanCompany(tx.QueryRowContext(ctx, insertCompanyQuery, insertCompanyArgs(req)...))
+		if err != nil {
+			return err
+		}
+
+		if err := recordAudit(ctx, tx, company.Id, api.Create); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE idempotency_keys SET company_id = $2 WHERE key = $1", key, company.Id)
+		return err
+	})
+	if err != nil {
+		return nil, nil, translateWriteError(err)
+	}
+
+	return company, existing, nil
+}
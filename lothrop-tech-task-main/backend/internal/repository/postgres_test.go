@@ -0,0 +1,243 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/repository"
+	"backend/internal/repository/repositorytest"
+
+	"github.com/lib/pq"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"go.uber.org/zap"
+)
+
+// answer returns a responder answering every query with the given columns and rows
+func answer(columns []string, rows ...[]driver.Value) repositorytest.Responder {
+	return func(string, []driver.NamedValue) (*repositorytest.Rows, error) {
+		return &repositorytest.Rows{Columns: columns, Values: rows}, nil
+	}
+}
+
+func TestGetAllStopsWhenContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Company rows without end, standing in for a slow list query, cancelled part way through
+	var read int
+	db, _ := repositorytest.OpenDB(t, func(string, []driver.NamedValue) (*repositorytest.Rows, error) {
+		return &repositorytest.Rows{Columns: repository.CompanyColumns, Next: func(n int) ([]driver.Value, error) {
+			read = n
+			if n == 3 {
+				cancel()
+			}
+			now := time.Now()
+			return []driver.Value{"123e4567-e89b-12d3-a456-426614174000", "UK", "Example Corp Ltd", "1 High Street",
+				nil, nil, nil, nil, nil, nil, now, now, nil}, nil
+		}}, nil
+	})
+
+	repo := repository.NewPostgresCompanyRepository(db, 1, 0, zap.NewNop())
+
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := repo.GetAll(ctx, 1<<30, 0, repository.Filter{}, repository.DefaultSort, false)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+		// The row fetched as the context was cancelled is the last one scanned
+		if read > 4 {
+			t.Errorf("read %d rows, want scanning to stop at the first row after cancellation", read)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetAll kept scanning after the context was cancelled")
+	}
+}
+
+func TestReadsRetryTransientErrors(t *testing.T) {
+	id := openapi_types.UUID{}
+	reads := map[string]func(r repository.CompanyRepository) error{
+		"GetRawByID": func(r repository.CompanyRepository) error {
+			_, err := r.GetRawByID(context.Background(), id)
+			return err
+		},
+		"GetSharedAddressGroups": func(r repository.CompanyRepository) error {
+			_, err := r.GetSharedAddressGroups(context.Background(), 2)
+			return err
+		},
+		"ExplainGetAll": func(r repository.CompanyRepository) error {
+			_, err := r.ExplainGetAll(context.Background(), 20, 0, repository.Filter{}, repository.DefaultSort)
+			return err
+		},
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			// The first query fails with a serialization failure, which retry treats as transient
+			failed := false
+			db, flaky := repositorytest.OpenDB(t, func(string, []driver.NamedValue) (*repositorytest.Rows, error) {
+				if !failed {
+					failed = true
+					return nil, &pq.Error{Code: "40001"}
+				}
+				return &repositorytest.Rows{}, nil
+			})
+			db.SetMaxOpenConns(1)
+
+			if err := read(repository.NewPostgresCompanyRepository(db, 3, 0, zap.NewNop())); err != nil {
+				t.Fatalf("err = %v, want the transient failure retried", err)
+			}
+			if queries := flaky.Queries(); queries != 2 {
+				t.Errorf("ran %d queries, want a failed attempt and a retry", queries)
+			}
+		})
+	}
+}
+
+func TestGetSharedAddressGroups(t *testing.T) {
+	// Rows as the query orders them: by normalized address, then by creation
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	row := func(id, name, address, normalized string, minutes int) []driver.Value {
+		at := created.Add(time.Duration(minutes) * time.Minute)
+		return []driver.Value{id, "UK", name, address, nil, nil, nil, nil, nil, nil, at, at, nil, normalized}
+	}
+	columns := append(append([]string{}, repository.CompanyColumns...), "normalized_address")
+	db, _ := repositorytest.OpenDB(t, answer(columns,
+		row("00000000-0000-0000-0000-000000000001", "Acme Ltd", "1 High Street", "1 high street", 0),
+		row("00000000-0000-0000-0000-000000000002", "Acme Trading Ltd", " 1  HIGH street", "1 high street", 1),
+		row("00000000-0000-0000-0000-000000000003", "Beta Ltd", "2 Low Road", "2 low road", 2),
+		row("00000000-0000-0000-0000-000000000004", "Beta Holdings Ltd", "2 low road", "2 low road", 3),
+		row("00000000-0000-0000-0000-000000000005", "Beta Trading Ltd", "2 LOW ROAD", "2 low road", 4),
+	))
+
+	groups, err := repository.NewPostgresCompanyRepository(db, 0, 0, zap.NewNop()).GetSharedAddressGroups(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		address   string
+		companies []string
+	}{
+		{"1 high street", []string{"Acme Ltd", "Acme Trading Ltd"}},
+		{"2 low road", []string{"Beta Ltd", "Beta Holdings Ltd", "Beta Trading Ltd"}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for i, group := range groups {
+		if group.Address != want[i].address || group.Count != len(want[i].companies) {
+			t.Errorf("group %d = %q with count %d, want %q with %d", i, group.Address, group.Count, want[i].address, len(want[i].companies))
+		}
+		var names []string
+		for _, company := range group.Companies {
+			names = append(names, company.CompanyName)
+		}
+		if strings.Join(names, ", ") != strings.Join(want[i].companies, ", ") {
+			t.Errorf("group %q companies = %v, want %v", group.Address, names, want[i].companies)
+		}
+	}
+}
+
+func TestExplainGetAll(t *testing.T) {
+	db, explain := repositorytest.OpenDB(t, answer([]string{"QUERY PLAN"},
+		[]driver.Value{"Limit  (cost=0.15..8.17 rows=1 width=200) (actual time=0.010..0.011 rows=0 loops=1)"},
+		[]driver.Value{"  ->  Index Scan using idx_companies_date_created on companies"},
+		[]driver.Value{"Planning Time: 0.100 ms"},
+	))
+	repo := repository.NewPostgresCompanyRepository(db, 0, 0, zap.NewNop())
+
+	search := "acme"
+	filter := repository.Filter{Jurisdictions: []string{"UK"}, Search: &search}
+	query, argCount := repo.DescribeGetAll(20, 40, filter, repository.DefaultSort)
+	if strings.Contains(query, "acme") || strings.Contains(query, "'UK'") {
+		t.Errorf("described query %q inlines filter values", query)
+	}
+
+	plan, err := repo.ExplainGetAll(context.Background(), 20, 40, filter, repository.DefaultSort)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	explained, args := explain.LastQuery()
+	if explained != "EXPLAIN (ANALYZE, BUFFERS) "+query {
+		t.Errorf("explained %q, want the described query %q under EXPLAIN (ANALYZE, BUFFERS)", explained, query)
+	}
+	if args != argCount {
+		t.Errorf("explained with %d arguments, want the described %d", args, argCount)
+	}
+	want := "Limit  (cost=0.15..8.17 rows=1 width=200) (actual time=0.010..0.011 rows=0 loops=1)\n" +
+		"  ->  Index Scan using idx_companies_date_created on companies\nPlanning Time: 0.100 ms"
+	if plan != want {
+		t.Errorf("plan = %q, want the plan lines joined by newlines", plan)
+	}
+}
+
+func TestScanCompanyReturnsUTC(t *testing.T) {
+	// lib/pq returns timestamptz values in the session time zone, here Singapore's
+	singapore := time.FixedZone("+08", 8*60*60)
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, singapore)
+	updated := time.Date(2024, 1, 2, 8, 30, 0, 123456000, singapore)
+	deleted := time.Date(2024, 1, 3, 7, 0, 0, 0, singapore)
+	db, _ := repositorytest.OpenDB(t, answer(repository.CompanyColumns,
+		[]driver.Value{"00000000-0000-0000-0000-000000000001", "Singapore", "Acme Pte", "1 Orchard Road", nil, nil, nil, nil, nil, nil, created, updated, deleted},
+	))
+
+	companies, err := repository.NewPostgresCompanyRepository(db, 0, 0, zap.NewNop()).GetChangedSince(context.Background(), nil, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(companies) != 1 {
+		t.Fatalf("got %d companies, want 1", len(companies))
+	}
+	company := companies[0]
+
+	for name, got := range map[string]time.Time{"date_created": company.DateCreated, "date_updated": company.DateUpdated, "deleted_at": *company.DeletedAt} {
+		if got.Location() != time.UTC {
+			t.Errorf("%s location = %s, want UTC", name, got.Location())
+		}
+	}
+	if !company.DateCreated.Equal(created) || !company.DateUpdated.Equal(updated) || !company.DeletedAt.Equal(deleted) {
+		t.Errorf("timestamps = %s, %s, %s, want the same instants as scanned", company.DateCreated, company.DateUpdated, company.DeletedAt)
+	}
+
+	body, err := json.Marshal(company)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"date_created":"2024-01-01T04:00:00Z"`,
+		`"date_updated":"2024-01-02T00:30:00.123456Z"`,
+		`"deleted_at":"2024-01-02T23:00:00Z"`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("JSON %s, want it to contain %s", body, want)
+		}
+	}
+}
+
+func TestScanCompanyReadsNullsAsNil(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	db, _ := repositorytest.OpenDB(t, answer(repository.CompanyColumns,
+		[]driver.Value{"00000000-0000-0000-0000-000000000001", "UK", "Acme Ltd", "1 High Street", nil, nil, nil, nil, nil, nil, at, at, nil},
+	))
+
+	company, err := repository.NewPostgresCompanyRepository(db, 0, 0, zap.NewNop()).GetByID(context.Background(), openapi_types.UUID{0x01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if company.NatureOfBusiness != nil || company.NumberOfDirectors != nil || company.NumberOfShareholders != nil ||
+		company.SecCode != nil || company.RegistrySource != nil || company.RegistryNumber != nil || company.DeletedAt != nil {
+		t.Errorf("company = %+v, want every NULL column read as nil", company)
+	}
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+)
+
+// queryBuilder assembles a SQL statement together with its bind parameters. Values only ever reach the
+// database through bind, which numbers each placeholder from the parameters added so far, so no caller counts
+// placeholders by hand and no value, however many quotes, semicolons or "--" it contains, becomes SQL text.
+// Identifiers and keywords written with write must come from code, never from input.
+type queryBuilder struct {
+	sql  strings.Builder
+	args []interface{}
+}
+
+// write appends trusted SQL text
+func (b *queryBuilder) write(parts ...string) {
+	for _, part := range parts {
+		b.sql.WriteString(part)
+	}
+}
+
+// bind adds a parameter and returns its placeholder, e.g. "$3", for use in text passed to write
+func (b *queryBuilder) bind(value interface{}) string {
+	b.args = append(b.args, value)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// where appends " WHERE " and the conditions joined by AND, or nothing when there are none
+func (b *queryBuilder) where(conditions []string) {
+	if len(conditions) > 0 {
+		b.write(" WHERE ", strings.Join(conditions, " AND "))
+	}
+}
+
+// build returns the statement and its parameters, in placeholder order
+func (b *queryBuilder) build() (string, []interface{}) {
+	return b.sql.String(), b.args
+}
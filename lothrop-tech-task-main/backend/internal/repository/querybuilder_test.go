@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(b *queryBuilder)
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:    "no parameters",
+			build:   func(b *queryBuilder) { b.write("SELECT 1") },
+			wantSQL: "SELECT 1",
+		},
+		{
+			name: "placeholders number in bind order",
+			build: func(b *queryBuilder) {
+				b.write("SELECT * FROM companies WHERE a = ", b.bind("x"), " AND b = ", b.bind(2))
+				b.write(" LIMIT ", b.bind(10))
+			},
+			wantSQL:  "SELECT * FROM companies WHERE a = $1 AND b = $2 LIMIT $3",
+			wantArgs: []interface{}{"x", 2, 10},
+		},
+		{
+			name: "where joins conditions with AND",
+			build: func(b *queryBuilder) {
+				b.write("SELECT * FROM companies")
+				b.where([]string{"deleted_at IS NULL", "jurisdiction = " + b.bind("UK")})
+			},
+			wantSQL:  "SELECT * FROM companies WHERE deleted_at IS NULL AND jurisdiction = $1",
+			wantArgs: []interface{}{"UK"},
+		},
+		{
+			name: "where without conditions writes nothing",
+			build: func(b *queryBuilder) {
+				b.write("SELECT * FROM companies")
+				b.where(nil)
+			},
+			wantSQL: "SELECT * FROM companies",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b queryBuilder
+			tt.build(&b)
+			sql, args := b.build()
+			if sql != tt.wantSQL {
+				t.Errorf("sql = %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestListQueryKeepsValuesOutOfSQL(t *testing.T) {
+	adversarial := []string{
+		"'; DROP TABLE companies; --",
+		`" OR 1=1 --`,
+		"x' OR '1'='1",
+		"$1; DELETE FROM companies",
+		"/* comment */ UNION SELECT password FROM users",
+	}
+
+	filters := []struct {
+		name   string
+		filter func(value string) Filter
+		sort   Sort
+	}{
+		{"jurisdiction", func(v string) Filter { return Filter{Jurisdictions: []string{v}} }, DefaultSort},
+		{"search", func(v string) Filter { return Filter{Search: &v} }, DefaultSort},
+		{"folded search", func(v string) Filter { return Filter{Search: &v, FoldedSearch: true} }, DefaultSort},
+		{"text query ranked", func(v string) Filter { return Filter{TextQuery: &v} }, Sort{Column: "company_name", Relevance: true}},
+	}
+
+	for _, f := range filters {
+		wantSQL, wantArgs := listQuery("id", 20, 0, f.filter("benign"), f.sort)
+
+		for _, value := range adversarial {
+			t.Run(f.name+"/"+value, func(t *testing.T) {
+				sql, args := listQuery("id", 20, 0, f.filter(value), f.sort)
+
+				if sql != wantSQL {
+					t.Errorf("sql = %q, want it unchanged from %q", sql, wantSQL)
+				}
+				if strings.Contains(sql, value) {
+					t.Errorf("sql %q contains the value %q", sql, value)
+				}
+				if len(args) != len(wantArgs) {
+					t.Fatalf("got %d args, want %d", len(args), len(wantArgs))
+				}
+
+				found := false
+				for _, arg := range args {
+					if arg == value {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("args %#v do not carry the value %q", args, value)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,920 @@
+// Package repositorytest provides an in-memory CompanyRepository for service and handler tests that should not
+// need a real Postgres, and a fake database/sql driver for tests of code written against *sql.DB.
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/api"
+	"backend/internal/repository"
+	"backend/internal/textfold"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// ErrUnsupported is returned by the methods that only make sense against a database: BeginSnapshot and
+// ExplainGetAll
+var ErrUnsupported = errors.New("not supported by the in-memory repository")
+
+// storedCompany is a company as held by CompanyRepository
+type storedCompany struct {
+	api.Company
+}
+
+// public returns a copy of the company that shares no pointers with the stored one
+func (c *storedCompany) public() api.Company {
+	company := c.Company
+	company.NatureOfBusiness = clone(c.NatureOfBusiness)
+	company.NumberOfDirectors = clone(c.NumberOfDirectors)
+	company.NumberOfShareholders = clone(c.NumberOfShareholders)
+	company.SecCode = clone(c.SecCode)
+	company.RegistrySource = clone(c.RegistrySource)
+	company.RegistryNumber = clone(c.RegistryNumber)
+	company.DeletedAt = clone(c.DeletedAt)
+	company.Directors = nil
+	return company
+}
+
+// clone copies the value behind an optional field
+func clone[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	copied := *v
+	return &copied
+}
+
+// idempotencyKey is a recorded idempotent create
+type idempotencyKey struct {
+	requestHash string
+	companyID   openapi_types.UUID
+	created     time.Time
+}
+
+// CompanyRepository is an in-memory repository.CompanyRepository: companies are held in a map keyed by ID and
+// every method runs under one mutex, so each call is atomic like the Postgres repository's transactions.
+// Filtering, sorting, pagination, soft deletes, uniqueness, limits and audit entries follow the Postgres
+// implementation; full-text search is approximated (see textQuery), and snapshots and EXPLAIN are not
+// supported. The zero value is not usable; create one with NewCompanyRepository.
+type CompanyRepository struct {
+	mu           sync.RWMutex
+	companies    map[openapi_types.UUID]*storedCompany
+	directors    map[openapi_types.UUID][]api.Director
+	shareholders map[openapi_types.UUID][]api.Shareholder
+	audit        []api.AuditEntry
+	idempotency  map[string]idempotencyKey
+
+	// Now returns the current time, stamped on writes and compared against by RecentMinutes and idempotency
+	// key expiry. Tests may replace it before use to control timestamps.
+	Now func() time.Time
+}
+
+var _ repository.CompanyRepository = (*CompanyRepository)(nil)
+
+// NewCompanyRepository returns an empty in-memory repository
+func NewCompanyRepository() *CompanyRepository {
+	return &CompanyRepository{
+		companies:    make(map[openapi_types.UUID]*storedCompany),
+		directors:    make(map[openapi_types.UUID][]api.Director),
+		shareholders: make(map[openapi_types.UUID][]api.Shareholder),
+		idempotency:  make(map[string]idempotencyKey),
+		Now:          time.Now,
+	}
+}
+
+// now returns the current time at the microsecond precision Postgres stores, in UTC as scanCompany returns it
+func (r *CompanyRepository) now() time.Time {
+	return r.Now().UTC().Truncate(time.Microsecond)
+}
+
+// selectCompanies returns the companies matching the filter in sort order; r.mu must be held
+func (r *CompanyRepository) selectCompanies(filter repository.Filter, s repository.Sort) []*storedCompany {
+	now := r.now()
+	var selected []*storedCompany
+	for _, c := range r.companies {
+		if matches(c, filter, now) {
+			selected = append(selected, c)
+		}
+	}
+	sortCompanies(selected, filter, s)
+	return selected
+}
+
+// count returns the number of companies matching the filter, ignoring Filter.After; r.mu must be held
+func (r *CompanyRepository) count(filter repository.Filter) int {
+	filter.After = nil
+	now := r.now()
+	total := 0
+	for _, c := range r.companies {
+		if matches(c, filter, now) {
+			total++
+		}
+	}
+	return total
+}
+
+// GetAll retrieves a page of companies and, with includeTotal, the number matching the filter
+func (r *CompanyRepository) GetAll(ctx context.Context, limit, offset int, filter repository.Filter, sort repository.Sort, includeTotal bool) ([]api.Company, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	total := -1
+	if includeTotal {
+		total = r.count(filter)
+	}
+
+	var companies []api.Company
+	for _, c := range page(r.selectCompanies(filter, sort), limit, offset) {
+		companies = append(companies, c.public())
+	}
+	return companies, total, nil
+}
+
+// GetAllIDs retrieves a page of company IDs and, with includeTotal, the number matching the filter
+func (r *CompanyRepository) GetAllIDs(ctx context.Context, limit, offset int, filter repository.Filter, sort repository.Sort, includeTotal bool) ([]openapi_types.UUID, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	total := -1
+	if includeTotal {
+		total = r.count(filter)
+	}
+
+	ids := []openapi_types.UUID{}
+	for _, c := range page(r.selectCompanies(filter, sort), limit, offset) {
+		ids = append(ids, c.Id)
+	}
+	return ids, total, nil
+}
+
+// Count returns the number of companies matching the filter; Filter.After is ignored
+func (r *CompanyRepository) Count(ctx context.Context, filter repository.Filter) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.count(filter), nil
+}
+
+// Checksum returns the number of companies matching the filter and the same checksum of their ids and
+// date_updated values the Postgres repository computes; Filter.After is ignored
+func (r *CompanyRepository) Checksum(ctx context.Context, filter repository.Filter) (int, uint64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	filter.After = nil
+	now := r.now()
+	var total int
+	var sum uint64
+	for _, c := range r.companies {
+		if matches(c, filter, now) {
+			total++
+			sum += rowHash(c) // Wraps modulo 2^64
+		}
+	}
+	return total, sum, nil
+}
+
+// StreamAll calls fn for every company matching the filter in sort order. The matching companies are copied
+// out first, so fn may call back into the repository.
+func (r *CompanyRepository) StreamAll(ctx context.Context, filter repository.Filter, sort repository.Sort, fn func(*api.Company) error) error {
+	r.mu.RLock()
+	companies := sortedCompanies(r.selectCompanies(filter, sort))
+	r.mu.RUnlock()
+
+	for i := range companies {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&companies[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BeginSnapshot returns ErrUnsupported, as snapshots are database transactions
+func (r *CompanyRepository) BeginSnapshot(lifetime time.Duration) (*repository.Snapshot, error) {
+	return nil, fmt.Errorf("snapshots are %w", ErrUnsupported)
+}
+
+// GetChangedSince returns up to limit companies, soft-deleted ones included, changed after the (since, sinceID)
+// position in (date_updated, id) order
+func (r *CompanyRepository) GetChangedSince(ctx context.Context, since *time.Time, sinceID *openapi_types.UUID, limit int) ([]api.Company, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var changed []*storedCompany
+	for _, c := range r.companies {
+		if changedAfter(c, since, sinceID) {
+			changed = append(changed, c)
+		}
+	}
+	sortCompanies(changed, repository.Filter{}, repository.Sort{Column: "date_updated"})
+
+	companies := []api.Company{}
+	for _, c := range page(changed, limit, 0) {
+		companies = append(companies, c.public())
+	}
+	return companies, nil
+}
+
+// GetRawByID returns the company's stored columns keyed by column name, including the derived name columns
+// the Postgres repository keeps, or nil if no company, live or soft-deleted, has the ID
+func (r *CompanyRepository) GetRawByID(ctx context.Context, id openapi_types.UUID) (map[string]interface{}, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.companies[id]
+	if !ok {
+		return nil, nil // Company not found
+	}
+
+	company := c.public()
+	return map[string]interface{}{
+		"id":                     company.Id.String(),
+		"jurisdiction":           company.Jurisdiction,
+		"company_name":           company.CompanyName,
+		"company_address":        company.CompanyAddress,
+		"nature_of_business":     company.NatureOfBusiness,
+		"number_of_directors":    company.NumberOfDirectors,
+		"number_of_shareholders": company.NumberOfShareholders,
+		"sec_code":               company.SecCode,
+		"registry_source":        company.RegistrySource,
+		"registry_number":        company.RegistryNumber,
+		"search_name":            textfold.Fold(company.CompanyName),
+		"name_key":               textfold.NameKey(company.CompanyName),
+		"normalized_name":        textfold.NormalizedName(company.CompanyName),
+		"date_created":           company.DateCreated,
+		"date_updated":           company.DateUpdated,
+		"deleted_at":             company.DeletedAt,
+	}, nil
+}
+
+// DescribeGetAll describes the in-memory scan GetAll performs; there is no SQL and there are no arguments
+func (r *CompanyRepository) DescribeGetAll(limit, offset int, filter repository.Filter, sort repository.Sort) (string, int) {
+	return fmt.Sprintf("in-memory scan: LIMIT %d OFFSET %d", limit, offset), 0
+}
+
+// ExplainGetAll returns ErrUnsupported, as there is no query plan to explain
+func (r *CompanyRepository) ExplainGetAll(ctx context.Context, limit, offset int, filter repository.Filter, sort repository.Sort) (string, error) {
+	return "", fmt.Errorf("explain is %w", ErrUnsupported)
+}
+
+// liveCompany returns the live company with the ID, or nil; r.mu must be held
+func (r *CompanyRepository) liveCompany(id openapi_types.UUID) *storedCompany {
+	c, ok := r.companies[id]
+	if !ok || c.DeletedAt != nil {
+		return nil
+	}
+	return c
+}
+
+// GetByID retrieves a live company by its ID, or nil if there is none
+func (r *CompanyRepository) GetByID(ctx context.Context, id openapi_types.UUID) (*api.Company, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c := r.liveCompany(id)
+	if c == nil {
+		return nil, nil // Company not found
+	}
+	company := c.public()
+	return &company, nil
+}
+
+// GetByRegistry retrieves a live company by its external registry source and number, or nil if there is none
+func (r *CompanyRepository) GetByRegistry(ctx context.Context, source, number string) (*api.Company, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, c := range r.companies {
+		if c.DeletedAt == nil && c.RegistrySource != nil && *c.RegistrySource == source &&
+			c.RegistryNumber != nil && *c.RegistryNumber == number {
+			company := c.public()
+			return &company, nil
+		}
+	}
+	return nil, nil // Company not found
+}
+
+// FindByNormalizedName retrieves the oldest live company in the jurisdiction whose name normalizes like name
+func (r *CompanyRepository) FindByNormalizedName(ctx context.Context, jurisdiction, name string) (*api.Company, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	normalized := textfold.NormalizedName(name)
+	var matched []*storedCompany
+	for _, c := range r.companies {
+		if c.DeletedAt == nil && c.Jurisdiction == jurisdiction && textfold.NormalizedName(c.CompanyName) == normalized {
+			matched = append(matched, c)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, nil // No near-duplicate
+	}
+
+	sortCompanies(matched, repository.Filter{}, repository.Sort{Column: "date_created"})
+	company := matched[0].public()
+	return &company, nil
+}
+
+// checkUniqueName returns repository.ErrDuplicateName if another live company in the jurisdiction shares the
+// name's key, as the unique index on (jurisdiction, name_key) would; r.mu must be held
+func (r *CompanyRepository) checkUniqueName(id openapi_types.UUID, jurisdiction, name string) error {
+	key := textfold.NameKey(name)
+	for _, c := range r.companies {
+		if c.Id != id && c.DeletedAt == nil && c.Jurisdiction == jurisdiction && textfold.NameKey(c.CompanyName) == key {
+			return repository.ErrDuplicateName
+		}
+	}
+	return nil
+}
+
+// secCodeKey is the canonical form sec_codes are compared by for uniqueness, empty for a NULL or blank code
+func secCodeKey(code *string) string {
+	if code == nil {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(*code))
+}
+
+// checkUniqueSecCode returns repository.ErrDuplicateSecCode if another live company has the same non-blank
+// canonical sec_code, as the partial unique index on it would; r.mu must be held
+func (r *CompanyRepository) checkUniqueSecCode(id openapi_types.UUID, code *string) error {
+	key := secCodeKey(code)
+	if key == "" {
+		return nil
+	}
+	for _, c := range r.companies {
+		if c.Id != id && c.DeletedAt == nil && secCodeKey(c.SecCode) == key {
+			return repository.ErrDuplicateSecCode
+		}
+	}
+	return nil
+}
+
+// newCompany builds a company from a create request, checked against the stored companies and the ones
+// already pending in the same batch; r.mu must be held
+func (r *CompanyRepository) newCompany(req api.CreateCompanyRequest, pending []*storedCompany, now time.Time) (*storedCompany, error) {
+	if err := r.checkUniqueName(uuid.Nil, req.Jurisdiction, req.CompanyName); err != nil {
+		return nil, err
+	}
+	if err := r.checkUniqueSecCode(uuid.Nil, req.SecCode); err != nil {
+		return nil, err
+	}
+	key, secCode := textfold.NameKey(req.CompanyName), secCodeKey(req.SecCode)
+	for _, c := range pending {
+		if c.Jurisdiction == req.Jurisdiction && textfold.NameKey(c.CompanyName) == key {
+			return nil, repository.ErrDuplicateName
+		}
+		if secCode != "" && secCodeKey(c.SecCode) == secCode {
+			return nil, repository.ErrDuplicateSecCode
+		}
+	}
+
+	c := &storedCompany{Company: api.Company{
+		Id:                   uuid.New(),
+		Jurisdiction:         req.Jurisdiction,
+		CompanyName:          req.CompanyName,
+		CompanyAddress:       req.CompanyAddress,
+		NatureOfBusiness:     clone(req.NatureOfBusiness),
+		NumberOfDirectors:    clone(req.NumberOfDirectors),
+		NumberOfShareholders: clone(req.NumberOfShareholders),
+		SecCode:              clone(req.SecCode),
+		RegistrySource:       clone(req.RegistrySource),
+		RegistryNumber:       clone(req.RegistryNumber),
+		DateCreated:          now,
+		DateUpdated:          now,
+	}}
+	return c, nil
+}
+
+// recordAudit appends an audit entry for a company, attributed to the context's actor; r.mu must be held
+func (r *CompanyRepository) recordAudit(ctx context.Context, companyID openapi_types.UUID, action api.AuditEntryAction, now time.Time) {
+	entry := api.AuditEntry{
+		Id:          int64(len(r.audit) + 1),
+		CompanyId:   companyID,
+		Action:      action,
+		DateCreated: now,
+	}
+	entry.Actor = repository.ActorFromContext(ctx)
+	r.audit = append(r.audit, entry)
+}
+
+// Create creates a new company and returns it with its generated ID and timestamps
+func (r *CompanyRepository) Create(ctx context.Context, req api.CreateCompanyRequest) (*api.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	c, err := r.newCompany(req, nil, now)
+	if err != nil {
+		return nil, err
+	}
+	r.companies[c.Id] = c
+	r.recordAudit(ctx, c.Id, api.Create, now)
+
+	company := c.public()
+	return &company, nil
+}
+
+// idempotencyRecord returns the record for key if it is younger than ttl and its company still exists, or nil;
+// r.mu must be held
+func (r *CompanyRepository) idempotencyRecord(key string, ttl time.Duration, now time.Time) *repository.IdempotencyRecord {
+	recorded, ok := r.idempotency[key]
+	if !ok || !recorded.created.After(now.Add(-ttl)) {
+		return nil // No unexpired key
+	}
+	c, ok := r.companies[recorded.companyID]
+	if !ok {
+		return nil
+	}
+	company := c.public()
+	return &repository.IdempotencyRecord{RequestHash: recorded.requestHash, Company: &company}
+}
+
+// GetIdempotencyRecord returns the record for key if it is younger than ttl, or nil
+func (r *CompanyRepository) GetIdempotencyRecord(ctx context.Context, key string, ttl time.Duration) (*repository.IdempotencyRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.idempotencyRecord(key, ttl, r.now()), nil
+}
+
+// CreateIdempotent creates a company and records it under key, first expiring keys older than ttl. If the key
+// is already recorded it creates nothing and returns the existing record instead.
+func (r *CompanyRepository) CreateIdempotent(ctx context.Context, key, requestHash string, ttl time.Duration, req api.CreateCompanyRequest) (*api.Company, *repository.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for k, recorded := range r.idempotency {
+		if !recorded.created.After(now.Add(-ttl)) {
+			delete(r.idempotency, k)
+		}
+	}
+
+	if _, claimed := r.idempotency[key]; claimed {
+		return nil, r.idempotencyRecord(key, ttl, now), nil
+	}
+
+	c, err := r.newCompany(req, nil, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.companies[c.Id] = c
+	r.recordAudit(ctx, c.Id, api.Create, now)
+	r.idempotency[key] = idempotencyKey{requestHash: requestHash, companyID: c.Id, created: now}
+
+	company := c.public()
+	return &company, nil, nil
+}
+
+// CreateMany creates all companies at once; if any would fail none are created
+func (r *CompanyRepository) CreateMany(ctx context.Context, reqs []api.CreateCompanyRequest) ([]api.Company, error) {
+	return r.createMany(ctx, reqs, false)
+}
+
+// PreviewCreateMany checks and builds the companies CreateMany would create without storing them
+func (r *CompanyRepository) PreviewCreateMany(ctx context.Context, reqs []api.CreateCompanyRequest) ([]api.Company, error) {
+	return r.createMany(ctx, reqs, true)
+}
+
+// createMany builds every company before storing any, so a failure leaves the repository unchanged
+func (r *CompanyRepository) createMany(ctx context.Context, reqs []api.CreateCompanyRequest, preview bool) ([]api.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	pending := make([]*storedCompany, 0, len(reqs))
+	for _, req := range reqs {
+		c, err := r.newCompany(req, pending, now)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, c)
+	}
+
+	companies := make([]api.Company, 0, len(pending))
+	for _, c := range pending {
+		if !preview {
+			r.companies[c.Id] = c
+			r.recordAudit(ctx, c.Id, api.Create, now)
+		}
+		companies = append(companies, c.public())
+	}
+	return companies, nil
+}
+
+// liveForWrite returns the live company a conditional write applies to: nil if there is none, and
+// repository.ErrStaleVersion if expected is set and does not match its date_updated; r.mu must be held
+func (r *CompanyRepository) liveForWrite(id openapi_types.UUID, expected *time.Time) (*storedCompany, error) {
+	c := r.liveCompany(id)
+	if c == nil {
+		return nil, nil // Company not found
+	}
+	if expected != nil && !c.DateUpdated.Equal(*expected) {
+		return nil, repository.ErrStaleVersion
+	}
+	return c, nil
+}
+
+// Update replaces a company's fields and returns the updated company, or nil if it does not exist
+func (r *CompanyRepository) Update(ctx context.Context, id openapi_types.UUID, req api.CreateCompanyRequest, expected *time.Time) (*api.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, err := r.liveForWrite(id, expected)
+	if c == nil || err != nil {
+		return nil, err
+	}
+	if err := r.checkUniqueName(id, req.Jurisdiction, req.CompanyName); err != nil {
+		return nil, err
+	}
+	if err := r.checkUniqueSecCode(id, req.SecCode); err != nil {
+		return nil, err
+	}
+	candidate := c.public()
+	candidate.NumberOfDirectors, candidate.NumberOfShareholders = req.NumberOfDirectors, req.NumberOfShareholders
+	if err := r.members(id).Check(&candidate); err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	c.Jurisdiction = req.Jurisdiction
+	c.CompanyName = req.CompanyName
+	c.CompanyAddress = req.CompanyAddress
+	c.NatureOfBusiness = clone(req.NatureOfBusiness)
+	c.NumberOfDirectors = clone(req.NumberOfDirectors)
+	c.NumberOfShareholders = clone(req.NumberOfShareholders)
+	c.SecCode = clone(req.SecCode)
+	c.RegistrySource = clone(req.RegistrySource)
+	c.RegistryNumber = clone(req.RegistryNumber)
+	c.DateUpdated = now
+	r.recordAudit(ctx, id, api.Update, now)
+
+	company := c.public()
+	return &company, nil
+}
+
+// Patch updates only the non-nil fields of req and returns the updated company, or nil if it does not exist.
+// A patch changing nothing leaves date_updated alone and records no audit entry.
+func (r *CompanyRepository) Patch(ctx context.Context, id openapi_types.UUID, req api.PatchCompanyRequest, expected *time.Time) (*api.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, err := r.liveForWrite(id, expected)
+	if c == nil || err != nil {
+		return nil, err
+	}
+
+	changed := req != (api.PatchCompanyRequest{})
+	if !changed {
+		company := c.public()
+		return &company, nil
+	}
+
+	jurisdiction, name := c.Jurisdiction, c.CompanyName
+	if req.Jurisdiction != nil {
+		jurisdiction = *req.Jurisdiction
+	}
+	if req.CompanyName != nil {
+		name = *req.CompanyName
+	}
+	if err := r.checkUniqueName(id, jurisdiction, name); err != nil {
+		return nil, err
+	}
+	if req.SecCode != nil {
+		if err := r.checkUniqueSecCode(id, req.SecCode); err != nil {
+			return nil, err
+		}
+	}
+	candidate := c.public()
+	if req.NumberOfDirectors != nil {
+		candidate.NumberOfDirectors = req.NumberOfDirectors
+	}
+	if req.NumberOfShareholders != nil {
+		candidate.NumberOfShareholders = req.NumberOfShareholders
+	}
+	if err := r.members(id).Check(&candidate); err != nil {
+		return nil, err
+	}
+
+	c.Jurisdiction = jurisdiction
+	c.CompanyName = name
+	if req.CompanyAddress != nil {
+		c.CompanyAddress = *req.CompanyAddress
+	}
+	if req.NatureOfBusiness != nil {
+		c.NatureOfBusiness = clone(req.NatureOfBusiness)
+	}
+	if req.NumberOfDirectors != nil {
+		c.NumberOfDirectors = clone(req.NumberOfDirectors)
+	}
+	if req.NumberOfShareholders != nil {
+		c.NumberOfShareholders = clone(req.NumberOfShareholders)
+	}
+	if req.SecCode != nil {
+		c.SecCode = clone(req.SecCode)
+	}
+	if req.RegistrySource != nil {
+		c.RegistrySource = clone(req.RegistrySource)
+	}
+	if req.RegistryNumber != nil {
+		c.RegistryNumber = clone(req.RegistryNumber)
+	}
+
+	now := r.now()
+	c.DateUpdated = now
+	r.recordAudit(ctx, id, api.Update, now)
+
+	company := c.public()
+	return &company, nil
+}
+
+// Delete soft-deletes a live company, returning sql.ErrNoRows if there is none
+func (r *CompanyRepository) Delete(ctx context.Context, id openapi_types.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.liveCompany(id)
+	if c == nil {
+		return sql.ErrNoRows // Company not found
+	}
+
+	now := r.now()
+	c.DeletedAt = &now
+	c.DateUpdated = now
+	r.recordAudit(ctx, id, api.Delete, now)
+	return nil
+}
+
+// DeleteMany soft-deletes the live companies among ids and returns the IDs it deleted
+func (r *CompanyRepository) DeleteMany(ctx context.Context, ids []openapi_types.UUID) ([]openapi_types.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	var deleted []openapi_types.UUID
+	for _, id := range ids {
+		c := r.liveCompany(id)
+		if c == nil {
+			continue
+		}
+		deletedAt := now
+		c.DeletedAt = &deletedAt
+		c.DateUpdated = now
+		r.recordAudit(ctx, id, api.Delete, now)
+		deleted = append(deleted, id)
+	}
+	return deleted, nil
+}
+
+// Restore undoes a soft delete and returns the restored company, or nil if no deleted company has the ID
+func (r *CompanyRepository) Restore(ctx context.Context, id openapi_types.UUID) (*api.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.companies[id]
+	if !ok || c.DeletedAt == nil {
+		return nil, nil // No deleted company with this ID
+	}
+	if err := r.checkUniqueName(id, c.Jurisdiction, c.CompanyName); err != nil {
+		return nil, err
+	}
+	if err := r.checkUniqueSecCode(id, c.SecCode); err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	c.DeletedAt = nil
+	c.DateUpdated = now
+	r.recordAudit(ctx, id, api.Restore, now)
+
+	company := c.public()
+	return &company, nil
+}
+
+// PurgeDeleted permanently deletes companies soft-deleted before olderThan, with their directors, shareholders
+// and idempotency keys, and returns how many were deleted. Their audit history is kept.
+func (r *CompanyRepository) PurgeDeleted(ctx context.Context, olderThan time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := make(map[openapi_types.UUID]bool)
+	for id, c := range r.companies {
+		if c.DeletedAt != nil && c.DeletedAt.Before(olderThan) {
+			purged[id] = true
+			delete(r.companies, id)
+			delete(r.directors, id)
+			delete(r.shareholders, id)
+		}
+	}
+
+	for key, recorded := range r.idempotency {
+		if purged[recorded.companyID] {
+			delete(r.idempotency, key)
+		}
+	}
+
+	return int64(len(purged)), nil
+}
+
+// TransferJurisdiction moves a live company to another jurisdiction and returns the updated company, or nil if
+// no live company has the ID
+func (r *CompanyRepository) TransferJurisdiction(ctx context.Context, id openapi_types.UUID, jurisdiction string) (*api.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.liveCompany(id)
+	if c == nil {
+		return nil, nil // Company not found
+	}
+	if err := r.checkUniqueName(id, jurisdiction, c.CompanyName); err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	c.Jurisdiction = jurisdiction
+	c.DateUpdated = now
+	r.recordAudit(ctx, id, api.JurisdictionChange, now)
+
+	company := c.public()
+	return &company, nil
+}
+
+// AdjustCount adds delta to a live company's count and returns the updated company, or nil if no live company
+// has the ID. It returns repository.ErrCountOutOfRange, changing nothing, if the new count would fall outside
+// bounds, and a *repository.SyncedCountError if records keep the count.
+func (r *CompanyRepository) AdjustCount(ctx context.Context, id openapi_types.UUID, column repository.CountColumn, delta int, bounds repository.CountBounds) (*api.Company, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var field **int
+	c := r.liveCompany(id)
+	switch column {
+	case repository.DirectorCount:
+		if c != nil {
+			field = &c.NumberOfDirectors
+		}
+	case repository.ShareholderCount:
+		if c != nil {
+			field = &c.NumberOfShareholders
+		}
+	default:
+		return nil, errors.New("unknown count column " + string(column))
+	}
+	if c == nil {
+		return nil, nil // No live company with this ID
+	}
+
+	count := delta
+	if *field != nil {
+		count += **field
+	}
+	lower, upper := bounds.Limits(c.Jurisdiction)
+	if count < lower || count > upper {
+		return nil, repository.ErrCountOutOfRange
+	}
+	candidate := c.public()
+	if column == repository.DirectorCount {
+		candidate.NumberOfDirectors = &count
+	} else {
+		candidate.NumberOfShareholders = &count
+	}
+	if err := r.members(id).Check(&candidate); err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	*field = &count
+	c.DateUpdated = now
+	r.recordAudit(ctx, id, api.Update, now)
+
+	company := c.public()
+	return &company, nil
+}
+
+// GetHistory returns a company's audit entries, oldest first, or nil if no company, live, soft-deleted or
+// purged, has the ID
+func (r *CompanyRepository) GetHistory(ctx context.Context, companyID openapi_types.UUID) ([]api.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []api.AuditEntry{}
+	for _, entry := range r.audit {
+		if entry.CompanyId == companyID {
+			entry.Actor = clone(entry.Actor)
+			entries = append(entries, entry)
+		}
+	}
+	if _, ok := r.companies[companyID]; !ok && len(entries) == 0 {
+		return nil, nil // No company with this ID
+	}
+	return entries, nil
+}
+
+// GetSharedAddressGroups retrieves live companies grouped by normalized address, keeping groups of at least
+// minSize companies, ordered by address and then by creation
+func (r *CompanyRepository) GetSharedAddressGroups(ctx context.Context, minSize int) ([]api.SharedAddressGroup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byAddress := make(map[string][]*storedCompany)
+	for _, c := range r.companies {
+		if c.DeletedAt == nil {
+			address := normalizedAddress(c.CompanyAddress)
+			byAddress[address] = append(byAddress[address], c)
+		}
+	}
+
+	groups := []api.SharedAddressGroup{}
+	for address, companies := range byAddress {
+		if len(companies) < minSize {
+			continue
+		}
+		sortCompanies(companies, repository.Filter{}, repository.Sort{Column: "date_created"})
+		groups = append(groups, api.SharedAddressGroup{
+			Address:   address,
+			Count:     len(companies),
+			Companies: sortedCompanies(companies),
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Address < groups[j].Address })
+	return groups, nil
+}
+
+// CountByJurisdiction returns the number of live companies in each jurisdiction that has any, ordered by count
+// descending and then by jurisdiction
+func (r *CompanyRepository) CountByJurisdiction(ctx context.Context) ([]api.JurisdictionCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byJurisdiction := make(map[string]int)
+	for _, c := range r.companies {
+		if c.DeletedAt == nil {
+			byJurisdiction[c.Jurisdiction]++
+		}
+	}
+
+	counts := []api.JurisdictionCount{}
+	for jurisdiction, count := range byJurisdiction {
+		counts = append(counts, api.JurisdictionCount{Jurisdiction: jurisdiction, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Jurisdiction < counts[j].Jurisdiction
+	})
+	return counts, nil
+}
+
+// CountByNatureOfBusiness returns the number of live companies with each nature of business, only counting those
+// in jurisdiction (case-insensitively) when it is non-nil. Missing and empty values are counted together under
+// nil. Results are ordered by count descending and then by nature of business, nil last.
+func (r *CompanyRepository) CountByNatureOfBusiness(ctx context.Context, jurisdiction *string) ([]api.NatureOfBusinessCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byNature := make(map[string]int)
+	var unset int
+	for _, c := range r.companies {
+		if c.DeletedAt != nil || (jurisdiction != nil && !strings.EqualFold(c.Jurisdiction, *jurisdiction)) {
+			continue
+		}
+		if c.NatureOfBusiness == nil || *c.NatureOfBusiness == "" {
+			unset++
+		} else {
+			byNature[*c.NatureOfBusiness]++
+		}
+	}
+
+	counts := []api.NatureOfBusinessCount{}
+	for nature, count := range byNature {
+		counts = append(counts, api.NatureOfBusinessCount{NatureOfBusiness: &nature, Count: count})
+	}
+	if unset > 0 {
+		counts = append(counts, api.NatureOfBusinessCount{Count: unset})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := counts[i], counts[j]
+		switch {
+		case a.Count != b.Count:
+			return a.Count > b.Count
+		case a.NatureOfBusiness == nil || b.NatureOfBusiness == nil:
+			return b.NatureOfBusiness == nil && a.NatureOfBusiness != nil
+		default:
+			return *a.NatureOfBusiness < *b.NatureOfBusiness
+		}
+	})
+	return counts, nil
+}
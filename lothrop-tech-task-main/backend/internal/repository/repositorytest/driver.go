@@ -0,0 +1,187 @@
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Rows is the answer to one query: fixed values, or values produced by Next
+type Rows struct {
+	Columns []string
+	Values  [][]driver.Value
+	// Next, when set, produces the nth row (counting from 1) instead of Values, returning io.EOF after the last
+	Next func(n int) ([]driver.Value, error)
+}
+
+// Responder answers a query or statement with rows, or fails it with an error. Rows are ignored for statements.
+type Responder func(query string, args []driver.NamedValue) (*Rows, error)
+
+// Driver is a database/sql driver standing in for Postgres in tests of code written against *sql.DB. Every
+// query and statement is answered by its Responder, and open connections and transactions are counted, so a
+// test can check what the code under test left open.
+type Driver struct {
+	respond Responder
+
+	mu        sync.Mutex
+	conns     int
+	txs       int
+	txOptions driver.TxOptions
+	queries   int
+	lastQuery string
+	lastArgs  int
+}
+
+// drivers numbers the registered drivers, as database/sql needs a unique name for each
+var drivers atomic.Int64
+
+// OpenDB registers a new Driver answering with respond, or with no rows when respond is nil, and returns a pool
+// over it that is closed when the test ends
+func OpenDB(t testing.TB, respond Responder) (*sql.DB, *Driver) {
+	t.Helper()
+
+	if respond == nil {
+		respond = func(string, []driver.NamedValue) (*Rows, error) { return &Rows{}, nil }
+	}
+	d := &Driver{respond: respond}
+	name := fmt.Sprintf("repositorytest-%d", drivers.Add(1))
+	sql.Register(name, d)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+// Open implements driver.Driver
+func (d *Driver) Open(string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.conns++
+	return &conn{driver: d}, nil
+}
+
+// OpenConns returns the number of connections open, idle ones in the pool included
+func (d *Driver) OpenConns() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.conns
+}
+
+// OpenTxs returns the number of transactions begun and not yet committed or rolled back
+func (d *Driver) OpenTxs() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.txs
+}
+
+// TxOptions returns the options of the last transaction begun
+func (d *Driver) TxOptions() driver.TxOptions {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.txOptions
+}
+
+// Queries returns the number of queries and statements run
+func (d *Driver) Queries() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.queries
+}
+
+// LastQuery returns the last query or statement run and its number of arguments
+func (d *Driver) LastQuery() (string, int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastQuery, d.lastArgs
+}
+
+// run records a query or statement and asks the responder for its answer
+func (d *Driver) run(query string, args []driver.NamedValue) (*Rows, error) {
+	d.mu.Lock()
+	d.queries++
+	d.lastQuery, d.lastArgs = query, len(args)
+	d.mu.Unlock()
+
+	return d.respond(query, args)
+}
+
+type conn struct{ driver *Driver }
+
+func (c *conn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *conn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *conn) Close() error {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.conns--
+	return nil
+}
+
+func (c *conn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.txs++
+	c.driver.txOptions = opts
+	return tx{driver: c.driver}, nil
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result, err := c.driver.run(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &rows{result: result}, nil
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if _, err := c.driver.run(query, args); err != nil {
+		return nil, err
+	}
+	return driver.ResultNoRows, nil
+}
+
+type tx struct{ driver *Driver }
+
+func (t tx) Commit() error { return t.Rollback() }
+
+func (t tx) Rollback() error {
+	t.driver.mu.Lock()
+	defer t.driver.mu.Unlock()
+	t.driver.txs--
+	return nil
+}
+
+type rows struct {
+	result *Rows
+	n      int
+}
+
+func (r *rows) Columns() []string { return r.result.Columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	r.n++
+	if r.result.Next != nil {
+		values, err := r.result.Next(r.n)
+		if err != nil {
+			return err
+		}
+		copy(dest, values)
+		return nil
+	}
+
+	if r.n > len(r.result.Values) {
+		return io.EOF
+	}
+	copy(dest, r.result.Values[r.n-1])
+	return nil
+}
@@ -0,0 +1,225 @@
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/api"
+	"backend/internal/repository"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// ListDirectors returns a live company's directors, oldest first, or nil if no live company has the ID
+func (r *CompanyRepository) ListDirectors(ctx context.Context, companyID openapi_types.UUID) ([]api.Director, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.liveCompany(companyID) == nil {
+		return nil, nil // No live company with this ID
+	}
+	return append([]api.Director{}, r.directors[companyID]...), nil
+}
+
+// members returns how many director and shareholder records a company has; r.mu must be held
+func (r *CompanyRepository) members(companyID openapi_types.UUID) repository.MemberCounts {
+	return repository.MemberCounts{
+		Directors:    len(r.directors[companyID]),
+		Shareholders: len(r.shareholders[companyID]),
+	}
+}
+
+// CountMembers returns how many director and shareholder records a company has
+func (r *CompanyRepository) CountMembers(ctx context.Context, id openapi_types.UUID) (repository.MemberCounts, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.members(id), nil
+}
+
+// AddDirector adds a director to a live company and sets its number_of_directors to its director count. It
+// returns nil if no live company has the ID and repository.ErrDirectorLimit if the company already has the
+// maximum bounds allow in its jurisdiction.
+func (r *CompanyRepository) AddDirector(ctx context.Context, companyID openapi_types.UUID, req api.CreateDirectorRequest, bounds repository.CountBounds) (*api.Director, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.liveCompany(companyID)
+	if c == nil {
+		return nil, nil // No live company with this ID
+	}
+	if _, upper := bounds.Limits(c.Jurisdiction); len(r.directors[companyID]) >= upper {
+		return nil, repository.ErrDirectorLimit
+	}
+
+	now := r.now()
+	director := api.Director{Id: uuid.New(), CompanyId: companyID, Name: req.Name, Role: req.Role, DateCreated: now}
+	r.directors[companyID] = append(r.directors[companyID], director)
+	count := len(r.directors[companyID])
+	c.NumberOfDirectors = &count
+	c.DateUpdated = now
+	r.recordAudit(ctx, companyID, api.AddDirector, now)
+
+	return &director, nil
+}
+
+// RemoveDirector deletes a live company's director and sets its number_of_directors to its director count,
+// returning sql.ErrNoRows if the company is not live or has no such director and repository.ErrDirectorMinimum,
+// changing nothing, if the company would be left with fewer directors than bounds allow in its jurisdiction
+func (r *CompanyRepository) RemoveDirector(ctx context.Context, companyID, directorID openapi_types.UUID, bounds repository.CountBounds) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.liveCompany(companyID)
+	if c == nil {
+		return sql.ErrNoRows
+	}
+
+	directors := r.directors[companyID]
+	for i, director := range directors {
+		if director.Id != directorID {
+			continue
+		}
+		if lower, _ := bounds.Limits(c.Jurisdiction); len(directors)-1 < lower {
+			return repository.ErrDirectorMinimum
+		}
+
+		r.directors[companyID] = append(directors[:i:i], directors[i+1:]...)
+		now := r.now()
+		count := len(r.directors[companyID])
+		c.NumberOfDirectors = &count
+		c.DateUpdated = now
+		r.recordAudit(ctx, companyID, api.RemoveDirector, now)
+		return nil
+	}
+	return sql.ErrNoRows // Director not found
+}
+
+// ListShareholders returns a live company's shareholders, oldest first, or nil if no live company has the ID
+func (r *CompanyRepository) ListShareholders(ctx context.Context, companyID openapi_types.UUID) ([]api.Shareholder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.liveCompany(companyID) == nil {
+		return nil, nil // No live company with this ID
+	}
+	return append([]api.Shareholder{}, r.shareholders[companyID]...), nil
+}
+
+// ownershipExceeded reports whether shareholders own more than 100% of their company
+func ownershipExceeded(shareholders []api.Shareholder) bool {
+	var total float64
+	for _, shareholder := range shareholders {
+		total += shareholder.OwnershipPercentage
+	}
+	return total > 100
+}
+
+// AddShareholder adds a shareholder to a live company and sets its number_of_shareholders to its shareholder
+// count. It returns nil if no live company has the ID, repository.ErrShareholderLimit if the company already
+// has the maximum bounds allow in its jurisdiction and repository.ErrOwnershipExceeded if its total ownership
+// would pass 100%.
+func (r *CompanyRepository) AddShareholder(ctx context.Context, companyID openapi_types.UUID, req api.CreateShareholderRequest, bounds repository.CountBounds) (*api.Shareholder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.liveCompany(companyID)
+	if c == nil {
+		return nil, nil // No live company with this ID
+	}
+
+	existing := r.shareholders[companyID]
+	if _, upper := bounds.Limits(c.Jurisdiction); len(existing) >= upper {
+		return nil, repository.ErrShareholderLimit
+	}
+
+	now := r.now()
+	shareholder := api.Shareholder{
+		Id:                  uuid.New(),
+		CompanyId:           companyID,
+		Name:                req.Name,
+		OwnershipPercentage: req.OwnershipPercentage,
+		DateCreated:         now,
+	}
+	shareholders := append(existing[:len(existing):len(existing)], shareholder)
+	if ownershipExceeded(shareholders) {
+		return nil, repository.ErrOwnershipExceeded
+	}
+
+	r.shareholders[companyID] = shareholders
+	count := len(shareholders)
+	c.NumberOfShareholders = &count
+	c.DateUpdated = now
+	r.recordAudit(ctx, companyID, api.AddShareholder, now)
+
+	return &shareholder, nil
+}
+
+// UpdateShareholder replaces a live company's shareholder's name and ownership percentage, returning
+// sql.ErrNoRows if the company is not live or has no such shareholder and repository.ErrOwnershipExceeded if
+// its total ownership would pass 100%
+func (r *CompanyRepository) UpdateShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID, req api.CreateShareholderRequest) (*api.Shareholder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.liveCompany(companyID)
+	if c == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	shareholders := append([]api.Shareholder{}, r.shareholders[companyID]...)
+	for i := range shareholders {
+		if shareholders[i].Id != shareholderID {
+			continue
+		}
+
+		shareholders[i].Name = req.Name
+		shareholders[i].OwnershipPercentage = req.OwnershipPercentage
+		if ownershipExceeded(shareholders) {
+			return nil, repository.ErrOwnershipExceeded
+		}
+
+		r.shareholders[companyID] = shareholders
+		now := r.now()
+		c.DateUpdated = now
+		r.recordAudit(ctx, companyID, api.UpdateShareholder, now)
+
+		shareholder := shareholders[i]
+		return &shareholder, nil
+	}
+	return nil, sql.ErrNoRows // Shareholder not found
+}
+
+// RemoveShareholder deletes a live company's shareholder and sets its number_of_shareholders to its
+// shareholder count, returning sql.ErrNoRows if the company is not live or has no such shareholder and
+// repository.ErrShareholderMinimum, changing nothing, if the company would be left with fewer shareholders than
+// bounds allow in its jurisdiction
+func (r *CompanyRepository) RemoveShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID, bounds repository.CountBounds) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.liveCompany(companyID)
+	if c == nil {
+		return sql.ErrNoRows
+	}
+
+	shareholders := r.shareholders[companyID]
+	for i, shareholder := range shareholders {
+		if shareholder.Id != shareholderID {
+			continue
+		}
+		if lower, _ := bounds.Limits(c.Jurisdiction); len(shareholders)-1 < lower {
+			return repository.ErrShareholderMinimum
+		}
+
+		r.shareholders[companyID] = append(shareholders[:i:i], shareholders[i+1:]...)
+		now := r.now()
+		count := len(r.shareholders[companyID])
+		c.NumberOfShareholders = &count
+		c.DateUpdated = now
+		r.recordAudit(ctx, companyID, api.RemoveShareholder, now)
+		return nil
+	}
+	return sql.ErrNoRows // Shareholder not found
+}
@@ -0,0 +1,244 @@
+package repositorytest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/api"
+	"backend/internal/repository"
+	"backend/internal/textfold"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// matches reports whether a stored company passes the filter, as Filter's WHERE clause would
+func matches(c *storedCompany, filter repository.Filter, now time.Time) bool {
+	if !filter.IncludeDeleted && c.DeletedAt != nil {
+		return false
+	}
+
+	if len(filter.Jurisdictions) > 0 {
+		found := false
+		for _, jurisdiction := range filter.Jurisdictions {
+			if strings.EqualFold(c.Jurisdiction, jurisdiction) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filter.Search != nil {
+		if filter.FoldedSearch {
+			if !strings.Contains(textfold.Fold(c.CompanyName), *filter.Search) {
+				return false
+			}
+		} else if !strings.Contains(strings.ToLower(c.CompanyName), strings.ToLower(*filter.Search)) {
+			return false
+		}
+	}
+
+	if filter.TextQuery != nil && !parseTextQuery(*filter.TextQuery).matches(c) {
+		return false
+	}
+
+	if filter.CreatedAfter != nil && c.DateCreated.Before(*filter.CreatedAfter) {
+		return false
+	}
+
+	if filter.CreatedBefore != nil && c.DateCreated.After(*filter.CreatedBefore) {
+		return false
+	}
+
+	if filter.RecentMinutes > 0 && c.DateCreated.Before(now.Add(-time.Duration(filter.RecentMinutes)*time.Minute)) {
+		return false
+	}
+
+	if filter.After != nil && !keysetBefore(c, filter.After) {
+		return false
+	}
+
+	return true
+}
+
+// keysetBefore reports whether the company comes after the cursor in DefaultSort order, i.e. whether
+// (date_created, id) < (after.DateCreated, after.ID)
+func keysetBefore(c *storedCompany, after *repository.Cursor) bool {
+	if !c.DateCreated.Equal(after.DateCreated) {
+		return c.DateCreated.Before(after.DateCreated)
+	}
+	return bytes.Compare(c.Id[:], after.ID[:]) < 0
+}
+
+// changedAfter reports whether the company comes after the (since, sinceID) watermark in (date_updated, id)
+// order; a nil since matches every company and a nil sinceID compares date_updated only
+func changedAfter(c *storedCompany, since *time.Time, sinceID *openapi_types.UUID) bool {
+	switch {
+	case since == nil:
+		return true
+	case sinceID == nil || !c.DateUpdated.Equal(*since):
+		return c.DateUpdated.After(*since)
+	default:
+		return bytes.Compare(c.Id[:], sinceID[:]) > 0
+	}
+}
+
+// textQuery approximates websearch_to_tsquery: every plain word must appear in the company name or nature of
+// business and no word prefixed with "-" may, compared accent- and case-insensitively. There is no stemming or
+// stop word list, so "fintechs" does not match "fintech" as it would in Postgres.
+type textQuery struct {
+	include []string
+	exclude []string
+}
+
+// parseTextQuery splits a web search query into included and excluded words; quotes are ignored and "or" is
+// treated as a word separator
+func parseTextQuery(query string) textQuery {
+	var q textQuery
+	for _, word := range strings.Fields(textfold.Fold(strings.ReplaceAll(query, `"`, " "))) {
+		switch {
+		case word == "or":
+		case strings.HasPrefix(word, "-") && len(word) > 1:
+			q.exclude = append(q.exclude, word[1:])
+		default:
+			q.include = append(q.include, word)
+		}
+	}
+	return q
+}
+
+// matches reports whether the company's searchable text satisfies the query
+func (q textQuery) matches(c *storedCompany) bool {
+	text := searchText(c)
+	for _, word := range q.include {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	for _, word := range q.exclude {
+		if strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// rank scores a match so name matches outrank nature of business ones, as the weighted search_vector does
+func (q textQuery) rank(c *storedCompany) float64 {
+	name := textfold.Fold(c.CompanyName)
+	var nature string
+	if c.NatureOfBusiness != nil {
+		nature = textfold.Fold(*c.NatureOfBusiness)
+	}
+
+	var score float64
+	for _, word := range q.include {
+		if strings.Contains(name, word) {
+			score += 1
+		}
+		if strings.Contains(nature, word) {
+			score += 0.4
+		}
+	}
+	return score
+}
+
+// searchText is the folded text a full-text query is matched against
+func searchText(c *storedCompany) string {
+	text := textfold.Fold(c.CompanyName)
+	if c.NatureOfBusiness != nil {
+		text += " " + textfold.Fold(*c.NatureOfBusiness)
+	}
+	return text
+}
+
+// sortCompanies orders companies as orderClause would: by relevance first when requested, then by the sort
+// column, breaking ties by id in the same direction. String columns compare byte-wise, which can differ from
+// a database collation for mixed case or accented names.
+func sortCompanies(companies []*storedCompany, filter repository.Filter, s repository.Sort) {
+	if s.Column == "" {
+		s = repository.DefaultSort
+	}
+
+	var query *textQuery
+	if s.Relevance && filter.TextQuery != nil {
+		parsed := parseTextQuery(*filter.TextQuery)
+		query = &parsed
+	}
+
+	sort.SliceStable(companies, func(i, j int) bool {
+		a, b := companies[i], companies[j]
+		if query != nil {
+			if ra, rb := query.rank(a), query.rank(b); ra != rb {
+				return ra > rb
+			}
+		}
+
+		cmp := compareColumn(a, b, s.Column)
+		if cmp == 0 {
+			cmp = bytes.Compare(a.Id[:], b.Id[:])
+		}
+		if s.Descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+// compareColumn compares two companies by one of the service's sortable columns
+func compareColumn(a, b *storedCompany, column string) int {
+	switch column {
+	case "company_name":
+		return strings.Compare(a.CompanyName, b.CompanyName)
+	case "jurisdiction":
+		return strings.Compare(a.Jurisdiction, b.Jurisdiction)
+	case "date_updated":
+		return a.DateUpdated.Compare(b.DateUpdated)
+	default:
+		return a.DateCreated.Compare(b.DateCreated)
+	}
+}
+
+// page returns the companies from offset, at most limit of them
+func page(companies []*storedCompany, limit, offset int) []*storedCompany {
+	if offset >= len(companies) {
+		return nil
+	}
+	companies = companies[offset:]
+	if limit < len(companies) {
+		companies = companies[:limit]
+	}
+	return companies
+}
+
+// rowHash matches the Postgres repository's row hash: the first 15 hex digits of md5("<id>|<microseconds>")
+func rowHash(c *storedCompany) uint64 {
+	sum := md5.Sum([]byte(c.Id.String() + "|" + strconv.FormatInt(c.DateUpdated.UnixMicro(), 10)))
+	hash, _ := strconv.ParseUint(hex.EncodeToString(sum[:])[:15], 16, 64)
+	return hash
+}
+
+// whitespace matches the runs of whitespace collapsed when grouping addresses
+var whitespace = regexp.MustCompile(`\s+`)
+
+// normalizedAddress groups addresses as GetSharedAddressGroups does: trimmed, whitespace collapsed, lowercased
+func normalizedAddress(address string) string {
+	return strings.ToLower(whitespace.ReplaceAllString(strings.Trim(address, " "), " "))
+}
+
+// sortedCompanies copies companies out in their stored order
+func sortedCompanies(companies []*storedCompany) []api.Company {
+	out := make([]api.Company, 0, len(companies))
+	for _, c := range companies {
+		out = append(out, c.public())
+	}
+	return out
+}
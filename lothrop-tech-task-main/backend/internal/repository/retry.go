@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"syscall"
+	"time"
+
+	"backend/internal/metrics"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// retryBaseDelay is the wait before the first retry; each later retry waits twice as long as the one before
+	retryBaseDelay = 50 * time.Millisecond
+	// retryMaxDelay caps the wait between attempts
+	retryMaxDelay = time.Second
+)
+
+// errCommit marks a failed COMMIT. Whether the transaction was applied is unknown, so it is never retried.
+type errCommit struct {
+	err error
+}
+
+func (e *errCommit) Error() string { return "failed to commit transaction: " + e.err.Error() }
+
+func (e *errCommit) Unwrap() error { return e.err }
+
+// isTransient reports whether err is a failure that may succeed if the same statements are run again:
+// serialization failures, deadlocks, lost or refused connections and a server that is shutting down or starting.
+// Constraint violations such as unique_violation are not transient.
+func isTransient(err error) bool {
+	var commitErr *errCommit
+	if errors.As(err, &commitErr) {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"57P01", // admin_shutdown
+			"57P03": // cannot_connect_now
+			return true
+		}
+		return pqErr.Code.Class() == "08" // connection_exception
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// retry runs fn until it succeeds, returns an error that is not transient, or has been attempted r.maxAttempts
+// times, waiting with exponential backoff between attempts. It gives up early rather than wait past ctx's
+// deadline. fn must be safe to run more than once: a read, or a whole transaction. Each attempt is timed
+// separately for slow query logging.
+func (r *PostgresCompanyRepository) retry(ctx context.Context, op string, fn func() error) error {
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := fn()
+		r.observeQuery(ctx, op, attempt, time.Since(start), err)
+		if err == nil || attempt >= r.maxAttempts || !isTransient(err) {
+			return err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		metrics.RecordDBRetry(op)
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
+
+// query runs a multi-row query with retry. Only opening the result set is retried; an error while reading rows
+// is returned as is, since the caller may already have consumed some of them.
+func (r *PostgresCompanyRepository) query(ctx context.Context, op, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := r.retry(ctx, op, func() error {
+		var err error
+		rows, err = r.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
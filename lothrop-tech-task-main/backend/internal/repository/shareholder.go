@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"backend/api"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// ErrShareholderLimit is returned when adding a shareholder would take a company past the allowed number of shareholders
+var ErrShareholderLimit = errors.New("company has the maximum number of shareholders")
+
+// ErrShareholderMinimum is returned when removing a shareholder would leave a company fewer than the allowed
+// number of shareholders
+var ErrShareholderMinimum = errors.New("company has the minimum number of shareholders")
+
+// ErrOwnershipExceeded is returned when a write would take a company's total shareholder ownership past 100%
+var ErrOwnershipExceeded = errors.New("total shareholder ownership exceeds 100%")
+
+// shareholderColumns is the standard shareholder column list, in the order scanShareholder expects
+const shareholderColumns = `id, company_id, name, ownership_percentage, date_created`
+
+// scanShareholder scans a row of shareholderColumns
+func scanShareholder(row rowScanner) (*api.Shareholder, error) {
+	var shareholder api.Shareholder
+	if err := row.Scan(&shareholder.Id, &shareholder.CompanyId, &shareholder.Name, &shareholder.OwnershipPercentage,
+		&shareholder.DateCreated); err != nil {
+		return nil, err
+	}
+	shareholder.DateCreated = shareholder.DateCreated.UTC() // See scanCompany
+	return &shareholder, nil
+}
+
+// syncShareholderCountQuery sets a company's number_of_shareholders to its number of shareholder records
+const syncShareholderCountQuery = `
+		UPDATE companies
+		SET number_of_shareholders = (SELECT COUNT(*) FROM shareholders WHERE company_id = $1),
+		    date_updated = CURRENT_TIMESTAMP
+		WHERE id = $1`
+
+// checkOwnership returns ErrOwnershipExceeded if a company's shareholders, as written so far in the transaction,
+// own more than 100% of it. The company row must be locked so concurrent writes cannot both pass the check.
+func checkOwnership(ctx context.Context, tx *sql.Tx, companyID openapi_types.UUID) error {
+	var exceeded bool
+	err := tx.QueryRowContext(ctx,
+		"SELECT COALESCE(SUM(ownership_percentage), 0) > 100 FROM shareholders WHERE company_id = $1",
+		companyID).Scan(&exceeded)
+	if err != nil {
+		return err
+	}
+	if exceeded {
+		return ErrOwnershipExceeded
+	}
+	return nil
+}
+
+// ListShareholders returns a live company's shareholders, oldest first, or nil if no live company has the ID
+func (r *PostgresCompanyRepository) ListShareholders(ctx context.Context, companyID openapi_types.UUID) ([]api.Shareholder, error) {
+	var exists bool
+	err := r.retry(ctx, "list_shareholders", func() error {
+		return r.db.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM companies WHERE id = $1 AND deleted_at IS NULL)", companyID).Scan(&exists)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil // No live company with this ID
+	}
+
+	query := `
+		SELECT ` + shareholderColumns + `
+		FROM shareholders
+		WHERE company_id = $1
+		ORDER BY date_created, id`
+
+	rows, err := r.query(ctx, "list_shareholders", query, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shareholders := []api.Shareholder{}
+	for rows.Next() {
+		shareholder, err := scanShareholder(rows)
+		if err != nil {
+			return nil, err
+		}
+		shareholders = append(shareholders, *shareholder)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return shareholders, nil
+}
+
+// AddShareholder adds a shareholder to a live company and syncs its number_of_shareholders in one transaction.
+// It returns nil if no live company has the ID, ErrShareholderLimit if the company already has the maximum bounds
+// allow in its jurisdiction and ErrOwnershipExceeded if the company's total ownership would pass 100%.
+func (r *PostgresCompanyRepository) AddShareholder(ctx context.Context, companyID openapi_types.UUID, req api.CreateShareholderRequest, bounds CountBounds) (*api.Shareholder, error) {
+	var shareholder *api.Shareholder
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		jurisdiction, err := lockLiveCompany(ctx, tx, companyID)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM shareholders WHERE company_id = $1", companyID).Scan(&count); err != nil {
+			return err
+		}
+		if _, upper := bounds.Limits(jurisdiction); count >= upper {
+			return ErrShareholderLimit
+		}
+
+		query := `
+		INSERT INTO shareholders (company_id, name, ownership_percentage)
+		VALUES ($1, $2, $3)
+		RETURNING ` + shareholderColumns
+
+		shareholder, err = scanShareholder(tx.QueryRowContext(ctx, query, companyID, req.Name, req.OwnershipPercentage))
+		if err != nil {
+			return err
+		}
+
+		if err := checkOwnership(ctx, tx, companyID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, syncShareholderCountQuery, companyID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, companyID, api.AddShareholder)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No live company with this ID
+		}
+		return nil, err
+	}
+
+	return shareholder, nil
+}
+
+// UpdateShareholder replaces a live company's shareholder's name and ownership percentage, returning
+// sql.ErrNoRows if the company is not live or has no shareholder with the ID, and ErrOwnershipExceeded if the
+// company's total ownership would pass 100%
+func (r *PostgresCompanyRepository) UpdateShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID, req api.CreateShareholderRequest) (*api.Shareholder, error) {
+	var shareholder *api.Shareholder
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := lockLiveCompany(ctx, tx, companyID); err != nil {
+			return err
+		}
+
+		query := `
+		UPDATE shareholders
+		SET name = $3, ownership_percentage = $4
+		WHERE id = $1 AND company_id = $2
+		RETURNING ` + shareholderColumns
+
+		var err error
+		shareholder, err = scanShareholder(tx.QueryRowContext(ctx, query, shareholderID, companyID, req.Name, req.OwnershipPercentage))
+		if err != nil {
+			return err
+		}
+
+		if err := checkOwnership(ctx, tx, companyID); err != nil {
+			return err
+		}
+		// Bump date_updated so the company's ETag and incremental readers see the change
+		if _, err := tx.ExecContext(ctx, syncShareholderCountQuery, companyID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, companyID, api.UpdateShareholder)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shareholder, nil
+}
+
+// RemoveShareholder removes a live company's shareholder and syncs its number_of_shareholders in one transaction,
+// returning sql.ErrNoRows if the company is not live or has no shareholder with the ID, and ErrShareholderMinimum
+// if the company would be left with fewer shareholders than bounds allow in its jurisdiction
+func (r *PostgresCompanyRepository) RemoveShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID, bounds CountBounds) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		jurisdiction, err := lockLiveCompany(ctx, tx, companyID)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM shareholders WHERE id = $1 AND company_id = $2", shareholderID, companyID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return sql.ErrNoRows // Shareholder not found
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM shareholders WHERE company_id = $1", companyID).Scan(&count); err != nil {
+			return err
+		}
+		if lower, _ := bounds.Limits(jurisdiction); count < lower {
+			return ErrShareholderMinimum // Rolls the delete back
+		}
+
+		if _, err := tx.ExecContext(ctx, syncShareholderCountQuery, companyID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, companyID, api.RemoveShareholder)
+	})
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/logctx"
+
+	"go.uber.org/zap"
+)
+
+// observeQuery logs a warning when one attempt at a database operation took longer than r.slowQuery.
+// Only the operation name is logged, never the SQL arguments, which may hold company data.
+func (r *PostgresCompanyRepository) observeQuery(ctx context.Context, op string, attempt int, elapsed time.Duration, err error) {
+	if r.slowQuery <= 0 || elapsed < r.slowQuery {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("operation", op),
+		zap.Int("attempt", attempt),
+		zap.Duration("duration", elapsed),
+		zap.Duration("threshold", r.slowQuery),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	logctx.FromContext(ctx, r.logger).Warn("Slow database query", fields...)
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend/api"
+)
+
+// Snapshot is a read-only REPEATABLE READ transaction held open across requests, so every page read through it
+// sees the companies table as it was when the snapshot began. It holds a database connection until closed.
+type Snapshot struct {
+	mu     sync.Mutex
+	tx     *sql.Tx
+	cancel context.CancelFunc
+}
+
+// BeginSnapshot starts a snapshot that is rolled back after lifetime even if it is never closed
+func (r *PostgresCompanyRepository) BeginSnapshot(lifetime time.Duration) (*Snapshot, error) {
+	// The transaction outlives the request that opens it, so it is bound to its own deadline instead
+	ctx, cancel := context.WithTimeout(context.Background(), lifetime)
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to begin snapshot: %w", err)
+	}
+
+	// The snapshot is taken by the first query, not BEGIN, so take it now
+	if _, err := tx.ExecContext(ctx, "SELECT 1 FROM companies LIMIT 1"); err != nil {
+		tx.Rollback()
+		cancel()
+		return nil, fmt.Errorf("failed to begin snapshot: %w", err)
+	}
+
+	return &Snapshot{tx: tx, cancel: cancel}, nil
+}
+
+// Page returns up to limit companies matching the filter in DefaultSort order, as of the snapshot.
+// Pass the last company's position as Filter.After to continue from it. Pages are read one at a time.
+func (s *Snapshot) Page(ctx context.Context, filter Filter, limit int) ([]api.Company, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query, args := listQuery(companyColumns, limit, 0, filter, DefaultSort)
+	rows, err := s.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	companies := []api.Company{}
+	for rows.Next() {
+		company, err := scanCompany(rows)
+		if err != nil {
+			return nil, err
+		}
+		companies = append(companies, *company)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return companies, nil
+}
+
+// Close rolls back the snapshot transaction, releasing its connection. It is safe to call more than once.
+func (s *Snapshot) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	defer s.cancel()
+	if err := s.tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		return err
+	}
+	return nil
+}
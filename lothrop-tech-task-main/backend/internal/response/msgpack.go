@@ -0,0 +1,114 @@
+package response
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// encodeMsgPack encodes data as MessagePack. data is marshalled to JSON and decoded generically first, so the
+// result has the same field names and value formats (e.g. RFC 3339 timestamps) as the JSON response.
+func encodeMsgPack(data interface{}) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return appendMsgPack(nil, value)
+}
+
+// appendMsgPack appends the MessagePack encoding of a generically decoded JSON value to buf
+func appendMsgPack(buf []byte, value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, 0xc0), nil
+	case bool:
+		if v {
+			return append(buf, 0xc3), nil
+		}
+		return append(buf, 0xc2), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return appendMsgPackInt(buf, i), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, 0xcb)
+		return binary.BigEndian.AppendUint64(buf, math.Float64bits(f)), nil
+	case string:
+		buf = appendMsgPackHeader(buf, len(v), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		return append(buf, v...), nil
+	case []interface{}:
+		buf = appendMsgPackHeader(buf, len(v), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			var err error
+			if buf, err = appendMsgPack(buf, item); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case map[string]interface{}:
+		// Keys are sorted so equal values always encode identically
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf = appendMsgPackHeader(buf, len(v), 0x80, 16, 0, 0xde, 0xdf)
+		for _, key := range keys {
+			var err error
+			if buf, err = appendMsgPack(buf, key); err != nil {
+				return nil, err
+			}
+			if buf, err = appendMsgPack(buf, v[key]); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	return nil, fmt.Errorf("msgpack: unsupported value of type %T", value)
+}
+
+// appendMsgPackHeader appends a string, array or map header in its smallest form: the fix form for lengths
+// below fixLimit, then the 8-bit (when the type has one, i.e. code8 is non-zero), 16-bit and 32-bit forms
+func appendMsgPackHeader(buf []byte, length int, fixCode byte, fixLimit int, code8, code16, code32 byte) []byte {
+	switch {
+	case length < fixLimit:
+		return append(buf, fixCode|byte(length))
+	case code8 != 0 && length <= math.MaxUint8:
+		return append(buf, code8, byte(length))
+	case length <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, code16), uint16(length))
+	}
+	return binary.BigEndian.AppendUint32(append(buf, code32), uint32(length))
+}
+
+// appendMsgPackInt appends an integer in its smallest MessagePack form
+func appendMsgPackInt(buf []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i <= math.MaxInt8:
+		return append(buf, byte(i))
+	case i < 0 && i >= -32:
+		return append(buf, byte(int8(i)))
+	case i >= math.MinInt8 && i <= math.MaxInt8:
+		return append(buf, 0xd0, byte(int8(i)))
+	case i >= math.MinInt16 && i <= math.MaxInt16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(int16(i)))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(int32(i)))
+	}
+	return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(i))
+}
@@ -0,0 +1,113 @@
+package response
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/api"
+)
+
+func TestEncodeMsgPack(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string // Hex-encoded MessagePack
+	}{
+		{"nil", nil, "c0"},
+		{"true", true, "c3"},
+		{"false", false, "c2"},
+		{"positive fixint", 127, "7f"},
+		{"negative fixint", -32, "e0"},
+		{"int8", -33, "d0df"},
+		{"int16", 128, "d10080"},
+		{"negative int16", -129, "d1ff7f"},
+		{"int32", 40000, "d200009c40"},
+		{"int64", int64(1) << 32, "d30000000100000000"},
+		{"float", 1.5, "cb3ff8000000000000"},
+		{"fixstr", "abc", "a3616263"},
+		{"empty array", []int{}, "90"},
+		{"fixarray", []interface{}{1, "a", nil}, "9301a161c0"},
+		{"map keys sorted", map[string]int{"b": 1, "a": 2}, "82a16102a16201"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeMsgPack(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("encodeMsgPack(%v) = %x, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeMsgPackHeaderSizes(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  interface{}
+		header string // Hex-encoded header the encoding must start with
+	}{
+		{"longest fixstr", strings.Repeat("x", 31), "bf"},
+		{"str8", strings.Repeat("x", 32), "d920"},
+		{"longest str8", strings.Repeat("x", 255), "d9ff"},
+		{"str16", strings.Repeat("x", 256), "da0100"},
+		{"str32", strings.Repeat("x", 1<<16), "db00010000"},
+		{"longest fixarray", make([]int, 15), "9f"},
+		{"array16", make([]int, 16), "dc0010"},
+		{"array32", make([]int, 1<<16), "dd00010000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeMsgPack(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if header, _ := hex.DecodeString(tt.header); !bytes.HasPrefix(got, header) {
+				t.Errorf("encoding starts %x, want header %s", got[:len(header)], tt.header)
+			}
+		})
+	}
+
+	fields := map[string]int{}
+	for i := 0; i < 16; i++ {
+		fields[strings.Repeat("k", i+1)] = i
+	}
+	if got, _ := encodeMsgPack(fields); !bytes.HasPrefix(got, []byte{0xde, 0x00, 0x10}) {
+		t.Errorf("16-entry map starts %x, want map16 header de0010", got[:3])
+	}
+}
+
+func TestWriteNegotiatesMsgPack(t *testing.T) {
+	body := api.ErrorResponse{Error: true, Msg: "Company not found"}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/123", nil)
+	req.Header.Set("Accept", ContentTypeMsgPack)
+	rec := httptest.NewRecorder()
+	if err := Write(rec, req, http.StatusNotFound, body); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.Header().Get("Content-Type"); got != ContentTypeMsgPack {
+		t.Errorf("Content-Type = %q, want %q", got, ContentTypeMsgPack)
+	}
+	// {"error": true, "msg": "Company not found"}, keys in sorted order, as the JSON response names them
+	want := "82a56572726f72c3a36d7367b1436f6d70616e79206e6f7420666f756e64"
+	if got := hex.EncodeToString(rec.Body.Bytes()); got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+	if err := Write(rec, req, http.StatusNotFound, body); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.Header().Get("Content-Type"); got != ContentTypeJSON {
+		t.Errorf("Content-Type without msgpack in Accept = %q, want %q", got, ContentTypeJSON)
+	}
+}
@@ -0,0 +1,187 @@
+package response
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"backend/api"
+	"backend/internal/i18n"
+
+	"github.com/go-chi/chi/v5/middleware"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	// ContentTypeJSON is the default content type for API responses
+	ContentTypeJSON = "application/json"
+
+	// ContentTypeProblemJSON is the RFC 7807 problem details content type
+	ContentTypeProblemJSON = "application/problem+json"
+
+	// ContentTypeMsgPack is the MessagePack content type, served to clients that explicitly accept it
+	ContentTypeMsgPack = "application/msgpack"
+
+	// ContentTypeJSONAPI is the JSON:API content type, used for error documents to clients that explicitly accept it
+	ContentTypeJSONAPI = "application/vnd.api+json"
+)
+
+// WriteJSON encodes data as a JSON response with the given status code
+func WriteJSON(w http.ResponseWriter, statusCode int, data interface{}) error {
+	return write(w, ContentTypeJSON, statusCode, data)
+}
+
+// negotiatedHeaders are the request headers a response body depends on: its format follows Accept and its
+// messages follow Accept-Language
+const negotiatedHeaders = "Accept, Accept-Language"
+
+// Vary marks a cacheable response, one carrying Cache-Control or an ETag, as varying with the negotiated request
+// headers, so a cache does not answer a request for one format or language with a response stored for another
+func Vary(w http.ResponseWriter) {
+	header := w.Header()
+	if header.Get("Cache-Control") == "" && header.Get("ETag") == "" {
+		return
+	}
+	header.Add("Vary", negotiatedHeaders)
+}
+
+// WriteNotModified sends a bodiless 304 carrying the same Vary header as the full response would
+func WriteNotModified(w http.ResponseWriter) {
+	Vary(w)
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// Write encodes data as MessagePack when the client accepts application/msgpack and as JSON otherwise. A
+// cacheable response also gets a Vary header naming the negotiated request headers.
+func Write(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}) error {
+	Vary(w)
+	if !Accepts(r, ContentTypeMsgPack) {
+		return WriteJSON(w, statusCode, data)
+	}
+
+	body, err := encodeMsgPack(data)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", ContentTypeMsgPack)
+	w.WriteHeader(statusCode)
+	_, err = w.Write(body)
+	return err
+}
+
+// WriteError writes an error response, using RFC 7807 problem details when the client accepts
+// application/problem+json, a JSON:API error document when it accepts application/vnd.api+json, and the standard
+// api.ErrorResponse, encoded as by Write, otherwise. Problem and JSON:API titles are localized from the request's
+// Accept-Language.
+func WriteError(w http.ResponseWriter, r *http.Request, statusCode int, message string) error {
+	return WriteFieldErrors(w, r, statusCode, message, nil)
+}
+
+// WriteFieldErrors writes an error response like WriteError, adding a fields map of per-field messages
+// when fields is not empty
+func WriteFieldErrors(w http.ResponseWriter, r *http.Request, statusCode int, message string, fields map[string]string) error {
+	return writeError(w, r, statusCode, message, fields, nil)
+}
+
+// WriteConflict writes a 409 error response like WriteError, adding the conflicting_id of the existing resource
+// the request collides with
+func WriteConflict(w http.ResponseWriter, r *http.Request, message string, conflictingID openapi_types.UUID) error {
+	return writeError(w, r, http.StatusConflict, message, nil, &conflictingID)
+}
+
+// writeError writes an error response in the negotiated format with the optional fields map and conflicting ID
+func writeError(w http.ResponseWriter, r *http.Request, statusCode int, message string, fields map[string]string, conflictingID *openapi_types.UUID) error {
+	var fieldsPtr *map[string]string
+	if len(fields) > 0 {
+		fieldsPtr = &fields
+	}
+
+	locale := i18n.Negotiate(r)
+	w.Header().Set("Content-Language", locale)
+
+	var requestID *string
+	if id := middleware.GetReqID(r.Context()); id != "" {
+		requestID = &id
+	}
+
+	if Accepts(r, ContentTypeProblemJSON) {
+		instance := r.URL.Path
+		problem := api.ProblemDetails{
+			Type:          "about:blank",
+			Title:         i18n.StatusText(locale, statusCode),
+			Status:        statusCode,
+			Detail:        &message,
+			Instance:      &instance,
+			Fields:        fieldsPtr,
+			RequestId:     requestID,
+			ConflictingId: conflictingID,
+		}
+		return write(w, ContentTypeProblemJSON, statusCode, problem)
+	}
+
+	if Accepts(r, ContentTypeJSONAPI) {
+		return write(w, ContentTypeJSONAPI, statusCode, jsonAPIErrors(locale, statusCode, message, fields, requestID, conflictingID))
+	}
+
+	return Write(w, r, statusCode, api.ErrorResponse{
+		Error:         true,
+		Msg:           message,
+		Fields:        fieldsPtr,
+		RequestId:     requestID,
+		ConflictingId: conflictingID,
+	})
+}
+
+// jsonAPIErrors builds a JSON:API error document: one error for the failure itself, carrying the conflicting ID
+// in its meta, then one per field-level violation in field order, each naming its field in meta
+func jsonAPIErrors(locale string, statusCode int, message string, fields map[string]string, requestID *string, conflictingID *openapi_types.UUID) api.JsonApiErrorDocument {
+	status := strconv.Itoa(statusCode)
+	title := i18n.StatusText(locale, statusCode)
+
+	primary := api.JsonApiError{Id: requestID, Status: status, Title: title, Detail: &message}
+	if conflictingID != nil {
+		primary.Meta = &api.JsonApiErrorMeta{ConflictingId: conflictingID}
+	}
+	errs := []api.JsonApiError{primary}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		name, detail := name, fields[name]
+		errs = append(errs, api.JsonApiError{
+			Id:     requestID,
+			Status: status,
+			Title:  title,
+			Detail: &detail,
+			Meta:   &api.JsonApiErrorMeta{Field: &name},
+		})
+	}
+
+	return api.JsonApiErrorDocument{Errors: errs}
+}
+
+// Accepts reports whether the request's Accept header explicitly lists the given media type
+func Accepts(r *http.Request, mediaType string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		accepted, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && accepted == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// write sets the content type and status code, then encodes data as JSON
+func write(w http.ResponseWriter, contentType string, statusCode int, data interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+
+	return json.NewEncoder(w).Encode(data)
+}
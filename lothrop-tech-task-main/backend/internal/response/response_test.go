@@ -0,0 +1,123 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/api"
+)
+
+func TestWriteErrorProblemDetails(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{"problem+json", "application/problem+json", ContentTypeProblemJSON},
+		{"problem+json among others", "text/html, application/problem+json;q=0.9", ContentTypeProblemJSON},
+		{"no Accept header", "", ContentTypeJSON},
+		{"wildcard", "*/*", ContentTypeJSON},
+		{"plain JSON", "application/json", ContentTypeJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/123", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			if err := WriteError(rec, req, http.StatusNotFound, "Company not found"); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want 404", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Fatalf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+
+			if tt.contentType == ContentTypeJSON {
+				var body api.ErrorResponse
+				if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+					t.Fatal(err)
+				}
+				if !body.Error || body.Msg != "Company not found" {
+					t.Errorf("body = %+v, want the standard error response", body)
+				}
+				return
+			}
+
+			var problem api.ProblemDetails
+			if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Type != "about:blank" || problem.Status != http.StatusNotFound || problem.Title != "Not Found" {
+				t.Errorf("type, status, title = %q, %d, %q, want about:blank, 404, Not Found", problem.Type, problem.Status, problem.Title)
+			}
+			if problem.Detail == nil || *problem.Detail != "Company not found" {
+				t.Errorf("detail = %v, want Company not found", problem.Detail)
+			}
+			if problem.Instance == nil || *problem.Instance != "/api/v1/companies/123" {
+				t.Errorf("instance = %v, want the request path", problem.Instance)
+			}
+		})
+	}
+}
+
+func TestWriteFieldErrorsProblemDetails(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/companies", nil)
+	req.Header.Set("Accept", ContentTypeProblemJSON)
+	req.Header.Set("Accept-Language", "fr")
+	rec := httptest.NewRecorder()
+
+	fields := map[string]string{"company_name": "le nom de la société est obligatoire"}
+	if err := WriteFieldErrors(rec, req, http.StatusUnprocessableEntity, fields["company_name"], fields); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rec.Header().Get("Content-Language"); got != "fr" {
+		t.Errorf("Content-Language = %q, want fr", got)
+	}
+	var problem api.ProblemDetails
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Title != "Entité non traitable" {
+		t.Errorf("title = %q, want the French status text", problem.Title)
+	}
+	if problem.Fields == nil || (*problem.Fields)["company_name"] != fields["company_name"] {
+		t.Errorf("fields = %v, want %v", problem.Fields, fields)
+	}
+}
+
+func TestWriteVariesCacheableResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   string
+	}{
+		{"Cache-Control", "Cache-Control", "max-age=60", "Accept, Accept-Language"},
+		{"ETag", "ETag", `W/"abc"`, "Accept, Accept-Language"},
+		{"not cacheable", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if tt.header != "" {
+				rec.Header().Set(tt.header, tt.value)
+			}
+			if err := Write(rec, httptest.NewRequest(http.MethodGet, "/api/v1/companies", nil), http.StatusOK, map[string]int{"total": 0}); err != nil {
+				t.Fatal(err)
+			}
+			if got := rec.Header().Get("Vary"); got != tt.want {
+				t.Errorf("Vary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
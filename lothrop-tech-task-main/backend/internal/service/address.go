@@ -0,0 +1,149 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"backend/api"
+)
+
+// AddressRule overrides how company_address is validated for one jurisdiction
+type AddressRule struct {
+	// Optional allows a blank address
+	Optional bool `json:"optional"`
+	// Pattern is a regular expression a full address must match, e.g. requiring a postcode
+	Pattern string `json:"pattern"`
+	// AgentPattern is a regular expression for a registered agent reference accepted instead of a full address
+	AgentPattern string `json:"agent_pattern"`
+
+	pattern      *regexp.Regexp
+	agentPattern *regexp.Regexp
+}
+
+// ParseAddressRules decodes a JSON object mapping jurisdictions to their address rules and compiles the
+// patterns, e.g. {"Cayman Islands": {"agent_pattern": "^RA-[0-9]{4,}$"}}. An empty string yields no rules.
+func ParseAddressRules(raw string) (map[string]AddressRule, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var rules map[string]AddressRule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse address rules: %w", err)
+	}
+
+	for jurisdiction, rule := range rules {
+		var err error
+		if rule.Pattern != "" {
+			if rule.pattern, err = regexp.Compile(rule.Pattern); err != nil {
+				return nil, fmt.Errorf("invalid address pattern for %s: %w", jurisdiction, err)
+			}
+		}
+		if rule.AgentPattern != "" {
+			if rule.agentPattern, err = regexp.Compile(rule.AgentPattern); err != nil {
+				return nil, fmt.Errorf("invalid agent pattern for %s: %w", jurisdiction, err)
+			}
+		}
+		rules[jurisdiction] = rule
+	}
+	return rules, nil
+}
+
+// normalizeAddress trims the address and collapses runs of whitespace, newlines included, to single spaces
+func normalizeAddress(req *api.CreateCompanyRequest) {
+	req.CompanyAddress = strings.Join(strings.Fields(req.CompanyAddress), " ")
+}
+
+// checkAddressLength records a violation when the address is longer than the configured maximum
+func (s *companyService) checkAddressLength(req api.CreateCompanyRequest, violations *fieldErrors) {
+	if s.opts.AddressMaxLength > 0 && utf8.RuneCountInString(req.CompanyAddress) > s.opts.AddressMaxLength {
+		violations.add("company_address", "company address cannot exceed %d characters", s.opts.AddressMaxLength)
+	}
+}
+
+// checkAddress records a violation when the address breaks the jurisdiction's address rule, or is blank where
+// no rule makes it optional. It reports whether the address is a registered agent reference.
+func (s *companyService) checkAddress(req api.CreateCompanyRequest, violations *fieldErrors) (agent bool) {
+	rule := s.opts.AddressRules[req.Jurisdiction]
+
+	address := strings.TrimSpace(req.CompanyAddress)
+	if address == "" {
+		if !rule.Optional {
+			violations.add("company_address", "company address is required")
+		}
+		return false
+	}
+
+	if rule.agentPattern != nil && rule.agentPattern.MatchString(address) {
+		return true
+	}
+
+	if rule.pattern != nil && !rule.pattern.MatchString(address) {
+		if rule.agentPattern != nil {
+			violations.add("company_address", "company address for %s must be a full address in the required format or a registered agent reference", req.Jurisdiction)
+		} else {
+			violations.add("company_address", "company address for %s is not in the required format", req.Jurisdiction)
+		}
+	}
+	return false
+}
+
+// Address country check modes
+const (
+	addressCheckWarn  = "warn"
+	addressCheckError = "error"
+)
+
+// countryMarkers lists the whole-word address terms naming each jurisdiction's country. Periods separate
+// words, so "U.K." reads as "u k".
+var countryMarkers = map[string][]string{
+	"UK":             {"uk", "u k", "united kingdom", "great britain", "england", "scotland", "wales", "northern ireland"},
+	"Singapore":      {"singapore"},
+	"Cayman Islands": {"cayman islands", "grand cayman", "cayman brac", "little cayman"},
+}
+
+// addressCountries returns the jurisdictions whose country the address names
+func addressCountries(address string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(address), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	padded := " " + strings.Join(words, " ") + " "
+
+	found := map[string]bool{}
+	for jurisdiction, markers := range countryMarkers {
+		for _, marker := range markers {
+			if strings.Contains(padded, " "+marker+" ") {
+				found[jurisdiction] = true
+				break
+			}
+		}
+	}
+	return found
+}
+
+// addressMismatch reports whether the address names another jurisdiction's country but not its own.
+// Addresses naming no known country are not flagged.
+func addressMismatch(req api.CreateCompanyRequest) bool {
+	found := addressCountries(req.CompanyAddress)
+	return len(found) > 0 && !found[req.Jurisdiction]
+}
+
+// checkAddressCountry rejects an address outside its jurisdiction when the jurisdiction's check is in error mode
+func (s *companyService) checkAddressCountry(req api.CreateCompanyRequest) error {
+	if s.opts.AddressCountryCheck[req.Jurisdiction] == addressCheckError && addressMismatch(req) {
+		return fieldErrorf("company_address", "company address appears to be outside %s", req.Jurisdiction)
+	}
+	return nil
+}
+
+// addressWarnings returns a warning for an address outside its jurisdiction when the jurisdiction's check is in warn mode
+func (s *companyService) addressWarnings(req api.CreateCompanyRequest) []string {
+	if s.opts.AddressCountryCheck[req.Jurisdiction] == addressCheckWarn && addressMismatch(req) {
+		return []string{fmt.Sprintf("company_address appears to be outside %s", req.Jurisdiction)}
+	}
+	return nil
+}
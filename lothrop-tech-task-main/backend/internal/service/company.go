@@ -2,11 +2,18 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"regexp"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"backend/api"
 	"backend/internal/repository"
+	"backend/internal/textfold"
 
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
@@ -16,150 +23,1496 @@ type CompanyService interface {
 	// ListCompanies retrieves companies with pagination and optional filtering
 	ListCompanies(ctx context.Context, params api.GetCompaniesParams) (*api.CompaniesResponse, error)
 
+	// ListCompanyIDs retrieves only company IDs with the same pagination and filtering as ListCompanies
+	ListCompanyIDs(ctx context.Context, params api.GetCompaniesParams) (*api.CompanyIdsResponse, error)
+
+	// CountCompanies returns the number of companies matching the list filters
+	CountCompanies(ctx context.Context, params api.GetCompaniesParams) (*api.CompanyCountResponse, error)
+
+	// ChecksumCompanies returns the count and an order-independent checksum of the companies matching the list filters
+	ChecksumCompanies(ctx context.Context, params api.GetCompaniesParams) (*api.CompanyChecksumResponse, error)
+
+	// ExportCompanies calls fn for every company matching the list filters, ignoring pagination
+	ExportCompanies(ctx context.Context, params api.GetCompaniesParams, fn func(*api.Company) error) error
+
+	// ExtractCompanies returns companies changed since a watermark, tombstones included, with the next watermark
+	ExtractCompanies(ctx context.Context, params api.ExtractCompaniesParams) (*api.ExtractResponse, error)
+
+	// GetRawCompany returns the stored company row with every column, for debugging
+	GetRawCompany(ctx context.Context, id openapi_types.UUID) (map[string]interface{}, error)
+
+	// ExplainListCompanies returns the query plan for the list query ListCompanies would run
+	ExplainListCompanies(ctx context.Context, params api.GetCompaniesParams) (string, error)
+
 	// GetCompanyByID retrieves a company by its ID
 	GetCompanyByID(ctx context.Context, id openapi_types.UUID) (*api.Company, error)
 
+	// GetCompanyWithRelations retrieves a company by its ID with the named related collections, e.g. "directors",
+	// included inline
+	GetCompanyWithRelations(ctx context.Context, id openapi_types.UUID, relations []string) (*api.Company, error)
+
+	// GetCompanyByRegistry retrieves a company by its external registry source and number
+	GetCompanyByRegistry(ctx context.Context, source, number string) (*api.Company, error)
+
 	// CreateCompany creates a new company with validation
 	CreateCompany(ctx context.Context, req api.CreateCompanyRequest) (*api.Company, error)
 
-	// DeleteCompany removes a company by its ID
+	// CreateCompanyIdempotent creates a company once per idempotency key, returning the original company with
+	// replayed set when the same request is repeated with the key
+	CreateCompanyIdempotent(ctx context.Context, key string, req api.CreateCompanyRequest) (*api.Company, bool, error)
+
+	// ImportCompaniesCSV creates companies from the rows of a CSV file, reporting the outcome of each row.
+	// A dry run reports the same outcomes but saves nothing.
+	ImportCompaniesCSV(ctx context.Context, file io.Reader, strict, dryRun bool) (*ImportResult, error)
+
+	// CreateCompanies validates every request and creates them all atomically; if any is invalid
+	// none are created and a *BatchValidationError lists the failures by index. A dry run returns the
+	// companies that would be created but saves nothing.
+	CreateCompanies(ctx context.Context, reqs []api.CreateCompanyRequest, dryRun bool) ([]api.Company, error)
+
+	// UpdateCompany replaces a company's fields with the same validation as CreateCompany. A non-nil expected
+	// date_updated makes the update conditional, failing with ErrCompanyModified if the company has changed.
+	UpdateCompany(ctx context.Context, id openapi_types.UUID, req api.CreateCompanyRequest, expected *time.Time) (*api.Company, error)
+
+	// PatchCompany updates only the fields present in req, validating the merged result as UpdateCompany would.
+	// expected is handled as by UpdateCompany.
+	PatchCompany(ctx context.Context, id openapi_types.UUID, req api.PatchCompanyRequest, expected *time.Time) (*api.Company, error)
+
+	// DeleteCompany soft-deletes a company by its ID
 	DeleteCompany(ctx context.Context, id openapi_types.UUID) error
+
+	// DeleteCompanies soft-deletes the companies with the given IDs in one transaction, reporting the IDs
+	// that matched no live company
+	DeleteCompanies(ctx context.Context, ids []openapi_types.UUID) (*api.BatchDeleteResponse, error)
+
+	// RestoreCompany undoes a soft delete
+	RestoreCompany(ctx context.Context, id openapi_types.UUID) (*api.Company, error)
+
+	// PurgeDeletedCompanies permanently deletes companies soft-deleted longer ago than the retention window
+	PurgeDeletedCompanies(ctx context.Context) (*api.PurgeResponse, error)
+
+	// TransferJurisdiction moves a company to another allowed jurisdiction, recording it as a distinct audited change
+	TransferJurisdiction(ctx context.Context, id openapi_types.UUID, jurisdiction string) (*api.Company, error)
+
+	// AdjustDirectorCount atomically adds delta, which may be negative, to a company's number_of_directors
+	AdjustDirectorCount(ctx context.Context, id openapi_types.UUID, delta int) (*api.Company, error)
+
+	// AdjustShareholderCount atomically adds delta, which may be negative, to a company's number_of_shareholders
+	AdjustShareholderCount(ctx context.Context, id openapi_types.UUID, delta int) (*api.Company, error)
+
+	// GetCompanyHistory returns a company's audit entries, oldest first, including after it is soft-deleted or purged
+	GetCompanyHistory(ctx context.Context, id openapi_types.UUID) ([]api.AuditEntry, error)
+
+	// ListDirectors returns a company's directors, oldest first
+	ListDirectors(ctx context.Context, companyID openapi_types.UUID) ([]api.Director, error)
+
+	// AddDirector adds a director to a company, keeping number_of_directors equal to the director count
+	AddDirector(ctx context.Context, companyID openapi_types.UUID, req api.CreateDirectorRequest) (*api.Director, error)
+
+	// RemoveDirector removes a company's director, keeping number_of_directors equal to the director count
+	RemoveDirector(ctx context.Context, companyID, directorID openapi_types.UUID) error
+
+	// ListShareholders returns a company's shareholders, oldest first
+	ListShareholders(ctx context.Context, companyID openapi_types.UUID) ([]api.Shareholder, error)
+
+	// AddShareholder adds a shareholder to a company, keeping number_of_shareholders equal to the shareholder count
+	// and the company's total ownership at most 100%
+	AddShareholder(ctx context.Context, companyID openapi_types.UUID, req api.CreateShareholderRequest) (*api.Shareholder, error)
+
+	// UpdateShareholder replaces a shareholder's name and ownership percentage, keeping the company's total
+	// ownership at most 100%
+	UpdateShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID, req api.CreateShareholderRequest) (*api.Shareholder, error)
+
+	// RemoveShareholder removes a company's shareholder, keeping number_of_shareholders equal to the shareholder count
+	RemoveShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID) error
+
+	// OpenSnapshot starts a consistent snapshot of the companies matching the list filters
+	OpenSnapshot(ctx context.Context, params api.GetCompaniesParams) (*api.SnapshotResponse, error)
+
+	// NextSnapshotPage returns the next page of an open snapshot, closing it after the last page
+	NextSnapshotPage(ctx context.Context, id string, limit *int) (*api.SnapshotPage, error)
+
+	// CloseSnapshot releases an open snapshot
+	CloseSnapshot(id string) error
+
+	// CloseSnapshots releases every open snapshot
+	CloseSnapshots()
+
+	// GetSharedAddressReport retrieves groups of at least minSize companies registered at the same address
+	GetSharedAddressReport(ctx context.Context, minSize int) (*api.SharedAddressReport, error)
+
+	// ListJurisdictions returns the canonical names of the allowed jurisdictions, in configured order
+	ListJurisdictions() []string
+
+	// CountCompaniesByJurisdiction returns how many live companies each jurisdiction with any has, most first
+	CountCompaniesByJurisdiction(ctx context.Context) ([]api.JurisdictionCount, error)
+
+	// CountCompaniesByNatureOfBusiness returns how many live companies each nature of business has, most first,
+	// only counting the given jurisdiction when one is set
+	CountCompaniesByNatureOfBusiness(ctx context.Context, jurisdiction *string) ([]api.NatureOfBusinessCount, error)
+
+	// ResolveJurisdiction normalizes a jurisdiction value or alias to its canonical form
+	ResolveJurisdiction(value string) *api.JurisdictionResolution
+}
+
+// Options configures optional company service behaviour
+type Options struct {
+	// Jurisdictions lists the jurisdictions companies may belong to; empty allows DefaultJurisdictions
+	Jurisdictions []string
+
+	// CanonicalizeSecCodes returns sec_code uppercased and trimmed regardless of how it was stored
+	CanonicalizeSecCodes bool
+
+	// AddressMaxLength is the maximum company_address length in characters, after whitespace is collapsed;
+	// zero disables the limit
+	AddressMaxLength int
+
+	// NatureOfBusinessMaxLength is the maximum nature_of_business length in characters; zero disables the limit
+	NatureOfBusinessMaxLength int
+
+	// TruncateNatureOfBusiness stores over-long nature_of_business truncated with a warning instead of rejecting it
+	TruncateNatureOfBusiness bool
+
+	// EnforceUniqueRegistryNumbers rejects a registry source and number already linked to another company
+	EnforceUniqueRegistryNumbers bool
+
+	// DuplicatePolicy is "reject", "warn" or "allow" (also the empty value): what a create does when a live company
+	// in the same jurisdiction has a name that normalizes the same way, e.g. "Acme Ltd" and "ACME LIMITED"
+	DuplicatePolicy string
+
+	// AllowLimitZero treats limit=0 as a count-only request that returns no rows but an accurate total
+	AllowLimitZero bool
+
+	// DefaultLimit is the page size of list requests without a limit
+	DefaultLimit int
+
+	// MaxLimit is the largest limit a list request may ask for
+	MaxLimit int
+
+	// MaxOffset is the largest offset a list request may ask for, since Postgres reads and discards every skipped
+	// row; deeper pages need cursor pagination. Zero disables the cap.
+	MaxOffset int
+
+	// MinDirectors requires number_of_directors of at least the given value for each listed jurisdiction
+	MinDirectors map[string]int
+
+	// MaxShareholders overrides the default 1000 shareholder maximum for each listed jurisdiction
+	MaxShareholders map[string]int
+
+	// MaxFilters caps the filter conditions combined in one list request, each jurisdiction value counting once; zero disables the cap
+	MaxFilters int
+
+	// AccentInsensitiveSearch matches name searches against the accent-folded search key, so "Muller" finds "Müller"
+	AccentInsensitiveSearch bool
+
+	// AddressCountryCheck maps jurisdictions to "warn" or "error": an address naming another jurisdiction's
+	// country is then returned with a warning or rejected with a 422
+	AddressCountryCheck map[string]string
+
+	// CreateDefaults supplies values for optional fields omitted on create, keyed by jurisdiction
+	CreateDefaults map[string]FieldDefaults
+
+	// MaxCreatesPerMinute caps companies created per minute across all clients, counting each element of
+	// batches and imports; creates beyond it fail with ErrCreateRateExceeded. 0 disables the cap.
+	MaxCreatesPerMinute int
+
+	// AddressRules override company_address requiredness and format per jurisdiction, e.g. accepting a
+	// registered agent reference instead of a full address
+	AddressRules map[string]AddressRule
+
+	// IdempotencyTTL is how long an idempotency key replays its create before it can be reused
+	IdempotencyTTL time.Duration
+
+	// SoftDeleteRetention is how long soft-deleted companies are kept, and so remain restorable, before
+	// PurgeDeletedCompanies may remove them
+	SoftDeleteRetention time.Duration
+
+	// MaxSnapshots caps the snapshots open at once, each holding a database connection; 0 disables snapshots
+	MaxSnapshots int
+
+	// SnapshotIdleTimeout closes a snapshot not read for this long
+	SnapshotIdleTimeout time.Duration
+
+	// SnapshotLifetime closes a snapshot this long after it was opened, however actively it is read
+	SnapshotLifetime time.Duration
+
+	// SecCodeRules are the formats a non-empty sec_code must match, by jurisdiction
+	SecCodeRules SecCodeRules
+
+	// PreCreateHook enriches or rejects create requests before validation; nil leaves them unchanged
+	PreCreateHook PreCreateHook
 }
 
 // companyService implements CompanyService
 type companyService struct {
-	repo repository.CompanyRepository
+	repo          repository.CompanyRepository
+	uncached      repository.CompanyRepository // repo without its cache, for the reads writes are checked against
+	opts          Options
+	jurisdictions jurisdictionSet
+	createLimiter *createRateLimiter
+	snapshots     *snapshotRegistry
 }
 
-// NewCompanyService creates a new company service
-func NewCompanyService(repo repository.CompanyRepository) CompanyService {
-	return &companyService{repo: repo}
+// NewCompanyService creates a new company service. Tests that should not need Postgres can pass
+// repositorytest.NewCompanyRepository() as repo, optionally wrapped in a CachingCompanyRepository as main does;
+// it behaves like the Postgres repository except that snapshots and EXPLAIN are unsupported and full-text
+// search is approximated.
+func NewCompanyService(repo repository.CompanyRepository, opts Options) CompanyService {
+	jurisdictions := newJurisdictionSet(opts.Jurisdictions)
+
+	// Key per-jurisdiction settings by canonical jurisdiction, so "uk" configures UK
+	minDirectors := make(map[string]int, len(opts.MinDirectors))
+	for jurisdiction, minimum := range opts.MinDirectors {
+		minDirectors[jurisdictions.canonical(jurisdiction)] = minimum
+	}
+	opts.MinDirectors = minDirectors
+
+	maxShareholders := make(map[string]int, len(opts.MaxShareholders))
+	for jurisdiction, limit := range opts.MaxShareholders {
+		maxShareholders[jurisdictions.canonical(jurisdiction)] = limit
+	}
+	opts.MaxShareholders = maxShareholders
+
+	addressRules := make(map[string]AddressRule, len(opts.AddressRules))
+	for jurisdiction, rule := range opts.AddressRules {
+		addressRules[jurisdictions.canonical(jurisdiction)] = rule
+	}
+	opts.AddressRules = addressRules
+
+	addressCountryCheck := make(map[string]string, len(opts.AddressCountryCheck))
+	for jurisdiction, mode := range opts.AddressCountryCheck {
+		addressCountryCheck[jurisdictions.canonical(jurisdiction)] = mode
+	}
+	opts.AddressCountryCheck = addressCountryCheck
+
+	secCodePatterns := make(map[string]*regexp.Regexp, len(opts.SecCodeRules.Jurisdictions))
+	for jurisdiction, pattern := range opts.SecCodeRules.Jurisdictions {
+		secCodePatterns[jurisdictions.canonical(jurisdiction)] = pattern
+	}
+	opts.SecCodeRules.Jurisdictions = secCodePatterns
+
+	createDefaults := make(map[string]FieldDefaults, len(opts.CreateDefaults))
+	for jurisdiction, defaults := range opts.CreateDefaults {
+		createDefaults[jurisdictions.canonical(jurisdiction)] = defaults
+	}
+	opts.CreateDefaults = createDefaults
+
+	if opts.PreCreateHook == nil {
+		opts.PreCreateHook = noopPreCreateHook{}
+	}
+
+	return &companyService{
+		repo:          repo,
+		uncached:      repository.Uncached(repo),
+		opts:          opts,
+		jurisdictions: jurisdictions,
+		createLimiter: newCreateRateLimiter(opts.MaxCreatesPerMinute),
+		snapshots:     newSnapshotRegistry(opts.MaxSnapshots, opts.SnapshotIdleTimeout, opts.SnapshotLifetime),
+	}
 }
 
 // ListCompanies retrieves companies with pagination and optional filtering
 func (s *companyService) ListCompanies(ctx context.Context, params api.GetCompaniesParams) (*api.CompaniesResponse, error) {
-	// Set default values
-	limit := 20
-	offset := 0
+	limit, offset, err := s.paginationFromParams(params)
+	if err != nil {
+		return nil, err
+	}
 
-	if params.Limit != nil {
-		if *params.Limit < 1 || *params.Limit > 100 {
-			return nil, fmt.Errorf("limit must be between 1 and 100")
-		}
-		limit = *params.Limit
+	sort, err := sortFromParams(params)
+	if err != nil {
+		return nil, err
 	}
 
-	if params.Offset != nil {
-		if *params.Offset < 0 {
-			return nil, fmt.Errorf("offset must be non-negative")
-		}
-		offset = *params.Offset
+	filter, err := s.filterFromParams(params)
+	if err != nil {
+		return nil, err
 	}
 
-	var jurisdiction *string
-	if params.Jurisdiction != nil {
-		j := string(*params.Jurisdiction)
-		jurisdiction = &j
+	includeTotal, err := includeTotalFromParams(params, limit)
+	if err != nil {
+		return nil, err
 	}
 
-	companies, total, err := s.repo.GetAll(ctx, limit, offset, jurisdiction)
+	counted := includeTotal && filter.After == nil
+	companies, total, err := s.repo.GetAll(ctx, fetchLimit(limit, counted), offset, filter, sort, includeTotal)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve companies: %w", err)
 	}
 
+	more := len(companies) > limit
+	if more {
+		companies = companies[:limit]
+	}
+
+	for i := range companies {
+		s.prepareCompany(&companies[i])
+	}
+
+	// Count-only requests still return an empty array rather than null
+	if companies == nil {
+		companies = []api.Company{}
+	}
+
 	response := &api.CompaniesResponse{
 		Companies: companies,
 		Total:     total,
 		Limit:     limit,
 		Offset:    offset,
+		HasPrev:   offset > 0,
+	}
+
+	// A zero limit only counts, so there are no pages to number. The total ignores the cursor, so after one
+	// the page numbers are unknown and the extra row fetched says whether a next page exists.
+	if limit > 0 && filter.After == nil {
+		response.TotalPages = (total + limit - 1) / limit
+		response.CurrentPage = offset/limit + 1
+		response.HasNext = offset+limit < total
+	} else if limit > 0 {
+		response.HasNext = more
+	}
+
+	// Without a total there is no page count, and the extra row fetched says whether a next page exists
+	if !includeTotal {
+		response.TotalPages = -1
+		response.HasNext = more
+	}
+
+	// A page in the default order with a successor links to it by keyset cursor
+	if sort == repository.DefaultSort && limit > 0 && response.HasNext {
+		next := encodeCursor(companies[len(companies)-1])
+		response.NextCursor = &next
+	}
+
+	if params.DebugQuery != nil && *params.DebugQuery {
+		query, argCount := s.repo.DescribeGetAll(limit, offset, filter, sort)
+		response.Debug = &api.QueryDebug{Sql: query, ArgCount: argCount}
 	}
 
 	return response, nil
 }
 
-// GetCompanyByID retrieves a company by its ID
-func (s *companyService) GetCompanyByID(ctx context.Context, id openapi_types.UUID) (*api.Company, error) {
-	company, err := s.repo.GetByID(ctx, id)
+// ListCompanyIDs retrieves only company IDs with the same pagination and filtering as ListCompanies
+func (s *companyService) ListCompanyIDs(ctx context.Context, params api.GetCompaniesParams) (*api.CompanyIdsResponse, error) {
+	limit, offset, err := s.paginationFromParams(params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve company: %w", err)
+		return nil, err
 	}
 
-	if company == nil {
-		return nil, fmt.Errorf("company not found")
+	sort, err := sortFromParams(params)
+	if err != nil {
+		return nil, err
 	}
 
-	return company, nil
+	filter, err := s.filterFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	includeTotal, err := includeTotalFromParams(params, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	counted := includeTotal && filter.After == nil
+	ids, total, err := s.repo.GetAllIDs(ctx, fetchLimit(limit, counted), offset, filter, sort, includeTotal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve company IDs: %w", err)
+	}
+
+	hasNext := offset+limit < total
+	if !counted {
+		hasNext = len(ids) > limit
+	}
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	if ids == nil {
+		ids = []openapi_types.UUID{}
+	}
+
+	return &api.CompanyIdsResponse{
+		Ids:     ids,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasNext: hasNext,
+	}, nil
 }
 
-// CreateCompany creates a new company with validation
-func (s *companyService) CreateCompany(ctx context.Context, req api.CreateCompanyRequest) (*api.Company, error) {
-	// Validate required fields
-	if err := s.validateCreateRequest(req); err != nil {
+// includeTotalFromParams reports whether a list should count every matching company, which it does unless
+// include_total=false. A count-only (limit=0) request has nothing to return without the count.
+func includeTotalFromParams(params api.GetCompaniesParams, limit int) (bool, error) {
+	if params.IncludeTotal == nil || *params.IncludeTotal {
+		return true, nil
+	}
+	if limit == 0 {
+		return false, validationErrorf("include_total=false cannot be combined with limit=0")
+	}
+	return false, nil
+}
+
+// fetchLimit is how many rows to read for a page of limit companies. Unless the total counts from the first
+// page, i.e. without a total or after a cursor, one extra row says whether a next page exists.
+func fetchLimit(limit int, counted bool) int {
+	if counted {
+		return limit
+	}
+	return limit + 1
+}
+
+// CountCompanies returns the number of companies matching the list filters
+func (s *companyService) CountCompanies(ctx context.Context, params api.GetCompaniesParams) (*api.CompanyCountResponse, error) {
+	filter, err := s.filterFromParams(params)
+	if err != nil {
 		return nil, err
 	}
 
-	company, err := s.repo.Create(ctx, req)
+	total, err := s.repo.Count(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create company: %w", err)
+		return nil, fmt.Errorf("failed to count companies: %w", err)
 	}
 
-	return company, nil
+	return &api.CompanyCountResponse{Total: total}, nil
 }
 
-// DeleteCompany removes a company by its ID
-func (s *companyService) DeleteCompany(ctx context.Context, id openapi_types.UUID) error {
-	err := s.repo.Delete(ctx, id)
+// ChecksumCompanies returns the count and an order-independent checksum of the companies matching the list
+// filters, so sync clients can detect drift against their local copy
+func (s *companyService) ChecksumCompanies(ctx context.Context, params api.GetCompaniesParams) (*api.CompanyChecksumResponse, error) {
+	filter, err := s.filterFromParams(params)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			return fmt.Errorf("company not found")
-		}
-		return fmt.Errorf("failed to delete company: %w", err)
+		return nil, err
+	}
+
+	total, checksum, err := s.repo.Checksum(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checksum companies: %w", err)
+	}
+
+	return &api.CompanyChecksumResponse{
+		Total:     total,
+		Checksum:  fmt.Sprintf("%016x", checksum),
+		Algorithm: checksumAlgorithm,
+	}, nil
+}
+
+// checksumAlgorithm names the checksum computation, so clients can tell if it ever changes
+const checksumAlgorithm = "sum64-md5-id-updated-v1"
+
+// ExportCompanies calls fn for every company matching the list filters in the default order, ignoring pagination.
+// Invalid filters are reported before fn is first called.
+func (s *companyService) ExportCompanies(ctx context.Context, params api.GetCompaniesParams, fn func(*api.Company) error) error {
+	filter, err := s.filterFromParams(params)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.StreamAll(ctx, filter, repository.DefaultSort, func(company *api.Company) error {
+		s.prepareCompany(company)
+		return fn(company)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export companies: %w", err)
 	}
 
 	return nil
 }
 
-// validateCreateRequest validates the create company request
-func (s *companyService) validateCreateRequest(req api.CreateCompanyRequest) error {
-	// Validate company name
-	if strings.TrimSpace(req.CompanyName) == "" {
-		return fmt.Errorf("company name is required")
+// ExtractCompanies returns companies changed since a watermark in change order, tombstones included.
+// The response watermark is the position of the last company returned, or the request's when nothing changed.
+func (s *companyService) ExtractCompanies(ctx context.Context, params api.ExtractCompaniesParams) (*api.ExtractResponse, error) {
+	limit := 100
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit < 1 || limit > 1000 {
+		return nil, validationErrorf("extract limit must be between 1 and 1000")
+	}
+
+	if params.SinceId != nil && params.Since == nil {
+		return nil, validationErrorf("since_id requires since")
 	}
 
-	if len(req.CompanyName) > 255 {
-		return fmt.Errorf("company name cannot exceed 255 characters")
+	companies, err := s.repo.GetChangedSince(ctx, params.Since, params.SinceId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract companies: %w", err)
 	}
 
-	// Validate company address
-	if strings.TrimSpace(req.CompanyAddress) == "" {
-		return fmt.Errorf("company address is required")
+	response := &api.ExtractResponse{
+		Companies:   companies,
+		Watermark:   params.Since,
+		WatermarkId: params.SinceId,
+	}
+	if len(companies) > 0 {
+		last := companies[len(companies)-1]
+		response.Watermark = &last.DateUpdated
+		response.WatermarkId = &last.Id
 	}
 
-	// Validate jurisdiction
-	validJurisdictions := []string{"UK", "Singapore", "Caymens"}
-	isValidJurisdiction := false
-	for _, j := range validJurisdictions {
-		if string(req.Jurisdiction) == j {
-			isValidJurisdiction = true
-			break
+	for i := range companies {
+		s.prepareCompany(&companies[i])
+	}
+
+	return response, nil
+}
+
+// ExplainListCompanies returns the query plan for the list query ListCompanies would run
+func (s *companyService) ExplainListCompanies(ctx context.Context, params api.GetCompaniesParams) (string, error) {
+	limit, offset, err := s.paginationFromParams(params)
+	if err != nil {
+		return "", err
+	}
+
+	sort, err := sortFromParams(params)
+	if err != nil {
+		return "", err
+	}
+
+	filter, err := s.filterFromParams(params)
+	if err != nil {
+		return "", err
+	}
+
+	plan, err := s.repo.ExplainGetAll(ctx, limit, offset, filter, sort)
+	if err != nil {
+		return "", fmt.Errorf("failed to explain companies query: %w", err)
+	}
+
+	return plan, nil
+}
+
+// paginationFromParams validates the list pagination parameters and applies defaults
+func (s *companyService) paginationFromParams(params api.GetCompaniesParams) (int, int, error) {
+	// Set default values
+	limit := s.opts.DefaultLimit
+	offset := 0
+
+	if params.Limit != nil {
+		minLimit := 1
+		if s.opts.AllowLimitZero {
+			minLimit = 0
+		}
+		if *params.Limit < minLimit || *params.Limit > s.opts.MaxLimit {
+			return 0, 0, validationErrorf("limit must be between %d and %d", minLimit, s.opts.MaxLimit)
 		}
+		limit = *params.Limit
 	}
-	if !isValidJurisdiction {
-		return fmt.Errorf("invalid jurisdiction: must be one of %v", validJurisdictions)
+
+	if params.Offset != nil {
+		if *params.Offset < 0 {
+			return 0, 0, validationErrorf("offset must be non-negative")
+		}
+		if s.opts.MaxOffset > 0 && *params.Offset > s.opts.MaxOffset {
+			return 0, 0, validationErrorf("offset must be at most %d; use cursor=next_cursor instead", s.opts.MaxOffset)
+		}
+		offset = *params.Offset
 	}
 
-	// Validate optional fields
-	if req.NumberOfDirectors != nil {
-		if *req.NumberOfDirectors < 1 || *req.NumberOfDirectors > 100 {
-			return fmt.Errorf("number of directors must be between 1 and 100")
+	return limit, offset, nil
+}
+
+// sortColumns is the allowlist of sort parameter values and the columns they order by
+var sortColumns = map[api.GetCompaniesParamsSort]string{
+	api.CompanyName:  "company_name",
+	api.Jurisdiction: "jurisdiction",
+	api.DateCreated:  "date_created",
+	api.DateUpdated:  "date_updated",
+}
+
+// sortFromParams validates the sort and order parameters against the allowlist.
+// Order defaults to descending for date columns and ascending otherwise. Without sort, a full-text query
+// orders by relevance.
+func sortFromParams(params api.GetCompaniesParams) (repository.Sort, error) {
+	if params.Sort == nil {
+		if params.Order != nil {
+			return repository.Sort{}, validationErrorf("order requires sort")
 		}
+		if textQuery(params) != "" {
+			return repository.Sort{Relevance: true}, nil
+		}
+		return repository.DefaultSort, nil
+	}
+
+	column, ok := sortColumns[*params.Sort]
+	if !ok {
+		return repository.Sort{}, validationErrorf("invalid sort column: %s", *params.Sort)
 	}
 
-	if req.NumberOfShareholders != nil {
-		if *req.NumberOfShareholders < 1 || *req.NumberOfShareholders > 1000 {
-			return fmt.Errorf("number of shareholders must be between 1 and 1000")
+	sort := repository.Sort{Column: column, Descending: strings.HasPrefix(column, "date_")}
+	if params.Order != nil {
+		switch *params.Order {
+		case api.Asc:
+			sort.Descending = false
+		case api.Desc:
+			sort.Descending = true
+		default:
+			return repository.Sort{}, validationErrorf("invalid sort order: %s", *params.Order)
 		}
 	}
 
-	return nil
+	return sort, nil
+}
+
+// textQuery returns the trimmed full-text query parameter, empty when absent or blank
+func textQuery(params api.GetCompaniesParams) string {
+	if params.Q == nil {
+		return ""
+	}
+	return strings.TrimSpace(*params.Q)
+}
+
+// maxRecentMinutes caps the recent_minutes quick filter at 30 days; longer windows should use created_after
+const maxRecentMinutes = 30 * 24 * 60
+
+// filterFromParams extracts the optional jurisdiction, name search, full-text query, creation window,
+// recent_minutes and cursor filters from the list parameters, resolving jurisdiction aliases to their canonical
+// value, rejecting unknown jurisdictions and ignoring a blank search or query
+func (s *companyService) filterFromParams(params api.GetCompaniesParams) (repository.Filter, error) {
+	var filter repository.Filter
+
+	if params.Jurisdiction != nil {
+		for _, value := range *params.Jurisdiction {
+			j, ok := s.jurisdictions.resolve(value)
+			if !ok {
+				return repository.Filter{}, validationErrorf("invalid jurisdiction filter: %s", value)
+			}
+			filter.Jurisdictions = append(filter.Jurisdictions, j)
+		}
+	}
+
+	if params.Search != nil {
+		if search := strings.TrimSpace(*params.Search); search != "" {
+			if s.opts.AccentInsensitiveSearch {
+				search = textfold.Fold(search)
+				filter.FoldedSearch = true
+			}
+			filter.Search = &search
+		}
+	}
+
+	if q := textQuery(params); q != "" {
+		filter.TextQuery = &q
+	}
+
+	if params.CreatedAfter != nil && params.CreatedBefore != nil && params.CreatedAfter.After(*params.CreatedBefore) {
+		return repository.Filter{}, validationErrorf("created_after cannot be later than created_before")
+	}
+	filter.CreatedAfter = params.CreatedAfter
+	filter.CreatedBefore = params.CreatedBefore
+
+	if params.RecentMinutes != nil {
+		if *params.RecentMinutes < 1 || *params.RecentMinutes > maxRecentMinutes {
+			return repository.Filter{}, validationErrorf("recent_minutes must be between 1 and %d", maxRecentMinutes)
+		}
+		filter.RecentMinutes = *params.RecentMinutes
+	}
+
+	filter.IncludeDeleted = params.IncludeDeleted != nil && *params.IncludeDeleted
+
+	if s.opts.MaxFilters > 0 && filterCount(filter) > s.opts.MaxFilters {
+		return repository.Filter{}, validationErrorf("too many filters: at most %d may be combined", s.opts.MaxFilters)
+	}
+
+	if params.Cursor != nil {
+		// Keyset positions only make sense in the default order and replace the offset
+		if params.Sort != nil {
+			return repository.Filter{}, validationErrorf("cursor cannot be combined with sort")
+		}
+		if params.Offset != nil && *params.Offset != 0 {
+			return repository.Filter{}, validationErrorf("cursor cannot be combined with offset")
+		}
+		if filter.TextQuery != nil {
+			return repository.Filter{}, validationErrorf("cursor cannot be combined with q")
+		}
+
+		after, err := decodeCursor(*params.Cursor)
+		if err != nil {
+			return repository.Filter{}, err
+		}
+		filter.After = after
+	}
+
+	return filter, nil
+}
+
+// filterCount returns the number of filter conditions applied, counting each jurisdiction value separately
+func filterCount(filter repository.Filter) int {
+	count := len(filter.Jurisdictions)
+	for _, set := range []bool{filter.Search != nil, filter.TextQuery != nil, filter.CreatedAfter != nil, filter.CreatedBefore != nil, filter.RecentMinutes > 0} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
+// GetCompanyByID retrieves a company by its ID
+func (s *companyService) GetCompanyByID(ctx context.Context, id openapi_types.UUID) (*api.Company, error) {
+	company, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve company: %w", err)
+	}
+
+	if company == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	s.prepareCompany(company)
+	return company, nil
+}
+
+// GetCompanyWithRelations retrieves a company by its ID and embeds each named relation. Relations other than
+// "directors" and "shareholders" are ignored; the handler rejects unknown names.
+func (s *companyService) GetCompanyWithRelations(ctx context.Context, id openapi_types.UUID, relations []string) (*api.Company, error) {
+	company, err := s.GetCompanyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, relation := range relations {
+		switch relation {
+		case "directors":
+			directors, err := s.ListDirectors(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			company.Directors = &directors
+		case "shareholders":
+			shareholders, err := s.ListShareholders(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			company.Shareholders = &shareholders
+		}
+	}
+
+	return company, nil
+}
+
+// GetRawCompany retrieves the stored company row with every column, for debugging
+func (s *companyService) GetRawCompany(ctx context.Context, id openapi_types.UUID) (map[string]interface{}, error) {
+	row, err := s.repo.GetRawByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve raw company: %w", err)
+	}
+
+	if row == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	return row, nil
+}
+
+// GetCompanyByRegistry retrieves a company by its external registry source and number
+func (s *companyService) GetCompanyByRegistry(ctx context.Context, source, number string) (*api.Company, error) {
+	source = strings.ToLower(strings.TrimSpace(source))
+	number = strings.ToUpper(strings.TrimSpace(number))
+	if source == "" || number == "" {
+		return nil, validationErrorf("registry source and number are required")
+	}
+
+	company, err := s.repo.GetByRegistry(ctx, source, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve company: %w", err)
+	}
+
+	if company == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	s.prepareCompany(company)
+	return company, nil
+}
+
+// CreateCompany creates a new company with validation
+func (s *companyService) CreateCompany(ctx context.Context, req api.CreateCompanyRequest) (*api.Company, error) {
+	warnings, err := s.prepareCreateRequest(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.createLimiter.reserve(1, time.Now()); err != nil {
+		return nil, err
+	}
+
+	company, err := s.repo.Create(ctx, req)
+	if err != nil {
+		return nil, writeError(err, "failed to create company")
+	}
+
+	s.prepareCompany(company)
+	if len(warnings) > 0 {
+		company.Warnings = &warnings
+	}
+	return company, nil
+}
+
+// createMany creates the companies in one transaction, or previews them when dryRun is set. Previews do not
+// count towards the creation rate.
+func (s *companyService) createMany(ctx context.Context, reqs []api.CreateCompanyRequest, dryRun bool) ([]api.Company, error) {
+	if dryRun {
+		return s.repo.PreviewCreateMany(ctx, reqs)
+	}
+
+	if err := s.createLimiter.reserve(len(reqs), time.Now()); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateMany(ctx, reqs)
+}
+
+// maxBatchSize caps the number of companies accepted by CreateCompanies
+const maxBatchSize = 100
+
+// CreateCompanies validates every request and creates them all in one transaction
+func (s *companyService) CreateCompanies(ctx context.Context, reqs []api.CreateCompanyRequest, dryRun bool) ([]api.Company, error) {
+	if len(reqs) == 0 {
+		return nil, validationErrorf("batch must contain at least one company")
+	}
+	if len(reqs) > maxBatchSize {
+		return nil, validationErrorf("batch cannot exceed %d companies", maxBatchSize)
+	}
+
+	// Validate everything up front so the response reports every invalid index, not just the first
+	batchErr := &BatchValidationError{}
+	warnings := make([][]string, len(reqs))
+	seen := newBatchKeys()
+	for i := range reqs {
+		w, err := s.prepareCreateRequest(ctx, &reqs[i])
+		err = asDuplicateRowError(err)
+		if err == nil {
+			err = s.checkBatchUnique(reqs[i], seen)
+		}
+		if err != nil {
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				return nil, err
+			}
+			batchErr.Items = append(batchErr.Items, BatchItemError{Index: i, Err: validationErr})
+			continue
+		}
+		warnings[i] = w
+	}
+	if len(batchErr.Items) > 0 {
+		return nil, batchErr
+	}
+
+	companies, err := s.createMany(ctx, reqs, dryRun)
+	if err != nil {
+		return nil, writeError(err, "failed to create companies")
+	}
+
+	for i := range companies {
+		s.prepareCompany(&companies[i])
+		if len(warnings[i]) > 0 {
+			companies[i].Warnings = &warnings[i]
+		}
+	}
+	return companies, nil
+}
+
+// prepareCreateRequest normalizes a create request, applies jurisdiction defaults and the pre-create hook,
+// validates it and applies the duplicate policy, returning warnings for truncated fields and near-duplicate names
+func (s *companyService) prepareCreateRequest(ctx context.Context, req *api.CreateCompanyRequest) ([]string, error) {
+	trimTextFields(req)
+	req.Jurisdiction = s.jurisdictions.canonical(req.Jurisdiction)
+	if defaults, ok := s.opts.CreateDefaults[req.Jurisdiction]; ok {
+		defaults.apply(req)
+	}
+	if err := s.runPreCreateHook(ctx, req); err != nil {
+		return nil, err
+	}
+	warnings := s.truncateOverlongFields(req)
+	normalizeAddress(req)
+	normalizeRegistryFields(req)
+
+	// Validate required fields
+	if err := s.validateCreateRequest(*req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRegistryUnique(ctx, *req, nil); err != nil {
+		return nil, err
+	}
+
+	duplicateWarnings, err := s.checkDuplicateName(ctx, *req)
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, duplicateWarnings...)
+
+	return append(warnings, s.addressWarnings(*req)...), nil
+}
+
+// UpdateCompany replaces a company's fields with the same validation as CreateCompany, except that counts kept
+// by director or shareholder records must be left as they are
+func (s *companyService) UpdateCompany(ctx context.Context, id openapi_types.UUID, req api.CreateCompanyRequest, expected *time.Time) (*api.Company, error) {
+	existing, err := s.uncached.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve company: %w", err)
+	}
+
+	if existing == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	trimTextFields(&req)
+	warnings := s.truncateOverlongFields(&req)
+	req.Jurisdiction = s.jurisdictions.canonical(req.Jurisdiction)
+	normalizeAddress(&req)
+	normalizeRegistryFields(&req)
+
+	synced, err := s.checkSyncedCounts(ctx, existing, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateFields(req, nil, synced); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRegistryUnique(ctx, req, &id); err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, s.addressWarnings(req)...)
+
+	company, err := s.repo.Update(ctx, id, req, expected)
+	if err != nil {
+		return nil, writeError(err, "failed to update company")
+	}
+
+	if company == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	s.prepareCompany(company)
+	if len(warnings) > 0 {
+		company.Warnings = &warnings
+	}
+	return company, nil
+}
+
+// PatchCompany updates only the fields present in req. Only those fields, and the checks that depend on them,
+// are validated against the merged result, so stored values predating a rule don't block unrelated updates.
+func (s *companyService) PatchCompany(ctx context.Context, id openapi_types.UUID, req api.PatchCompanyRequest, expected *time.Time) (*api.Company, error) {
+	existing, err := s.uncached.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve company: %w", err)
+	}
+
+	if existing == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	// Fail before validating the merge; the conditional write still catches changes made after this read
+	if expected != nil && !existing.DateUpdated.Equal(*expected) {
+		return nil, ErrCompanyModified
+	}
+
+	merged := mergePatch(existing, req)
+	trimTextFields(&merged)
+	warnings := s.truncateOverlongFields(&merged)
+	merged.Jurisdiction = s.jurisdictions.canonical(merged.Jurisdiction)
+	normalizeAddress(&merged)
+	normalizeRegistryFields(&merged)
+
+	synced, err := s.checkSyncedCounts(ctx, existing, merged)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateFields(merged, patchFields(req), synced); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRegistryUnique(ctx, merged, &id); err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, s.addressWarnings(merged)...)
+
+	// Persist the normalized values, but only for the fields the caller sent
+	if req.CompanyName != nil {
+		req.CompanyName = &merged.CompanyName
+	}
+	if req.Jurisdiction != nil {
+		req.Jurisdiction = &merged.Jurisdiction
+	}
+	if req.CompanyAddress != nil {
+		req.CompanyAddress = &merged.CompanyAddress
+	}
+	if req.NatureOfBusiness != nil {
+		req.NatureOfBusiness = merged.NatureOfBusiness
+	}
+	if req.SecCode != nil {
+		req.SecCode = merged.SecCode
+	}
+	if req.RegistrySource != nil {
+		req.RegistrySource = merged.RegistrySource
+	}
+	if req.RegistryNumber != nil {
+		req.RegistryNumber = merged.RegistryNumber
+	}
+
+	company, err := s.repo.Patch(ctx, id, req, expected)
+	if err != nil {
+		return nil, writeError(err, "failed to update company")
+	}
+
+	if company == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	s.prepareCompany(company)
+	if len(warnings) > 0 {
+		company.Warnings = &warnings
+	}
+	return company, nil
+}
+
+// mergePatch overlays the fields present in a patch onto an existing company
+func mergePatch(existing *api.Company, patch api.PatchCompanyRequest) api.CreateCompanyRequest {
+	merged := api.CreateCompanyRequest{
+		Jurisdiction:         existing.Jurisdiction,
+		CompanyName:          existing.CompanyName,
+		CompanyAddress:       existing.CompanyAddress,
+		NatureOfBusiness:     existing.NatureOfBusiness,
+		NumberOfDirectors:    existing.NumberOfDirectors,
+		NumberOfShareholders: existing.NumberOfShareholders,
+		SecCode:              existing.SecCode,
+		RegistrySource:       existing.RegistrySource,
+		RegistryNumber:       existing.RegistryNumber,
+	}
+
+	if patch.Jurisdiction != nil {
+		merged.Jurisdiction = *patch.Jurisdiction
+	}
+	if patch.CompanyName != nil {
+		merged.CompanyName = *patch.CompanyName
+	}
+	if patch.CompanyAddress != nil {
+		merged.CompanyAddress = *patch.CompanyAddress
+	}
+	if patch.NatureOfBusiness != nil {
+		merged.NatureOfBusiness = patch.NatureOfBusiness
+	}
+	if patch.NumberOfDirectors != nil {
+		merged.NumberOfDirectors = patch.NumberOfDirectors
+	}
+	if patch.NumberOfShareholders != nil {
+		merged.NumberOfShareholders = patch.NumberOfShareholders
+	}
+	if patch.SecCode != nil {
+		merged.SecCode = patch.SecCode
+	}
+	if patch.RegistrySource != nil {
+		merged.RegistrySource = patch.RegistrySource
+	}
+	if patch.RegistryNumber != nil {
+		merged.RegistryNumber = patch.RegistryNumber
+	}
+
+	return merged
+}
+
+// patchFields returns the JSON names of the fields present in a patch
+func patchFields(patch api.PatchCompanyRequest) fieldSet {
+	present := map[string]bool{
+		"jurisdiction":           patch.Jurisdiction != nil,
+		"company_name":           patch.CompanyName != nil,
+		"company_address":        patch.CompanyAddress != nil,
+		"nature_of_business":     patch.NatureOfBusiness != nil,
+		"number_of_directors":    patch.NumberOfDirectors != nil,
+		"number_of_shareholders": patch.NumberOfShareholders != nil,
+		"sec_code":               patch.SecCode != nil,
+		"registry_source":        patch.RegistrySource != nil,
+		"registry_number":        patch.RegistryNumber != nil,
+	}
+
+	fields := fieldSet{}
+	for field, ok := range present {
+		if ok {
+			fields[field] = true
+		}
+	}
+	return fields
+}
+
+// DeleteCompany removes a company by its ID
+func (s *companyService) DeleteCompany(ctx context.Context, id openapi_types.UUID) error {
+	err := s.repo.Delete(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCompanyNotFound
+		}
+		return fmt.Errorf("failed to delete company: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCompanies soft-deletes up to maxBatchSize companies in one transaction, ignoring repeated IDs, and
+// reports how many were deleted and which IDs matched no live company
+func (s *companyService) DeleteCompanies(ctx context.Context, ids []openapi_types.UUID) (*api.BatchDeleteResponse, error) {
+	if len(ids) == 0 {
+		return nil, validationErrorf("batch must contain at least one company ID")
+	}
+	if len(ids) > maxBatchSize {
+		return nil, validationErrorf("batch cannot exceed %d companies", maxBatchSize)
+	}
+
+	seen := make(map[openapi_types.UUID]bool, len(ids))
+	unique := make([]openapi_types.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	deleted, err := s.repo.DeleteMany(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete companies: %w", err)
+	}
+
+	for _, id := range deleted {
+		delete(seen, id)
+	}
+	notFound := []openapi_types.UUID{}
+	for _, id := range unique {
+		if seen[id] {
+			notFound = append(notFound, id)
+		}
+	}
+
+	return &api.BatchDeleteResponse{Deleted: len(deleted), NotFound: notFound}, nil
+}
+
+// RestoreCompany undoes a soft delete, returning ErrCompanyNotFound if no deleted company has the ID
+func (s *companyService) RestoreCompany(ctx context.Context, id openapi_types.UUID) (*api.Company, error) {
+	company, err := s.repo.Restore(ctx, id)
+	if err != nil {
+		return nil, writeError(err, "failed to restore company")
+	}
+
+	if company == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	s.prepareCompany(company)
+	return company, nil
+}
+
+// PurgeDeletedCompanies permanently deletes companies soft-deleted before now minus SoftDeleteRetention,
+// reporting how many were deleted and the cutoff used
+func (s *companyService) PurgeDeletedCompanies(ctx context.Context) (*api.PurgeResponse, error) {
+	cutoff := time.Now().UTC().Add(-s.opts.SoftDeleteRetention)
+	purged, err := s.repo.PurgeDeleted(ctx, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge deleted companies: %w", err)
+	}
+
+	return &api.PurgeResponse{Purged: purged, Cutoff: cutoff}, nil
+}
+
+// TransferJurisdiction changes only a company's jurisdiction. The target is validated as a PATCH of jurisdiction
+// would be, re-checking the stored fields whose rules depend on it. It returns ErrCompanyNotFound for a missing
+// company and ErrSameJurisdiction if the company is already in the target jurisdiction.
+func (s *companyService) TransferJurisdiction(ctx context.Context, id openapi_types.UUID, jurisdiction string) (*api.Company, error) {
+	existing, err := s.uncached.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve company: %w", err)
+	}
+
+	if existing == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	merged := mergePatch(existing, api.PatchCompanyRequest{Jurisdiction: &jurisdiction})
+	merged.Jurisdiction = s.jurisdictions.canonical(merged.Jurisdiction)
+	normalizeRegistryFields(&merged)
+
+	if err := s.validateFields(merged, fieldSet{"jurisdiction": true}, nil); err != nil {
+		return nil, err
+	}
+
+	if merged.Jurisdiction == existing.Jurisdiction {
+		return nil, ErrSameJurisdiction
+	}
+
+	company, err := s.repo.TransferJurisdiction(ctx, id, merged.Jurisdiction)
+	if err != nil {
+		return nil, writeError(err, "failed to transfer company")
+	}
+
+	if company == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	s.prepareCompany(company)
+	return company, nil
+}
+
+// GetCompanyHistory returns a company's audit entries, oldest first, including after it is soft-deleted or purged
+func (s *companyService) GetCompanyHistory(ctx context.Context, id openapi_types.UUID) ([]api.AuditEntry, error) {
+	entries, err := s.repo.GetHistory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company history: %w", err)
+	}
+
+	if entries == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	return entries, nil
+}
+
+// GetSharedAddressReport retrieves every group of at least minSize companies registered at the same address;
+// the report is not paginated
+func (s *companyService) GetSharedAddressReport(ctx context.Context, minSize int) (*api.SharedAddressReport, error) {
+	if minSize < 2 {
+		return nil, validationErrorf("min must be at least 2")
+	}
+
+	groups, err := s.repo.GetSharedAddressGroups(ctx, minSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve shared address groups: %w", err)
+	}
+
+	for i := range groups {
+		for j := range groups[i].Companies {
+			s.prepareCompany(&groups[i].Companies[j])
+		}
+	}
+
+	return &api.SharedAddressReport{Groups: groups}, nil
+}
+
+// ListJurisdictions returns the canonical names of the allowed jurisdictions, in configured order. The slice is
+// a copy, so callers cannot change the allowlist.
+func (s *companyService) ListJurisdictions() []string {
+	return append([]string(nil), s.jurisdictions.names...)
+}
+
+// CountCompaniesByJurisdiction returns how many live companies each jurisdiction with any has, most first. Unlike
+// ListJurisdictions it reflects stored data, so allowed jurisdictions without companies are absent.
+func (s *companyService) CountCompaniesByJurisdiction(ctx context.Context) ([]api.JurisdictionCount, error) {
+	counts, err := s.repo.CountByJurisdiction(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count companies by jurisdiction: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CountCompaniesByNatureOfBusiness returns how many live companies each nature of business has, most first. A
+// jurisdiction scopes the counts, resolving aliases like the list filter and rejecting unknown values; a blank
+// one counts every jurisdiction.
+func (s *companyService) CountCompaniesByNatureOfBusiness(ctx context.Context, jurisdiction *string) ([]api.NatureOfBusinessCount, error) {
+	if jurisdiction != nil && strings.TrimSpace(*jurisdiction) == "" {
+		jurisdiction = nil
+	}
+	if jurisdiction != nil {
+		canonical, ok := s.jurisdictions.resolve(*jurisdiction)
+		if !ok {
+			return nil, validationErrorf("invalid jurisdiction filter: %s", *jurisdiction)
+		}
+		jurisdiction = &canonical
+	}
+
+	counts, err := s.repo.CountByNatureOfBusiness(ctx, jurisdiction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count companies by nature of business: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ResolveJurisdiction normalizes a jurisdiction value or alias to its canonical form
+func (s *companyService) ResolveJurisdiction(value string) *api.JurisdictionResolution {
+	canonical, ok := s.jurisdictions.resolve(value)
+	if !ok {
+		return &api.JurisdictionResolution{Matched: false}
+	}
+
+	return &api.JurisdictionResolution{
+		Canonical: &canonical,
+		Matched:   true,
+	}
+}
+
+// prepareCompany applies response-side normalization to a company read from the repository
+func (s *companyService) prepareCompany(company *api.Company) {
+	// Rows written before the Cayman Islands spelling fix may still read "Caymens"
+	company.Jurisdiction = s.jurisdictions.canonical(company.Jurisdiction)
+
+	if s.opts.CanonicalizeSecCodes && company.SecCode != nil {
+		canonical := CanonicalSecCode(*company.SecCode)
+		company.SecCode = &canonical
+	}
+}
+
+// CanonicalSecCode returns the canonical (trimmed, uppercase) form of a SEC code
+func CanonicalSecCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// fieldSet holds the JSON names of the request fields to validate; a nil set means every field
+type fieldSet map[string]bool
+
+// has reports whether any of the fields is to be validated
+func (f fieldSet) has(fields ...string) bool {
+	if f == nil {
+		return true
+	}
+	for _, field := range fields {
+		if f[field] {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCreateRequest validates the create company request, reporting every field violation at once
+func (s *companyService) validateCreateRequest(req api.CreateCompanyRequest) error {
+	return s.validateFields(req, nil, nil)
+}
+
+// validateFields runs the checks of validateCreateRequest that involve at least one of the given fields,
+// reporting every violation at once. Checks spanning several fields, such as a jurisdiction's address rule,
+// run when any of their fields is included. The count rules skip the counts in synced, which records keep.
+func (s *companyService) validateFields(req api.CreateCompanyRequest, fields, synced fieldSet) error {
+	var violations fieldErrors
+
+	if fields.has("company_name") {
+		checkCompanyName(req, &violations)
+	}
+
+	if fields.has("company_address") {
+		s.checkAddressLength(req, &violations)
+	}
+
+	// Validate company address, per jurisdiction where an address rule is configured
+	checkAddress := fields.has("company_address", "jurisdiction")
+	var agentAddress bool
+	if checkAddress {
+		agentAddress = s.checkAddress(req, &violations)
+	}
+
+	// Validate jurisdiction
+	canonical, isValidJurisdiction := s.jurisdictions.resolve(req.Jurisdiction)
+	isValidJurisdiction = isValidJurisdiction && canonical == req.Jurisdiction
+	if !isValidJurisdiction && fields.has("jurisdiction") {
+		violations.add("jurisdiction", "invalid jurisdiction: must be one of %v", s.jurisdictions.names)
+	}
+
+	s.checkOptionalFields(req, fields, synced, &violations)
+
+	// The remaining checks depend on a known jurisdiction and a present address
+	if isValidJurisdiction {
+		// Agent references name no country to check
+		if checkAddress && strings.TrimSpace(req.CompanyAddress) != "" && !agentAddress {
+			violations.addErr(s.checkAddressCountry(req))
+		}
+
+		if fields.has("number_of_directors", "jurisdiction") && !synced["number_of_directors"] {
+			s.checkMinDirectors(req, &violations)
+		}
+
+		if fields.has("sec_code", "jurisdiction") {
+			s.checkSecCode(req, &violations)
+		}
+
+		if fields.has("registry_source", "registry_number", "jurisdiction") {
+			violations.addErr(validateRegistryFields(req))
+		}
+	}
+
+	return violations.err()
+}
+
+// checkCompanyName records a violation when the company name is blank or too long
+func checkCompanyName(req api.CreateCompanyRequest, violations *fieldErrors) {
+	if strings.TrimSpace(req.CompanyName) == "" {
+		violations.add("company_name", "company name is required")
+	} else if len(req.CompanyName) > 255 {
+		violations.add("company_name", "company name cannot exceed 255 characters")
+	}
+}
+
+// checkMinDirectors records a violation when the jurisdiction requires more directors than the request has
+func (s *companyService) checkMinDirectors(req api.CreateCompanyRequest, violations *fieldErrors) {
+	minimum, ok := s.opts.MinDirectors[req.Jurisdiction]
+	if !ok {
+		return
+	}
+
+	if req.NumberOfDirectors == nil {
+		violations.add("number_of_directors", "number of directors is required for %s", req.Jurisdiction)
+	} else if *req.NumberOfDirectors < minimum {
+		violations.add("number_of_directors", "%s requires at least %d directors", req.Jurisdiction, minimum)
+	}
+}
+
+// validateOptionalFields checks the bounds of the optional numeric and free-text fields
+func (s *companyService) validateOptionalFields(req api.CreateCompanyRequest) error {
+	var violations fieldErrors
+	s.checkOptionalFields(req, nil, nil, &violations)
+	return violations.err()
+}
+
+// checkOptionalFields records a violation for each optional numeric or free-text field in fields, and not in synced,
+// that is out of bounds
+func (s *companyService) checkOptionalFields(req api.CreateCompanyRequest, fields, synced fieldSet, violations *fieldErrors) {
+	if fields.has("nature_of_business") && req.NatureOfBusiness != nil && s.opts.NatureOfBusinessMaxLength > 0 {
+		if utf8.RuneCountInString(*req.NatureOfBusiness) > s.opts.NatureOfBusinessMaxLength {
+			violations.add("nature_of_business", "nature of business cannot exceed %d characters", s.opts.NatureOfBusinessMaxLength)
+		}
+	}
+
+	if fields.has("number_of_directors") && !synced["number_of_directors"] && req.NumberOfDirectors != nil {
+		if *req.NumberOfDirectors < 1 || *req.NumberOfDirectors > 100 {
+			violations.add("number_of_directors", "number of directors must be between 1 and 100")
+		}
+	}
+
+	// The shareholder limit is per jurisdiction
+	if fields.has("number_of_shareholders", "jurisdiction") && !synced["number_of_shareholders"] && req.NumberOfShareholders != nil {
+		if limit, ok := s.opts.MaxShareholders[req.Jurisdiction]; ok {
+			if *req.NumberOfShareholders < 1 || *req.NumberOfShareholders > limit {
+				violations.add("number_of_shareholders", "number of shareholders for %s must be between 1 and %d", req.Jurisdiction, limit)
+			}
+		} else if *req.NumberOfShareholders < 1 || *req.NumberOfShareholders > 1000 {
+			violations.add("number_of_shareholders", "number of shareholders must be between 1 and 1000")
+		}
+	}
+}
+
+// trimTextFields strips surrounding whitespace from the free-text fields before they are validated or stored,
+// so " UK " is an allowed jurisdiction and padded names are not saved; normalizeAddress and
+// normalizeRegistryFields then go further for their fields
+func trimTextFields(req *api.CreateCompanyRequest) {
+	req.CompanyName = strings.TrimSpace(req.CompanyName)
+	req.CompanyAddress = strings.TrimSpace(req.CompanyAddress)
+	req.Jurisdiction = strings.TrimSpace(req.Jurisdiction)
+	for _, field := range []**string{&req.NatureOfBusiness, &req.SecCode} {
+		if *field != nil {
+			trimmed := strings.TrimSpace(**field)
+			*field = &trimmed
+		}
+	}
+}
+
+// truncateOverlongFields shortens fields configured for truncation and returns a warning for each one changed
+func (s *companyService) truncateOverlongFields(req *api.CreateCompanyRequest) []string {
+	var warnings []string
+
+	maxLength := s.opts.NatureOfBusinessMaxLength
+	if s.opts.TruncateNatureOfBusiness && maxLength > 0 && req.NatureOfBusiness != nil {
+		if runes := []rune(*req.NatureOfBusiness); len(runes) > maxLength {
+			truncated := string(runes[:maxLength])
+			req.NatureOfBusiness = &truncated
+			warnings = append(warnings, fmt.Sprintf("nature_of_business truncated to %d characters", maxLength))
+		}
+	}
+
+	return warnings
 }
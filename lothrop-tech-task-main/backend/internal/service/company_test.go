@@ -0,0 +1,2069 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/api"
+	"backend/internal/repository"
+	"backend/internal/repository/repositorytest"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"go.uber.org/zap"
+)
+
+// newTestService returns a service with the default configuration over an empty in-memory repository whose
+// clock advances a second on every write, so companies are created in a known order
+func newTestService(t *testing.T) (CompanyService, *repositorytest.CompanyRepository) {
+	t.Helper()
+	return newTestServiceWith(t, func(*Options) {})
+}
+
+// newTestServiceWith is newTestService with the default options changed by configure
+func newTestServiceWith(t *testing.T, configure func(opts *Options)) (CompanyService, *repositorytest.CompanyRepository) {
+	t.Helper()
+
+	repo := repositorytest.NewCompanyRepository()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo.Now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	rules, err := ParseSecCodeRules(`^[A-Z]{2,4}[0-9]+$`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{
+		Jurisdictions:             []string{"UK", "Singapore", "Cayman Islands"},
+		DefaultLimit:              20,
+		MaxLimit:                  100,
+		MaxOffset:                 10000,
+		SecCodeRules:              rules,
+		IdempotencyTTL:            time.Hour,
+		SoftDeleteRetention:       time.Hour,
+		AddressMaxLength:          500,
+		NatureOfBusinessMaxLength: 500,
+	}
+	configure(&opts)
+	return NewCompanyService(repo, opts), repo
+}
+
+// createCompanies creates one company per name and jurisdiction pair, in order
+func createCompanies(t *testing.T, svc CompanyService, companies ...[2]string) []*api.Company {
+	t.Helper()
+
+	created := make([]*api.Company, 0, len(companies))
+	for _, c := range companies {
+		company, err := svc.CreateCompany(context.Background(), api.CreateCompanyRequest{
+			CompanyName:    c[0],
+			CompanyAddress: "1 High Street",
+			Jurisdiction:   c[1],
+		})
+		if err != nil {
+			t.Fatalf("creating %s: %v", c[0], err)
+		}
+		created = append(created, company)
+	}
+	return created
+}
+
+// fieldError returns the field a validation error names, or "" if err is not one
+func fieldError(err error) string {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return ""
+	}
+	return validationErr.Field
+}
+
+// names returns the names of the companies in a list response, in order
+func names(resp *api.CompaniesResponse) []string {
+	names := make([]string, len(resp.Companies))
+	for i, company := range resp.Companies {
+		names[i] = company.CompanyName
+	}
+	return names
+}
+
+func equalNames(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestListCompaniesFiltering(t *testing.T) {
+	svc, _ := newTestService(t)
+	createCompanies(t, svc,
+		[2]string{"Acme Ltd", "UK"},
+		[2]string{"Lion City Pte", "Singapore"},
+		[2]string{"Acme Asia Pte", "Singapore"},
+		[2]string{"Island Holdings", "Cayman Islands"},
+	)
+
+	tests := []struct {
+		name   string
+		params api.GetCompaniesParams
+		want   []string
+	}{
+		{"no filters", api.GetCompaniesParams{}, []string{"Island Holdings", "Acme Asia Pte", "Lion City Pte", "Acme Ltd"}},
+		{"jurisdiction", api.GetCompaniesParams{Jurisdiction: &[]string{"Singapore"}}, []string{"Acme Asia Pte", "Lion City Pte"}},
+		{"jurisdiction alias", api.GetCompaniesParams{Jurisdiction: &[]string{"Caymens"}}, []string{"Island Holdings"}},
+		{"several jurisdictions", api.GetCompaniesParams{Jurisdiction: &[]string{"uk", "Cayman Islands"}}, []string{"Island Holdings", "Acme Ltd"}},
+		{"search", api.GetCompaniesParams{Search: ptr("acme")}, []string{"Acme Asia Pte", "Acme Ltd"}},
+		{"search and jurisdiction", api.GetCompaniesParams{Search: ptr("acme"), Jurisdiction: &[]string{"UK"}}, []string{"Acme Ltd"}},
+		{"blank search", api.GetCompaniesParams{Search: ptr("  ")}, []string{"Island Holdings", "Acme Asia Pte", "Lion City Pte", "Acme Ltd"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.ListCompanies(context.Background(), tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(resp); !equalNames(got, tt.want) {
+				t.Errorf("companies = %v, want %v", got, tt.want)
+			}
+			if resp.Total != len(tt.want) {
+				t.Errorf("total = %d, want %d", resp.Total, len(tt.want))
+			}
+		})
+	}
+
+	t.Run("unknown jurisdiction", func(t *testing.T) {
+		_, err := svc.ListCompanies(context.Background(), api.GetCompaniesParams{Jurisdiction: &[]string{"Atlantis"}})
+		if !isValidationError(err) {
+			t.Errorf("err = %v, want a validation error", err)
+		}
+	})
+}
+
+func TestListCompaniesPagination(t *testing.T) {
+	svc, _ := newTestService(t)
+	createCompanies(t, svc,
+		[2]string{"One", "UK"},
+		[2]string{"Two", "UK"},
+		[2]string{"Three", "UK"},
+		[2]string{"Four", "UK"},
+		[2]string{"Five", "UK"},
+	)
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		want          []string
+		page, pages   int
+		next, prev    bool
+	}{
+		{"first page", 2, 0, []string{"Five", "Four"}, 1, 3, true, false},
+		{"middle page", 2, 2, []string{"Three", "Two"}, 2, 3, true, true},
+		{"last page", 2, 4, []string{"One"}, 3, 3, false, true},
+		{"past the end", 2, 10, []string{}, 6, 3, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.ListCompanies(context.Background(), api.GetCompaniesParams{Limit: &tt.limit, Offset: &tt.offset})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(resp); !equalNames(got, tt.want) {
+				t.Errorf("companies = %v, want %v", got, tt.want)
+			}
+			if resp.Total != 5 || resp.Limit != tt.limit || resp.Offset != tt.offset {
+				t.Errorf("total, limit, offset = %d, %d, %d, want 5, %d, %d", resp.Total, resp.Limit, resp.Offset, tt.limit, tt.offset)
+			}
+			if resp.CurrentPage != tt.page || resp.TotalPages != tt.pages {
+				t.Errorf("page %d of %d, want %d of %d", resp.CurrentPage, resp.TotalPages, tt.page, tt.pages)
+			}
+			if resp.HasNext != tt.next || resp.HasPrev != tt.prev {
+				t.Errorf("has_next, has_prev = %v, %v, want %v, %v", resp.HasNext, resp.HasPrev, tt.next, tt.prev)
+			}
+		})
+	}
+
+	t.Run("default limit", func(t *testing.T) {
+		resp, err := svc.ListCompanies(context.Background(), api.GetCompaniesParams{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Limit != 20 {
+			t.Errorf("limit = %d, want the default 20", resp.Limit)
+		}
+	})
+
+	for name, params := range map[string]api.GetCompaniesParams{
+		"limit over max":  {Limit: ptr(101)},
+		"negative offset": {Offset: ptr(-1)},
+		"offset over max": {Offset: ptr(10001)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := svc.ListCompanies(context.Background(), params); !isValidationError(err) {
+				t.Errorf("err = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestListCompaniesCursorPagination(t *testing.T) {
+	svc, _ := newTestService(t)
+	createCompanies(t, svc,
+		[2]string{"One", "UK"},
+		[2]string{"Two", "UK"},
+		[2]string{"Three", "UK"},
+		[2]string{"Four", "UK"},
+		[2]string{"Five", "UK"},
+		[2]string{"Six", "UK"},
+	)
+	ctx := context.Background()
+
+	for _, limit := range []int{2, 4} {
+		t.Run("limit "+strconv.Itoa(limit), func(t *testing.T) {
+			var walked []string
+			params := api.GetCompaniesParams{Limit: &limit}
+			for page := 1; ; page++ {
+				resp, err := svc.ListCompanies(ctx, params)
+				if err != nil {
+					t.Fatal(err)
+				}
+				walked = append(walked, names(resp)...)
+
+				ids, err := svc.ListCompanyIDs(ctx, params)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(ids.Ids) != len(resp.Companies) || ids.HasNext != resp.HasNext {
+					t.Errorf("page %d: %d ids with has_next %v, want %d with %v", page, len(ids.Ids), ids.HasNext, len(resp.Companies), resp.HasNext)
+				}
+
+				if params.Cursor != nil && (resp.CurrentPage != 0 || resp.TotalPages != 0) {
+					t.Errorf("page %d: page %d of %d after a cursor, want both unset", page, resp.CurrentPage, resp.TotalPages)
+				}
+				if resp.HasNext != (resp.NextCursor != nil) {
+					t.Fatalf("page %d: has_next %v with next_cursor %v", page, resp.HasNext, resp.NextCursor)
+				}
+				if !resp.HasNext {
+					break
+				}
+				if page > 6 {
+					t.Fatal("cursor pages never end")
+				}
+				params.Cursor = resp.NextCursor
+			}
+
+			if want := []string{"Six", "Five", "Four", "Three", "Two", "One"}; !equalNames(walked, want) {
+				t.Errorf("walked %v, want %v", walked, want)
+			}
+		})
+	}
+}
+
+func TestListCompaniesSorting(t *testing.T) {
+	svc, _ := newTestService(t)
+	createCompanies(t, svc,
+		[2]string{"Bravo", "UK"},
+		[2]string{"Alpha", "Singapore"},
+		[2]string{"Charlie", "Cayman Islands"},
+	)
+
+	tests := []struct {
+		name  string
+		sort  api.GetCompaniesParamsSort
+		order *api.GetCompaniesParamsOrder
+		want  []string
+	}{
+		{"name defaults to ascending", api.CompanyName, nil, []string{"Alpha", "Bravo", "Charlie"}},
+		{"name descending", api.CompanyName, ptr(api.Desc), []string{"Charlie", "Bravo", "Alpha"}},
+		{"date created defaults to descending", api.DateCreated, nil, []string{"Charlie", "Alpha", "Bravo"}},
+		{"date created ascending", api.DateCreated, ptr(api.Asc), []string{"Bravo", "Alpha", "Charlie"}},
+		{"jurisdiction", api.Jurisdiction, nil, []string{"Charlie", "Alpha", "Bravo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.ListCompanies(context.Background(), api.GetCompaniesParams{Sort: &tt.sort, Order: tt.order})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(resp); !equalNames(got, tt.want) {
+				t.Errorf("companies = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	svc, _ := newTestService(t)
+	created := createCompanies(t, svc, [2]string{"Kept", "UK"}, [2]string{"Deleted", "UK"})
+	ctx := context.Background()
+	id := created[1].Id
+
+	if err := svc.DeleteCompany(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.GetCompanyByID(ctx, id); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("get after delete: err = %v, want ErrCompanyNotFound", err)
+	}
+	resp, err := svc.ListCompanies(ctx, api.GetCompaniesParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(resp); !equalNames(got, []string{"Kept"}) || resp.Total != 1 {
+		t.Errorf("list after delete = %v (total %d), want [Kept]", got, resp.Total)
+	}
+
+	resp, err = svc.ListCompanies(ctx, api.GetCompaniesParams{IncludeDeleted: ptr(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(resp); !equalNames(got, []string{"Deleted", "Kept"}) {
+		t.Errorf("list including deleted = %v, want [Deleted Kept]", got)
+	} else if resp.Companies[0].DeletedAt == nil {
+		t.Error("deleted company listed without deleted_at")
+	}
+
+	if err := svc.DeleteCompany(ctx, id); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("second delete: err = %v, want ErrCompanyNotFound", err)
+	}
+
+	restored, err := svc.RestoreCompany(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("restored company has deleted_at %v", restored.DeletedAt)
+	}
+	if _, err := svc.GetCompanyByID(ctx, id); err != nil {
+		t.Errorf("get after restore: %v", err)
+	}
+	resp, err = svc.ListCompanies(ctx, api.GetCompaniesParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(resp); !equalNames(got, []string{"Deleted", "Kept"}) {
+		t.Errorf("list after restore = %v, want [Deleted Kept]", got)
+	}
+
+	if _, err := svc.RestoreCompany(ctx, id); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("restoring a live company: err = %v, want ErrCompanyNotFound", err)
+	}
+}
+
+func TestTrimTextFields(t *testing.T) {
+	tests := []struct {
+		field string
+		set   func(req *api.CreateCompanyRequest, value string)
+		get   func(req *api.CreateCompanyRequest) string
+	}{
+		{"company_name",
+			func(req *api.CreateCompanyRequest, v string) { req.CompanyName = v },
+			func(req *api.CreateCompanyRequest) string { return req.CompanyName }},
+		{"company_address",
+			func(req *api.CreateCompanyRequest, v string) { req.CompanyAddress = v },
+			func(req *api.CreateCompanyRequest) string { return req.CompanyAddress }},
+		{"jurisdiction",
+			func(req *api.CreateCompanyRequest, v string) { req.Jurisdiction = v },
+			func(req *api.CreateCompanyRequest) string { return req.Jurisdiction }},
+		{"nature_of_business",
+			func(req *api.CreateCompanyRequest, v string) { req.NatureOfBusiness = &v },
+			func(req *api.CreateCompanyRequest) string { return *req.NatureOfBusiness }},
+		{"sec_code",
+			func(req *api.CreateCompanyRequest, v string) { req.SecCode = &v },
+			func(req *api.CreateCompanyRequest) string { return *req.SecCode }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			var req api.CreateCompanyRequest
+			tt.set(&req, " \t value \n")
+			trimTextFields(&req)
+			if got := tt.get(&req); got != "value" {
+				t.Errorf("%s = %q, want %q", tt.field, got, "value")
+			}
+		})
+	}
+
+	t.Run("absent optional fields stay absent", func(t *testing.T) {
+		var req api.CreateCompanyRequest
+		trimTextFields(&req)
+		if req.NatureOfBusiness != nil || req.SecCode != nil {
+			t.Errorf("nature_of_business, sec_code = %v, %v, want nil", req.NatureOfBusiness, req.SecCode)
+		}
+	})
+}
+
+func TestCreateCompanyTrimsBeforeValidating(t *testing.T) {
+	valid := func() api.CreateCompanyRequest {
+		return api.CreateCompanyRequest{
+			CompanyName:    "Example Corp Ltd",
+			CompanyAddress: "1 High Street",
+			Jurisdiction:   "UK",
+		}
+	}
+
+	tests := []struct {
+		field  string
+		change func(req *api.CreateCompanyRequest)
+		check  func(company *api.Company) bool
+	}{
+		{"company_name at the length limit",
+			func(req *api.CreateCompanyRequest) { req.CompanyName = "  " + strings.Repeat("a", 255) + "  " },
+			func(c *api.Company) bool { return c.CompanyName == strings.Repeat("a", 255) }},
+		{"company_address",
+			func(req *api.CreateCompanyRequest) { req.CompanyAddress = "  1 High Street  " },
+			func(c *api.Company) bool { return c.CompanyAddress == "1 High Street" }},
+		{"jurisdiction against the allowlist",
+			func(req *api.CreateCompanyRequest) { req.Jurisdiction = " Singapore " },
+			func(c *api.Company) bool { return c.Jurisdiction == "Singapore" }},
+		{"nature_of_business at the length limit",
+			func(req *api.CreateCompanyRequest) { req.NatureOfBusiness = ptr(" " + strings.Repeat("b", 500) + " ") },
+			func(c *api.Company) bool { return *c.NatureOfBusiness == strings.Repeat("b", 500) }},
+		{"sec_code against its format",
+			func(req *api.CreateCompanyRequest) { req.SecCode = ptr(" SEC123 ") },
+			func(c *api.Company) bool { return *c.SecCode == "SEC123" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			svc, _ := newTestService(t)
+			req := valid()
+			tt.change(&req)
+
+			company, err := svc.CreateCompany(context.Background(), req)
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			if !tt.check(company) {
+				t.Errorf("stored company %+v was not trimmed", company)
+			}
+		})
+	}
+
+	t.Run("whitespace-only name is empty", func(t *testing.T) {
+		svc, _ := newTestService(t)
+		req := valid()
+		req.CompanyName = "   "
+		if _, err := svc.CreateCompany(context.Background(), req); !isValidationError(err) {
+			t.Errorf("err = %v, want a validation error", err)
+		}
+	})
+}
+
+func TestCountCompaniesByNatureOfBusiness(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	for i, c := range []struct {
+		jurisdiction string
+		nature       *string
+	}{
+		{"UK", ptr("Software")},
+		{"UK", ptr("Software")},
+		{"Singapore", ptr("Software")},
+		{"UK", ptr("Shipping")},
+		{"Singapore", ptr("Banking")},
+		{"UK", nil},
+		{"Singapore", ptr("   ")},
+		{"UK", ptr("Deleted Ltd's trade")},
+	} {
+		company, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{
+			CompanyName:      "Company " + string(rune('A'+i)),
+			CompanyAddress:   "1 High Street",
+			Jurisdiction:     c.jurisdiction,
+			NatureOfBusiness: c.nature,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.nature != nil && *c.nature == "Deleted Ltd's trade" {
+			if err := svc.DeleteCompany(ctx, company.Id); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// format renders counts as "nature=count", the null bucket as "<null>"
+	format := func(counts []api.NatureOfBusinessCount) []string {
+		out := make([]string, len(counts))
+		for i, c := range counts {
+			nature := "<null>"
+			if c.NatureOfBusiness != nil {
+				nature = *c.NatureOfBusiness
+			}
+			out[i] = nature + "=" + strconv.Itoa(c.Count)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name         string
+		jurisdiction *string
+		want         []string
+	}{
+		{"every jurisdiction", nil, []string{"Software=3", "<null>=2", "Banking=1", "Shipping=1"}},
+		{"blank jurisdiction", ptr(" "), []string{"Software=3", "<null>=2", "Banking=1", "Shipping=1"}},
+		{"one jurisdiction", ptr("UK"), []string{"Software=2", "Shipping=1", "<null>=1"}},
+		{"jurisdiction alias", ptr("sg"), []string{"Banking=1", "Software=1", "<null>=1"}},
+		{"no companies", ptr("Cayman Islands"), []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts, err := svc.CountCompaniesByNatureOfBusiness(ctx, tt.jurisdiction)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := format(counts); !equalNames(got, tt.want) {
+				t.Errorf("counts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown jurisdiction", func(t *testing.T) {
+		if _, err := svc.CountCompaniesByNatureOfBusiness(ctx, ptr("Atlantis")); !isValidationError(err) {
+			t.Errorf("err = %v, want a validation error", err)
+		}
+	})
+}
+
+func TestWritesCheckTheUncachedCompany(t *testing.T) {
+	ctx := context.Background()
+
+	// staleCompany returns a service with a cache, and a company it has cached in UK that another instance has
+	// since moved to Singapore, as it was created and as it now is
+	staleCompany := func(t *testing.T) (CompanyService, *api.Company, *api.Company) {
+		t.Helper()
+		_, inner := newTestService(t)
+		svc := NewCompanyService(repository.NewCachingCompanyRepository(inner, 10, time.Hour), Options{
+			Jurisdictions: []string{"UK", "Singapore", "Cayman Islands"},
+			DefaultLimit:  20,
+			MaxLimit:      100,
+		})
+
+		created := createCompanies(t, svc, [2]string{"Example Ltd", "UK"})[0]
+		if _, err := svc.GetCompanyByID(ctx, created.Id); err != nil { // Caches the company
+			t.Fatal(err)
+		}
+		moved, err := inner.TransferJurisdiction(ctx, created.Id, "Singapore")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cached, _ := svc.GetCompanyByID(ctx, created.Id); cached.Jurisdiction != "UK" {
+			t.Fatalf("cached jurisdiction = %s, want the stale UK", cached.Jurisdiction)
+		}
+		return svc, created, moved
+	}
+
+	t.Run("patch expecting the current version", func(t *testing.T) {
+		svc, created, moved := staleCompany(t)
+		name := "Renamed Ltd"
+		patched, err := svc.PatchCompany(ctx, created.Id, api.PatchCompanyRequest{CompanyName: &name}, &moved.DateUpdated)
+		if err != nil {
+			t.Fatalf("err = %v, want the patch applied", err)
+		}
+		if patched.Jurisdiction != "Singapore" {
+			t.Errorf("patched jurisdiction = %s, want Singapore kept from the current row", patched.Jurisdiction)
+		}
+	})
+
+	t.Run("patch expecting the stale version", func(t *testing.T) {
+		svc, created, _ := staleCompany(t)
+		name := "Renamed Ltd"
+		if _, err := svc.PatchCompany(ctx, created.Id, api.PatchCompanyRequest{CompanyName: &name}, &created.DateUpdated); !errors.Is(err, ErrCompanyModified) {
+			t.Errorf("err = %v, want ErrCompanyModified", err)
+		}
+	})
+
+	t.Run("transfer to the current jurisdiction", func(t *testing.T) {
+		svc, created, _ := staleCompany(t)
+		if _, err := svc.TransferJurisdiction(ctx, created.Id, "Singapore"); !errors.Is(err, ErrSameJurisdiction) {
+			t.Errorf("err = %v, want ErrSameJurisdiction", err)
+		}
+	})
+
+	t.Run("transfer back to the cached jurisdiction", func(t *testing.T) {
+		svc, created, _ := staleCompany(t)
+		company, err := svc.TransferJurisdiction(ctx, created.Id, "UK")
+		if err != nil {
+			t.Fatalf("err = %v, want the transfer applied", err)
+		}
+		if company.Jurisdiction != "UK" {
+			t.Errorf("jurisdiction = %s, want UK", company.Jurisdiction)
+		}
+	})
+}
+
+func TestDirectorRecordsKeepTheCount(t *testing.T) {
+	ctx := context.Background()
+
+	// newCompany returns a service requiring two directors in Singapore and a company created in jurisdiction
+	// with number_of_directors 5 and the given number of director records
+	newCompany := func(t *testing.T, jurisdiction string, records int) (CompanyService, *api.Company) {
+		t.Helper()
+		svc, _ := newTestServiceWith(t, func(opts *Options) {
+			opts.MinDirectors = map[string]int{"Singapore": 2}
+		})
+		company, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{
+			CompanyName:       "Example Ltd",
+			CompanyAddress:    "1 High Street",
+			Jurisdiction:      jurisdiction,
+			NumberOfDirectors: ptr(5),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < records; i++ {
+			if _, err := svc.AddDirector(ctx, company.Id, api.CreateDirectorRequest{Name: "Director " + strconv.Itoa(i), Role: "Director"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		company, err = svc.GetCompanyByID(ctx, company.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return svc, company
+	}
+
+	// replace returns a PUT body repeating the company with number_of_directors set to directors
+	replace := func(company *api.Company, directors *int) api.CreateCompanyRequest {
+		return api.CreateCompanyRequest{
+			CompanyName:       company.CompanyName,
+			CompanyAddress:    company.CompanyAddress,
+			Jurisdiction:      company.Jurisdiction,
+			NumberOfDirectors: directors,
+		}
+	}
+
+	t.Run("the first record replaces the declared count", func(t *testing.T) {
+		_, company := newCompany(t, "UK", 1)
+		if company.NumberOfDirectors == nil || *company.NumberOfDirectors != 1 {
+			t.Errorf("number_of_directors = %v, want 1", company.NumberOfDirectors)
+		}
+	})
+
+	t.Run("without records the count is written directly", func(t *testing.T) {
+		svc, company := newCompany(t, "UK", 0)
+		updated, err := svc.UpdateCompany(ctx, company.Id, replace(company, ptr(7)), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *updated.NumberOfDirectors != 7 {
+			t.Errorf("number_of_directors = %d, want 7", *updated.NumberOfDirectors)
+		}
+	})
+
+	t.Run("a round trip below the jurisdiction's minimum", func(t *testing.T) {
+		svc, company := newCompany(t, "Singapore", 1)
+		if _, err := svc.UpdateCompany(ctx, company.Id, replace(company, company.NumberOfDirectors), nil); err != nil {
+			t.Errorf("err = %v, want the unchanged count accepted", err)
+		}
+	})
+
+	for name, write := range map[string]func(svc CompanyService, company *api.Company) error{
+		"put another count": func(svc CompanyService, company *api.Company) error {
+			_, err := svc.UpdateCompany(ctx, company.Id, replace(company, ptr(3)), nil)
+			return err
+		},
+		"put without the count": func(svc CompanyService, company *api.Company) error {
+			_, err := svc.UpdateCompany(ctx, company.Id, replace(company, nil), nil)
+			return err
+		},
+		"patch the count": func(svc CompanyService, company *api.Company) error {
+			_, err := svc.PatchCompany(ctx, company.Id, api.PatchCompanyRequest{NumberOfDirectors: ptr(2)}, nil)
+			return err
+		},
+		"adjust the count": func(svc CompanyService, company *api.Company) error {
+			_, err := svc.AdjustDirectorCount(ctx, company.Id, 1)
+			return err
+		},
+		"remove the last director": func(svc CompanyService, company *api.Company) error {
+			directors, err := svc.ListDirectors(ctx, company.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return svc.RemoveDirector(ctx, company.Id, directors[0].Id)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, company := newCompany(t, "UK", 1)
+			if err := write(svc, company); fieldError(err) != "number_of_directors" {
+				t.Errorf("err = %v, want a validation error on number_of_directors", err)
+			}
+			if got, _ := svc.GetCompanyByID(ctx, company.Id); *got.NumberOfDirectors != 1 {
+				t.Errorf("number_of_directors = %d, want 1 kept", *got.NumberOfDirectors)
+			}
+		})
+	}
+
+	t.Run("removals stop at the jurisdiction's minimum", func(t *testing.T) {
+		svc, company := newCompany(t, "Singapore", 3)
+		directors, err := svc.ListDirectors(ctx, company.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.RemoveDirector(ctx, company.Id, directors[0].Id); err != nil {
+			t.Fatalf("removing the third director: %v", err)
+		}
+		if err := svc.RemoveDirector(ctx, company.Id, directors[1].Id); fieldError(err) != "number_of_directors" {
+			t.Errorf("removing the second director: err = %v, want a validation error on number_of_directors", err)
+		}
+		if got, _ := svc.GetCompanyByID(ctx, company.Id); *got.NumberOfDirectors != 2 {
+			t.Errorf("number_of_directors = %d, want 2", *got.NumberOfDirectors)
+		}
+	})
+
+	t.Run("a transfer checks the records against the new minimum", func(t *testing.T) {
+		svc, company := newCompany(t, "UK", 1)
+		if _, err := svc.TransferJurisdiction(ctx, company.Id, "Singapore"); fieldError(err) != "number_of_directors" {
+			t.Errorf("err = %v, want a validation error on number_of_directors", err)
+		}
+	})
+}
+
+func TestShareholderRecordsKeepTheCount(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestServiceWith(t, func(opts *Options) {
+		opts.MaxShareholders = map[string]int{"singapore": 2}
+	})
+	company, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{
+		CompanyName:          "Example Pte Ltd",
+		CompanyAddress:       "1 Raffles Place",
+		Jurisdiction:         "Singapore",
+		NumberOfShareholders: ptr(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var added []*api.Shareholder
+	for _, name := range []string{"First", "Second"} {
+		shareholder, err := svc.AddShareholder(ctx, company.Id, api.CreateShareholderRequest{Name: name, OwnershipPercentage: 10})
+		if err != nil {
+			t.Fatalf("adding %s: %v", name, err)
+		}
+		added = append(added, shareholder)
+	}
+
+	if _, err := svc.AddShareholder(ctx, company.Id, api.CreateShareholderRequest{Name: "Third", OwnershipPercentage: 10}); !isValidationError(err) {
+		t.Errorf("adding past the jurisdiction's maximum: err = %v, want a validation error", err)
+	}
+	if _, err := svc.PatchCompany(ctx, company.Id, api.PatchCompanyRequest{NumberOfShareholders: ptr(1)}, nil); fieldError(err) != "number_of_shareholders" {
+		t.Errorf("patching the count: err = %v, want a validation error on number_of_shareholders", err)
+	}
+	if _, err := svc.AdjustShareholderCount(ctx, company.Id, -1); fieldError(err) != "number_of_shareholders" {
+		t.Errorf("adjusting the count: err = %v, want a validation error on number_of_shareholders", err)
+	}
+
+	if err := svc.RemoveShareholder(ctx, company.Id, added[0].Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RemoveShareholder(ctx, company.Id, added[1].Id); fieldError(err) != "number_of_shareholders" {
+		t.Errorf("removing the last shareholder: err = %v, want a validation error on number_of_shareholders", err)
+	}
+
+	got, err := svc.GetCompanyByID(ctx, company.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got.NumberOfShareholders != 1 {
+		t.Errorf("number_of_shareholders = %d, want 1", *got.NumberOfShareholders)
+	}
+}
+
+func TestPurgeKeepsHistory(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t)
+	company := createCompanies(t, svc, [2]string{"Acme Ltd", "UK"})[0]
+
+	if err := svc.DeleteCompany(ctx, company.Id); err != nil {
+		t.Fatal(err)
+	}
+	purged, err := svc.PurgeDeletedCompanies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged.Purged != 1 {
+		t.Fatalf("purged = %d, want 1", purged.Purged)
+	}
+	if _, err := svc.RestoreCompany(ctx, company.Id); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("restoring a purged company: err = %v, want ErrCompanyNotFound", err)
+	}
+
+	entries, err := svc.GetCompanyHistory(ctx, company.Id)
+	if err != nil {
+		t.Fatalf("history of a purged company: %v", err)
+	}
+	var actions []api.AuditEntryAction
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	if len(actions) != 2 || actions[0] != api.Create || actions[1] != api.Delete {
+		t.Errorf("actions = %v, want [create delete]", actions)
+	}
+}
+
+func TestImportReportsKeysRepeatedInTheFile(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t)
+
+	file := "jurisdiction,company_name,company_address,sec_code\n" +
+		"UK,Acme Ltd,1 High Street,AB1\n" +
+		"UK,ACME  ltd,2 High Street,\n" + // Same name key in the same jurisdiction
+		"Singapore,Acme Ltd,3 High Street,\n" + // Same name in another jurisdiction is allowed
+		"UK,Beta Ltd,4 High Street, ab1 \n" // Same canonical SEC code
+
+	result, err := svc.ImportCompaniesCSV(ctx, strings.NewReader(file), false, false)
+	if err != nil {
+		t.Fatalf("lenient import: err = %v, want the repeated rows reported", err)
+	}
+
+	want := []string{"", "company_name", "", "sec_code"}
+	if len(result.Rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(result.Rows), len(want))
+	}
+	for i, row := range result.Rows {
+		got := ""
+		if row.Err != nil {
+			got = row.Err.Field
+		}
+		if got != want[i] {
+			t.Errorf("line %d: error field = %q, want %q", row.Line, got, want[i])
+		}
+		if (row.Company != nil) != (want[i] == "") {
+			t.Errorf("line %d: created = %v, want %v", row.Line, row.Company != nil, want[i] == "")
+		}
+	}
+}
+
+func TestListCompanyIDsMatchesListCompanies(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t)
+	createCompanies(t, svc,
+		[2]string{"One", "UK"},
+		[2]string{"Two", "Singapore"},
+		[2]string{"Three", "UK"},
+		[2]string{"Four", "UK"},
+	)
+
+	tests := []struct {
+		name   string
+		params api.GetCompaniesParams
+	}{
+		{"default", api.GetCompaniesParams{}},
+		{"filtered", api.GetCompaniesParams{Jurisdiction: &[]string{"UK"}}},
+		{"paged", api.GetCompaniesParams{Limit: ptr(2), Offset: ptr(1)}},
+		{"sorted", api.GetCompaniesParams{Sort: ptr(api.CompanyName)}},
+		{"without total", api.GetCompaniesParams{Limit: ptr(3), IncludeTotal: ptr(false)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			companies, err := svc.ListCompanies(ctx, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids, err := svc.ListCompanyIDs(ctx, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(ids.Ids) != len(companies.Companies) {
+				t.Fatalf("got %d IDs, want %d", len(ids.Ids), len(companies.Companies))
+			}
+			for i, company := range companies.Companies {
+				if ids.Ids[i] != company.Id {
+					t.Errorf("ids[%d] = %s, want %s (%s)", i, ids.Ids[i], company.Id, company.CompanyName)
+				}
+			}
+			if ids.Total != companies.Total || ids.HasNext != companies.HasNext {
+				t.Errorf("total, has_next = %d, %v, want %d, %v", ids.Total, ids.HasNext, companies.Total, companies.HasNext)
+			}
+		})
+	}
+}
+
+func TestGetSharedAddressReport(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t)
+
+	create := func(name, address string) *api.Company {
+		company, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{CompanyName: name, CompanyAddress: address, Jurisdiction: "UK"})
+		if err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+		return company
+	}
+	create("Acme Ltd", "1 High Street")
+	create("Acme Trading Ltd", "1  HIGH street")
+	create("Beta Ltd", "2 Low Road")
+	create("Beta Holdings Ltd", "2 low road")
+	create("Beta Trading Ltd", "2 LOW ROAD")
+	create("Gamma Ltd", "3 Side Lane")
+	deleted := create("Gamma Holdings Ltd", "3 Side Lane")
+	if err := svc.DeleteCompany(ctx, deleted.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		min  int
+		want map[string]int
+	}{
+		{2, map[string]int{"1 high street": 2, "2 low road": 3}}, // The soft-deleted company leaves 3 Side Lane alone
+		{3, map[string]int{"2 low road": 3}},
+		{4, map[string]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.min), func(t *testing.T) {
+			report, err := svc.GetSharedAddressReport(ctx, tt.min)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]int{}
+			for _, group := range report.Groups {
+				got[group.Address] = group.Count
+				if len(group.Companies) != group.Count {
+					t.Errorf("group %q lists %d companies, want its count %d", group.Address, len(group.Companies), group.Count)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("groups = %v, want %v", got, tt.want)
+			}
+			for address, count := range tt.want {
+				if got[address] != count {
+					t.Errorf("groups = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	if _, err := svc.GetSharedAddressReport(ctx, 1); !isValidationError(err) {
+		t.Errorf("min 1: err = %v, want a validation error", err)
+	}
+}
+
+func TestCanonicalizeSecCodesOnRead(t *testing.T) {
+	ctx := context.Background()
+
+	for _, canonicalize := range []bool{true, false} {
+		t.Run(strconv.FormatBool(canonicalize), func(t *testing.T) {
+			svc, repo := newTestServiceWith(t, func(opts *Options) { opts.CanonicalizeSecCodes = canonicalize })
+
+			// Stored directly, as rows written before SEC code validation may be
+			stored, err := repo.Create(ctx, api.CreateCompanyRequest{
+				CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK", SecCode: ptr(" ab123 "),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := " ab123 "
+			if canonicalize {
+				want = "AB123"
+			}
+
+			got, err := svc.GetCompanyByID(ctx, stored.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.SecCode == nil || *got.SecCode != want {
+				t.Errorf("get: sec_code = %v, want %q", got.SecCode, want)
+			}
+
+			list, err := svc.ListCompanies(ctx, api.GetCompaniesParams{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list.Companies) != 1 || list.Companies[0].SecCode == nil || *list.Companies[0].SecCode != want {
+				t.Errorf("list: companies = %+v, want one with sec_code %q", list.Companies, want)
+			}
+		})
+	}
+}
+
+func TestNatureOfBusinessLengthBoundary(t *testing.T) {
+	ctx := context.Background()
+	atLimit := strings.Repeat("é", 10) // Counted in characters, not bytes
+	overLimit := atLimit + "x"
+
+	tests := []struct {
+		name     string
+		truncate bool
+		nature   string
+		want     string // Stored nature_of_business, "" when rejected
+		warned   bool
+	}{
+		{"reject at the limit", false, atLimit, atLimit, false},
+		{"reject over the limit", false, overLimit, "", false},
+		{"truncate at the limit", true, atLimit, atLimit, false},
+		{"truncate over the limit", true, overLimit, atLimit, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestServiceWith(t, func(opts *Options) {
+				opts.NatureOfBusinessMaxLength = 10
+				opts.TruncateNatureOfBusiness = tt.truncate
+			})
+
+			company, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{
+				CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK", NatureOfBusiness: ptr(tt.nature),
+			})
+			if tt.want == "" {
+				if fieldError(err) != "nature_of_business" {
+					t.Errorf("err = %v, want a validation error on nature_of_business", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if *company.NatureOfBusiness != tt.want {
+				t.Errorf("nature_of_business = %q, want %q", *company.NatureOfBusiness, tt.want)
+			}
+			if warned := company.Warnings != nil && len(*company.Warnings) > 0; warned != tt.warned {
+				t.Errorf("warnings = %v, want warned = %v", company.Warnings, tt.warned)
+			}
+		})
+	}
+}
+
+func TestRegistryFields(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		source    *string
+		number    *string
+		wantField string
+	}{
+		{"neither", nil, nil, ""},
+		{"valid pair", ptr("companies_house"), ptr("01234567"), ""},
+		{"normalized pair", ptr(" Companies_House "), ptr(" sc123456 "), ""},
+		{"source without number", ptr("companies_house"), nil, "registry_number"},
+		{"number without source", nil, ptr("01234567"), "registry_source"},
+		{"blank number", ptr("companies_house"), ptr("  "), "registry_number"},
+		{"wrong source", ptr("acra"), ptr("01234567"), "registry_source"},
+		{"bad number", ptr("companies_house"), ptr("1234"), "registry_number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestService(t)
+			_, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{
+				CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK",
+				RegistrySource: tt.source, RegistryNumber: tt.number,
+			})
+			if tt.wantField == "" && err != nil {
+				t.Errorf("err = %v, want the company created", err)
+			}
+			if tt.wantField != "" && fieldError(err) != tt.wantField {
+				t.Errorf("err = %v, want a validation error on %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestRegistryUniquenessAndLookup(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestServiceWith(t, func(opts *Options) { opts.EnforceUniqueRegistryNumbers = true })
+
+	acme, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{
+		CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK",
+		RegistrySource: ptr("companies_house"), RegistryNumber: ptr("sc123456"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := svc.GetCompanyByRegistry(ctx, " COMPANIES_HOUSE", "sc123456 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != acme.Id {
+		t.Errorf("lookup found %s, want %s", found.Id, acme.Id)
+	}
+	if _, err := svc.GetCompanyByRegistry(ctx, "companies_house", "SC654321"); !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("unknown number: err = %v, want ErrCompanyNotFound", err)
+	}
+	if _, err := svc.GetCompanyByRegistry(ctx, "companies_house", " "); !isValidationError(err) {
+		t.Errorf("blank number: err = %v, want a validation error", err)
+	}
+
+	_, err = svc.CreateCompany(ctx, api.CreateCompanyRequest{
+		CompanyName: "Beta Ltd", CompanyAddress: "2 High Street", Jurisdiction: "UK",
+		RegistrySource: ptr("companies_house"), RegistryNumber: ptr("SC123456"),
+	})
+	if !isValidationError(err) {
+		t.Errorf("reusing the registry number: err = %v, want a validation error", err)
+	}
+
+	// A company keeps its own registry number through an update
+	update := api.CreateCompanyRequest{
+		CompanyName: "Acme Group Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK",
+		RegistrySource: ptr("companies_house"), RegistryNumber: ptr("SC123456"),
+	}
+	if _, err := svc.UpdateCompany(ctx, acme.Id, update, nil); err != nil {
+		t.Errorf("updating with its own registry number: %v", err)
+	}
+}
+
+func TestListCompaniesCountOnly(t *testing.T) {
+	ctx := context.Background()
+
+	svc, _ := newTestService(t)
+	if _, err := svc.ListCompanies(ctx, api.GetCompaniesParams{Limit: ptr(0)}); !isValidationError(err) {
+		t.Errorf("limit=0 when not allowed: err = %v, want a validation error", err)
+	}
+
+	svc, _ = newTestServiceWith(t, func(opts *Options) { opts.AllowLimitZero = true })
+	createCompanies(t, svc, [2]string{"One", "UK"}, [2]string{"Two", "UK"}, [2]string{"Three", "Singapore"})
+
+	resp, err := svc.ListCompanies(ctx, api.GetCompaniesParams{Limit: ptr(0), Jurisdiction: &[]string{"UK"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Companies) != 0 || resp.Total != 2 || resp.HasNext {
+		t.Errorf("count-only: %d companies, total %d, has_next %v, want none, 2 and false", len(resp.Companies), resp.Total, resp.HasNext)
+	}
+
+	ids, err := svc.ListCompanyIDs(ctx, api.GetCompaniesParams{Limit: ptr(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids.Ids) != 0 || ids.Total != 3 {
+		t.Errorf("count-only IDs: %d IDs, total %d, want none and 3", len(ids.Ids), ids.Total)
+	}
+
+	params := api.GetCompaniesParams{Limit: ptr(0), IncludeTotal: ptr(false)}
+	if _, err := svc.ListCompanies(ctx, params); !isValidationError(err) {
+		t.Errorf("limit=0 with include_total=false: err = %v, want a validation error", err)
+	}
+	if _, err := svc.ListCompanyIDs(ctx, params); !isValidationError(err) {
+		t.Errorf("id-only limit=0 with include_total=false: err = %v, want a validation error", err)
+	}
+}
+
+func TestMinDirectors(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestServiceWith(t, func(opts *Options) { opts.MinDirectors = map[string]int{"singapore": 2} })
+
+	create := func(name, jurisdiction string, directors *int) (*api.Company, error) {
+		return svc.CreateCompany(ctx, api.CreateCompanyRequest{
+			CompanyName: name, CompanyAddress: "1 High Street", Jurisdiction: jurisdiction, NumberOfDirectors: directors,
+		})
+	}
+
+	tests := []struct {
+		name         string
+		jurisdiction string
+		directors    *int
+		ok           bool
+	}{
+		{"omitted where required", "Singapore", nil, false},
+		{"below the minimum", "Singapore", ptr(1), false},
+		{"at the minimum", "Singapore", ptr(2), true},
+		{"jurisdiction without a minimum", "UK", nil, true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := create(fmt.Sprintf("Company %d", i), tt.jurisdiction, tt.directors)
+			if tt.ok && err != nil {
+				t.Errorf("err = %v, want the company created", err)
+			}
+			if !tt.ok && fieldError(err) != "number_of_directors" {
+				t.Errorf("err = %v, want a validation error on number_of_directors", err)
+			}
+		})
+	}
+
+	singapore, err := create("Lion City Pte", "Singapore", ptr(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.PatchCompany(ctx, singapore.Id, api.PatchCompanyRequest{NumberOfDirectors: ptr(1)}, nil); fieldError(err) != "number_of_directors" {
+		t.Errorf("patching below the minimum: err = %v, want a validation error on number_of_directors", err)
+	}
+
+	uk, err := create("Acme Ltd", "UK", ptr(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.TransferJurisdiction(ctx, uk.Id, "Singapore"); fieldError(err) != "number_of_directors" {
+		t.Errorf("transferring into the minimum: err = %v, want a validation error on number_of_directors", err)
+	}
+}
+
+func TestCreateDefaultsFillOnlyOmittedFields(t *testing.T) {
+	ctx := context.Background()
+	defaults, err := ParseFieldDefaults(`{"uk": {"nature_of_business": "Holding company", "number_of_directors": 2, "sec_code": "UK1"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, _ := newTestServiceWith(t, func(opts *Options) { opts.CreateDefaults = defaults })
+
+	tests := []struct {
+		name      string
+		req       api.CreateCompanyRequest
+		nature    *string
+		directors *int
+		secCode   *string
+	}{
+		{
+			"all omitted",
+			api.CreateCompanyRequest{CompanyName: "One Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK"},
+			ptr("Holding company"), ptr(2), ptr("UK1"),
+		},
+		{
+			"explicit values kept",
+			api.CreateCompanyRequest{CompanyName: "Two Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK",
+				NatureOfBusiness: ptr("Retail"), NumberOfDirectors: ptr(5), SecCode: ptr("UK2")},
+			ptr("Retail"), ptr(5), ptr("UK2"),
+		},
+		{
+			"explicit empty value kept",
+			api.CreateCompanyRequest{CompanyName: "Three Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK",
+				NatureOfBusiness: ptr(""), SecCode: ptr("UK3")},
+			ptr(""), ptr(2), ptr("UK3"),
+		},
+		{
+			"other jurisdiction",
+			api.CreateCompanyRequest{CompanyName: "Four Pte", CompanyAddress: "1 Raffles Place", Jurisdiction: "Singapore"},
+			nil, nil, nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			company, err := svc.CreateCompany(ctx, tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(deref(company.NatureOfBusiness)) != fmt.Sprint(deref(tt.nature)) {
+				t.Errorf("nature_of_business = %v, want %v", deref(company.NatureOfBusiness), deref(tt.nature))
+			}
+			if fmt.Sprint(deref(company.NumberOfDirectors)) != fmt.Sprint(deref(tt.directors)) {
+				t.Errorf("number_of_directors = %v, want %v", deref(company.NumberOfDirectors), deref(tt.directors))
+			}
+			if fmt.Sprint(deref(company.SecCode)) != fmt.Sprint(deref(tt.secCode)) {
+				t.Errorf("sec_code = %v, want %v", deref(company.SecCode), deref(tt.secCode))
+			}
+		})
+	}
+}
+
+// deref returns the value p points to, or nil when p is nil
+func deref[T any](p *T) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func TestMaxFilters(t *testing.T) {
+	ctx := context.Background()
+	after := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		params api.GetCompaniesParams
+		ok     bool
+	}{
+		{"none", api.GetCompaniesParams{}, true},
+		{"at the cap", api.GetCompaniesParams{Jurisdiction: &[]string{"UK"}, Search: ptr("acme"), CreatedAfter: &after}, true},
+		{"jurisdiction values count separately", api.GetCompaniesParams{Jurisdiction: &[]string{"UK", "Singapore", "Cayman Islands", "uk"}}, false},
+		{"over the cap", api.GetCompaniesParams{Jurisdiction: &[]string{"UK"}, Search: ptr("acme"), CreatedAfter: &after, RecentMinutes: ptr(5)}, false},
+		{"include_deleted is not a filter", api.GetCompaniesParams{Jurisdiction: &[]string{"UK", "Singapore"}, Search: ptr("acme"), IncludeDeleted: ptr(true)}, true},
+	}
+
+	svc, _ := newTestServiceWith(t, func(opts *Options) { opts.MaxFilters = 3 })
+	uncapped, _ := newTestService(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ListCompanies(ctx, tt.params)
+			if tt.ok && err != nil {
+				t.Errorf("err = %v, want the list", err)
+			}
+			if !tt.ok && !isValidationError(err) {
+				t.Errorf("err = %v, want a validation error", err)
+			}
+
+			if _, err := uncapped.ListCompanies(ctx, tt.params); err != nil {
+				t.Errorf("without a cap: err = %v, want the list", err)
+			}
+		})
+	}
+}
+
+func TestMaxShareholdersPerJurisdiction(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestServiceWith(t, func(opts *Options) { opts.MaxShareholders = map[string]int{"caymens": 50} })
+
+	create := func(name, jurisdiction string, shareholders int) (*api.Company, error) {
+		return svc.CreateCompany(ctx, api.CreateCompanyRequest{
+			CompanyName: name, CompanyAddress: "1 High Street", Jurisdiction: jurisdiction, NumberOfShareholders: &shareholders,
+		})
+	}
+
+	tests := []struct {
+		name         string
+		jurisdiction string
+		shareholders int
+		ok           bool
+	}{
+		{"at the jurisdiction's maximum", "Cayman Islands", 50, true},
+		{"over the jurisdiction's maximum", "Cayman Islands", 51, false},
+		{"default maximum elsewhere", "UK", 1000, true},
+		{"over the default maximum", "UK", 1001, false},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := create(fmt.Sprintf("Company %d", i), tt.jurisdiction, tt.shareholders)
+			if tt.ok && err != nil {
+				t.Errorf("err = %v, want the company created", err)
+			}
+			if !tt.ok && fieldError(err) != "number_of_shareholders" {
+				t.Errorf("err = %v, want a validation error on number_of_shareholders", err)
+			}
+		})
+	}
+
+	cayman, err := create("Island Holdings", "Cayman Islands", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.PatchCompany(ctx, cayman.Id, api.PatchCompanyRequest{NumberOfShareholders: ptr(60)}, nil); fieldError(err) != "number_of_shareholders" {
+		t.Errorf("patching over the maximum: err = %v, want a validation error on number_of_shareholders", err)
+	}
+	if _, err := svc.AdjustShareholderCount(ctx, cayman.Id, 41); !errors.Is(err, ErrCountOutOfRange) {
+		t.Errorf("adjusting over the maximum: err = %v, want ErrCountOutOfRange", err)
+	}
+	if _, err := svc.AdjustShareholderCount(ctx, cayman.Id, 40); err != nil {
+		t.Errorf("adjusting to the maximum: %v", err)
+	}
+
+	uk, err := create("Acme Ltd", "UK", 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.TransferJurisdiction(ctx, uk.Id, "Cayman Islands"); fieldError(err) != "number_of_shareholders" {
+		t.Errorf("transferring over the maximum: err = %v, want a validation error on number_of_shareholders", err)
+	}
+}
+
+func TestExtractFollowsTheWatermark(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService(t)
+
+	// Five companies share one date_updated, so paging by time alone would skip or repeat some of them
+	tie := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	now := repo.Now
+	repo.Now = func() time.Time { return tie }
+	created := createCompanies(t, svc,
+		[2]string{"One", "UK"}, [2]string{"Two", "UK"}, [2]string{"Three", "UK"},
+		[2]string{"Four", "UK"}, [2]string{"Five", "UK"},
+	)
+	repo.Now = now
+	createCompanies(t, svc, [2]string{"Six", "UK"})
+
+	extractAll := func(since *time.Time, sinceID *openapi_types.UUID) ([]api.Company, *time.Time, *openapi_types.UUID) {
+		var all []api.Company
+		for {
+			resp, err := svc.ExtractCompanies(ctx, api.ExtractCompaniesParams{Since: since, SinceId: sinceID, Limit: ptr(2)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Companies) == 0 {
+				if resp.Watermark != since || resp.WatermarkId != sinceID {
+					t.Errorf("empty page moved the watermark")
+				}
+				return all, since, sinceID
+			}
+			all = append(all, resp.Companies...)
+			since, sinceID = resp.Watermark, resp.WatermarkId
+		}
+	}
+
+	extracted, since, sinceID := extractAll(nil, nil)
+	seen := map[openapi_types.UUID]int{}
+	for _, company := range extracted {
+		seen[company.Id]++
+	}
+	if len(extracted) != 6 || len(seen) != 6 {
+		t.Fatalf("extracted %d companies, %d distinct, want each of 6 once", len(extracted), len(seen))
+	}
+
+	// Later changes, deletes included as tombstones, are picked up from the last watermark
+	if err := svc.DeleteCompany(ctx, created[2].Id); err != nil {
+		t.Fatal(err)
+	}
+	changed, _, _ := extractAll(since, sinceID)
+	if len(changed) != 1 || changed[0].Id != created[2].Id || changed[0].DeletedAt == nil {
+		t.Errorf("changes = %+v, want only the tombstone of %s", changed, created[2].CompanyName)
+	}
+
+	if _, err := svc.ExtractCompanies(ctx, api.ExtractCompaniesParams{SinceId: sinceID}); !isValidationError(err) {
+		t.Errorf("since_id without since: err = %v, want a validation error", err)
+	}
+}
+
+func TestAddressCountryCheck(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestServiceWith(t, func(opts *Options) {
+		opts.AddressCountryCheck = map[string]string{"UK": "error", "Singapore": "warn"}
+	})
+
+	tests := []struct {
+		name         string
+		jurisdiction string
+		address      string
+		rejected     bool
+		warned       bool
+	}{
+		{"own country", "UK", "10 Downing Street, London, U.K.", false, false},
+		{"no country named", "UK", "1 High Street", false, false},
+		{"word containing a marker", "UK", "1 Ukraine Road", false, false},
+		{"own country among others", "UK", "1 Orchard Road, Singapore, United Kingdom", false, false},
+		{"other country in error mode", "UK", "1 Orchard Road, Singapore", true, false},
+		{"other country in warn mode", "Singapore", "1 High Street, Edinburgh, Scotland", false, true},
+		{"other country without a check", "Cayman Islands", "1 High Street, England", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			company, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{
+				CompanyName: tt.name, CompanyAddress: tt.address, Jurisdiction: tt.jurisdiction,
+			})
+			if tt.rejected {
+				if fieldError(err) != "company_address" {
+					t.Errorf("err = %v, want a validation error on company_address", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if warned := company.Warnings != nil && len(*company.Warnings) > 0; warned != tt.warned {
+				t.Errorf("warnings = %v, want warned = %v", company.Warnings, tt.warned)
+			}
+		})
+	}
+}
+
+// preCreateFunc adapts a function to PreCreateHook
+type preCreateFunc func(ctx context.Context, req *api.CreateCompanyRequest) error
+
+func (f preCreateFunc) PreCreate(ctx context.Context, req *api.CreateCompanyRequest) error {
+	return f(ctx, req)
+}
+
+func TestPreCreateHook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("enriches the request", func(t *testing.T) {
+		svc, _ := newTestServiceWith(t, func(opts *Options) {
+			opts.PreCreateHook = preCreateFunc(func(ctx context.Context, req *api.CreateCompanyRequest) error {
+				if req.NatureOfBusiness == nil {
+					req.NatureOfBusiness = ptr("Retail")
+				}
+				return nil
+			})
+		})
+
+		company, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if company.NatureOfBusiness == nil || *company.NatureOfBusiness != "Retail" {
+			t.Errorf("nature_of_business = %v, want the hook's Retail", deref(company.NatureOfBusiness))
+		}
+	})
+
+	t.Run("enriched request is still validated", func(t *testing.T) {
+		svc, _ := newTestServiceWith(t, func(opts *Options) {
+			opts.PreCreateHook = preCreateFunc(func(ctx context.Context, req *api.CreateCompanyRequest) error {
+				req.CompanyName = ""
+				return nil
+			})
+		})
+
+		_, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK"})
+		if fieldError(err) != "company_name" {
+			t.Errorf("err = %v, want a validation error on company_name", err)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		hookErr   error
+		wantField string // "" when the hook's error is passed through
+	}{
+		{"plain error", errors.New("classifier rejected the company"), "company"},
+		{"validation error", fieldErrorf("sec_code", "sec code is required"), "sec_code"},
+		{"context error", context.DeadlineExceeded, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run("rejects with "+tt.name, func(t *testing.T) {
+			svc, repo := newTestServiceWith(t, func(opts *Options) {
+				opts.PreCreateHook = preCreateFunc(func(context.Context, *api.CreateCompanyRequest) error { return tt.hookErr })
+			})
+
+			_, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK"})
+			if tt.wantField == "" {
+				if !errors.Is(err, tt.hookErr) || isValidationError(err) {
+					t.Errorf("err = %v, want %v passed through", err, tt.hookErr)
+				}
+			} else if fieldError(err) != tt.wantField {
+				t.Errorf("err = %v, want a validation error on %s", err, tt.wantField)
+			}
+
+			if count, err := repo.Count(ctx, repository.Filter{}); err != nil || count != 0 {
+				t.Errorf("companies stored = %d (%v), want none", count, err)
+			}
+		})
+	}
+}
+
+func TestCreateRateLimiterSlidingWindow(t *testing.T) {
+	limiter := newCreateRateLimiter(3)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name    string
+		n       int
+		at      time.Duration // Offset from start
+		allowed bool
+	}{
+		{"first creation", 1, 0, true},
+		{"batch within the limit", 2, 20 * time.Second, true},
+		{"over the limit", 1, 30 * time.Second, false},
+		{"still inside the window of the first", 1, time.Minute - time.Nanosecond, false},
+		{"first is a minute old", 1, time.Minute, true},
+		{"batch larger than the room left", 2, 70 * time.Second, false},
+		{"rejected batch recorded nothing", 1, 80 * time.Second, true},
+		{"batch larger than the limit", 4, 10 * time.Minute, false},
+	}
+
+	for _, step := range steps {
+		err := limiter.reserve(step.n, start.Add(step.at))
+		if allowed := err == nil; allowed != step.allowed {
+			t.Fatalf("%s: err = %v, want allowed = %v", step.name, err, step.allowed)
+		}
+		if err != nil && !errors.Is(err, ErrCreateRateExceeded) {
+			t.Fatalf("%s: err = %v, want ErrCreateRateExceeded", step.name, err)
+		}
+	}
+
+	if err := newCreateRateLimiter(0).reserve(1000, start); err != nil {
+		t.Errorf("limit 0: err = %v, want no limit", err)
+	}
+}
+
+// previewOnlyRepository fails the test on any create, so a dry run can be shown to write nothing
+type previewOnlyRepository struct {
+	*repositorytest.CompanyRepository
+	t *testing.T
+}
+
+func (r previewOnlyRepository) Create(context.Context, api.CreateCompanyRequest) (*api.Company, error) {
+	r.t.Error("dry run called Create")
+	return nil, errors.New("unexpected write")
+}
+
+func (r previewOnlyRepository) CreateMany(context.Context, []api.CreateCompanyRequest) ([]api.Company, error) {
+	r.t.Error("dry run called CreateMany")
+	return nil, errors.New("unexpected write")
+}
+
+// newDryRunService returns a service over a repository holding one UK company, Acme Ltd, that fails the test
+// on any further create, and allows two creates a minute
+func newDryRunService(t *testing.T) (CompanyService, *repositorytest.CompanyRepository) {
+	t.Helper()
+
+	var opts Options
+	_, repo := newTestServiceWith(t, func(o *Options) {
+		o.MaxCreatesPerMinute = 2
+		opts = *o
+	})
+	if _, err := repo.Create(context.Background(), api.CreateCompanyRequest{CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK"}); err != nil {
+		t.Fatal(err)
+	}
+	return NewCompanyService(previewOnlyRepository{repo, t}, opts), repo
+}
+
+func TestCreateCompaniesDryRun(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newDryRunService(t)
+
+	reqs := []api.CreateCompanyRequest{
+		{CompanyName: "Beta Ltd", CompanyAddress: "2 High Street", Jurisdiction: "UK"},
+		{CompanyName: "Gamma Pte", CompanyAddress: "3 Orchard Road", Jurisdiction: "Singapore"},
+	}
+	// Previews do not count towards the creation rate, so any number of them fit in a limit of two
+	for i := 0; i < 3; i++ {
+		companies, err := svc.CreateCompanies(ctx, reqs, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(companies) != 2 || companies[0].CompanyName != "Beta Ltd" || companies[1].CompanyName != "Gamma Pte" {
+			t.Fatalf("preview = %+v, want Beta Ltd and Gamma Pte", companies)
+		}
+	}
+
+	invalid := append([]api.CreateCompanyRequest{{CompanyName: "", CompanyAddress: "4 High Street", Jurisdiction: "UK"}}, reqs...)
+	var batchErr *BatchValidationError
+	if _, err := svc.CreateCompanies(ctx, invalid, true); !errors.As(err, &batchErr) || len(batchErr.Items) != 1 || batchErr.Items[0].Index != 0 {
+		t.Errorf("invalid batch: err = %v, want a batch error for index 0", err)
+	}
+
+	duplicate := []api.CreateCompanyRequest{{CompanyName: "ACME LTD", CompanyAddress: "5 High Street", Jurisdiction: "UK"}}
+	if _, err := svc.CreateCompanies(ctx, duplicate, true); !errors.Is(err, ErrCompanyAlreadyExists) {
+		t.Errorf("existing name: err = %v, want ErrCompanyAlreadyExists", err)
+	}
+
+	if count, err := repo.Count(ctx, repository.Filter{}); err != nil || count != 1 {
+		t.Errorf("companies stored = %d (%v), want only the seeded one", count, err)
+	}
+}
+
+func TestImportCompaniesCSVDryRun(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newDryRunService(t)
+
+	file := "jurisdiction,company_name,company_address\n" +
+		"UK,Beta Ltd,2 High Street\n" +
+		"UK,,3 High Street\n" +
+		"Singapore,Gamma Pte,4 Orchard Road\n"
+
+	for _, strict := range []bool{false, true} {
+		result, err := svc.ImportCompaniesCSV(ctx, strings.NewReader(file), strict, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.DryRun || result.Failed() != 1 || result.Rows[1].Err == nil || result.Rows[1].Err.Field != "company_name" {
+			t.Errorf("strict=%v: result = %+v, want a dry run with line 3 failing on company_name", strict, result)
+		}
+		for _, i := range []int{0, 2} {
+			if created := result.Rows[i].Company != nil; created == strict {
+				t.Errorf("strict=%v: line %d previewed = %v, want %v", strict, result.Rows[i].Line, created, !strict)
+			}
+		}
+	}
+
+	if count, err := repo.Count(ctx, repository.Filter{}); err != nil || count != 1 {
+		t.Errorf("companies stored = %d (%v), want only the seeded one", count, err)
+	}
+}
+
+func TestJurisdictionAddressRules(t *testing.T) {
+	ctx := context.Background()
+	rules, err := ParseAddressRules(`{
+		"UK": {"pattern": "[A-Z]{1,2}[0-9][A-Z0-9]? ?[0-9][A-Z]{2}$"},
+		"Cayman Islands": {"optional": true, "pattern": "KY[0-9]-[0-9]{4}$", "agent_pattern": "^RA-[0-9]{4,}$"}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, _ := newTestServiceWith(t, func(opts *Options) { opts.AddressRules = rules })
+
+	tests := []struct {
+		jurisdiction string
+		address      string
+		valid        bool
+	}{
+		{"UK", "1 High Street, London SW1A 1AA", true},
+		{"UK", "1 High Street, London", false},
+		{"UK", "RA-1234", false}, // Agent references are only accepted where a rule allows them
+		{"UK", "", false},
+		{"Cayman Islands", "1 Harbour Drive, George Town KY1-1102", true},
+		{"Cayman Islands", "RA-1234", true},
+		{"Cayman Islands", "RA-12", false},
+		{"Cayman Islands", "1 Harbour Drive", false},
+		{"Cayman Islands", "", true},
+		{"Singapore", "1 Orchard Road", true}, // No rule: any non-blank address
+		{"Singapore", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.jurisdiction+"/"+tt.address, func(t *testing.T) {
+			_, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{
+				CompanyName: "Acme " + tt.address, CompanyAddress: tt.address, Jurisdiction: tt.jurisdiction,
+			})
+			if tt.valid && err != nil {
+				t.Errorf("err = %v, want the address accepted", err)
+			}
+			if !tt.valid && fieldError(err) != "company_address" {
+				t.Errorf("err = %v, want a validation error on company_address", err)
+			}
+		})
+	}
+
+	if _, err := ParseAddressRules(`{"UK": {"pattern": "("}}`); err == nil {
+		t.Error("invalid pattern: err = nil, want an error")
+	}
+}
+
+func TestNewJurisdictionSet(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured []string
+		wantNames  []string
+		resolves   map[string]string // Input to canonical value; "" when the input is not allowed
+	}{
+		{
+			name:      "defaults",
+			wantNames: DefaultJurisdictions,
+			resolves:  map[string]string{"uk": "UK", " Great  Britain ": "UK", "SG": "Singapore", "caymens": "Cayman Islands", "Hong Kong": ""},
+		},
+		{
+			name:       "configured list with a new jurisdiction",
+			configured: []string{"UK", "  Hong   Kong "},
+			wantNames:  []string{"UK", "Hong Kong"},
+			resolves:   map[string]string{"england": "UK", "hong kong": "Hong Kong", "Singapore": "", "ky": ""},
+		},
+		{
+			name:       "aliases fold to their canonical name",
+			configured: []string{"gb", "Cayman", "united kingdom", ""},
+			wantNames:  []string{"UK", "Cayman Islands"},
+			resolves:   map[string]string{"U.K.": "UK", "Cayman Islands": "Cayman Islands", "sg": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := newJurisdictionSet(tt.configured)
+			if !equalNames(set.names, tt.wantNames) {
+				t.Errorf("names = %q, want %q", set.names, tt.wantNames)
+			}
+			for input, want := range tt.resolves {
+				got, ok := set.resolve(input)
+				if ok != (want != "") || got != want {
+					t.Errorf("resolve(%q) = %q, %v, want %q", input, got, ok, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfiguredJurisdictions(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestServiceWith(t, func(opts *Options) { opts.Jurisdictions = []string{"UK", "Hong Kong"} })
+
+	company, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{CompanyName: "Acme Ltd", CompanyAddress: "1 Queen's Road", Jurisdiction: "hong kong"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if company.Jurisdiction != "Hong Kong" {
+		t.Errorf("jurisdiction = %q, want the configured Hong Kong", company.Jurisdiction)
+	}
+
+	_, err = svc.CreateCompany(ctx, api.CreateCompanyRequest{CompanyName: "Acme Pte", CompanyAddress: "1 Orchard Road", Jurisdiction: "Singapore"})
+	if fieldError(err) != "jurisdiction" {
+		t.Errorf("unconfigured default jurisdiction: err = %v, want a validation error on jurisdiction", err)
+	}
+}
+
+func TestListCompaniesRecentMinutes(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService(t)
+
+	// Companies created 60, 30, 6 and 5 minutes before the clock is read
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	repo.Now = func() time.Time { return clock }
+	for _, c := range []struct {
+		name, jurisdiction string
+		minutes            int
+	}{
+		{"Old Ltd", "UK", 0},
+		{"Middle Ltd", "UK", 30},
+		{"New Pte", "Singapore", 54},
+		{"New Ltd", "UK", 55},
+	} {
+		clock = start.Add(time.Duration(c.minutes) * time.Minute)
+		createCompanies(t, svc, [2]string{c.name, c.jurisdiction})
+	}
+	clock = start.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		params api.GetCompaniesParams
+		want   []string
+	}{
+		{"last 10 minutes", api.GetCompaniesParams{RecentMinutes: ptr(10)}, []string{"New Ltd", "New Pte"}},
+		{"boundary is inclusive", api.GetCompaniesParams{RecentMinutes: ptr(30)}, []string{"New Ltd", "New Pte", "Middle Ltd"}},
+		{"with a jurisdiction", api.GetCompaniesParams{RecentMinutes: ptr(45), Jurisdiction: &[]string{"UK"}}, []string{"New Ltd", "Middle Ltd"}},
+		{"wider than every company", api.GetCompaniesParams{RecentMinutes: ptr(120)}, []string{"New Ltd", "New Pte", "Middle Ltd", "Old Ltd"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.ListCompanies(ctx, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(resp); !equalNames(got, tt.want) {
+				t.Errorf("companies = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, minutes := range []int{0, -5, maxRecentMinutes + 1} {
+		if _, err := svc.ListCompanies(ctx, api.GetCompaniesParams{RecentMinutes: ptr(minutes)}); !isValidationError(err) {
+			t.Errorf("recent_minutes=%d: err = %v, want a validation error", minutes, err)
+		}
+	}
+}
+
+// newSnapshotService returns a service allowing two snapshots over a Postgres repository on a fake driver, and
+// the driver, which counts the snapshot transactions still open
+func newSnapshotService(t *testing.T, idleTimeout time.Duration) (CompanyService, *repositorytest.Driver) {
+	t.Helper()
+
+	db, snapshotDB := repositorytest.OpenDB(t, nil)
+
+	var opts Options
+	newTestServiceWith(t, func(o *Options) {
+		o.MaxSnapshots = 2
+		o.SnapshotIdleTimeout = idleTimeout
+		o.SnapshotLifetime = time.Hour
+		opts = *o
+	})
+	svc := NewCompanyService(repository.NewPostgresCompanyRepository(db, 0, 0, zap.NewNop()), opts)
+	t.Cleanup(svc.CloseSnapshots)
+	return svc, snapshotDB
+}
+
+// openSnapshot opens a snapshot of every company and returns its ID
+func openSnapshot(t *testing.T, svc CompanyService) string {
+	t.Helper()
+	snapshot, err := svc.OpenSnapshot(context.Background(), api.GetCompaniesParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snapshot.SnapshotId
+}
+
+func TestSnapshotRegistry(t *testing.T) {
+	ctx := context.Background()
+	svc, snapshotDB := newSnapshotService(t, time.Hour)
+
+	// Each snapshot holds a read-only repeatable read transaction, up to the limit
+	first := openSnapshot(t, svc)
+	openSnapshot(t, svc)
+	if got := snapshotDB.OpenTxs(); got != 2 {
+		t.Errorf("open transactions = %d, want 2", got)
+	}
+	if opts := snapshotDB.TxOptions(); opts.Isolation != driver.IsolationLevel(sql.LevelRepeatableRead) || !opts.ReadOnly {
+		t.Errorf("transaction options = %+v, want a read-only repeatable read", opts)
+	}
+	if _, err := svc.OpenSnapshot(ctx, api.GetCompaniesParams{}); !errors.Is(err, ErrSnapshotLimit) {
+		t.Errorf("third snapshot: err = %v, want ErrSnapshotLimit", err)
+	}
+
+	// Closing one frees its transaction and its slot
+	if err := svc.CloseSnapshot(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CloseSnapshot(first); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("closing twice: err = %v, want ErrSnapshotNotFound", err)
+	}
+	third := openSnapshot(t, svc)
+
+	// The last page closes the snapshot
+	page, err := svc.NextSnapshotPage(ctx, third, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.HasMore {
+		t.Error("has_more = true for an empty snapshot")
+	}
+	if _, err := svc.NextSnapshotPage(ctx, third, nil); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("page after the last: err = %v, want ErrSnapshotNotFound", err)
+	}
+
+	// Shutdown closes whatever is still open
+	openSnapshot(t, svc)
+	svc.CloseSnapshots()
+	if got := snapshotDB.OpenTxs(); got != 0 {
+		t.Errorf("open transactions after CloseSnapshots = %d, want 0", got)
+	}
+}
+
+func TestSnapshotIdleTimeout(t *testing.T) {
+	svc, snapshotDB := newSnapshotService(t, 20*time.Millisecond)
+	id := openSnapshot(t, svc)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for snapshotDB.OpenTxs() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := snapshotDB.OpenTxs(); got != 0 {
+		t.Fatalf("open transactions after the idle timeout = %d, want 0", got)
+	}
+	if _, err := svc.NextSnapshotPage(context.Background(), id, nil); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("expired snapshot: err = %v, want ErrSnapshotNotFound", err)
+	}
+}
+
+func TestChecksumCompanies(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t)
+	created := createCompanies(t, svc, [2]string{"Acme Ltd", "UK"}, [2]string{"Beta Ltd", "UK"}, [2]string{"Gamma Pte", "Singapore"})
+
+	checksum := func(params api.GetCompaniesParams) *api.CompanyChecksumResponse {
+		t.Helper()
+		resp, err := svc.ChecksumCompanies(ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	uk := api.GetCompaniesParams{Jurisdiction: &[]string{"UK"}}
+	singapore := api.GetCompaniesParams{Jurisdiction: &[]string{"Singapore"}}
+
+	all := checksum(api.GetCompaniesParams{})
+	if all.Total != 3 || len(all.Checksum) != 16 || all.Algorithm != checksumAlgorithm {
+		t.Fatalf("checksum = %+v, want 3 companies with a 16 digit hex checksum", all)
+	}
+	if again := checksum(api.GetCompaniesParams{}); *again != *all {
+		t.Errorf("repeated checksum = %+v, want the stable %+v", again, all)
+	}
+
+	// Row hashes are summed, so the whole is the sum of any partition, whatever the row order
+	parse := func(resp *api.CompanyChecksumResponse) uint64 {
+		sum, err := strconv.ParseUint(resp.Checksum, 16, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sum
+	}
+	before := checksum(uk)
+	if parse(before)+parse(checksum(singapore)) != parse(all) {
+		t.Errorf("UK and Singapore checksums do not add up to the checksum of every company")
+	}
+
+	changes := []struct {
+		name   string
+		change func() error
+		total  int
+	}{
+		{"create", func() error {
+			_, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{CompanyName: "Delta Ltd", CompanyAddress: "4 High Street", Jurisdiction: "UK"})
+			return err
+		}, 3},
+		{"update", func() error {
+			_, err := svc.UpdateCompany(ctx, created[0].Id, api.CreateCompanyRequest{CompanyName: "Acme Holdings Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK"}, nil)
+			return err
+		}, 3},
+		{"delete", func() error { return svc.DeleteCompany(ctx, created[1].Id) }, 2},
+	}
+	unchanged := checksum(singapore)
+	for _, change := range changes {
+		if err := change.change(); err != nil {
+			t.Fatalf("%s: %v", change.name, err)
+		}
+		after := checksum(uk)
+		if after.Checksum == before.Checksum || after.Total != change.total {
+			t.Errorf("after %s: checksum = %+v, want a new checksum over %d companies (was %+v)", change.name, after, change.total, before)
+		}
+		before = after
+	}
+	if got := checksum(singapore); *got != *unchanged {
+		t.Errorf("Singapore checksum = %+v after UK changes, want the unchanged %+v", got, unchanged)
+	}
+}
+
+func TestDeleteCompanies(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService(t)
+	created := createCompanies(t, svc, [2]string{"Acme Ltd", "UK"}, [2]string{"Beta Ltd", "UK"}, [2]string{"Gamma Pte", "Singapore"}, [2]string{"Delta Ltd", "UK"})
+	if err := svc.DeleteCompany(ctx, created[3].Id); err != nil {
+		t.Fatal(err)
+	}
+
+	missing := openapi_types.UUID{0x01}
+	// Repeated IDs are deleted and reported once; already deleted and unknown IDs are reported as not found
+	resp, err := svc.DeleteCompanies(ctx, []openapi_types.UUID{created[0].Id, missing, created[1].Id, created[0].Id, created[3].Id, missing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Deleted != 2 {
+		t.Errorf("deleted = %d, want 2", resp.Deleted)
+	}
+	if len(resp.NotFound) != 2 || resp.NotFound[0] != missing || resp.NotFound[1] != created[3].Id {
+		t.Errorf("not_found = %v, want [%s %s] in request order", resp.NotFound, missing, created[3].Id)
+	}
+
+	if count, err := repo.Count(ctx, repository.Filter{}); err != nil || count != 1 {
+		t.Errorf("live companies = %d (%v), want only Gamma Pte", count, err)
+	}
+
+	// Deleting again finds nothing to delete
+	resp, err = svc.DeleteCompanies(ctx, []openapi_types.UUID{created[0].Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Deleted != 0 || len(resp.NotFound) != 1 {
+		t.Errorf("second delete = %+v, want nothing deleted and the ID not found", resp)
+	}
+
+	tooMany := make([]openapi_types.UUID, maxBatchSize+1)
+	for _, ids := range [][]openapi_types.UUID{nil, tooMany} {
+		if _, err := svc.DeleteCompanies(ctx, ids); !isValidationError(err) {
+			t.Errorf("%d IDs: err = %v, want a validation error", len(ids), err)
+		}
+	}
+}
+
+func TestOmittedOptionalFieldsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t)
+
+	created, err := svc.CreateCompany(ctx, api.CreateCompanyRequest{CompanyName: "Acme Ltd", CompanyAddress: "1 High Street", Jurisdiction: "UK"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	company, err := svc.GetCompanyByID(ctx, created.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, value := range map[string]interface{}{
+		"nature_of_business":     deref(company.NatureOfBusiness),
+		"number_of_directors":    deref(company.NumberOfDirectors),
+		"number_of_shareholders": deref(company.NumberOfShareholders),
+		"sec_code":               deref(company.SecCode),
+		"registry_source":        deref(company.RegistrySource),
+		"registry_number":        deref(company.RegistryNumber),
+	} {
+		if value != nil {
+			t.Errorf("%s = %v, want it omitted as it was on create", name, value)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"backend/api"
+	"backend/internal/repository"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// maxCountDelta bounds a single count adjustment; larger changes should set the count with PUT or PATCH
+const maxCountDelta = maxShareholders
+
+// directorBounds are the limits on number_of_directors, which director records are held to as well: between 1,
+// or the jurisdiction's minimum, and maxDirectors
+func (s *companyService) directorBounds() repository.CountBounds {
+	return repository.CountBounds{
+		Min:               1,
+		Max:               maxDirectors,
+		MinByJurisdiction: s.opts.MinDirectors,
+	}
+}
+
+// AdjustDirectorCount atomically adds delta to a company's number_of_directors, keeping it within directorBounds
+func (s *companyService) AdjustDirectorCount(ctx context.Context, id openapi_types.UUID, delta int) (*api.Company, error) {
+	return s.adjustCount(ctx, id, repository.DirectorCount, delta, s.directorBounds())
+}
+
+// shareholderBounds are the limits on number_of_shareholders, which shareholder records are held to as well:
+// between 1 and maxShareholders, or the jurisdiction's maximum
+func (s *companyService) shareholderBounds() repository.CountBounds {
+	return repository.CountBounds{
+		Min:               1,
+		Max:               maxShareholders,
+		MaxByJurisdiction: s.opts.MaxShareholders,
+	}
+}
+
+// AdjustShareholderCount atomically adds delta to a company's number_of_shareholders, keeping it within
+// shareholderBounds
+func (s *companyService) AdjustShareholderCount(ctx context.Context, id openapi_types.UUID, delta int) (*api.Company, error) {
+	return s.adjustCount(ctx, id, repository.ShareholderCount, delta, s.shareholderBounds())
+}
+
+// adjustCount validates delta and applies it to the count column, returning ErrCompanyNotFound for a missing
+// company, ErrCountOutOfRange when the result would leave bounds and a validation error when records keep the count
+func (s *companyService) adjustCount(ctx context.Context, id openapi_types.UUID, column repository.CountColumn, delta int, bounds repository.CountBounds) (*api.Company, error) {
+	if delta == 0 || delta < -maxCountDelta || delta > maxCountDelta {
+		return nil, fieldErrorf("delta", "delta must be a non-zero integer between %d and %d", -maxCountDelta, maxCountDelta)
+	}
+
+	company, err := s.repo.AdjustCount(ctx, id, column, delta, bounds)
+	if err != nil {
+		if errors.Is(err, repository.ErrCountOutOfRange) {
+			return nil, ErrCountOutOfRange
+		}
+		var syncedErr *repository.SyncedCountError
+		if errors.As(err, &syncedErr) {
+			return nil, syncedCountError(syncedErr)
+		}
+		return nil, fmt.Errorf("failed to adjust %s: %w", column, err)
+	}
+
+	if company == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	s.prepareCompany(company)
+	return company, nil
+}
+
+// syncedCountError reports a write that would set a count kept equal to the company's records
+func syncedCountError(err *repository.SyncedCountError) error {
+	if err.Column == repository.ShareholderCount {
+		return fieldErrorf(string(err.Column),
+			"number of shareholders is set by the company's %d shareholder records; add or remove shareholders instead", err.Records)
+	}
+	return fieldErrorf(string(err.Column),
+		"number of directors is set by the company's %d director records; add or remove directors instead", err.Records)
+}
+
+// checkSyncedCounts rejects a write of req over existing that sets a count away from the company's records, once
+// it has any. Records may be added one at a time below a jurisdiction's minimum, so it returns the counts that
+// the count rules should skip: those kept by records and left unchanged in an unchanged jurisdiction.
+func (s *companyService) checkSyncedCounts(ctx context.Context, existing *api.Company, req api.CreateCompanyRequest) (fieldSet, error) {
+	members, err := s.uncached.CountMembers(ctx, existing.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count directors and shareholders: %w", err)
+	}
+
+	candidate := *existing
+	candidate.NumberOfDirectors, candidate.NumberOfShareholders = req.NumberOfDirectors, req.NumberOfShareholders
+	var syncedErr *repository.SyncedCountError
+	if errors.As(members.Check(&candidate), &syncedErr) {
+		return nil, syncedCountError(syncedErr)
+	}
+
+	synced := fieldSet{}
+	if req.Jurisdiction == existing.Jurisdiction {
+		synced["number_of_directors"] = members.Directors > 0
+		synced["number_of_shareholders"] = members.Shareholders > 0
+	}
+	return synced, nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"backend/api"
+	"backend/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// encodeCursor returns the opaque cursor pointing just after the given company
+func encodeCursor(company api.Company) string {
+	raw := company.DateCreated.UTC().Format(time.RFC3339Nano) + "|" + company.Id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor parses an opaque cursor produced by encodeCursor
+func decodeCursor(cursor string) (*repository.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, validationErrorf("invalid cursor")
+	}
+
+	createdStr, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, validationErrorf("invalid cursor")
+	}
+
+	created, err := time.Parse(time.RFC3339Nano, createdStr)
+	if err != nil {
+		return nil, validationErrorf("invalid cursor")
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, validationErrorf("invalid cursor")
+	}
+
+	return &repository.Cursor{DateCreated: created, ID: id}, nil
+}
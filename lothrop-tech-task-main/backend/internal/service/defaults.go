@@ -0,0 +1,114 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"backend/api"
+)
+
+// FieldDefaults holds default values for optional company fields, applied on create when the request omits them
+type FieldDefaults struct {
+	NatureOfBusiness     *string `json:"nature_of_business"`
+	NumberOfDirectors    *int    `json:"number_of_directors"`
+	NumberOfShareholders *int    `json:"number_of_shareholders"`
+	SecCode              *string `json:"sec_code"`
+}
+
+// ParseFieldDefaults decodes a JSON object mapping jurisdictions to their create defaults,
+// e.g. {"UK": {"nature_of_business": "Holding company"}}. An empty string yields no defaults.
+func ParseFieldDefaults(raw string) (map[string]FieldDefaults, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var defaults map[string]FieldDefaults
+	if err := json.Unmarshal([]byte(raw), &defaults); err != nil {
+		return nil, fmt.Errorf("failed to parse create defaults: %w", err)
+	}
+	return defaults, nil
+}
+
+// apply fills fields the request omitted; explicit request values are left untouched
+func (d FieldDefaults) apply(req *api.CreateCompanyRequest) {
+	if req.NatureOfBusiness == nil {
+		req.NatureOfBusiness = d.NatureOfBusiness
+	}
+	if req.NumberOfDirectors == nil {
+		req.NumberOfDirectors = d.NumberOfDirectors
+	}
+	if req.NumberOfShareholders == nil {
+		req.NumberOfShareholders = d.NumberOfShareholders
+	}
+	if req.SecCode == nil {
+		req.SecCode = d.SecCode
+	}
+}
+
+// Validate checks the configured address country checks, address rules and create defaults, the latter
+// against the field rules applied to requests
+func (o Options) Validate() error {
+	jurisdictions := newJurisdictionSet(o.Jurisdictions)
+
+	for jurisdiction, mode := range o.AddressCountryCheck {
+		if _, ok := jurisdictions.resolve(jurisdiction); !ok {
+			return fmt.Errorf("address country check: unknown jurisdiction %q", jurisdiction)
+		}
+		if mode != addressCheckWarn && mode != addressCheckError {
+			return fmt.Errorf("address country check for %s: mode must be warn or error, got %q", jurisdiction, mode)
+		}
+	}
+
+	for jurisdiction := range o.SecCodeRules.Jurisdictions {
+		if _, ok := jurisdictions.resolve(jurisdiction); !ok {
+			return fmt.Errorf("sec code patterns: unknown jurisdiction %q", jurisdiction)
+		}
+	}
+
+	for jurisdiction := range o.AddressRules {
+		if _, ok := jurisdictions.resolve(jurisdiction); !ok {
+			return fmt.Errorf("address rules: unknown jurisdiction %q", jurisdiction)
+		}
+	}
+
+	switch o.DuplicatePolicy {
+	case "", duplicateReject, duplicateWarn, duplicateAllow:
+	default:
+		return fmt.Errorf("duplicate policy must be reject, warn or allow, got %q", o.DuplicatePolicy)
+	}
+
+	if o.DefaultLimit < 1 || o.DefaultLimit > o.MaxLimit {
+		return fmt.Errorf("list default limit must be between 1 and the max limit, got %d with max %d", o.DefaultLimit, o.MaxLimit)
+	}
+
+	if o.MaxOffset < 0 {
+		return fmt.Errorf("list max offset must not be negative, got %d", o.MaxOffset)
+	}
+
+	if o.IdempotencyTTL <= 0 {
+		return fmt.Errorf("idempotency key TTL must be positive")
+	}
+
+	if o.SoftDeleteRetention <= 0 {
+		return fmt.Errorf("soft delete retention must be positive")
+	}
+
+	if o.MaxSnapshots > 0 && (o.SnapshotIdleTimeout <= 0 || o.SnapshotLifetime <= 0) {
+		return fmt.Errorf("snapshots: idle timeout and lifetime must be positive")
+	}
+
+	s := &companyService{opts: o, jurisdictions: jurisdictions}
+	for jurisdiction, defaults := range o.CreateDefaults {
+		canonical, ok := jurisdictions.resolve(jurisdiction)
+		if !ok {
+			return fmt.Errorf("create defaults: unknown jurisdiction %q", jurisdiction)
+		}
+
+		req := api.CreateCompanyRequest{Jurisdiction: canonical}
+		defaults.apply(&req)
+		if err := s.validateOptionalFields(req); err != nil {
+			return fmt.Errorf("create defaults for %s: %w", canonical, err)
+		}
+	}
+	return nil
+}
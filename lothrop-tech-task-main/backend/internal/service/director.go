@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"backend/api"
+	"backend/internal/repository"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// maxDirectors matches the number_of_directors upper bound, which director records keep in sync
+const maxDirectors = 100
+
+// ListDirectors returns a company's directors, oldest first
+func (s *companyService) ListDirectors(ctx context.Context, companyID openapi_types.UUID) ([]api.Director, error) {
+	directors, err := s.repo.ListDirectors(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directors: %w", err)
+	}
+
+	if directors == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	return directors, nil
+}
+
+// AddDirector validates and adds a director, setting the company's number_of_directors to its director count
+func (s *companyService) AddDirector(ctx context.Context, companyID openapi_types.UUID, req api.CreateDirectorRequest) (*api.Director, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Role = strings.TrimSpace(req.Role)
+	if err := validateDirectorRequest(req); err != nil {
+		return nil, err
+	}
+
+	director, err := s.repo.AddDirector(ctx, companyID, req, s.directorBounds())
+	if err != nil {
+		if errors.Is(err, repository.ErrDirectorLimit) {
+			return nil, fieldErrorf("name", "a company cannot have more than %d directors", maxDirectors)
+		}
+		return nil, fmt.Errorf("failed to add director: %w", err)
+	}
+
+	if director == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	return director, nil
+}
+
+// RemoveDirector removes a director, setting the company's number_of_directors to its remaining director count.
+// The last directors cannot be removed below the jurisdiction's minimum.
+func (s *companyService) RemoveDirector(ctx context.Context, companyID, directorID openapi_types.UUID) error {
+	err := s.repo.RemoveDirector(ctx, companyID, directorID, s.directorBounds())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrDirectorNotFound
+		}
+		if errors.Is(err, repository.ErrDirectorMinimum) {
+			return fieldErrorf("number_of_directors", "removing the director would leave the company fewer directors than its jurisdiction requires")
+		}
+		return fmt.Errorf("failed to remove director: %w", err)
+	}
+
+	return nil
+}
+
+// validateDirectorRequest validates a trimmed add director request, reporting every field violation at once
+func validateDirectorRequest(req api.CreateDirectorRequest) error {
+	var violations fieldErrors
+
+	if req.Name == "" {
+		violations.add("name", "director name is required")
+	} else if utf8.RuneCountInString(req.Name) > 255 {
+		violations.add("name", "director name cannot exceed 255 characters")
+	}
+
+	if req.Role == "" {
+		violations.add("role", "director role is required")
+	} else if utf8.RuneCountInString(req.Role) > 100 {
+		violations.add("role", "director role cannot exceed 100 characters")
+	}
+
+	return violations.err()
+}
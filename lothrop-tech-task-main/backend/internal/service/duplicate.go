@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"backend/api"
+	"backend/internal/textfold"
+)
+
+// Duplicate policies for creates whose name normalizes like a live company's in the same jurisdiction
+const (
+	duplicateReject = "reject"
+	duplicateWarn   = "warn"
+	duplicateAllow  = "allow"
+)
+
+// checkDuplicateName applies the duplicate policy to a validated create request: under reject a near-duplicate
+// name fails with a DuplicateNameError, under warn it is returned as a warning, and under allow it is not looked up
+func (s *companyService) checkDuplicateName(ctx context.Context, req api.CreateCompanyRequest) ([]string, error) {
+	if s.opts.DuplicatePolicy != duplicateReject && s.opts.DuplicatePolicy != duplicateWarn {
+		return nil, nil
+	}
+
+	existing, err := s.repo.FindByNormalizedName(ctx, req.Jurisdiction, req.CompanyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for duplicate names: %w", err)
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	if s.opts.DuplicatePolicy == duplicateReject {
+		return nil, &DuplicateNameError{ExistingID: existing.Id}
+	}
+	return []string{fmt.Sprintf("company_name resembles existing company %q (%s) in %s",
+		existing.CompanyName, existing.Id, existing.Jurisdiction)}, nil
+}
+
+// asDuplicateRowError turns a DuplicateNameError into a field validation error, so batches and imports report
+// the duplicate against its element instead of failing as a whole; other errors are returned unchanged
+func asDuplicateRowError(err error) error {
+	var duplicateErr *DuplicateNameError
+	if errors.As(err, &duplicateErr) {
+		return fieldErrorf("company_name", "company name matches existing company %s", duplicateErr.ExistingID)
+	}
+	return err
+}
+
+// batchKeys records the unique keys taken by the rows of a batch or import validated so far
+type batchKeys struct {
+	registry map[string]bool // Registry source and number
+	names    map[string]bool // Jurisdiction and name key
+	secCodes map[string]bool // Canonical SEC code
+}
+
+func newBatchKeys() batchKeys {
+	return batchKeys{registry: map[string]bool{}, names: map[string]bool{}, secCodes: map[string]bool{}}
+}
+
+// checkBatchUnique rejects a validated create request that shares a unique key with an earlier row of the same
+// batch, so the row is reported on its own instead of the whole insert failing with a conflict. The keys are
+// the ones the database enforces: the name key within a jurisdiction, the canonical SEC code and, when
+// enforced, the registry source and number. A rejected row's keys are not recorded.
+func (s *companyService) checkBatchUnique(req api.CreateCompanyRequest, seen batchKeys) error {
+	registryKey := ""
+	if s.opts.EnforceUniqueRegistryNumbers && req.RegistrySource != nil && req.RegistryNumber != nil {
+		registryKey = *req.RegistrySource + "/" + *req.RegistryNumber
+		if seen.registry[registryKey] {
+			return validationErrorf("registry number %s appears more than once in the batch", *req.RegistryNumber)
+		}
+	}
+
+	nameKey := req.Jurisdiction + "/" + textfold.NameKey(req.CompanyName)
+	if seen.names[nameKey] {
+		return fieldErrorf("company_name", "company name %s appears more than once for %s in the batch",
+			req.CompanyName, req.Jurisdiction)
+	}
+
+	secCode := ""
+	if req.SecCode != nil {
+		secCode = CanonicalSecCode(*req.SecCode)
+		if seen.secCodes[secCode] {
+			return fieldErrorf("sec_code", "sec code %s appears more than once in the batch", secCode)
+		}
+	}
+
+	if registryKey != "" {
+		seen.registry[registryKey] = true
+	}
+	seen.names[nameKey] = true
+	if secCode != "" {
+		seen.secCodes[secCode] = true
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"backend/internal/repository"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// ErrCompanyNotFound is returned when the requested company does not exist
+var ErrCompanyNotFound = errors.New("company not found")
+
+// ErrDirectorNotFound is returned when the company has no director with the requested ID, or the company does not exist
+var ErrDirectorNotFound = errors.New("director not found")
+
+// ErrShareholderNotFound is returned when the company has no shareholder with the requested ID, or the company does not exist
+var ErrShareholderNotFound = errors.New("shareholder not found")
+
+// ErrCompanyAlreadyExists is returned when a write would duplicate a company name within a jurisdiction
+var ErrCompanyAlreadyExists = errors.New("company already exists")
+
+// DuplicateNameError is returned under the reject duplicate policy when a live company in the same jurisdiction
+// has a name that normalizes the same way. It matches ErrCompanyAlreadyExists with errors.Is.
+type DuplicateNameError struct {
+	ExistingID openapi_types.UUID
+}
+
+func (e *DuplicateNameError) Error() string {
+	return fmt.Sprintf("company name duplicates company %s", e.ExistingID)
+}
+
+func (e *DuplicateNameError) Unwrap() error { return ErrCompanyAlreadyExists }
+
+// ErrSecCodeTaken is returned when a write would give a company the sec_code of another live company
+var ErrSecCodeTaken = errors.New("sec_code is already taken")
+
+// ErrCompanyModified is returned when a conditional update's expected version no longer matches the company
+var ErrCompanyModified = errors.New("company was modified")
+
+// ErrSameJurisdiction is returned when a company is transferred to the jurisdiction it is already in
+var ErrSameJurisdiction = errors.New("company is already in the jurisdiction")
+
+// ErrCountOutOfRange is returned when a count adjustment would take a company's director or shareholder count
+// outside its allowed range
+var ErrCountOutOfRange = errors.New("count would leave its allowed range")
+
+// writeError maps a repository write failure to a service error, wrapping unexpected failures with context
+func writeError(err error, context string) error {
+	if errors.Is(err, repository.ErrDuplicateName) {
+		return ErrCompanyAlreadyExists
+	}
+	if errors.Is(err, repository.ErrDuplicateSecCode) {
+		return ErrSecCodeTaken
+	}
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return ErrCompanyModified
+	}
+	var syncedErr *repository.SyncedCountError
+	if errors.As(err, &syncedErr) {
+		return syncedCountError(syncedErr)
+	}
+	return fmt.Errorf("%s: %w", context, err)
+}
+
+// ValidationError reports invalid client input. The message format and arguments are kept
+// separately so handlers can localize the message.
+type ValidationError struct {
+	// Field names the offending request field, when the error is specific to one
+	Field  string
+	Format string
+	Args   []interface{}
+	// Fields lists every field violation found in the request; the error's own message is the first of them
+	Fields []*ValidationError
+}
+
+// Error returns the English message
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf(e.Format, e.Args...)
+}
+
+// BatchValidationError reports the invalid elements of a batch request; nothing in the batch was applied
+type BatchValidationError struct {
+	Items []BatchItemError
+}
+
+// BatchItemError is the validation failure of one batch element
+type BatchItemError struct {
+	// Index is the element's position in the request
+	Index int
+	Err   *ValidationError
+}
+
+// Error returns the English messages of every invalid element
+func (e *BatchValidationError) Error() string {
+	messages := make([]string, len(e.Items))
+	for i, item := range e.Items {
+		messages[i] = fmt.Sprintf("index %d: %s", item.Index, item.Err.Error())
+	}
+	return "invalid batch: " + strings.Join(messages, "; ")
+}
+
+// validationErrorf returns a *ValidationError for the given English message format
+func validationErrorf(format string, args ...interface{}) error {
+	return &ValidationError{Format: format, Args: args}
+}
+
+// fieldErrors accumulates the field violations of one request, so they can be reported together
+type fieldErrors []*ValidationError
+
+// add records a violation of the given request field
+func (f *fieldErrors) add(field, format string, args ...interface{}) {
+	*f = append(*f, &ValidationError{Field: field, Format: format, Args: args})
+}
+
+// addErr records err, which must be nil or a *ValidationError
+func (f *fieldErrors) addErr(err error) {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		*f = append(*f, validationErr)
+	}
+}
+
+// err returns nil when no violation was recorded, otherwise a *ValidationError carrying the first violation's
+// message and listing all of them in Fields
+func (f fieldErrors) err() error {
+	if len(f) == 0 {
+		return nil
+	}
+	first := *f[0]
+	first.Fields = f
+	return &first
+}
+
+// fieldErrorf returns a *ValidationError for the given request field and English message format
+func fieldErrorf(field, format string, args ...interface{}) error {
+	return &ValidationError{Field: field, Format: format, Args: args}
+}
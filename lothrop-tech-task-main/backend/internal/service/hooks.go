@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"backend/api"
+)
+
+// PreCreateHook lets a deployment enrich or reject a company before it is validated and stored, e.g. filling
+// nature_of_business from an external classifier. It runs after jurisdiction defaults are applied.
+type PreCreateHook interface {
+	// PreCreate may modify req. A returned *ValidationError is reported as is; any other error rejects the
+	// create with a 422, except context errors, which are passed through.
+	PreCreate(ctx context.Context, req *api.CreateCompanyRequest) error
+}
+
+// noopPreCreateHook is the default PreCreateHook, leaving every request unchanged
+type noopPreCreateHook struct{}
+
+func (noopPreCreateHook) PreCreate(context.Context, *api.CreateCompanyRequest) error {
+	return nil
+}
+
+// runPreCreateHook invokes the configured hook, mapping its rejections to validation errors
+func (s *companyService) runPreCreateHook(ctx context.Context, req *api.CreateCompanyRequest) error {
+	err := s.opts.PreCreateHook.PreCreate(ctx, req)
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return fieldErrorf("company", "company rejected: %s", err.Error())
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/api"
+	"backend/internal/repository"
+)
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with a different request
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+
+// maxIdempotencyKeyLength matches the idempotency_keys.key column
+const maxIdempotencyKeyLength = 255
+
+// CreateCompanyIdempotent creates a company once per idempotency key. Repeating the same request with the key
+// before it expires returns the company created the first time, with replayed set, instead of creating another.
+func (s *companyService) CreateCompanyIdempotent(ctx context.Context, key string, req api.CreateCompanyRequest) (*api.Company, bool, error) {
+	if err := validateIdempotencyKey(key); err != nil {
+		return nil, false, err
+	}
+
+	// Fingerprint the request as sent, before defaults and normalization, so a retry matches exactly
+	hash, err := requestHash(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	record, err := s.repo.GetIdempotencyRecord(ctx, key, s.opts.IdempotencyTTL)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to look up idempotency key: %w", err)
+	}
+	if record != nil {
+		return s.replay(record, hash)
+	}
+
+	warnings, err := s.prepareCreateRequest(ctx, &req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := s.createLimiter.reserve(1, time.Now()); err != nil {
+		return nil, false, err
+	}
+
+	company, record, err := s.repo.CreateIdempotent(ctx, key, hash, s.opts.IdempotencyTTL, req)
+	if err != nil {
+		return nil, false, writeError(err, "failed to create company")
+	}
+	if record != nil {
+		// A concurrent request with the same key won
+		return s.replay(record, hash)
+	}
+	if company == nil {
+		return nil, false, fmt.Errorf("failed to create company: idempotency key %q claimed without a company", key)
+	}
+
+	s.prepareCompany(company)
+	if len(warnings) > 0 {
+		company.Warnings = &warnings
+	}
+	return company, false, nil
+}
+
+// replay returns the company recorded for an idempotency key, provided the request matches the original
+func (s *companyService) replay(record *repository.IdempotencyRecord, hash string) (*api.Company, bool, error) {
+	if record.RequestHash != hash {
+		return nil, false, ErrIdempotencyKeyReused
+	}
+
+	s.prepareCompany(record.Company)
+	return record.Company, true, nil
+}
+
+// validateIdempotencyKey accepts 1 to 255 printable ASCII characters
+func validateIdempotencyKey(key string) error {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return validationErrorf("Idempotency-Key must be between 1 and %d characters", maxIdempotencyKeyLength)
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return validationErrorf("Idempotency-Key must contain only printable ASCII characters")
+		}
+	}
+	return nil
+}
+
+// requestHash returns the hex SHA-256 of the request's JSON encoding
+func requestHash(req api.CreateCompanyRequest) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fingerprint request: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
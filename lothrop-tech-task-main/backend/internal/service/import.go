@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"backend/api"
+)
+
+// maxImportRows caps the number of data rows accepted by ImportCompaniesCSV
+const maxImportRows = 5000
+
+// ImportResult reports the outcome of each data row of an import
+type ImportResult struct {
+	// Strict reports whether the import ran in strict mode, where any invalid row prevents every insert
+	Strict bool
+	// DryRun reports whether the created companies were rolled back rather than saved
+	DryRun bool
+	Rows   []ImportRowResult
+}
+
+// ImportRowResult is the outcome of one CSV data row. Exactly one of Company and Err is set,
+// unless a strict import skipped this valid row because another row was invalid.
+type ImportRowResult struct {
+	// Line is the row's line number in the file, counting the header as line 1
+	Line    int
+	Company *api.Company
+	Err     *ValidationError
+}
+
+// Failed returns the number of invalid rows
+func (r *ImportResult) Failed() int {
+	failed := 0
+	for _, row := range r.Rows {
+		if row.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// ImportCompaniesCSV parses a CSV file with a header row into create requests, validates each row and inserts
+// the valid ones in one transaction, which a dry run rolls back. Columns are matched by header name; unknown
+// columns, such as the id and dates of an export, are ignored. In lenient mode invalid rows are reported and
+// the rest are created; in strict mode any invalid row means nothing is created.
+func (s *companyService) ImportCompaniesCSV(ctx context.Context, file io.Reader, strict, dryRun bool) (*ImportResult, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // Short rows are reported per line rather than failing the file
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, validationErrorf("CSV file is empty")
+		}
+		return nil, validationErrorf("invalid CSV header")
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"jurisdiction", "company_name", "company_address"} {
+		if _, ok := columns[required]; !ok {
+			return nil, validationErrorf("CSV header is missing the %s column", required)
+		}
+	}
+
+	result := &ImportResult{Strict: strict, DryRun: dryRun}
+	var reqs []api.CreateCompanyRequest
+	var valid []int // Indices into result.Rows of the rows in reqs
+	seen := newBatchKeys()
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("failed to read CSV: %w", err)
+			}
+			result.Rows = append(result.Rows, ImportRowResult{Line: parseErr.StartLine, Err: &ValidationError{Format: "malformed CSV row"}})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		if len(result.Rows) >= maxImportRows {
+			return nil, validationErrorf("CSV file cannot exceed %d rows", maxImportRows)
+		}
+
+		row := ImportRowResult{Line: line}
+		req, err := importRequest(record, columns)
+		if err == nil {
+			_, err = s.prepareCreateRequest(ctx, &req)
+			err = asDuplicateRowError(err)
+		}
+		if err == nil {
+			err = s.checkBatchUnique(req, seen)
+		}
+		if err != nil {
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				return nil, err
+			}
+			row.Err = validationErr
+		} else {
+			reqs = append(reqs, req)
+			valid = append(valid, len(result.Rows))
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	if len(result.Rows) == 0 {
+		return nil, validationErrorf("CSV file has no data rows")
+	}
+
+	if len(reqs) == 0 || (strict && result.Failed() > 0) {
+		return result, nil
+	}
+
+	companies, err := s.createMany(ctx, reqs, dryRun)
+	if err != nil {
+		return nil, writeError(err, "failed to import companies")
+	}
+
+	for i := range companies {
+		s.prepareCompany(&companies[i])
+		result.Rows[valid[i]].Company = &companies[i]
+	}
+
+	return result, nil
+}
+
+// importRequest builds a create request from a CSV record; blank optional cells are treated as omitted
+func importRequest(record []string, columns map[string]int) (api.CreateCompanyRequest, error) {
+	cell := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	optionalText := func(name string) *string {
+		if value := cell(name); value != "" {
+			return &value
+		}
+		return nil
+	}
+	optionalInt := func(name string) (*int, error) {
+		value := cell(name)
+		if value == "" {
+			return nil, nil
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fieldErrorf(name, "%s must be a whole number", name)
+		}
+		return &parsed, nil
+	}
+
+	req := api.CreateCompanyRequest{
+		Jurisdiction:     cell("jurisdiction"),
+		CompanyName:      cell("company_name"),
+		CompanyAddress:   cell("company_address"),
+		NatureOfBusiness: optionalText("nature_of_business"),
+		SecCode:          optionalText("sec_code"),
+		RegistrySource:   optionalText("registry_source"),
+		RegistryNumber:   optionalText("registry_number"),
+	}
+
+	var err error
+	if req.NumberOfDirectors, err = optionalInt("number_of_directors"); err != nil {
+		return req, err
+	}
+	if req.NumberOfShareholders, err = optionalInt("number_of_shareholders"); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import "strings"
+
+// DefaultJurisdictions are the jurisdictions accepted when none are configured
+var DefaultJurisdictions = []string{"UK", "Singapore", "Cayman Islands"}
+
+// jurisdictionAliases maps normalized jurisdiction inputs to their canonical stored value
+var jurisdictionAliases = map[string]string{
+	"uk":                    "UK",
+	"u.k.":                  "UK",
+	"gb":                    "UK",
+	"united kingdom":        "UK",
+	"great britain":         "UK",
+	"england":               "UK",
+	"singapore":             "Singapore",
+	"sg":                    "Singapore",
+	"republic of singapore": "Singapore",
+	"cayman islands":        "Cayman Islands",
+	"cayman":                "Cayman Islands",
+	"caymans":               "Cayman Islands",
+	"caymens":               "Cayman Islands", // legacy stored spelling
+	"ky":                    "Cayman Islands",
+}
+
+// jurisdictionSet resolves inputs to the allowed jurisdictions
+type jurisdictionSet struct {
+	// names lists the canonical names in configured order
+	names []string
+	// lookup maps normalized names and aliases to canonical names
+	lookup map[string]string
+}
+
+// newJurisdictionSet allows the named jurisdictions, or DefaultJurisdictions when names is empty. A name that is
+// an alias of a known jurisdiction allows that jurisdiction under its canonical name, together with its aliases.
+func newJurisdictionSet(names []string) jurisdictionSet {
+	if len(names) == 0 {
+		names = DefaultJurisdictions
+	}
+
+	set := jurisdictionSet{lookup: map[string]string{}}
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		if name == "" {
+			continue
+		}
+		if canonical, ok := jurisdictionAliases[normalizeJurisdictionInput(name)]; ok {
+			name = canonical
+		}
+		if _, ok := set.lookup[normalizeJurisdictionInput(name)]; ok {
+			continue
+		}
+		set.names = append(set.names, name)
+		set.lookup[normalizeJurisdictionInput(name)] = name
+	}
+
+	for alias, canonical := range jurisdictionAliases {
+		if _, ok := set.lookup[normalizeJurisdictionInput(canonical)]; ok {
+			set.lookup[alias] = canonical
+		}
+	}
+	return set
+}
+
+// normalizeJurisdictionInput lowercases the input and collapses surrounding and repeated whitespace
+func normalizeJurisdictionInput(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
+
+// canonical returns the canonical value for an allowed jurisdiction or alias, and the input unchanged otherwise
+func (j jurisdictionSet) canonical(value string) string {
+	if canonical, ok := j.resolve(value); ok {
+		return canonical
+	}
+	return value
+}
+
+// resolve maps an arbitrary jurisdiction input to its canonical value, if it is allowed
+func (j jurisdictionSet) resolve(value string) (string, bool) {
+	canonical, ok := j.lookup[normalizeJurisdictionInput(value)]
+	return canonical, ok
+}
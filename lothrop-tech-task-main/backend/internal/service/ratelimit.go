@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCreateRateExceeded is returned when a create would exceed the global company creation rate
+var ErrCreateRateExceeded = errors.New("company creation rate exceeded")
+
+// createRateWindow is the sliding window over which the global creation rate is measured
+const createRateWindow = time.Minute
+
+// createRateLimiter caps company creations across all clients within a sliding window, keeping the time of
+// each creation still inside the window
+type createRateLimiter struct {
+	mu    sync.Mutex
+	limit int
+	times []time.Time
+}
+
+// newCreateRateLimiter returns a limiter allowing limit creations per window, or nil when limit is 0
+func newCreateRateLimiter(limit int) *createRateLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &createRateLimiter{limit: limit}
+}
+
+// reserve records n creations at now, or returns ErrCreateRateExceeded without recording any when they
+// would exceed the limit. A nil limiter allows everything.
+func (l *createRateLimiter) reserve(n int, now time.Time) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := now.Add(-createRateWindow)
+	expired := 0
+	for expired < len(l.times) && !l.times[expired].After(cutoff) {
+		expired++
+	}
+	l.times = l.times[expired:]
+
+	if len(l.times)+n > l.limit {
+		return ErrCreateRateExceeded
+	}
+	for i := 0; i < n; i++ {
+		l.times = append(l.times, now)
+	}
+	return nil
+}
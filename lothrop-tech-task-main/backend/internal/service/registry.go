@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"backend/api"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// registryFormat describes the external registry of record for a jurisdiction
+type registryFormat struct {
+	source  string
+	pattern *regexp.Regexp
+}
+
+// registryFormats maps each jurisdiction to its registry source and company number format
+var registryFormats = map[string]registryFormat{
+	// Companies House: eight digits, or a two-letter prefix (e.g. SC, NI) and six digits
+	"UK": {source: "companies_house", pattern: regexp.MustCompile(`^([0-9]{8}|[A-Z]{2}[0-9]{6})$`)},
+	// ACRA Unique Entity Number: business, local company or other entity formats
+	"Singapore": {source: "acra", pattern: regexp.MustCompile(`^([0-9]{8}[A-Z]|[0-9]{9}[A-Z]|[ST][0-9]{2}[A-Z]{2}[0-9]{4}[A-Z])$`)},
+	// Cayman Islands General Registry: optional two-letter entity prefix and four to six digits
+	"Cayman Islands": {source: "cayman_registry", pattern: regexp.MustCompile(`^([A-Z]{2}-)?[0-9]{4,6}$`)},
+}
+
+// normalizeRegistryFields trims the registry source and number, uppercases the number and clears blank values
+func normalizeRegistryFields(req *api.CreateCompanyRequest) {
+	if req.RegistrySource != nil {
+		source := strings.ToLower(strings.TrimSpace(*req.RegistrySource))
+		req.RegistrySource = &source
+		if source == "" {
+			req.RegistrySource = nil
+		}
+	}
+
+	if req.RegistryNumber != nil {
+		number := strings.ToUpper(strings.TrimSpace(*req.RegistryNumber))
+		req.RegistryNumber = &number
+		if number == "" {
+			req.RegistryNumber = nil
+		}
+	}
+}
+
+// validateRegistryFields checks the registry source and number against the jurisdiction's registry format
+func validateRegistryFields(req api.CreateCompanyRequest) error {
+	if req.RegistrySource == nil && req.RegistryNumber == nil {
+		return nil
+	}
+
+	if req.RegistrySource == nil || req.RegistryNumber == nil {
+		field := "registry_number"
+		if req.RegistrySource == nil {
+			field = "registry_source"
+		}
+		return fieldErrorf(field, "registry source and registry number must be provided together")
+	}
+
+	format, ok := registryFormats[req.Jurisdiction]
+	if !ok {
+		return nil // Jurisdiction is validated separately
+	}
+
+	if *req.RegistrySource != format.source {
+		return fieldErrorf("registry_source", "registry source for %s must be %s", req.Jurisdiction, format.source)
+	}
+
+	if !format.pattern.MatchString(*req.RegistryNumber) {
+		return fieldErrorf("registry_number", "invalid registry number format for %s", format.source)
+	}
+
+	return nil
+}
+
+// checkRegistryUnique rejects a registry source and number already linked to a company other than exclude
+func (s *companyService) checkRegistryUnique(ctx context.Context, req api.CreateCompanyRequest, exclude *openapi_types.UUID) error {
+	if !s.opts.EnforceUniqueRegistryNumbers || req.RegistrySource == nil || req.RegistryNumber == nil {
+		return nil
+	}
+
+	existing, err := s.repo.GetByRegistry(ctx, *req.RegistrySource, *req.RegistryNumber)
+	if err != nil {
+		return fmt.Errorf("failed to check registry number: %w", err)
+	}
+
+	if existing != nil && (exclude == nil || existing.Id != *exclude) {
+		return validationErrorf("registry number %s is already linked to another company", *req.RegistryNumber)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"backend/api"
+)
+
+// SecCodeRules are the formats a non-empty sec_code must match
+type SecCodeRules struct {
+	// Default applies to jurisdictions without their own pattern; nil accepts any sec_code
+	Default *regexp.Regexp
+	// Jurisdictions overrides Default per jurisdiction; a nil pattern accepts any sec_code there
+	Jurisdictions map[string]*regexp.Regexp
+}
+
+// ParseSecCodeRules compiles the default sec_code pattern and a JSON object mapping jurisdictions to their own
+// patterns, e.g. {"Singapore": "^[0-9]{9}[A-Z]$"}. An empty pattern disables the check where it applies.
+func ParseSecCodeRules(defaultPattern, raw string) (SecCodeRules, error) {
+	var rules SecCodeRules
+
+	var err error
+	if defaultPattern != "" {
+		if rules.Default, err = regexp.Compile(defaultPattern); err != nil {
+			return SecCodeRules{}, fmt.Errorf("invalid default sec code pattern: %w", err)
+		}
+	}
+
+	if raw == "" {
+		return rules, nil
+	}
+
+	var patterns map[string]string
+	if err := json.Unmarshal([]byte(raw), &patterns); err != nil {
+		return SecCodeRules{}, fmt.Errorf("failed to parse sec code patterns: %w", err)
+	}
+
+	rules.Jurisdictions = make(map[string]*regexp.Regexp, len(patterns))
+	for jurisdiction, pattern := range patterns {
+		var compiled *regexp.Regexp
+		if pattern != "" {
+			if compiled, err = regexp.Compile(pattern); err != nil {
+				return SecCodeRules{}, fmt.Errorf("invalid sec code pattern for %s: %w", jurisdiction, err)
+			}
+		}
+		rules.Jurisdictions[jurisdiction] = compiled
+	}
+	return rules, nil
+}
+
+// pattern returns the sec_code pattern for a canonical jurisdiction, or nil when any sec_code is accepted
+func (r SecCodeRules) pattern(jurisdiction string) *regexp.Regexp {
+	if pattern, ok := r.Jurisdictions[jurisdiction]; ok {
+		return pattern
+	}
+	return r.Default
+}
+
+// checkSecCode records a violation when a non-empty sec_code does not match its jurisdiction's pattern
+func (s *companyService) checkSecCode(req api.CreateCompanyRequest, violations *fieldErrors) {
+	if req.SecCode == nil {
+		return
+	}
+
+	code := strings.TrimSpace(*req.SecCode)
+	if code == "" {
+		return
+	}
+
+	if pattern := s.opts.SecCodeRules.pattern(req.Jurisdiction); pattern != nil && !pattern.MatchString(code) {
+		violations.add("sec_code", "sec code does not match the required format for %s", req.Jurisdiction)
+	}
+}
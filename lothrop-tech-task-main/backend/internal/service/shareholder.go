@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"backend/api"
+	"backend/internal/repository"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// maxShareholders matches the number_of_shareholders upper bound, which shareholder records keep in sync
+const maxShareholders = 1000
+
+// ListShareholders returns a company's shareholders, oldest first
+func (s *companyService) ListShareholders(ctx context.Context, companyID openapi_types.UUID) ([]api.Shareholder, error) {
+	shareholders, err := s.repo.ListShareholders(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shareholders: %w", err)
+	}
+
+	if shareholders == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	return shareholders, nil
+}
+
+// AddShareholder validates and adds a shareholder, setting the company's number_of_shareholders to its shareholder count
+func (s *companyService) AddShareholder(ctx context.Context, companyID openapi_types.UUID, req api.CreateShareholderRequest) (*api.Shareholder, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validateShareholderRequest(req); err != nil {
+		return nil, err
+	}
+
+	shareholder, err := s.repo.AddShareholder(ctx, companyID, req, s.shareholderBounds())
+	if err != nil {
+		if errors.Is(err, repository.ErrShareholderLimit) {
+			return nil, fieldErrorf("name", "the company already has the most shareholders its jurisdiction allows")
+		}
+		if errors.Is(err, repository.ErrOwnershipExceeded) {
+			return nil, validationErrorf("total ownership across a company's shareholders cannot exceed 100%%")
+		}
+		return nil, fmt.Errorf("failed to add shareholder: %w", err)
+	}
+
+	if shareholder == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	return shareholder, nil
+}
+
+// UpdateShareholder validates and replaces a shareholder's name and ownership percentage
+func (s *companyService) UpdateShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID, req api.CreateShareholderRequest) (*api.Shareholder, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validateShareholderRequest(req); err != nil {
+		return nil, err
+	}
+
+	shareholder, err := s.repo.UpdateShareholder(ctx, companyID, shareholderID, req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrShareholderNotFound
+		}
+		if errors.Is(err, repository.ErrOwnershipExceeded) {
+			return nil, validationErrorf("total ownership across a company's shareholders cannot exceed 100%%")
+		}
+		return nil, fmt.Errorf("failed to update shareholder: %w", err)
+	}
+
+	return shareholder, nil
+}
+
+// RemoveShareholder removes a shareholder, setting the company's number_of_shareholders to its remaining shareholder
+// count. The last shareholder cannot be removed.
+func (s *companyService) RemoveShareholder(ctx context.Context, companyID, shareholderID openapi_types.UUID) error {
+	err := s.repo.RemoveShareholder(ctx, companyID, shareholderID, s.shareholderBounds())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrShareholderNotFound
+		}
+		if errors.Is(err, repository.ErrShareholderMinimum) {
+			return fieldErrorf("number_of_shareholders", "a company must keep at least one shareholder")
+		}
+		return fmt.Errorf("failed to remove shareholder: %w", err)
+	}
+
+	return nil
+}
+
+// validateShareholderRequest validates a trimmed shareholder request, reporting every field violation at once
+func validateShareholderRequest(req api.CreateShareholderRequest) error {
+	var violations fieldErrors
+
+	if req.Name == "" {
+		violations.add("name", "shareholder name is required")
+	} else if utf8.RuneCountInString(req.Name) > 255 {
+		violations.add("name", "shareholder name cannot exceed 255 characters")
+	}
+
+	// Stored as NUMERIC(5, 2), so more precision would be silently rounded away
+	percentage := req.OwnershipPercentage
+	if percentage <= 0 || percentage > 100 {
+		violations.add("ownership_percentage", "ownership percentage must be greater than 0 and at most 100")
+	} else if hundredths := percentage * 100; math.Abs(hundredths-math.Round(hundredths)) > 1e-6 {
+		violations.add("ownership_percentage", "ownership percentage cannot have more than 2 decimal places")
+	}
+
+	return violations.err()
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend/api"
+	"backend/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrSnapshotNotFound is returned for a snapshot ID that was never opened, or was closed or expired
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// ErrSnapshotLimit is returned when opening a snapshot while the maximum number are already open
+var ErrSnapshotLimit = errors.New("too many open snapshots")
+
+// snapshotSession is an open snapshot and the position reached in it
+type snapshotSession struct {
+	mu       sync.Mutex
+	snapshot *repository.Snapshot
+	filter   repository.Filter
+	expires  time.Time
+	timer    *time.Timer
+}
+
+// snapshotRegistry tracks the open snapshots. Each holds a database connection and transaction, so their number
+// is capped and each is closed once idle for idleTimeout or open for lifetime, whichever comes first.
+type snapshotRegistry struct {
+	mu          sync.Mutex
+	sessions    map[string]*snapshotSession
+	opening     int
+	max         int
+	idleTimeout time.Duration
+	lifetime    time.Duration
+}
+
+// newSnapshotRegistry returns a registry allowing max open snapshots, or nil when max is 0
+func newSnapshotRegistry(max int, idleTimeout, lifetime time.Duration) *snapshotRegistry {
+	if max <= 0 {
+		return nil
+	}
+	return &snapshotRegistry{
+		sessions:    make(map[string]*snapshotSession),
+		max:         max,
+		idleTimeout: idleTimeout,
+		lifetime:    lifetime,
+	}
+}
+
+// reserve claims a slot for a snapshot about to be opened, returning false when none is free
+func (r *snapshotRegistry) reserve() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.sessions)+r.opening >= r.max {
+		return false
+	}
+	r.opening++
+	return true
+}
+
+// add registers an opened snapshot in a reserved slot, or just frees the slot when session is nil
+func (r *snapshotRegistry) add(id string, session *snapshotSession) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.opening--
+	if session != nil {
+		r.sessions[id] = session
+		session.timer = time.AfterFunc(r.timeout(session), func() { r.close(id) })
+	}
+}
+
+// get returns the open snapshot with the ID, or nil
+func (r *snapshotRegistry) get(id string) *snapshotSession {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sessions[id]
+}
+
+// touch restarts the snapshot's idle timeout after it was read
+func (r *snapshotRegistry) touch(session *snapshotSession) {
+	session.timer.Reset(r.timeout(session))
+}
+
+// timeout is how long the snapshot may stay idle before it is closed
+func (r *snapshotRegistry) timeout(session *snapshotSession) time.Duration {
+	return min(r.idleTimeout, time.Until(session.expires))
+}
+
+// close removes and rolls back the snapshot with the ID, returning false if it is not open
+func (r *snapshotRegistry) close(id string) bool {
+	r.mu.Lock()
+	session, ok := r.sessions[id]
+	delete(r.sessions, id)
+	r.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	session.timer.Stop()
+	session.snapshot.Close()
+	return true
+}
+
+// closeAll rolls back every open snapshot
+func (r *snapshotRegistry) closeAll() {
+	r.mu.Lock()
+	ids := make([]string, 0, len(r.sessions))
+	for id := range r.sessions {
+		ids = append(ids, id)
+	}
+	r.mu.Unlock()
+
+	for _, id := range ids {
+		r.close(id)
+	}
+}
+
+// OpenSnapshot starts a consistent snapshot of the companies matching the list filters, to be paged with
+// NextSnapshotPage. It returns ErrSnapshotLimit when the maximum number of snapshots are already open.
+func (s *companyService) OpenSnapshot(ctx context.Context, params api.GetCompaniesParams) (*api.SnapshotResponse, error) {
+	if params.Cursor != nil {
+		return nil, validationErrorf("cursor cannot be used with snapshots")
+	}
+
+	filter, err := s.filterFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.snapshots == nil || !s.snapshots.reserve() {
+		return nil, ErrSnapshotLimit
+	}
+
+	id := uuid.NewString()
+	snapshot, err := s.repo.BeginSnapshot(s.snapshots.lifetime)
+	if err != nil {
+		s.snapshots.add(id, nil)
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+
+	session := &snapshotSession{
+		snapshot: snapshot,
+		filter:   filter,
+		expires:  time.Now().Add(s.snapshots.lifetime),
+	}
+	s.snapshots.add(id, session)
+
+	return &api.SnapshotResponse{SnapshotId: id, ExpiresAt: session.expires.UTC()}, nil
+}
+
+// NextSnapshotPage returns the next page of a snapshot. The snapshot is closed after its last page, so
+// has_more false means the snapshot ID is no longer valid.
+func (s *companyService) NextSnapshotPage(ctx context.Context, id string, limit *int) (*api.SnapshotPage, error) {
+	pageLimit := 100
+	if limit != nil {
+		pageLimit = *limit
+	}
+	if pageLimit < 1 || pageLimit > 1000 {
+		return nil, validationErrorf("snapshot page limit must be between 1 and 1000")
+	}
+
+	var session *snapshotSession
+	if s.snapshots != nil {
+		session = s.snapshots.get(id)
+	}
+	if session == nil {
+		return nil, ErrSnapshotNotFound
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	// One extra row tells whether another page follows
+	companies, err := session.snapshot.Page(ctx, session.filter, pageLimit+1)
+	if err != nil {
+		// A failed query aborts the transaction, so the snapshot cannot be read again
+		s.snapshots.close(id)
+		if errors.Is(err, sql.ErrTxDone) {
+			return nil, ErrSnapshotNotFound // Expired while the page was requested
+		}
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	hasMore := len(companies) > pageLimit
+	if hasMore {
+		companies = companies[:pageLimit]
+		last := companies[len(companies)-1]
+		session.filter.After = &repository.Cursor{DateCreated: last.DateCreated, ID: last.Id}
+		s.snapshots.touch(session)
+	} else {
+		s.snapshots.close(id)
+	}
+
+	for i := range companies {
+		s.prepareCompany(&companies[i])
+	}
+
+	return &api.SnapshotPage{Companies: companies, HasMore: hasMore, ExpiresAt: session.expires.UTC()}, nil
+}
+
+// CloseSnapshot releases a snapshot before its last page
+func (s *companyService) CloseSnapshot(id string) error {
+	if s.snapshots == nil || !s.snapshots.close(id) {
+		return ErrSnapshotNotFound
+	}
+	return nil
+}
+
+// CloseSnapshots releases every open snapshot, e.g. on shutdown
+func (s *companyService) CloseSnapshots() {
+	if s.snapshots != nil {
+		s.snapshots.closeAll()
+	}
+}
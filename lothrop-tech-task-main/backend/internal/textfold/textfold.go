@@ -0,0 +1,70 @@
+package textfold
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// letters spells out the letters Postgres unaccent folds but Unicode does not decompose, e.g. "ø" and "ß"
+var letters = strings.NewReplacer(
+	"Æ", "AE", "æ", "ae", "Œ", "OE", "œ", "oe", "Ø", "O", "ø", "o", "ß", "ss", "Ł", "L", "ł", "l",
+	"Đ", "D", "đ", "d", "Ð", "D", "ð", "d", "Þ", "TH", "þ", "th",
+)
+
+// Fold returns the accent- and case-insensitive search key for s, e.g. "Müller" becomes "muller".
+// Text is decomposed (NFKD), combining marks are dropped, letters without a decomposition are spelled out as
+// unaccent does and the result is lowercased, matching the LOWER(unaccent(...)) backfills of the migrations.
+func Fold(s string) string {
+	// Transformers carry state, so each call builds its own chain
+	t := transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+	return strings.ToLower(letters.Replace(folded))
+}
+
+// NameKey returns the key company names are compared by for uniqueness: Fold with runs of whitespace
+// collapsed to one space and the ends trimmed, so "Acme  Ltd" and "acme ltd" share a key
+func NameKey(s string) string {
+	return strings.Join(strings.Fields(Fold(s)), " ")
+}
+
+// legalWords maps spelled-out legal form words to the abbreviation NormalizedName uses for them
+var legalWords = map[string]string{
+	"limited":      "ltd",
+	"incorporated": "inc",
+	"corporation":  "corp",
+	"company":      "co",
+	"private":      "pte",
+}
+
+// NormalizedName returns the key near-duplicate company names share: Fold with full stops and apostrophes
+// dropped, "&" spelled "and", other punctuation treated as spaces and legal form words abbreviated, so
+// "ACME LTD." and "Acme Limited" both become "acme ltd"
+func NormalizedName(s string) string {
+	var b strings.Builder
+	for _, r := range Fold(s) {
+		switch {
+		case r == '.' || r == '\'' || r == '’':
+		case r == '&':
+			b.WriteString(" and ")
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	words := strings.Fields(b.String())
+	for i, word := range words {
+		if abbreviation, ok := legalWords[word]; ok {
+			words[i] = abbreviation
+		}
+	}
+	return strings.Join(words, " ")
+}
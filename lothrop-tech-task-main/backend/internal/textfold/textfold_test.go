@@ -0,0 +1,63 @@
+package textfold
+
+import "testing"
+
+// The expected keys are the ones the migrations' SQL backfills produce for the same names, so companies
+// written before and after each migration compare alike
+
+func TestFold(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Acme Ltd", "acme ltd"},
+		{"Müller GmbH", "muller gmbh"},
+		{"Société Générale", "societe generale"},
+		{"Ørsted A/S", "orsted a/s"},
+		{"Straße AG", "strasse ag"},
+		{"Cæsar & Œuvre", "caesar & oeuvre"},
+		{"Łódź Holdings", "lodz holdings"},
+		{"Þór ehf", "thor ehf"},
+		{"  Two  Spaces ", "  two  spaces "}, // Fold keeps whitespace; NameKey collapses it
+	}
+
+	for _, tt := range tests {
+		if got := Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNameKey(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Acme Ltd", "acme ltd"},
+		{"Acme   Ltd", "acme ltd"},
+		{"Acme\tLtd\n", "acme ltd"},
+		{"Ácme Ltd", "acme ltd"},
+		{"Acme Ltd.", "acme ltd."}, // Punctuation is kept; NormalizedName drops it
+	}
+
+	for _, tt := range tests {
+		if got := NameKey(tt.in); got != tt.want {
+			t.Errorf("NameKey(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizedName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"ACME LTD.", "acme ltd"},
+		{"Acme Limited", "acme ltd"},
+		{"Acme, Inc.", "acme inc"},
+		{"Acme Incorporated", "acme inc"},
+		{"Acme Corporation", "acme corp"},
+		{"Acme & Company", "acme and co"},
+		{"Lion City Private Limited", "lion city pte ltd"},
+		{"O'Brien’s Café", "obriens cafe"},
+		{"Acme-Asia (Holdings)", "acme asia holdings"},
+		{"Limitedness Ltd", "limitedness ltd"}, // Only whole words are abbreviated
+	}
+
+	for _, tt := range tests {
+		if got := NormalizedName(tt.in); got != tt.want {
+			t.Errorf("NormalizedName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
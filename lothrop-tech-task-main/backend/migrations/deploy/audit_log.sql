@@ -0,0 +1,17 @@
+-- Deploy lothrop-backend:audit_log to pg
+-- requires: companies
+
+BEGIN;
+
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    actor TEXT,
+    date_created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on company_id for reading a company's history in order
+CREATE INDEX idx_audit_log_company_id ON audit_log(company_id, id);
+
+COMMIT;
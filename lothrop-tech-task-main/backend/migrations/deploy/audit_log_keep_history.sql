@@ -0,0 +1,10 @@
+-- Deploy lothrop-backend:audit_log_keep_history to pg
+-- requires: audit_log
+
+BEGIN;
+
+-- Purging soft-deleted companies must not erase who created, changed or deleted them, so audit entries
+-- keep the company ID without a foreign key and outlive the company row
+ALTER TABLE audit_log DROP CONSTRAINT audit_log_company_id_fkey;
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- Deploy lothrop-backend:cayman_islands_spelling to pg
+-- requires: companies
+
+BEGIN;
+
+ALTER TABLE companies DROP CONSTRAINT companies_jurisdiction_check;
+
+UPDATE companies SET jurisdiction = 'Cayman Islands' WHERE jurisdiction = 'Caymens';
+
+ALTER TABLE companies ADD CONSTRAINT companies_jurisdiction_check
+    CHECK (jurisdiction IN ('UK', 'Singapore', 'Cayman Islands'));
+
+-- Jurisdiction filtering compares case-insensitively
+CREATE INDEX idx_companies_jurisdiction_lower ON companies(LOWER(jurisdiction));
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Deploy lothrop-backend:companies_keyset_index to pg
+-- requires: companies
+
+BEGIN;
+
+-- Create index matching the default list order for cursor (keyset) pagination
+CREATE INDEX idx_companies_date_created_id ON companies(date_created DESC, id DESC);
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- Deploy lothrop-backend:companies_name_key to pg
+-- requires: companies_soft_delete
+-- requires: companies_search_name
+
+BEGIN;
+
+-- Accent-, case- and whitespace-folded company name, so "Acme  Ltd" and "acme ltd" share a key; the application
+-- keeps it in sync on write
+ALTER TABLE companies ADD COLUMN name_key VARCHAR(255);
+
+UPDATE companies SET name_key = BTRIM(REGEXP_REPLACE(LOWER(unaccent(company_name)), '\s+', ' ', 'g'));
+
+ALTER TABLE companies ALTER COLUMN name_key SET NOT NULL;
+
+-- Uniqueness now compares name keys. Fails if live companies in a jurisdiction share a key; resolve them
+-- before deploying.
+DROP INDEX idx_companies_jurisdiction_name_unique;
+CREATE UNIQUE INDEX idx_companies_jurisdiction_name_unique ON companies(jurisdiction, name_key)
+    WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,24 @@
+-- Deploy lothrop-backend:companies_normalized_name to pg
+-- requires: companies_name_key
+
+BEGIN;
+
+-- Punctuation- and legal-form-normalized company name, so "ACME LTD." and "Acme Limited" share a key; the
+-- application keeps it in sync on write. Unlike name_key it is not unique: near-duplicates are warned about or
+-- rejected on create according to DUPLICATE_POLICY.
+ALTER TABLE companies ADD COLUMN normalized_name VARCHAR(255);
+
+UPDATE companies SET normalized_name = BTRIM(REGEXP_REPLACE(
+    REGEXP_REPLACE(REGEXP_REPLACE(REGEXP_REPLACE(REGEXP_REPLACE(REGEXP_REPLACE(
+        REGEXP_REPLACE(REGEXP_REPLACE(REPLACE(REGEXP_REPLACE(LOWER(unaccent(company_name)), '[.''’]', '', 'g'),
+            '&', ' and '), '[^[:alnum:]]+', ' ', 'g'),
+        '\mlimited\M', 'ltd', 'g'), '\mincorporated\M', 'inc', 'g'), '\mcorporation\M', 'corp', 'g'),
+        '\mcompany\M', 'co', 'g'), '\mprivate\M', 'pte', 'g'),
+    '\s+', ' ', 'g'));
+
+ALTER TABLE companies ALTER COLUMN normalized_name SET NOT NULL;
+
+CREATE INDEX idx_companies_jurisdiction_normalized_name ON companies(jurisdiction, normalized_name)
+    WHERE deleted_at IS NULL;
+
+COMMIT;
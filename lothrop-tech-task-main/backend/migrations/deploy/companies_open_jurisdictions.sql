@@ -0,0 +1,11 @@
+-- Deploy lothrop-backend:companies_open_jurisdictions to pg
+-- requires: cayman_islands_spelling
+
+BEGIN;
+
+-- The application validates jurisdictions against its configured list, so new ones need no migration
+ALTER TABLE companies DROP CONSTRAINT companies_jurisdiction_check;
+
+ALTER TABLE companies ALTER COLUMN jurisdiction TYPE VARCHAR(100);
+
+COMMIT;
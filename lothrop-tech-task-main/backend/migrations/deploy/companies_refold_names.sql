@@ -0,0 +1,20 @@
+-- Deploy lothrop-backend:companies_refold_names to pg
+-- requires: companies_normalized_name
+
+BEGIN;
+
+-- The application now folds letters such as "ø" and "ß" as unaccent does, so recompute the keys it wrote
+-- before. Fails if live companies in a jurisdiction now share a name key; resolve them before deploying.
+UPDATE companies SET
+    search_name = LOWER(unaccent(company_name)),
+    name_key = BTRIM(REGEXP_REPLACE(LOWER(unaccent(company_name)), '\s+', ' ', 'g')),
+    normalized_name = BTRIM(REGEXP_REPLACE(
+        REGEXP_REPLACE(REGEXP_REPLACE(REGEXP_REPLACE(REGEXP_REPLACE(REGEXP_REPLACE(
+            REGEXP_REPLACE(REGEXP_REPLACE(REPLACE(REGEXP_REPLACE(LOWER(unaccent(company_name)), '[.''’]', '', 'g'),
+                '&', ' and '), '[^[:alnum:]]+', ' ', 'g'),
+            '\mlimited\M', 'ltd', 'g'), '\mincorporated\M', 'inc', 'g'), '\mcorporation\M', 'corp', 'g'),
+            '\mcompany\M', 'co', 'g'), '\mprivate\M', 'pte', 'g'),
+        '\s+', ' ', 'g'))
+WHERE company_name ~ '[ÆæŒœØøßŁłĐđÐðÞþ]';
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- Deploy lothrop-backend:companies_search_name to pg
+-- requires: companies
+
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Accent- and case-folded company name used for name search; the application keeps it in sync on write
+ALTER TABLE companies ADD COLUMN search_name VARCHAR(255);
+
+UPDATE companies SET search_name = LOWER(unaccent(company_name));
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Deploy lothrop-backend:companies_search_vector to pg
+-- requires: companies
+
+BEGIN;
+
+-- Full-text document for relevance-ranked search, weighting the company name above its nature of business.
+-- Postgres keeps the generated column in sync on every write.
+ALTER TABLE companies ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', company_name), 'A') ||
+    setweight(to_tsvector('english', COALESCE(nature_of_business, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_companies_search_vector ON companies USING GIN (search_vector);
+
+COMMIT;
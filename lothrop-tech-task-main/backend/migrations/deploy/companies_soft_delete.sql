@@ -0,0 +1,13 @@
+-- Deploy lothrop-backend:companies_soft_delete to pg
+-- requires: companies_unique_name
+
+BEGIN;
+
+ALTER TABLE companies ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Deleted companies no longer reserve their name within the jurisdiction
+DROP INDEX idx_companies_jurisdiction_name_unique;
+CREATE UNIQUE INDEX idx_companies_jurisdiction_name_unique ON companies(jurisdiction, LOWER(company_name))
+    WHERE deleted_at IS NULL;
+
+COMMIT;
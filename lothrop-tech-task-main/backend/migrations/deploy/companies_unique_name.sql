@@ -0,0 +1,10 @@
+-- Deploy lothrop-backend:companies_unique_name to pg
+-- requires: companies
+
+BEGIN;
+
+-- One company per name (case-insensitive) within a jurisdiction. Fails if duplicates already exist;
+-- resolve them before deploying.
+CREATE UNIQUE INDEX idx_companies_jurisdiction_name_unique ON companies(jurisdiction, LOWER(company_name));
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- Deploy lothrop-backend:companies_unique_sec_code to pg
+-- requires: companies_soft_delete
+
+BEGIN;
+
+-- One live company per SEC code, compared in the canonical trimmed, uppercase form the API returns, so " abc1"
+-- and "ABC1" collide. Companies without a SEC code, NULL or blank, are exempt. Fails if live companies already
+-- share a code; resolve them before deploying.
+CREATE UNIQUE INDEX idx_companies_sec_code_unique
+    ON companies(UPPER(regexp_replace(sec_code, '^\s+|\s+$', '', 'g')))
+    WHERE regexp_replace(sec_code, '^\s+|\s+$', '', 'g') <> '' AND deleted_at IS NULL;
+
+COMMIT;
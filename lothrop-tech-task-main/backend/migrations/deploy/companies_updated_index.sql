@@ -0,0 +1,9 @@
+-- Deploy lothrop-backend:companies_updated_index to pg
+-- requires: companies
+
+BEGIN;
+
+-- Create index matching the incremental extract order
+CREATE INDEX idx_companies_date_updated_id ON companies(date_updated, id);
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Deploy lothrop-backend:directors to pg
+-- requires: companies
+
+BEGIN;
+
+CREATE TABLE directors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    role VARCHAR(100) NOT NULL,
+    date_created TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on company_id for listing and counting a company's directors
+CREATE INDEX idx_directors_company_id ON directors(company_id, date_created);
+
+COMMIT;
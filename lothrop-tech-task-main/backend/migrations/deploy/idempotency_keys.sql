@@ -0,0 +1,16 @@
+-- Deploy lothrop-backend:idempotency_keys to pg
+-- requires: companies
+
+BEGIN;
+
+CREATE TABLE idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    request_hash CHAR(64) NOT NULL,
+    company_id UUID REFERENCES companies(id) ON DELETE CASCADE,
+    date_created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on date_created for expiring old keys
+CREATE INDEX idx_idempotency_keys_date_created ON idempotency_keys(date_created);
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Deploy lothrop-backend:registry_numbers to pg
+-- requires: companies
+
+BEGIN;
+
+ALTER TABLE companies
+    ADD COLUMN registry_source VARCHAR(50),
+    ADD COLUMN registry_number VARCHAR(50),
+    ADD CONSTRAINT companies_registry_pair_check
+        CHECK ((registry_source IS NULL) = (registry_number IS NULL));
+
+-- Create index on registry source and number for registry lookups
+CREATE INDEX idx_companies_registry ON companies(registry_source, registry_number);
+
+COMMIT;
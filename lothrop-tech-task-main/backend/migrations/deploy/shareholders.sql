@@ -0,0 +1,17 @@
+-- Deploy lothrop-backend:shareholders to pg
+-- requires: companies
+
+BEGIN;
+
+CREATE TABLE shareholders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    ownership_percentage NUMERIC(5, 2) NOT NULL CHECK (ownership_percentage > 0 AND ownership_percentage <= 100),
+    date_created TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on company_id for listing, counting and totalling a company's shareholders
+CREATE INDEX idx_shareholders_company_id ON shareholders(company_id, date_created);
+
+COMMIT;
@@ -0,0 +1,15 @@
+// Package migrations embeds the sqitch plan and deploy scripts so the server can bring the database up to date
+// at startup. The revert and verify scripts are only used by sqitch.
+package migrations
+
+import "embed"
+
+// Plan is the contents of sqitch.plan
+//
+//go:embed sqitch.plan
+var Plan string
+
+// Scripts holds the deploy scripts, as deploy/<change>.sql
+//
+//go:embed deploy/*.sql
+var Scripts embed.FS
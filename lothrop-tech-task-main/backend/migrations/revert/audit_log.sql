@@ -0,0 +1,7 @@
+-- Revert lothrop-backend:audit_log from pg
+
+BEGIN;
+
+DROP TABLE IF EXISTS audit_log;
+
+COMMIT;
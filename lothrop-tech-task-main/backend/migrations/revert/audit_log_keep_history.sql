@@ -0,0 +1,11 @@
+-- Revert lothrop-backend:audit_log_keep_history from pg
+
+BEGIN;
+
+-- Deletes the history of purged companies, which the restored foreign key cannot reference
+DELETE FROM audit_log WHERE NOT EXISTS (SELECT 1 FROM companies WHERE companies.id = audit_log.company_id);
+
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_company_id_fkey
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE;
+
+COMMIT;
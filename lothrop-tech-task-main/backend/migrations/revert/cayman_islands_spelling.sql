@@ -0,0 +1,14 @@
+-- Revert lothrop-backend:cayman_islands_spelling from pg
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_companies_jurisdiction_lower;
+
+ALTER TABLE companies DROP CONSTRAINT companies_jurisdiction_check;
+
+UPDATE companies SET jurisdiction = 'Caymens' WHERE jurisdiction = 'Cayman Islands';
+
+ALTER TABLE companies ADD CONSTRAINT companies_jurisdiction_check
+    CHECK (jurisdiction IN ('UK', 'Singapore', 'Caymens'));
+
+COMMIT;
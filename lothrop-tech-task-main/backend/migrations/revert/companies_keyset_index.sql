@@ -0,0 +1,7 @@
+-- Revert lothrop-backend:companies_keyset_index from pg
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_companies_date_created_id;
+
+COMMIT;
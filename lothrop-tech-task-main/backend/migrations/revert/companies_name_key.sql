@@ -0,0 +1,11 @@
+-- Revert lothrop-backend:companies_name_key from pg
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_companies_jurisdiction_name_unique;
+CREATE UNIQUE INDEX idx_companies_jurisdiction_name_unique ON companies(jurisdiction, LOWER(company_name))
+    WHERE deleted_at IS NULL;
+
+ALTER TABLE companies DROP COLUMN IF EXISTS name_key;
+
+COMMIT;
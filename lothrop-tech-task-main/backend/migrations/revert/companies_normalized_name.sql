@@ -0,0 +1,8 @@
+-- Revert lothrop-backend:companies_normalized_name from pg
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_companies_jurisdiction_normalized_name;
+ALTER TABLE companies DROP COLUMN IF EXISTS normalized_name;
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- Revert lothrop-backend:companies_open_jurisdictions from pg
+
+BEGIN;
+
+-- Fails if companies in other jurisdictions exist; remove them before reverting
+ALTER TABLE companies ALTER COLUMN jurisdiction TYPE VARCHAR(20);
+
+ALTER TABLE companies ADD CONSTRAINT companies_jurisdiction_check
+    CHECK (jurisdiction IN ('UK', 'Singapore', 'Cayman Islands'));
+
+COMMIT;
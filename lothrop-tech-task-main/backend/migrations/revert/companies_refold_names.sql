@@ -0,0 +1,7 @@
+-- Revert lothrop-backend:companies_refold_names from pg
+
+BEGIN;
+
+-- The recomputed keys are what unaccent produces and stay valid, so there is nothing to undo
+
+COMMIT;
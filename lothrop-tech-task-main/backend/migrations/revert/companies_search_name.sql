@@ -0,0 +1,7 @@
+-- Revert lothrop-backend:companies_search_name from pg
+
+BEGIN;
+
+ALTER TABLE companies DROP COLUMN IF EXISTS search_name;
+
+COMMIT;
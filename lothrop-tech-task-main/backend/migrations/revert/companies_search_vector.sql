@@ -0,0 +1,8 @@
+-- Revert lothrop-backend:companies_search_vector from pg
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_companies_search_vector;
+ALTER TABLE companies DROP COLUMN IF EXISTS search_vector;
+
+COMMIT;
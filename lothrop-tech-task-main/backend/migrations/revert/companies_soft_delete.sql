@@ -0,0 +1,13 @@
+-- Revert lothrop-backend:companies_soft_delete from pg
+
+BEGIN;
+
+-- Before soft deletes these rows would have been removed outright
+DELETE FROM companies WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_companies_jurisdiction_name_unique;
+CREATE UNIQUE INDEX idx_companies_jurisdiction_name_unique ON companies(jurisdiction, LOWER(company_name));
+
+ALTER TABLE companies DROP COLUMN IF EXISTS deleted_at;
+
+COMMIT;
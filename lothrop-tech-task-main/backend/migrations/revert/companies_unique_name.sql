@@ -0,0 +1,7 @@
+-- Revert lothrop-backend:companies_unique_name from pg
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_companies_jurisdiction_name_unique;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Revert lothrop-backend:companies_unique_sec_code from pg
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_companies_sec_code_unique;
+
+COMMIT;
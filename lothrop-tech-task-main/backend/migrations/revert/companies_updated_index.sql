@@ -0,0 +1,7 @@
+-- Revert lothrop-backend:companies_updated_index from pg
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_companies_date_updated_id;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Revert lothrop-backend:directors from pg
+
+BEGIN;
+
+DROP TABLE IF EXISTS directors;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Revert lothrop-backend:idempotency_keys from pg
+
+BEGIN;
+
+DROP TABLE IF EXISTS idempotency_keys;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Revert lothrop-backend:registry_numbers from pg
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_companies_registry;
+
+ALTER TABLE companies
+    DROP CONSTRAINT IF EXISTS companies_registry_pair_check,
+    DROP COLUMN IF EXISTS registry_number,
+    DROP COLUMN IF EXISTS registry_source;
+
+COMMIT;
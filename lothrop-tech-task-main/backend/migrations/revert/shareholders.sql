@@ -0,0 +1,7 @@
+-- Revert lothrop-backend:shareholders from pg
+
+BEGIN;
+
+DROP TABLE IF EXISTS shareholders;
+
+COMMIT;
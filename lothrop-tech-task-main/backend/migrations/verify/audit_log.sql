@@ -0,0 +1,9 @@
+-- Verify lothrop-backend:audit_log on pg
+
+BEGIN;
+
+SELECT id, company_id, action, actor, date_created
+FROM audit_log
+WHERE FALSE;
+
+ROLLBACK;
@@ -0,0 +1,7 @@
+-- Verify lothrop-backend:audit_log_keep_history on pg
+
+BEGIN;
+
+SELECT 1/(COUNT(*) = 0)::int FROM pg_constraint WHERE conname = 'audit_log_company_id_fkey';
+
+ROLLBACK;
@@ -0,0 +1,10 @@
+-- Verify lothrop-backend:cayman_islands_spelling on pg
+
+BEGIN;
+
+SELECT 1/COUNT(*) FROM pg_indexes
+WHERE tablename = 'companies' AND indexname = 'idx_companies_jurisdiction_lower';
+
+SELECT 1/(COUNT(*) = 0)::int FROM companies WHERE jurisdiction = 'Caymens';
+
+ROLLBACK;
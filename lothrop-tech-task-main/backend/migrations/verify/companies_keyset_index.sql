@@ -0,0 +1,8 @@
+-- Verify lothrop-backend:companies_keyset_index on pg
+
+BEGIN;
+
+SELECT 1/COUNT(*) FROM pg_indexes
+WHERE tablename = 'companies' AND indexname = 'idx_companies_date_created_id';
+
+ROLLBACK;
@@ -0,0 +1,13 @@
+-- Verify lothrop-backend:companies_name_key on pg
+
+BEGIN;
+
+SELECT name_key
+FROM companies
+WHERE FALSE;
+
+SELECT 1/COUNT(*) FROM pg_indexes
+WHERE tablename = 'companies' AND indexname = 'idx_companies_jurisdiction_name_unique'
+  AND indexdef LIKE '%name_key%';
+
+ROLLBACK;
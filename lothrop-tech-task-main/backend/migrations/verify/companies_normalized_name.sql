@@ -0,0 +1,12 @@
+-- Verify lothrop-backend:companies_normalized_name on pg
+
+BEGIN;
+
+SELECT normalized_name
+FROM companies
+WHERE FALSE;
+
+SELECT 1/COUNT(*) FROM pg_indexes
+WHERE tablename = 'companies' AND indexname = 'idx_companies_jurisdiction_normalized_name';
+
+ROLLBACK;
@@ -0,0 +1,7 @@
+-- Verify lothrop-backend:companies_open_jurisdictions on pg
+
+BEGIN;
+
+SELECT 1/(COUNT(*) = 0)::int FROM pg_constraint WHERE conname = 'companies_jurisdiction_check';
+
+ROLLBACK;
@@ -0,0 +1,8 @@
+-- Verify lothrop-backend:companies_refold_names on pg
+
+BEGIN;
+
+SELECT 1/(COUNT(*) = 0)::int FROM companies
+WHERE company_name ~ '[ÆæŒœØøßŁłĐđÐðÞþ]' AND search_name <> LOWER(unaccent(company_name));
+
+ROLLBACK;
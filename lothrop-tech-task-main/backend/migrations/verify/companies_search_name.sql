@@ -0,0 +1,9 @@
+-- Verify lothrop-backend:companies_search_name on pg
+
+BEGIN;
+
+SELECT search_name
+FROM companies
+WHERE FALSE;
+
+ROLLBACK;
@@ -0,0 +1,12 @@
+-- Verify lothrop-backend:companies_search_vector on pg
+
+BEGIN;
+
+SELECT search_vector
+FROM companies
+WHERE FALSE;
+
+SELECT 1/COUNT(*) FROM pg_indexes
+WHERE tablename = 'companies' AND indexname = 'idx_companies_search_vector';
+
+ROLLBACK;
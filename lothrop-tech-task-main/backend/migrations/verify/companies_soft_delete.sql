@@ -0,0 +1,9 @@
+-- Verify lothrop-backend:companies_soft_delete on pg
+
+BEGIN;
+
+SELECT deleted_at
+FROM companies
+WHERE FALSE;
+
+ROLLBACK;
@@ -0,0 +1,8 @@
+-- Verify lothrop-backend:companies_unique_name on pg
+
+BEGIN;
+
+SELECT 1/COUNT(*) FROM pg_indexes
+WHERE tablename = 'companies' AND indexname = 'idx_companies_jurisdiction_name_unique';
+
+ROLLBACK;
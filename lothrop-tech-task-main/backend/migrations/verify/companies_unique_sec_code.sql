@@ -0,0 +1,8 @@
+-- Verify lothrop-backend:companies_unique_sec_code on pg
+
+BEGIN;
+
+SELECT 1/COUNT(*) FROM pg_indexes
+WHERE tablename = 'companies' AND indexname = 'idx_companies_sec_code_unique';
+
+ROLLBACK;
@@ -0,0 +1,8 @@
+-- Verify lothrop-backend:companies_updated_index on pg
+
+BEGIN;
+
+SELECT 1/COUNT(*) FROM pg_indexes
+WHERE tablename = 'companies' AND indexname = 'idx_companies_date_updated_id';
+
+ROLLBACK;
@@ -0,0 +1,9 @@
+-- Verify lothrop-backend:directors on pg
+
+BEGIN;
+
+SELECT id, company_id, name, role, date_created
+FROM directors
+WHERE FALSE;
+
+ROLLBACK;
@@ -0,0 +1,9 @@
+-- Verify lothrop-backend:idempotency_keys on pg
+
+BEGIN;
+
+SELECT key, request_hash, company_id, date_created
+FROM idempotency_keys
+WHERE FALSE;
+
+ROLLBACK;
@@ -0,0 +1,9 @@
+-- Verify lothrop-backend:registry_numbers on pg
+
+BEGIN;
+
+SELECT registry_source, registry_number
+FROM companies
+WHERE FALSE;
+
+ROLLBACK;
@@ -0,0 +1,9 @@
+-- Verify lothrop-backend:shareholders on pg
+
+BEGIN;
+
+SELECT id, company_id, name, ownership_percentage, date_created
+FROM shareholders
+WHERE FALSE;
+
+ROLLBACK;
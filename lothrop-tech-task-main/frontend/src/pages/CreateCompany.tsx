@@ -11,7 +11,7 @@ import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@
 import { Form, FormControl, FormField, FormItem, FormLabel, FormMessage } from '@/components/ui/form';
 
 const companySchema = z.object({
-  jurisdiction: z.enum(['UK', 'Singapore', 'Caymens']),
+  jurisdiction: z.enum(['UK', 'Singapore', 'Cayman Islands']),
   company_name: z.string().min(1, 'Company name is required').max(255),
   company_address: z.string().min(1, 'Company address is required'),
   nature_of_business: z.string().optional(),
@@ -147,7 +147,7 @@ const CreateCompany: React.FC = () => {
                       <SelectContent>
                         <SelectItem value="UK">UK</SelectItem>
                         <SelectItem value="Singapore">Singapore</SelectItem>
-                        <SelectItem value="Caymens">Caymens</SelectItem>
+                        <SelectItem value="Cayman Islands">Cayman Islands</SelectItem>
                       </SelectContent>
                     </Select>
                     <FormMessage />